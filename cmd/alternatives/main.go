@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/srv"
+	"os"
+)
+
+// alternatives migrates the delimited vocab.alternatives text into vocab_alternative
+// records. Vocab that already have alternative records are skipped, so it can be re-run.
+func main() {
+	dsn := db.GetDatabaseURL()
+
+	err := db.CreatePool(dsn)
+	if err != nil {
+		fmt.Printf("Failed DB connections, %v\n", err)
+		os.Exit(1)
+	}
+
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		fmt.Printf("Failed to create vocab service, %v\n", err)
+		os.Exit(1)
+	}
+
+	migrated, err := vocabService.MigrateAlternatives()
+	if err != nil {
+		fmt.Printf("Failed alternatives migration after %d records, %v\n", migrated, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("migrated %d alternatives\n", migrated)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/srv"
+	"os"
+)
+
+// lint runs the content lint rules over the vocab table and reports the findings.
+// Run with -file to file a fixit for each new finding.
+func main() {
+	learningCode := flag.String("code", "", "learning language code to scan, empty scans all")
+	file := flag.Bool("file", false, "file fixits for the findings")
+	flag.Parse()
+
+	dsn := db.GetDatabaseURL()
+
+	err := db.CreatePool(dsn)
+	if err != nil {
+		fmt.Printf("Failed DB connections, %v\n", err)
+		os.Exit(1)
+	}
+
+	lintService, err := srv.NewLintService()
+	if err != nil {
+		fmt.Printf("Failed to create lint service, %v\n", err)
+		os.Exit(1)
+	}
+
+	results, err := lintService.LintVocabs(*learningCode)
+	if err != nil {
+		fmt.Printf("Failed lint, %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, r := range results {
+		for _, f := range r.Findings {
+			fmt.Printf("vocab %d '%s': %s %s\n", r.Vocab.ID, r.Vocab.LearningLang, f.Rule, f.Message)
+		}
+	}
+	fmt.Printf("found %d vocab records with findings\n", len(results))
+
+	if !*file {
+		return
+	}
+
+	fixits, err := lintService.FileFixits(results)
+	if err != nil {
+		fmt.Printf("Failed to file fixits, %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("filed %d fixits\n", len(fixits))
+}
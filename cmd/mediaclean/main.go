@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/srv"
+	"os"
+	"time"
+)
+
+// mediaclean reports the audio recordings no vocab refers to and the stored files left by
+// failed uploads. Run with -apply to remove them.
+func main() {
+	grace := flag.Duration("grace", 24*time.Hour, "keep recordings uploaded within this period")
+	apply := flag.Bool("apply", false, "remove the orphans found")
+	flag.Parse()
+
+	dsn := db.GetDatabaseURL()
+
+	err := db.CreatePool(dsn)
+	if err != nil {
+		fmt.Printf("Failed DB connections, %v\n", err)
+		os.Exit(1)
+	}
+
+	audioService, err := srv.NewAudioService()
+	if err != nil {
+		fmt.Printf("Failed to create audio service, %v\n", err)
+		os.Exit(1)
+	}
+
+	cleanup, err := audioService.CleanupOrphanAudio(*grace, *apply)
+	if err != nil {
+		fmt.Printf("Failed audio cleanup, %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, asset := range cleanup.Assets {
+		fmt.Printf("orphan audio %d %s '%s'\n", asset.ID, asset.Hash, asset.Filename)
+	}
+	for _, key := range cleanup.StrayKeys {
+		fmt.Printf("stray file %s\n", key)
+	}
+
+	verb := "found"
+	if *apply {
+		verb = "removed"
+	}
+	fmt.Printf("%s %d orphan audio and %d stray files\n", verb, len(cleanup.Assets), len(cleanup.StrayKeys))
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/srv"
+	"os"
+)
+
+// normreport scans the vocab table and reports the records whose learning_lang values
+// collide once normalized. These must be resolved, for example with renameVocab, before
+// the unique index on the normalized learning_lang can be created.
+func main() {
+	learningCode := flag.String("code", "", "learning language code to scan, empty scans all")
+	flag.Parse()
+
+	dsn := db.GetDatabaseURL()
+
+	err := db.CreatePool(dsn)
+	if err != nil {
+		fmt.Printf("Failed DB connections, %v\n", err)
+		os.Exit(1)
+	}
+
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		fmt.Printf("Failed to create vocab service, %v\n", err)
+		os.Exit(1)
+	}
+
+	collisions, err := vocabService.FindNormalizationCollisions(*learningCode)
+	if err != nil {
+		fmt.Printf("Failed normalization report, %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, c := range collisions {
+		fmt.Printf("%s '%s' is shared by:\n", c.LearningLangCode, c.Normalized)
+		for _, v := range c.Vocabs {
+			fmt.Printf("  vocab %d %q\n", v.ID, v.LearningLang)
+		}
+	}
+	fmt.Printf("found %d normalization collisions\n", len(collisions))
+}
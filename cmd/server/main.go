@@ -1,17 +1,31 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/heather92115/verdure-admin/graph"
 	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/event"
+	"github.com/heather92115/verdure-admin/internal/media"
+	"github.com/heather92115/verdure-admin/internal/outbox"
+	"github.com/heather92115/verdure-admin/internal/srv"
 	"log"
 	"net/http"
 	"os"
+	"time"
 )
 
-const defaultPort = "8090"
+const (
+	defaultPort = "8090"
+
+	// webhookPollInterval is how often the queued webhook deliveries are checked for due ones.
+	webhookPollInterval = 5 * time.Second
+
+	// outboxPollInterval is how often the outbox is checked for pending events.
+	outboxPollInterval = time.Second
+)
 
 func main() {
 	fmt.Println("Starting the gql server")
@@ -29,10 +43,60 @@ func main() {
 		port = defaultPort
 	}
 
-	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}))
+	storage, err := media.StorageFromEnv()
+	if err != nil {
+		fmt.Printf("Failed media storage, %v\n", err)
+		return
+	}
+
+	// Subscriptions on other instances see the changes saved here through Postgres notifications
+	if os.Getenv("EVENT_NOTIFY") == "true" {
+		relay, err := db.NewNotifyRelay(dsn, event.Default())
+		if err != nil {
+			fmt.Printf("Failed event relay, %v\n", err)
+			return
+		}
+		event.Default().SetRelay(relay)
+		go relay.Listen(context.Background())
+	}
+
+	// Every instance delivers webhooks, each due delivery is claimed by one of them
+	webhookService, err := srv.NewWebhookService()
+	if err != nil {
+		fmt.Printf("Failed webhook service, %v\n", err)
+		return
+	}
+	go webhookService.RunDeliveries(context.Background(), webhookPollInterval)
+
+	// Downstream systems are fed from the outbox when OUTBOX_SINK names a sink
+	sink, err := outbox.SinkFromEnv()
+	if err != nil {
+		fmt.Printf("Failed outbox sink, %v\n", err)
+		return
+	}
+	if sink != nil {
+		relay, err := outbox.NewRelay(sink)
+		if err != nil {
+			fmt.Printf("Failed outbox relay, %v\n", err)
+			return
+		}
+		go relay.Run(context.Background(), outboxPollInterval)
+	}
+
+	// The learner app reads the live snapshots, outside of the admin API
+	snapshotService, err := srv.NewSnapshotService()
+	if err != nil {
+		fmt.Printf("Failed snapshot service, %v\n", err)
+		return
+	}
+	http.Handle(srv.SnapshotPath, snapshotService.Handler())
+
+	gqlServer := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}))
+	gqlServer.SetErrorPresenter(graph.ErrorPresenter)
 
 	http.Handle("/admin/gql", playground.Handler("GraphQL playground", "/admin"))
-	http.Handle("/admin", srv)
+	http.Handle("/admin", srv.ActorHandler(gqlServer))
+	http.Handle(media.AudioPath, media.Handler(storage))
 
 	log.Fatal(http.ListenAndServe(":"+port, nil))
 }
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/srv"
+	"os"
+)
+
+// termreport lists the pos and skill values used by vocab that are not managed part of speech
+// or skill names, with the number of vocab using each and a suggested managed name.
+func main() {
+	dsn := db.GetDatabaseURL()
+
+	err := db.CreatePool(dsn)
+	if err != nil {
+		fmt.Printf("Failed DB connections, %v\n", err)
+		os.Exit(1)
+	}
+
+	lookupService, err := srv.NewLookupService()
+	if err != nil {
+		fmt.Printf("Failed to create lookup service, %v\n", err)
+		os.Exit(1)
+	}
+
+	terms, err := lookupService.FindNonConformingTerms()
+	if err != nil {
+		fmt.Printf("Failed term report, %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, t := range terms {
+		suggestion := t.Suggestion
+		if len(suggestion) == 0 {
+			suggestion = "no suggestion"
+		}
+		fmt.Printf("%s '%s' used by %d vocab: %s\n", t.Field, t.Value, t.Count, suggestion)
+	}
+	fmt.Printf("found %d non-conforming values\n", len(terms))
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/srv"
+	"os"
+)
+
+// wordcount scans the vocab table and reports the records whose num_learning_words
+// does not match the number of words in learning_lang. Run with -fix to correct them.
+func main() {
+	learningCode := flag.String("code", "", "learning language code to scan, empty scans all")
+	fix := flag.Bool("fix", false, "correct the mismatched records")
+	flag.Parse()
+
+	dsn := db.GetDatabaseURL()
+
+	err := db.CreatePool(dsn)
+	if err != nil {
+		fmt.Printf("Failed DB connections, %v\n", err)
+		os.Exit(1)
+	}
+
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		fmt.Printf("Failed to create vocab service, %v\n", err)
+		os.Exit(1)
+	}
+
+	mismatches, err := vocabService.CheckWordCounts(*learningCode, *fix)
+	for _, m := range mismatches {
+		fmt.Printf("vocab %d '%s': stored %d, computed %d\n", m.VocabID, m.LearningLang, m.Stored, m.Computed)
+	}
+	if err != nil {
+		fmt.Printf("Failed word count check, %v\n", err)
+		os.Exit(1)
+	}
+
+	action := "found"
+	if *fix {
+		action = "fixed"
+	}
+	fmt.Printf("%s %d mismatched vocab records\n", action, len(mismatches))
+}
@@ -0,0 +1,74 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/heather92115/verdure-admin/internal/srv"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"strconv"
+)
+
+// duplicateVocabError converts a srv.DuplicateVocabError into a GraphQL error whose
+// extensions carry the candidate vocab ids, so clients can offer them to the editor before
+// retrying with force. Any other error is returned unchanged.
+func duplicateVocabError(ctx context.Context, err error) error {
+	var duplicate *srv.DuplicateVocabError
+	if !errors.As(err, &duplicate) {
+		return err
+	}
+
+	ids := make([]string, len(duplicate.CandidateIDs))
+	for i, id := range duplicate.CandidateIDs {
+		ids[i] = strconv.Itoa(id)
+	}
+
+	return &gqlerror.Error{
+		Message: err.Error(),
+		Path:    graphql.GetPath(ctx),
+		Extensions: map[string]interface{}{
+			"code":          "DUPLICATE_CANDIDATES",
+			"candidate_ids": ids,
+		},
+	}
+}
+
+// ErrorPresenter presents the errors returned by resolvers. Validation failures from the
+// services, a srv.ValidationError or a single srv.FieldError, carry the code VALIDATION_FAILED
+// and a fields extension listing the path, rule code, limit and message of each failure, so
+// clients can highlight every field at once. Other errors are presented by the gqlgen default.
+//
+// Usage example:
+// server.SetErrorPresenter(graph.ErrorPresenter)
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+
+	var fieldErrs []srv.FieldError
+	var validation *srv.ValidationError
+	var fieldErr *srv.FieldError
+	if errors.As(err, &validation) {
+		fieldErrs = validation.Errors
+	} else if errors.As(err, &fieldErr) {
+		fieldErrs = []srv.FieldError{*fieldErr}
+	} else {
+		return presented
+	}
+
+	fields := make([]map[string]interface{}, len(fieldErrs))
+	for i, failure := range fieldErrs {
+		fields[i] = map[string]interface{}{
+			"field":   failure.Field,
+			"rule":    failure.Rule,
+			"limit":   failure.Limit,
+			"message": failure.Message,
+		}
+	}
+
+	if presented.Extensions == nil {
+		presented.Extensions = make(map[string]interface{})
+	}
+	presented.Extensions["code"] = "VALIDATION_FAILED"
+	presented.Extensions["fields"] = fields
+
+	return presented
+}
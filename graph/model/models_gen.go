@@ -8,16 +8,106 @@ import (
 	"strconv"
 )
 
+type AddAlternative struct {
+	VocabID     string  `json:"vocab_id"`
+	Alternative string  `json:"alternative"`
+	Notes       *string `json:"notes,omitempty"`
+}
+
+type Alternative struct {
+	Alternative string `json:"alternative"`
+	Notes       string `json:"notes"`
+	Position    int    `json:"position"`
+}
+
+type AudioAsset struct {
+	ID         string `json:"id"`
+	Hash       string `json:"hash"`
+	MimeType   string `json:"mime_type"`
+	Size       int    `json:"size"`
+	DurationMs int    `json:"duration_ms"`
+	Filename   string `json:"filename"`
+	URL        string `json:"url"`
+	CreatedBy  string `json:"created_by"`
+}
+
 type Audit struct {
+	ID        string      `json:"id"`
+	ObjectID  string      `json:"object_id"`
+	TableName string      `json:"table_name"`
+	Action    AuditAction `json:"action"`
+	Diff      string      `json:"diff"`
+	Before    string      `json:"before"`
+	After     string      `json:"after"`
+	Comments  string      `json:"comments"`
+	CreatedBy string      `json:"created_by"`
+	Created   string      `json:"created"`
+}
+
+type ChangeRequest struct {
+	ID             string              `json:"id"`
+	VocabID        string              `json:"vocab_id"`
+	Status         ChangeRequestStatus `json:"status"`
+	Before         string              `json:"before"`
+	Proposed       string              `json:"proposed"`
+	Diff           string              `json:"diff"`
+	Fields         []string            `json:"fields"`
+	Comments       string              `json:"comments"`
+	ReviewComments string              `json:"review_comments"`
+	CreatedBy      string              `json:"created_by"`
+	ReviewedBy     string              `json:"reviewed_by"`
+	Created        string              `json:"created"`
+	Reviewed       *string             `json:"reviewed,omitempty"`
+}
+
+type Concept struct {
+	ID            string   `json:"id"`
+	Gloss         string   `json:"gloss"`
+	GlossLangCode string   `json:"gloss_lang_code"`
+	Notes         string   `json:"notes"`
+	CreatedBy     string   `json:"created_by"`
+	Created       string   `json:"created"`
+	Translations  []*Vocab `json:"translations"`
+}
+
+type ConjugatedForm struct {
+	Tense     string `json:"tense"`
+	Person    string `json:"person"`
+	Form      string `json:"form"`
+	Irregular bool   `json:"irregular"`
+}
+
+type Conjugation struct {
 	ID        string `json:"id"`
-	ObjectID  string `json:"object_id"`
-	TableName string `json:"table_name"`
-	Diff      string `json:"diff"`
-	Before    string `json:"before"`
-	After     string `json:"after"`
-	Comments  string `json:"comments"`
-	CreatedBy string `json:"created_by"`
-	Created   string `json:"created"`
+	VocabID   string `json:"vocab_id"`
+	Tense     string `json:"tense"`
+	Person    string `json:"person"`
+	Form      string `json:"form"`
+	Irregular bool   `json:"irregular"`
+	Corrected bool   `json:"corrected"`
+}
+
+type CorrectConjugation struct {
+	VocabID string `json:"vocab_id"`
+	Tense   string `json:"tense"`
+	Person  string `json:"person"`
+	Form    string `json:"form"`
+}
+
+type DuplicateCluster struct {
+	Key    string   `json:"key"`
+	Vocabs []*Vocab `json:"vocabs"`
+}
+
+type ExampleSentence struct {
+	ID          string   `json:"id"`
+	VocabID     string   `json:"vocab_id"`
+	Sentence    string   `json:"sentence"`
+	Translation string   `json:"translation"`
+	Source      string   `json:"source"`
+	Tokens      []string `json:"tokens"`
+	Highlights  []int    `json:"highlights"`
+	CreatedBy   string   `json:"created_by"`
 }
 
 type Fixit struct {
@@ -30,9 +120,55 @@ type Fixit struct {
 	Created   string `json:"created"`
 }
 
+type GrammarSuggestion struct {
+	Gender  string `json:"gender"`
+	Plural  string `json:"plural"`
+	Article string `json:"article"`
+	Reason  string `json:"reason"`
+}
+
+type Language struct {
+	ID               string   `json:"id"`
+	Code             string   `json:"code"`
+	Name             string   `json:"name"`
+	Script           string   `json:"script"`
+	Direction        string   `json:"direction"`
+	Enabled          bool     `json:"enabled"`
+	DefaultKnown     bool     `json:"default_known"`
+	DefaultLearning  bool     `json:"default_learning"`
+	Elision          bool     `json:"elision"`
+	Articles         []string `json:"articles"`
+	DefiniteArticles []string `json:"definite_articles"`
+	Hooks            []string `json:"hooks"`
+}
+
+type LintFinding struct {
+	Rule      string `json:"rule"`
+	FieldName string `json:"field_name"`
+	Message   string `json:"message"`
+}
+
+type LintResult struct {
+	Vocab    *Vocab         `json:"vocab"`
+	Findings []*LintFinding `json:"findings"`
+}
+
 type Mutation struct {
 }
 
+type NewConcept struct {
+	Gloss         string  `json:"gloss"`
+	GlossLangCode *string `json:"gloss_lang_code,omitempty"`
+	Notes         *string `json:"notes,omitempty"`
+}
+
+type NewExampleSentence struct {
+	VocabID     string  `json:"vocab_id"`
+	Sentence    string  `json:"sentence"`
+	Translation string  `json:"translation"`
+	Source      *string `json:"source,omitempty"`
+}
+
 type NewFixit struct {
 	VocabID   string `json:"vocab_id"`
 	Status    Status `json:"status"`
@@ -40,52 +176,392 @@ type NewFixit struct {
 	Comments  string `json:"comments"`
 }
 
+type NewLanguage struct {
+	Code             string   `json:"code"`
+	Name             string   `json:"name"`
+	Script           *string  `json:"script,omitempty"`
+	Direction        *string  `json:"direction,omitempty"`
+	Enabled          bool     `json:"enabled"`
+	DefaultKnown     *bool    `json:"default_known,omitempty"`
+	DefaultLearning  *bool    `json:"default_learning,omitempty"`
+	Elision          *bool    `json:"elision,omitempty"`
+	Articles         []string `json:"articles,omitempty"`
+	DefiniteArticles []string `json:"definite_articles,omitempty"`
+}
+
+type NewSkill struct {
+	Name        string   `json:"name"`
+	Description *string  `json:"description,omitempty"`
+	Aliases     []string `json:"aliases,omitempty"`
+	ParentID    *string  `json:"parent_id,omitempty"`
+	SortOrder   *int     `json:"sort_order,omitempty"`
+}
+
+type NewTerm struct {
+	Name        string   `json:"name"`
+	Description *string  `json:"description,omitempty"`
+	Aliases     []string `json:"aliases,omitempty"`
+}
+
 type NewVocab struct {
-	LearningLang     string `json:"learning_lang"`
-	FirstLang        string `json:"first_lang"`
-	Alternatives     string `json:"alternatives"`
-	Skill            string `json:"skill"`
-	Infinitive       string `json:"infinitive"`
-	Pos              string `json:"pos"`
-	Hint             string `json:"hint"`
-	NumLearningWords int    `json:"num_learning_words"`
-	KnownLangCode    string `json:"known_lang_code"`
-	LearningLangCode string `json:"learning_lang_code"`
+	LearningLang     string   `json:"learning_lang"`
+	FirstLang        string   `json:"first_lang"`
+	Alternatives     []string `json:"alternatives,omitempty"`
+	Skill            string   `json:"skill"`
+	Infinitive       string   `json:"infinitive"`
+	Pos              string   `json:"pos"`
+	Hint             string   `json:"hint"`
+	Gender           *string  `json:"gender,omitempty"`
+	Plural           *string  `json:"plural,omitempty"`
+	Article          *string  `json:"article,omitempty"`
+	Register         *string  `json:"register,omitempty"`
+	NumLearningWords *int     `json:"num_learning_words,omitempty"`
+	KnownLangCode    *string  `json:"known_lang_code,omitempty"`
+	LearningLangCode *string  `json:"learning_lang_code,omitempty"`
+	ConceptID        *string  `json:"concept_id,omitempty"`
+	Force            *bool    `json:"force,omitempty"`
+}
+
+type NewWebhook struct {
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret"`
+	EventTypes  []string `json:"event_types,omitempty"`
+	Enabled     bool     `json:"enabled"`
+	Description *string  `json:"description,omitempty"`
+}
+
+type NonConformingTerm struct {
+	Field      string `json:"field"`
+	Value      string `json:"value"`
+	Count      int    `json:"count"`
+	Suggestion string `json:"suggestion"`
+}
+
+type PartOfSpeech struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Aliases     []string `json:"aliases"`
 }
 
 type Query struct {
 }
 
+type RenameVocab struct {
+	ID                   string  `json:"id"`
+	LearningLang         string  `json:"learning_lang"`
+	KnownLangCode        *string `json:"known_lang_code,omitempty"`
+	LearningLangCode     *string `json:"learning_lang_code,omitempty"`
+	KeepOldAsAlternative *bool   `json:"keep_old_as_alternative,omitempty"`
+}
+
+type Skill struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Aliases     []string `json:"aliases"`
+	ParentID    *string  `json:"parent_id,omitempty"`
+	SortOrder   int      `json:"sort_order"`
+}
+
+type SkillNode struct {
+	Skill           *Skill       `json:"skill"`
+	VocabCount      int          `json:"vocab_count"`
+	TotalVocabCount int          `json:"total_vocab_count"`
+	Children        []*SkillNode `json:"children"`
+}
+
+type Snapshot struct {
+	ID               string `json:"id"`
+	LearningLangCode string `json:"learning_lang_code"`
+	Version          int    `json:"version"`
+	Checksum         string `json:"checksum"`
+	Size             int    `json:"size"`
+	VocabCount       int    `json:"vocab_count"`
+	ExampleCount     int    `json:"example_count"`
+	AuditID          string `json:"audit_id"`
+	Live             bool   `json:"live"`
+	CreatedBy        string `json:"created_by"`
+	Created          string `json:"created"`
+}
+
+type SnapshotChange struct {
+	AuditID   string   `json:"audit_id"`
+	TableName string   `json:"table_name"`
+	ObjectID  string   `json:"object_id"`
+	VocabID   string   `json:"vocab_id"`
+	Action    string   `json:"action"`
+	Fields    []string `json:"fields"`
+	Comments  string   `json:"comments"`
+	CreatedBy string   `json:"created_by"`
+	Created   string   `json:"created"`
+}
+
+type Subscription struct {
+}
+
+type UpdateConcept struct {
+	ID            string  `json:"id"`
+	Gloss         *string `json:"gloss,omitempty"`
+	GlossLangCode *string `json:"gloss_lang_code,omitempty"`
+	Notes         *string `json:"notes,omitempty"`
+}
+
+type UpdateExampleSentence struct {
+	ID          string  `json:"id"`
+	Sentence    *string `json:"sentence,omitempty"`
+	Translation *string `json:"translation,omitempty"`
+	Source      *string `json:"source,omitempty"`
+}
+
 type UpdateFixit struct {
-	ID        string `json:"id"`
-	Status    Status `json:"status"`
-	FieldName string `json:"field_name"`
-	Comments  string `json:"comments"`
+	ID        string  `json:"id"`
+	Status    *Status `json:"status,omitempty"`
+	FieldName *string `json:"field_name,omitempty"`
+	Comments  *string `json:"comments,omitempty"`
+}
+
+type UpdateLanguage struct {
+	ID               string   `json:"id"`
+	Name             *string  `json:"name,omitempty"`
+	Script           *string  `json:"script,omitempty"`
+	Direction        *string  `json:"direction,omitempty"`
+	Enabled          *bool    `json:"enabled,omitempty"`
+	DefaultKnown     *bool    `json:"default_known,omitempty"`
+	DefaultLearning  *bool    `json:"default_learning,omitempty"`
+	Elision          *bool    `json:"elision,omitempty"`
+	Articles         []string `json:"articles,omitempty"`
+	DefiniteArticles []string `json:"definite_articles,omitempty"`
+}
+
+type UpdateSkill struct {
+	ID          string   `json:"id"`
+	Name        *string  `json:"name,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Aliases     []string `json:"aliases,omitempty"`
+	ParentID    *string  `json:"parent_id,omitempty"`
+	SortOrder   *int     `json:"sort_order,omitempty"`
+}
+
+type UpdateTerm struct {
+	ID          string   `json:"id"`
+	Name        *string  `json:"name,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Aliases     []string `json:"aliases,omitempty"`
 }
 
 type UpdateVocab struct {
-	ID               string `json:"id"`
-	FirstLang        string `json:"first_lang"`
-	Alternatives     string `json:"alternatives"`
-	Skill            string `json:"skill"`
-	Infinitive       string `json:"infinitive"`
-	Pos              string `json:"pos"`
-	Hint             string `json:"hint"`
-	NumLearningWords int    `json:"num_learning_words"`
+	ID               string  `json:"id"`
+	FirstLang        *string `json:"first_lang,omitempty"`
+	Skill            *string `json:"skill,omitempty"`
+	Infinitive       *string `json:"infinitive,omitempty"`
+	Pos              *string `json:"pos,omitempty"`
+	Hint             *string `json:"hint,omitempty"`
+	Gender           *string `json:"gender,omitempty"`
+	Plural           *string `json:"plural,omitempty"`
+	Article          *string `json:"article,omitempty"`
+	Register         *string `json:"register,omitempty"`
+	NumLearningWords *int    `json:"num_learning_words,omitempty"`
+}
+
+type UpdateWebhook struct {
+	ID          string   `json:"id"`
+	URL         *string  `json:"url,omitempty"`
+	Secret      *string  `json:"secret,omitempty"`
+	EventTypes  []string `json:"event_types,omitempty"`
+	Enabled     *bool    `json:"enabled,omitempty"`
+	Description *string  `json:"description,omitempty"`
 }
 
 type Vocab struct {
-	ID               string `json:"id"`
-	LearningLang     string `json:"learning_lang"`
-	FirstLang        string `json:"first_lang"`
-	Alternatives     string `json:"alternatives"`
-	Skill            string `json:"skill"`
-	Infinitive       string `json:"infinitive"`
-	Pos              string `json:"pos"`
-	Hint             string `json:"hint"`
-	NumLearningWords int    `json:"num_learning_words"`
-	KnownLangCode    string `json:"known_lang_code"`
-	LearningLangCode string `json:"learning_lang_code"`
+	ID                 string         `json:"id"`
+	LearningLang       string         `json:"learning_lang"`
+	FirstLang          string         `json:"first_lang"`
+	Alternatives       []string       `json:"alternatives"`
+	AlternativeDetails []*Alternative `json:"alternative_details"`
+	Skill              string         `json:"skill"`
+	SkillID            *string        `json:"skill_id,omitempty"`
+	Infinitive         string         `json:"infinitive"`
+	Pos                string         `json:"pos"`
+	Hint               string         `json:"hint"`
+	Gender             string         `json:"gender"`
+	Plural             string         `json:"plural"`
+	Article            string         `json:"article"`
+	Register           string         `json:"register"`
+	AudioID            *string        `json:"audio_id,omitempty"`
+	Audio              *AudioAsset    `json:"audio,omitempty"`
+	ConceptID          *string        `json:"concept_id,omitempty"`
+	NumLearningWords   int            `json:"num_learning_words"`
+	KnownLangCode      string         `json:"known_lang_code"`
+	LearningLangCode   string         `json:"learning_lang_code"`
+}
+
+type VocabField struct {
+	Name      string   `json:"name"`
+	Column    string   `json:"column"`
+	Label     string   `json:"label"`
+	Kind      string   `json:"kind"`
+	MaxLength int      `json:"max_length"`
+	Required  bool     `json:"required"`
+	Updatable bool     `json:"updatable"`
+	Values    []string `json:"values"`
+	Policy    string   `json:"policy"`
+}
+
+type Webhook struct {
+	ID          string   `json:"id"`
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"event_types"`
+	Enabled     bool     `json:"enabled"`
+	Description string   `json:"description"`
+	CreatedBy   string   `json:"created_by"`
+	Created     string   `json:"created"`
+}
+
+type WebhookDelivery struct {
+	ID             string         `json:"id"`
+	WebhookID      string         `json:"webhook_id"`
+	EventType      string         `json:"event_type"`
+	AuditID        string         `json:"audit_id"`
+	Payload        string         `json:"payload"`
+	Status         DeliveryStatus `json:"status"`
+	Attempts       int            `json:"attempts"`
+	NextAttempt    string         `json:"next_attempt"`
+	ResponseStatus int            `json:"response_status"`
+	LastError      string         `json:"last_error"`
+	Created        string         `json:"created"`
+	Delivered      *string        `json:"delivered,omitempty"`
+}
+
+type AuditAction string
+
+const (
+	AuditActionCreated  AuditAction = "CREATED"
+	AuditActionUpdated  AuditAction = "UPDATED"
+	AuditActionDeleted  AuditAction = "DELETED"
+	AuditActionReverted AuditAction = "REVERTED"
+)
+
+var AllAuditAction = []AuditAction{
+	AuditActionCreated,
+	AuditActionUpdated,
+	AuditActionDeleted,
+	AuditActionReverted,
+}
+
+func (e AuditAction) IsValid() bool {
+	switch e {
+	case AuditActionCreated, AuditActionUpdated, AuditActionDeleted, AuditActionReverted:
+		return true
+	}
+	return false
+}
+
+func (e AuditAction) String() string {
+	return string(e)
+}
+
+func (e *AuditAction) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AuditAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AuditAction", str)
+	}
+	return nil
+}
+
+func (e AuditAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ChangeRequestStatus string
+
+const (
+	ChangeRequestStatusPending  ChangeRequestStatus = "PENDING"
+	ChangeRequestStatusApproved ChangeRequestStatus = "APPROVED"
+	ChangeRequestStatusRejected ChangeRequestStatus = "REJECTED"
+)
+
+var AllChangeRequestStatus = []ChangeRequestStatus{
+	ChangeRequestStatusPending,
+	ChangeRequestStatusApproved,
+	ChangeRequestStatusRejected,
+}
+
+func (e ChangeRequestStatus) IsValid() bool {
+	switch e {
+	case ChangeRequestStatusPending, ChangeRequestStatusApproved, ChangeRequestStatusRejected:
+		return true
+	}
+	return false
+}
+
+func (e ChangeRequestStatus) String() string {
+	return string(e)
+}
+
+func (e *ChangeRequestStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ChangeRequestStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ChangeRequestStatus", str)
+	}
+	return nil
+}
+
+func (e ChangeRequestStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "PENDING"
+	DeliveryStatusDelivered DeliveryStatus = "DELIVERED"
+	DeliveryStatusFailed    DeliveryStatus = "FAILED"
+)
+
+var AllDeliveryStatus = []DeliveryStatus{
+	DeliveryStatusPending,
+	DeliveryStatusDelivered,
+	DeliveryStatusFailed,
+}
+
+func (e DeliveryStatus) IsValid() bool {
+	switch e {
+	case DeliveryStatusPending, DeliveryStatusDelivered, DeliveryStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e DeliveryStatus) String() string {
+	return string(e)
+}
+
+func (e *DeliveryStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DeliveryStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DeliveryStatus", str)
+	}
+	return nil
+}
+
+func (e DeliveryStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
 type Status string
@@ -9,11 +9,37 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/heather92115/verdure-admin/graph/model"
 	"github.com/heather92115/verdure-admin/internal/convert"
 	"github.com/heather92115/verdure-admin/internal/srv"
 )
 
+// Translations is the resolver for the translations field.
+func (r *conceptResolver) Translations(ctx context.Context, obj *model.Concept, learningLangCode *string) ([]*model.Vocab, error) {
+	conceptID, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid concept id %s", obj.ID)
+	}
+
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		return nil, err
+	}
+
+	learningCode := ""
+	if learningLangCode != nil {
+		learningCode = *learningLangCode
+	}
+
+	vocabs, err := vocabService.FindConceptTranslations(conceptID, learningCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.VocabsToGql(vocabs)
+}
+
 // CreateVocab is the resolver for the createVocab field.
 func (r *mutationResolver) CreateVocab(ctx context.Context, input model.NewVocab) (*model.Vocab, error) {
 	incoming, err := convert.VocabFromNewGql(&input)
@@ -21,132 +47,1095 @@ func (r *mutationResolver) CreateVocab(ctx context.Context, input model.NewVocab
 		return nil, err
 	}
 
-	vocabService, err := srv.NewVocabService()
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		return nil, err
+	}
+
+	err = vocabService.CreateVocab(incoming, input.Force != nil && *input.Force, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, duplicateVocabError(ctx, err)
+	}
+
+	outgoing, err := convert.VocabToGql(incoming)
+	if err != nil {
+		return nil, err
+	}
+
+	return outgoing, nil
+}
+
+// UpdateVocab is the resolver for the updateVocab field.
+func (r *mutationResolver) UpdateVocab(ctx context.Context, input model.UpdateVocab) (*model.Vocab, error) {
+	incoming, err := convert.VocabPatchFromGql(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := vocabService.UpdateVocab(incoming, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	outgoing, err := convert.VocabToGql(updated)
+	if err != nil {
+		return nil, err
+	}
+
+	return outgoing, nil
+}
+
+// RenameVocab is the resolver for the renameVocab field.
+func (r *mutationResolver) RenameVocab(ctx context.Context, input model.RenameVocab) (*model.Vocab, error) {
+	incoming, err := convert.VocabRenameFromGql(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		return nil, err
+	}
+
+	renamed, err := vocabService.RenameVocab(incoming, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.VocabToGql(renamed)
+}
+
+// AddAlternative is the resolver for the addAlternative field.
+func (r *mutationResolver) AddAlternative(ctx context.Context, input model.AddAlternative) (*model.Vocab, error) {
+	vocabID, err := strconv.Atoi(input.VocabID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vocab id %s", input.VocabID)
+	}
+
+	notes := ""
+	if input.Notes != nil {
+		notes = *input.Notes
+	}
+
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := vocabService.AddAlternative(vocabID, input.Alternative, notes, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.VocabToGql(updated)
+}
+
+// RemoveAlternative is the resolver for the removeAlternative field.
+func (r *mutationResolver) RemoveAlternative(ctx context.Context, vocabID string, alternative string) (*model.Vocab, error) {
+	primaryID, err := strconv.Atoi(vocabID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vocab id %s", vocabID)
+	}
+
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := vocabService.RemoveAlternative(primaryID, alternative, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.VocabToGql(updated)
+}
+
+// MergeVocabs is the resolver for the mergeVocabs field.
+func (r *mutationResolver) MergeVocabs(ctx context.Context, keepID string, mergeIds []string) (*model.Vocab, error) {
+	keepPrimaryID, err := strconv.Atoi(keepID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vocab id %s", keepID)
+	}
+
+	mergePrimaryIDs := make([]int, len(mergeIds))
+	for i, id := range mergeIds {
+		if mergePrimaryIDs[i], err = strconv.Atoi(id); err != nil {
+			return nil, fmt.Errorf("invalid vocab id %s", id)
+		}
+	}
+
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := vocabService.MergeVocabs(keepPrimaryID, mergePrimaryIDs, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.VocabToGql(merged)
+}
+
+// CreatePartOfSpeech is the resolver for the createPartOfSpeech field.
+func (r *mutationResolver) CreatePartOfSpeech(ctx context.Context, input model.NewTerm) (*model.PartOfSpeech, error) {
+	incoming, err := convert.PartOfSpeechFromNewGql(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	lookupService, err := srv.NewLookupService()
+	if err != nil {
+		return nil, err
+	}
+
+	err = lookupService.CreatePartOfSpeech(incoming, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.PartOfSpeechToGql(incoming)
+}
+
+// UpdatePartOfSpeech is the resolver for the updatePartOfSpeech field.
+func (r *mutationResolver) UpdatePartOfSpeech(ctx context.Context, input model.UpdateTerm) (*model.PartOfSpeech, error) {
+	patch, err := convert.TermPatchFromGql(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	lookupService, err := srv.NewLookupService()
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := lookupService.UpdatePartOfSpeech(patch, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.PartOfSpeechToGql(updated)
+}
+
+// DeletePartOfSpeech is the resolver for the deletePartOfSpeech field.
+func (r *mutationResolver) DeletePartOfSpeech(ctx context.Context, id string) (string, error) {
+	primaryID, err := strconv.Atoi(id)
+	if err != nil {
+		return "", fmt.Errorf("invalid part of speech id %s", id)
+	}
+
+	lookupService, err := srv.NewLookupService()
+	if err != nil {
+		return "", err
+	}
+
+	err = lookupService.DeletePartOfSpeech(primaryID, srv.ActorFrom(ctx))
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// CreateSkill is the resolver for the createSkill field.
+func (r *mutationResolver) CreateSkill(ctx context.Context, input model.NewSkill) (*model.Skill, error) {
+	incoming, err := convert.SkillFromNewGql(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	lookupService, err := srv.NewLookupService()
+	if err != nil {
+		return nil, err
+	}
+
+	err = lookupService.CreateSkill(incoming, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.SkillToGql(incoming)
+}
+
+// UpdateSkill is the resolver for the updateSkill field.
+func (r *mutationResolver) UpdateSkill(ctx context.Context, input model.UpdateSkill) (*model.Skill, error) {
+	patch, err := convert.SkillPatchFromGql(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	lookupService, err := srv.NewLookupService()
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := lookupService.UpdateSkill(patch, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.SkillToGql(updated)
+}
+
+// DeleteSkill is the resolver for the deleteSkill field.
+func (r *mutationResolver) DeleteSkill(ctx context.Context, id string) (string, error) {
+	primaryID, err := strconv.Atoi(id)
+	if err != nil {
+		return "", fmt.Errorf("invalid skill id %s", id)
+	}
+
+	lookupService, err := srv.NewLookupService()
+	if err != nil {
+		return "", err
+	}
+
+	err = lookupService.DeleteSkill(primaryID, srv.ActorFrom(ctx))
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// MoveVocabsToSkill is the resolver for the moveVocabsToSkill field.
+func (r *mutationResolver) MoveVocabsToSkill(ctx context.Context, vocabIds []string, skillID string) ([]*model.Vocab, error) {
+	skillPrimaryID, err := strconv.Atoi(skillID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid skill id %s", skillID)
+	}
+
+	vocabPrimaryIDs := make([]int, len(vocabIds))
+	for i, id := range vocabIds {
+		if vocabPrimaryIDs[i], err = strconv.Atoi(id); err != nil {
+			return nil, fmt.Errorf("invalid vocab id %s", id)
+		}
+	}
+
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		return nil, err
+	}
+
+	moved, err := vocabService.MoveVocabsToSkill(vocabPrimaryIDs, skillPrimaryID, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.VocabsToGql(&moved)
+}
+
+// CreateConcept is the resolver for the createConcept field.
+func (r *mutationResolver) CreateConcept(ctx context.Context, input model.NewConcept) (*model.Concept, error) {
+	concept, err := convert.ConceptFromNewGql(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	conceptService, err := srv.NewConceptService()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = conceptService.CreateConcept(concept, srv.ActorFrom(ctx)); err != nil {
+		return nil, err
+	}
+
+	return convert.ConceptToGql(concept)
+}
+
+// UpdateConcept is the resolver for the updateConcept field.
+func (r *mutationResolver) UpdateConcept(ctx context.Context, input model.UpdateConcept) (*model.Concept, error) {
+	patch, err := convert.ConceptPatchFromGql(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	conceptService, err := srv.NewConceptService()
+	if err != nil {
+		return nil, err
+	}
+
+	concept, err := conceptService.UpdateConcept(patch, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.ConceptToGql(concept)
+}
+
+// MoveVocabsToConcept is the resolver for the moveVocabsToConcept field.
+func (r *mutationResolver) MoveVocabsToConcept(ctx context.Context, vocabIds []string, conceptID string) ([]*model.Vocab, error) {
+	conceptPrimaryID, err := strconv.Atoi(conceptID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid concept id %s", conceptID)
+	}
+
+	vocabPrimaryIDs := make([]int, len(vocabIds))
+	for i, id := range vocabIds {
+		if vocabPrimaryIDs[i], err = strconv.Atoi(id); err != nil {
+			return nil, fmt.Errorf("invalid vocab id %s", id)
+		}
+	}
+
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		return nil, err
+	}
+
+	moved, err := vocabService.MoveVocabsToConcept(vocabPrimaryIDs, conceptPrimaryID, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.VocabsToGql(&moved)
+}
+
+// CreateLanguage is the resolver for the createLanguage field.
+func (r *mutationResolver) CreateLanguage(ctx context.Context, input model.NewLanguage) (*model.Language, error) {
+	language, err := convert.LanguageFromNewGql(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	languageService, err := srv.NewLanguageService()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = languageService.CreateLanguage(language, srv.ActorFrom(ctx)); err != nil {
+		return nil, err
+	}
+
+	return convert.LanguageToGql(language)
+}
+
+// UpdateLanguage is the resolver for the updateLanguage field.
+func (r *mutationResolver) UpdateLanguage(ctx context.Context, input model.UpdateLanguage) (*model.Language, error) {
+	patch, err := convert.LanguagePatchFromGql(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	languageService, err := srv.NewLanguageService()
+	if err != nil {
+		return nil, err
+	}
+
+	language, err := languageService.UpdateLanguage(patch, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.LanguageToGql(language)
+}
+
+// GenerateConjugations is the resolver for the generateConjugations field.
+func (r *mutationResolver) GenerateConjugations(ctx context.Context, vocabID string, reset *bool) ([]*model.Conjugation, error) {
+	primaryID, err := strconv.Atoi(vocabID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vocab id %s", vocabID)
+	}
+
+	conjugationService, err := srv.NewConjugationService()
+	if err != nil {
+		return nil, err
+	}
+
+	conjugations, err := conjugationService.GenerateConjugations(primaryID, reset != nil && *reset, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.ConjugationsToGql(conjugations)
+}
+
+// CorrectConjugation is the resolver for the correctConjugation field.
+func (r *mutationResolver) CorrectConjugation(ctx context.Context, input model.CorrectConjugation) (*model.Conjugation, error) {
+	primaryID, err := strconv.Atoi(input.VocabID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vocab id %s", input.VocabID)
+	}
+
+	conjugationService, err := srv.NewConjugationService()
+	if err != nil {
+		return nil, err
+	}
+
+	corrected, err := conjugationService.CorrectConjugation(primaryID, input.Tense, input.Person, input.Form, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.ConjugationToGql(corrected)
+}
+
+// UploadAudio is the resolver for the uploadAudio field.
+func (r *mutationResolver) UploadAudio(ctx context.Context, file graphql.Upload) (*model.AudioAsset, error) {
+	audioService, err := srv.NewAudioService()
+	if err != nil {
+		return nil, err
+	}
+
+	asset, err := audioService.UploadAudio(file.File, file.Filename, file.ContentType, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.AudioAssetToGql(asset)
+}
+
+// AttachAudio is the resolver for the attachAudio field.
+func (r *mutationResolver) AttachAudio(ctx context.Context, vocabID string, audioID string) (*model.Vocab, error) {
+	primaryID, err := strconv.Atoi(vocabID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vocab id %s", vocabID)
+	}
+
+	audioPrimaryID, err := strconv.Atoi(audioID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audio id %s", audioID)
+	}
+
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		return nil, err
+	}
+
+	vocab, err := vocabService.AttachAudio(primaryID, audioPrimaryID, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.VocabToGql(vocab)
+}
+
+// DetachAudio is the resolver for the detachAudio field.
+func (r *mutationResolver) DetachAudio(ctx context.Context, vocabID string) (*model.Vocab, error) {
+	primaryID, err := strconv.Atoi(vocabID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vocab id %s", vocabID)
+	}
+
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		return nil, err
+	}
+
+	vocab, err := vocabService.DetachAudio(primaryID, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.VocabToGql(vocab)
+}
+
+// CreateExampleSentence is the resolver for the createExampleSentence field.
+func (r *mutationResolver) CreateExampleSentence(ctx context.Context, input model.NewExampleSentence) (*model.ExampleSentence, error) {
+	example, err := convert.ExampleSentenceFromNewGql(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	exampleService, err := srv.NewExampleService()
+	if err != nil {
+		return nil, err
+	}
+
+	err = exampleService.CreateExampleSentence(example, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.ExampleSentenceToGql(example)
+}
+
+// UpdateExampleSentence is the resolver for the updateExampleSentence field.
+func (r *mutationResolver) UpdateExampleSentence(ctx context.Context, input model.UpdateExampleSentence) (*model.ExampleSentence, error) {
+	patch, err := convert.ExampleSentencePatchFromGql(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	exampleService, err := srv.NewExampleService()
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := exampleService.UpdateExampleSentence(patch, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.ExampleSentenceToGql(updated)
+}
+
+// DeleteExampleSentence is the resolver for the deleteExampleSentence field.
+func (r *mutationResolver) DeleteExampleSentence(ctx context.Context, id string) (string, error) {
+	primaryID, err := strconv.Atoi(id)
+	if err != nil {
+		return "", fmt.Errorf("invalid example sentence id %s", id)
+	}
+
+	exampleService, err := srv.NewExampleService()
+	if err != nil {
+		return "", err
+	}
+
+	err = exampleService.DeleteExampleSentence(primaryID, srv.ActorFrom(ctx))
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// CreateFixit is the resolver for the createFixit field.
+func (r *mutationResolver) CreateFixit(ctx context.Context, input model.NewFixit) (*model.Fixit, error) {
+	incoming, err := convert.NewFixitFromGql(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	fixitService, err := srv.NewFixitService()
+	if err != nil {
+		return nil, err
+	}
+
+	err = fixitService.CreateFixit(incoming, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	outgoing, err := convert.FixitToGql(incoming)
+	if err != nil {
+		return nil, err
+	}
+
+	return outgoing, nil
+}
+
+// FileLintFixits is the resolver for the fileLintFixits field.
+func (r *mutationResolver) FileLintFixits(ctx context.Context, learningCode string) ([]*model.Fixit, error) {
+	lintService, err := srv.NewLintService()
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := lintService.LintVocabs(learningCode)
+	if err != nil {
+		return nil, err
+	}
+
+	fixits, err := lintService.FileFixits(results)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.FixitsToGql(&fixits)
+}
+
+// UpdateFixit is the resolver for the updateFixit field.
+func (r *mutationResolver) UpdateFixit(ctx context.Context, input model.UpdateFixit) (*model.Fixit, error) {
+	incoming, err := convert.FixitPatchFromGql(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	fixitService, err := srv.NewFixitService()
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := fixitService.UpdateFixit(incoming, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	outgoing, err := convert.FixitToGql(updated)
+	if err != nil {
+		return nil, err
+	}
+
+	return outgoing, nil
+}
+
+// CreateWebhook is the resolver for the createWebhook field.
+func (r *mutationResolver) CreateWebhook(ctx context.Context, input model.NewWebhook) (*model.Webhook, error) {
+	webhook, err := convert.WebhookFromNewGql(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookService, err := srv.NewWebhookService()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = webhookService.CreateWebhook(webhook, srv.ActorFrom(ctx)); err != nil {
+		return nil, err
+	}
+
+	return convert.WebhookToGql(webhook)
+}
+
+// UpdateWebhook is the resolver for the updateWebhook field.
+func (r *mutationResolver) UpdateWebhook(ctx context.Context, input model.UpdateWebhook) (*model.Webhook, error) {
+	patch, err := convert.WebhookPatchFromGql(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookService, err := srv.NewWebhookService()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := webhookService.UpdateWebhook(patch, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.WebhookToGql(webhook)
+}
+
+// RedeliverWebhook is the resolver for the redeliverWebhook field.
+func (r *mutationResolver) RedeliverWebhook(ctx context.Context, deliveryID string) (*model.WebhookDelivery, error) {
+	primaryID, err := strconv.Atoi(deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid delivery id %s", deliveryID)
+	}
+
+	webhookService, err := srv.NewWebhookService()
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := webhookService.RedeliverWebhook(primaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.WebhookDeliveryToGql(delivery)
+}
+
+// SubmitChangeRequest is the resolver for the submitChangeRequest field.
+func (r *mutationResolver) SubmitChangeRequest(ctx context.Context, input model.UpdateVocab, comments *string) (*model.ChangeRequest, error) {
+	patch, err := convert.VocabPatchFromGql(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		return nil, err
+	}
+
+	note := ""
+	if comments != nil {
+		note = *comments
+	}
+
+	request, err := vocabService.SubmitChangeRequest(patch, note, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.ChangeRequestToGql(request)
+}
+
+// ApproveChangeRequest is the resolver for the approveChangeRequest field.
+func (r *mutationResolver) ApproveChangeRequest(ctx context.Context, id string, comments *string) (*model.ChangeRequest, error) {
+	primaryID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id %s", id)
+	}
+
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		return nil, err
+	}
+
+	note := ""
+	if comments != nil {
+		note = *comments
+	}
+
+	request, err := vocabService.ApproveChangeRequest(primaryID, note, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.ChangeRequestToGql(request)
+}
+
+// RejectChangeRequest is the resolver for the rejectChangeRequest field.
+func (r *mutationResolver) RejectChangeRequest(ctx context.Context, id string, comments *string) (*model.ChangeRequest, error) {
+	primaryID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id %s", id)
+	}
+
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		return nil, err
+	}
+
+	note := ""
+	if comments != nil {
+		note = *comments
+	}
+
+	request, err := vocabService.RejectChangeRequest(primaryID, note, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.ChangeRequestToGql(request)
+}
+
+// PublishSnapshot is the resolver for the publishSnapshot field.
+func (r *mutationResolver) PublishSnapshot(ctx context.Context, learningCode string) (*model.Snapshot, error) {
+	snapshotService, err := srv.NewSnapshotService()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := snapshotService.PublishSnapshot(learningCode, srv.ActorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.SnapshotToGql(snapshot)
+}
+
+// RollbackSnapshot is the resolver for the rollbackSnapshot field.
+func (r *mutationResolver) RollbackSnapshot(ctx context.Context, learningCode string, version int) (*model.Snapshot, error) {
+	snapshotService, err := srv.NewSnapshotService()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := snapshotService.RollbackSnapshot(learningCode, version, srv.ActorFrom(ctx))
 	if err != nil {
 		return nil, err
 	}
 
-	err = vocabService.CreateVocab(incoming)
+	return convert.SnapshotToGql(snapshot)
+}
+
+// Vocab is the resolver for the vocab field.
+func (r *queryResolver) Vocab(ctx context.Context, id *string) (*model.Vocab, error) {
+	primaryID, err := strconv.Atoi(*id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id %s", *id)
+	}
+
+	vocabService, err := srv.NewVocabService()
 	if err != nil {
 		return nil, err
 	}
 
-	outgoing, err := convert.VocabToGql(incoming)
+	interim, err := vocabService.FindVocabByID(primaryID)
 	if err != nil {
 		return nil, err
 	}
 
-	return outgoing, nil
+	return convert.VocabToGql(interim)
 }
 
-// UpdateVocab is the resolver for the updateVocab field.
-func (r *mutationResolver) UpdateVocab(ctx context.Context, input model.UpdateVocab) (*model.Vocab, error) {
-	incoming, err := convert.VocabFromGql(&input)
+// Vocabs is the resolver for the vocabs field.
+func (r *queryResolver) Vocabs(ctx context.Context, learningCode string, hasFirst bool, limit int) ([]*model.Vocab, error) {
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := vocabService.FindVocabs(learningCode, hasFirst, limit)
 	if err != nil {
 		return nil, err
 	}
 
+	return convert.VocabsToGql(list)
+}
+
+// DuplicateCandidates is the resolver for the duplicateCandidates field.
+func (r *queryResolver) DuplicateCandidates(ctx context.Context, learningCode string) ([]*model.DuplicateCluster, error) {
 	vocabService, err := srv.NewVocabService()
 	if err != nil {
 		return nil, err
 	}
 
-	updated, err := vocabService.UpdateVocab(incoming)
+	clusters, err := vocabService.FindDuplicateClusters(learningCode)
 	if err != nil {
 		return nil, err
 	}
 
-	outgoing, err := convert.VocabToGql(updated)
+	return convert.DuplicateClustersToGql(clusters)
+}
+
+// LintVocab is the resolver for the lintVocab field.
+func (r *queryResolver) LintVocab(ctx context.Context, id string) (*model.LintResult, error) {
+	primaryID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vocab id %s", id)
+	}
+
+	lintService, err := srv.NewLintService()
 	if err != nil {
 		return nil, err
 	}
 
-	return outgoing, nil
+	result, err := lintService.LintVocab(primaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.LintResultToGql(result)
 }
 
-// CreateFixit is the resolver for the createFixit field.
-func (r *mutationResolver) CreateFixit(ctx context.Context, input model.NewFixit) (*model.Fixit, error) {
-	incoming, err := convert.NewFixitFromGql(&input)
+// LintVocabs is the resolver for the lintVocabs field.
+func (r *queryResolver) LintVocabs(ctx context.Context, learningCode string) ([]*model.LintResult, error) {
+	lintService, err := srv.NewLintService()
 	if err != nil {
 		return nil, err
 	}
 
-	fixitService, err := srv.NewFixitService()
+	results, err := lintService.LintVocabs(learningCode)
 	if err != nil {
 		return nil, err
 	}
 
-	err = fixitService.CreateFixit(incoming)
+	return convert.LintResultsToGql(results)
+}
+
+// PartsOfSpeech is the resolver for the partsOfSpeech field.
+func (r *queryResolver) PartsOfSpeech(ctx context.Context) ([]*model.PartOfSpeech, error) {
+	lookupService, err := srv.NewLookupService()
 	if err != nil {
 		return nil, err
 	}
 
-	outgoing, err := convert.FixitToGql(incoming)
+	list, err := lookupService.FindPartsOfSpeech()
 	if err != nil {
 		return nil, err
 	}
 
-	return outgoing, nil
+	return convert.PartsOfSpeechToGql(list)
 }
 
-// UpdateFixit is the resolver for the updateFixit field.
-func (r *mutationResolver) UpdateFixit(ctx context.Context, input model.UpdateFixit) (*model.Fixit, error) {
-	incoming, err := convert.UpdateFixitFromGql(&input)
+// Skills is the resolver for the skills field.
+func (r *queryResolver) Skills(ctx context.Context) ([]*model.Skill, error) {
+	lookupService, err := srv.NewLookupService()
 	if err != nil {
 		return nil, err
 	}
 
-	fixitService, err := srv.NewFixitService()
+	list, err := lookupService.FindSkills()
 	if err != nil {
 		return nil, err
 	}
 
-	updated, err := fixitService.UpdateFixit(incoming)
+	return convert.SkillsToGql(list)
+}
+
+// SkillTree is the resolver for the skillTree field.
+func (r *queryResolver) SkillTree(ctx context.Context) ([]*model.SkillNode, error) {
+	lookupService, err := srv.NewLookupService()
 	if err != nil {
 		return nil, err
 	}
 
-	outgoing, err := convert.FixitToGql(updated)
+	tree, err := lookupService.FindSkillTree()
 	if err != nil {
 		return nil, err
 	}
 
-	return outgoing, nil
+	return convert.SkillTreeToGql(tree)
 }
 
-// Vocab is the resolver for the vocab field.
-func (r *queryResolver) Vocab(ctx context.Context, id *string) (*model.Vocab, error) {
-	primaryID, err := strconv.Atoi(*id)
+// NonConformingTerms is the resolver for the nonConformingTerms field.
+func (r *queryResolver) NonConformingTerms(ctx context.Context) ([]*model.NonConformingTerm, error) {
+	lookupService, err := srv.NewLookupService()
 	if err != nil {
-		return nil, fmt.Errorf("invalid id %s", *id)
+		return nil, err
 	}
 
-	vocabService, err := srv.NewVocabService()
+	terms, err := lookupService.FindNonConformingTerms()
 	if err != nil {
 		return nil, err
 	}
 
-	interim, err := vocabService.FindVocabByID(primaryID)
+	return convert.NonConformingTermsToGql(terms), nil
+}
+
+// Conjugations is the resolver for the conjugations field.
+func (r *queryResolver) Conjugations(ctx context.Context, vocabID string) ([]*model.Conjugation, error) {
+	primaryID, err := strconv.Atoi(vocabID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vocab id %s", vocabID)
+	}
+
+	conjugationService, err := srv.NewConjugationService()
 	if err != nil {
 		return nil, err
 	}
 
-	return convert.VocabToGql(interim)
+	conjugations, err := conjugationService.FindConjugations(primaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.ConjugationsToGql(conjugations)
 }
 
-// Vocabs is the resolver for the vocabs field.
-func (r *queryResolver) Vocabs(ctx context.Context, learningCode string, hasFirst bool, limit int) ([]*model.Vocab, error) {
+// Conjugate is the resolver for the conjugate field.
+func (r *queryResolver) Conjugate(ctx context.Context, learningLangCode string, infinitive string) ([]*model.ConjugatedForm, error) {
+	conjugationService, err := srv.NewConjugationService()
+	if err != nil {
+		return nil, err
+	}
+
+	forms, err := conjugationService.PreviewConjugations(learningLangCode, infinitive)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.ConjugatedFormsToGql(forms), nil
+}
+
+// AudioAsset is the resolver for the audioAsset field.
+func (r *queryResolver) AudioAsset(ctx context.Context, id string) (*model.AudioAsset, error) {
+	primaryID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audio id %s", id)
+	}
+
+	audioService, err := srv.NewAudioService()
+	if err != nil {
+		return nil, err
+	}
+
+	asset, err := audioService.FindAudioAssetByID(primaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.AudioAssetToGql(asset)
+}
+
+// Concept is the resolver for the concept field.
+func (r *queryResolver) Concept(ctx context.Context, id string) (*model.Concept, error) {
+	primaryID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid concept id %s", id)
+	}
+
+	conceptService, err := srv.NewConceptService()
+	if err != nil {
+		return nil, err
+	}
+
+	concept, err := conceptService.FindConceptByID(primaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.ConceptToGql(concept)
+}
+
+// Translations is the resolver for the translations field.
+func (r *queryResolver) Translations(ctx context.Context, vocabID string) ([]*model.Vocab, error) {
+	primaryID, err := strconv.Atoi(vocabID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vocab id %s", vocabID)
+	}
+
 	vocabService, err := srv.NewVocabService()
 	if err != nil {
 		return nil, err
 	}
 
-	list, err := vocabService.FindVocabs(learningCode, hasFirst, limit)
+	vocabs, err := vocabService.FindVocabTranslations(primaryID)
 	if err != nil {
 		return nil, err
 	}
 
-	return convert.VocabsToGql(list)
+	return convert.VocabsToGql(vocabs)
+}
+
+// Languages is the resolver for the languages field.
+func (r *queryResolver) Languages(ctx context.Context, enabledOnly *bool) ([]*model.Language, error) {
+	languageService, err := srv.NewLanguageService()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := languageService.FindLanguages(enabledOnly != nil && *enabledOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.LanguagesToGql(list)
+}
+
+// ExampleSentence is the resolver for the exampleSentence field.
+func (r *queryResolver) ExampleSentence(ctx context.Context, id string) (*model.ExampleSentence, error) {
+	primaryID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid example sentence id %s", id)
+	}
+
+	exampleService, err := srv.NewExampleService()
+	if err != nil {
+		return nil, err
+	}
+
+	example, err := exampleService.FindExampleSentenceByID(primaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.ExampleSentenceToGql(example)
+}
+
+// ExampleSentences is the resolver for the exampleSentences field.
+func (r *queryResolver) ExampleSentences(ctx context.Context, vocabID string) ([]*model.ExampleSentence, error) {
+	primaryID, err := strconv.Atoi(vocabID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vocab id %s", vocabID)
+	}
+
+	exampleService, err := srv.NewExampleService()
+	if err != nil {
+		return nil, err
+	}
+
+	examples, err := exampleService.FindExampleSentences(primaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.ExampleSentencesToGql(examples)
+}
+
+// SuggestGrammar is the resolver for the suggestGrammar field.
+func (r *queryResolver) SuggestGrammar(ctx context.Context, learningLangCode string, word string) (*model.GrammarSuggestion, error) {
+	suggestion, err := srv.SuggestGrammar(learningLangCode, word)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.GrammarSuggestionToGql(suggestion), nil
+}
+
+// VocabFieldSchema is the resolver for the vocabFieldSchema field.
+func (r *queryResolver) VocabFieldSchema(ctx context.Context) ([]*model.VocabField, error) {
+	return convert.VocabFieldsToGql(srv.VocabFields), nil
 }
 
 // Fixit is the resolver for the fixit field.
@@ -210,18 +1199,18 @@ func (r *queryResolver) Audit(ctx context.Context, id *string) (*model.Audit, er
 }
 
 // Audits is the resolver for the audits field.
-func (r *queryResolver) Audits(ctx context.Context, tableName string, objectID string, startTime string, endTime string, limit int) ([]*model.Audit, error) {
+func (r *queryResolver) Audits(ctx context.Context, tableName *string, objectID *string, startTime *string, endTime *string, createdBy *string, action *model.AuditAction, changedField *string, limit int) ([]*model.Audit, error) {
 	auditService, err := srv.NewAuditService()
 	if err != nil {
 		return nil, err
 	}
 
-	aObjectID, duration, err := convert.AuditQueryMapper(objectID, startTime, endTime)
+	filter, err := convert.AuditQueryMapper(tableName, objectID, startTime, endTime, createdBy, action, changedField)
 	if err != nil {
 		return nil, err
 	}
 
-	list, err := auditService.FindAudits(tableName, aObjectID, duration, limit)
+	list, err := auditService.FindAuditsMatching(filter, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -229,11 +1218,185 @@ func (r *queryResolver) Audits(ctx context.Context, tableName string, objectID s
 	return convert.AuditsToGql(list)
 }
 
+// Webhooks is the resolver for the webhooks field.
+func (r *queryResolver) Webhooks(ctx context.Context) ([]*model.Webhook, error) {
+	webhookService, err := srv.NewWebhookService()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := webhookService.FindWebhooks()
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.WebhooksToGql(list)
+}
+
+// WebhookDeliveries is the resolver for the webhookDeliveries field.
+func (r *queryResolver) WebhookDeliveries(ctx context.Context, webhookID *string, status *model.DeliveryStatus, limit int) ([]*model.WebhookDelivery, error) {
+	primaryID := 0
+	if webhookID != nil {
+		id, err := strconv.Atoi(*webhookID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook id %s", *webhookID)
+		}
+		primaryID = id
+	}
+
+	deliveryStatus, err := convert.DeliveryStatusFilterFromGql(status)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookService, err := srv.NewWebhookService()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := webhookService.FindDeliveries(primaryID, deliveryStatus, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.WebhookDeliveriesToGql(list)
+}
+
+// ChangeRequest is the resolver for the changeRequest field.
+func (r *queryResolver) ChangeRequest(ctx context.Context, id string) (*model.ChangeRequest, error) {
+	primaryID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id %s", id)
+	}
+
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := vocabService.FindChangeRequestByID(primaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.ChangeRequestToGql(request)
+}
+
+// ChangeRequests is the resolver for the changeRequests field.
+func (r *queryResolver) ChangeRequests(ctx context.Context, vocabID *string, status *model.ChangeRequestStatus, limit int) ([]*model.ChangeRequest, error) {
+	primaryID := 0
+	if vocabID != nil {
+		id, err := strconv.Atoi(*vocabID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vocab id %s", *vocabID)
+		}
+		primaryID = id
+	}
+
+	changeStatus, err := convert.ChangeStatusFilterFromGql(status)
+	if err != nil {
+		return nil, err
+	}
+
+	vocabService, err := srv.NewVocabService()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := vocabService.FindChangeRequests(primaryID, changeStatus, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.ChangeRequestsToGql(list)
+}
+
+// Snapshots is the resolver for the snapshots field.
+func (r *queryResolver) Snapshots(ctx context.Context, learningCode string) ([]*model.Snapshot, error) {
+	snapshotService, err := srv.NewSnapshotService()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := snapshotService.FindSnapshots(learningCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.SnapshotsToGql(list)
+}
+
+// SnapshotChangelog is the resolver for the snapshotChangelog field.
+func (r *queryResolver) SnapshotChangelog(ctx context.Context, learningCode string, fromVersion int, toVersion *int) ([]*model.SnapshotChange, error) {
+	snapshotService, err := srv.NewSnapshotService()
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := snapshotService.SnapshotChangelog(learningCode, fromVersion, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.SnapshotChangesToGql(changes)
+}
+
+// FixitChanged is the resolver for the fixitChanged field.
+func (r *subscriptionResolver) FixitChanged(ctx context.Context, status *model.Status) (<-chan *model.Fixit, error) {
+	fStatus, err := convert.FixitStatusFilterFromGql(status)
+	if err != nil {
+		return nil, err
+	}
+
+	return forward(ctx, srv.SubscribeFixitChanges(ctx, fStatus), convert.FixitToGql), nil
+}
+
+// VocabChanged is the resolver for the vocabChanged field.
+func (r *subscriptionResolver) VocabChanged(ctx context.Context, learningCode *string) (<-chan *model.Vocab, error) {
+	code := ""
+	if learningCode != nil {
+		code = *learningCode
+	}
+
+	return forward(ctx, srv.SubscribeVocabChanges(ctx, code), convert.VocabToGql), nil
+}
+
+// AuditCreated is the resolver for the auditCreated field.
+func (r *subscriptionResolver) AuditCreated(ctx context.Context, tableName *string) (<-chan *model.Audit, error) {
+	table := ""
+	if tableName != nil {
+		table = *tableName
+	}
+
+	return forward(ctx, srv.SubscribeAuditsCreated(ctx, table), convert.AuditToGql), nil
+}
+
+// Audio is the resolver for the audio field.
+func (r *vocabResolver) Audio(ctx context.Context, obj *model.Vocab) (*model.AudioAsset, error) {
+	if obj.AudioID == nil {
+		return nil, nil
+	}
+
+	return r.Query().AudioAsset(ctx, *obj.AudioID)
+}
+
+// Concept returns ConceptResolver implementation.
+func (r *Resolver) Concept() ConceptResolver { return &conceptResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
+// Vocab returns VocabResolver implementation.
+func (r *Resolver) Vocab() VocabResolver { return &vocabResolver{r} }
+
+type conceptResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
+type vocabResolver struct{ *Resolver }
@@ -0,0 +1,31 @@
+package graph
+
+import (
+	"context"
+	"log"
+)
+
+// forward converts the records of a service subscription to their GraphQL models until the
+// subscription ends, when the returned channel is closed. A record that cannot be converted
+// is logged and skipped rather than ending the subscription.
+func forward[From any, To any](ctx context.Context, records <-chan *From, toGql func(from *From) (*To, error)) <-chan *To {
+	models := make(chan *To, 1)
+
+	go func() {
+		defer close(models)
+		for record := range records {
+			model, err := toGql(record)
+			if err != nil {
+				log.Printf("Skipping a subscription record: %v", err)
+				continue
+			}
+			select {
+			case models <- model:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return models
+}
@@ -0,0 +1,97 @@
+// Package conj generates verb conjugation tables from an infinitive. Each learning language
+// with generated conjugations has a Generator, looked up by its language code with
+// ForLanguage. The generated tables are a starting point, they are stored per vocab and can
+// be corrected by hand where a generator gets a form wrong.
+package conj
+
+import (
+	"fmt"
+)
+
+// The tenses generated, indicative and subjunctive moods combined.
+const (
+	Present              = "present"
+	Preterite            = "preterite"
+	Imperfect            = "imperfect"
+	Future               = "future"
+	Conditional          = "conditional"
+	PresentSubjunctive   = "present_subjunctive"
+	ImperfectSubjunctive = "imperfect_subjunctive"
+)
+
+// Tenses lists every tense in table order.
+var Tenses = []string{Present, Preterite, Imperfect, Future, Conditional, PresentSubjunctive, ImperfectSubjunctive}
+
+// Persons lists every grammatical person in table order, first, second and third person
+// singular followed by the plurals.
+var Persons = []string{"1sg", "2sg", "3sg", "1pl", "2pl", "3pl"}
+
+// Form is a single conjugated form of a verb.
+//
+// Fields:
+//   - Tense: One of Tenses.
+//   - Person: One of Persons.
+//   - Form: The conjugated text, e.g. "hablo".
+//   - Irregular: Set when the form differs from the one the regular pattern of the verb gives.
+type Form struct {
+	Tense     string
+	Person    string
+	Form      string
+	Irregular bool
+}
+
+// Generator conjugates the verbs of one learning language.
+type Generator interface {
+	// Conjugate returns the forms of the infinitive for every tense and person, in table order.
+	Conjugate(infinitive string) ([]Form, error)
+}
+
+// generators are the conjugation generators by learning language code.
+var generators = map[string]Generator{
+	"es": Spanish{},
+}
+
+// ForLanguage returns the conjugation generator for a learning language code.
+//
+// Parameters:
+// - langCode: The learning language code, e.g. "es".
+//
+// Returns:
+// - The generator for the language.
+// - An error if the language has no generator.
+func ForLanguage(langCode string) (Generator, error) {
+	generator, found := generators[langCode]
+	if !found {
+		return nil, fmt.Errorf("conjugations are not generated for language %s", langCode)
+	}
+	return generator, nil
+}
+
+// IsTense reports whether the value is one of Tenses.
+func IsTense(value string) bool {
+	return indexOf(Tenses, value) >= 0
+}
+
+// IsPerson reports whether the value is one of Persons.
+func IsPerson(value string) bool {
+	return indexOf(Persons, value) >= 0
+}
+
+// Position returns the place of a tense and person in table order, for sorting stored forms.
+// Unknown values sort last.
+func Position(tense string, person string) int {
+	t, p := indexOf(Tenses, tense), indexOf(Persons, person)
+	if t < 0 || p < 0 {
+		return len(Tenses) * len(Persons)
+	}
+	return t*len(Persons) + p
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,492 @@
+package conj
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Spanish conjugates Spanish verbs. Regular -ar, -er and -ir verbs follow their pattern,
+// including the spelling changes that keep the stem sound, such as buscar to busqué and
+// coger to cojo. Stem changing and irregular verbs are listed with the parts of their
+// conjugation that differ, see spanishIrregulars. A verb ending in se is conjugated with
+// its reflexive pronouns.
+type Spanish struct{}
+
+// spanishReflexivePronouns are placed before each form of a reflexive verb, by person.
+var spanishReflexivePronouns = [6]string{"me", "te", "se", "nos", "os", "se"}
+
+// spanishEndings are the endings added to the stem, by tense and verb class. The future and
+// conditional endings are added to the infinitive, and the imperfect subjunctive endings to
+// the third person plural preterite without its final ron.
+var spanishEndings = map[string]map[string][6]string{
+	Present: {
+		"ar": {"o", "as", "a", "amos", "áis", "an"},
+		"er": {"o", "es", "e", "emos", "éis", "en"},
+		"ir": {"o", "es", "e", "imos", "ís", "en"},
+	},
+	Preterite: {
+		"ar": {"é", "aste", "ó", "amos", "asteis", "aron"},
+		"er": {"í", "iste", "ió", "imos", "isteis", "ieron"},
+		"ir": {"í", "iste", "ió", "imos", "isteis", "ieron"},
+	},
+	Imperfect: {
+		"ar": {"aba", "abas", "aba", "ábamos", "abais", "aban"},
+		"er": {"ía", "ías", "ía", "íamos", "íais", "ían"},
+		"ir": {"ía", "ías", "ía", "íamos", "íais", "ían"},
+	},
+	PresentSubjunctive: {
+		"ar": {"e", "es", "e", "emos", "éis", "en"},
+		"er": {"a", "as", "a", "amos", "áis", "an"},
+		"ir": {"a", "as", "a", "amos", "áis", "an"},
+	},
+}
+
+var (
+	spanishFutureEndings               = [6]string{"é", "ás", "á", "emos", "éis", "án"}
+	spanishConditionalEndings          = [6]string{"ía", "ías", "ía", "íamos", "íais", "ían"}
+	spanishImperfectSubjunctiveEndings = [6]string{"ra", "ras", "ra", "ramos", "rais", "ran"}
+	spanishStrongPreteriteEndings      = [6]string{"e", "iste", "o", "imos", "isteis", "ieron"}
+)
+
+// spanishIrregular lists how a verb departs from its regular pattern. Empty fields follow
+// the pattern.
+//
+// Fields:
+//   - stemChange: The stem vowel change in the stressed persons, "ie" for e to ie, "ue" for o
+//     or u to ue, or "i" for e to i. Set from spanishStemChanges.
+//   - yo: The first person singular present, the present subjunctive is built from it.
+//   - preteriteStem: The stem of a strong preterite, e.g. "tuv" for tuve, tuvo.
+//   - futureStem: The stem of the future and conditional in place of the infinitive.
+//   - forms: Whole tenses that follow no pattern, they replace the generated forms.
+type spanishIrregular struct {
+	stemChange    string
+	yo            string
+	preteriteStem string
+	futureStem    string
+	forms         map[string][6]string
+}
+
+// spanishIrregulars are the irregular verbs by infinitive. Compounds such as mantener are
+// not derived from their base verb and must be listed separately.
+var spanishIrregulars = map[string]*spanishIrregular{
+	"ser": {forms: map[string][6]string{
+		Present:            {"soy", "eres", "es", "somos", "sois", "son"},
+		Preterite:          {"fui", "fuiste", "fue", "fuimos", "fuisteis", "fueron"},
+		Imperfect:          {"era", "eras", "era", "éramos", "erais", "eran"},
+		PresentSubjunctive: {"sea", "seas", "sea", "seamos", "seáis", "sean"},
+	}},
+	"ir": {forms: map[string][6]string{
+		Present:            {"voy", "vas", "va", "vamos", "vais", "van"},
+		Preterite:          {"fui", "fuiste", "fue", "fuimos", "fuisteis", "fueron"},
+		Imperfect:          {"iba", "ibas", "iba", "íbamos", "ibais", "iban"},
+		PresentSubjunctive: {"vaya", "vayas", "vaya", "vayamos", "vayáis", "vayan"},
+	}},
+	"estar": {preteriteStem: "estuv", forms: map[string][6]string{
+		Present:            {"estoy", "estás", "está", "estamos", "estáis", "están"},
+		PresentSubjunctive: {"esté", "estés", "esté", "estemos", "estéis", "estén"},
+	}},
+	"dar": {forms: map[string][6]string{
+		Present:            {"doy", "das", "da", "damos", "dais", "dan"},
+		Preterite:          {"di", "diste", "dio", "dimos", "disteis", "dieron"},
+		PresentSubjunctive: {"dé", "des", "dé", "demos", "deis", "den"},
+	}},
+	"ver": {forms: map[string][6]string{
+		Present:   {"veo", "ves", "ve", "vemos", "veis", "ven"},
+		Preterite: {"vi", "viste", "vio", "vimos", "visteis", "vieron"},
+		Imperfect: {"veía", "veías", "veía", "veíamos", "veíais", "veían"},
+	}},
+	"haber": {preteriteStem: "hub", futureStem: "habr", forms: map[string][6]string{
+		Present:            {"he", "has", "ha", "hemos", "habéis", "han"},
+		PresentSubjunctive: {"haya", "hayas", "haya", "hayamos", "hayáis", "hayan"},
+	}},
+	"saber": {yo: "sé", preteriteStem: "sup", futureStem: "sabr", forms: map[string][6]string{
+		PresentSubjunctive: {"sepa", "sepas", "sepa", "sepamos", "sepáis", "sepan"},
+	}},
+	"oír": {futureStem: "oir", forms: map[string][6]string{
+		Present: {"oigo", "oyes", "oye", "oímos", "oís", "oyen"},
+	}},
+	"tener":      {yo: "tengo", preteriteStem: "tuv", futureStem: "tendr"},
+	"venir":      {yo: "vengo", preteriteStem: "vin", futureStem: "vendr"},
+	"poner":      {yo: "pongo", preteriteStem: "pus", futureStem: "pondr"},
+	"salir":      {yo: "salgo", futureStem: "saldr"},
+	"hacer":      {yo: "hago", preteriteStem: "hic", futureStem: "har"},
+	"decir":      {yo: "digo", preteriteStem: "dij", futureStem: "dir"},
+	"querer":     {preteriteStem: "quis", futureStem: "querr"},
+	"poder":      {preteriteStem: "pud", futureStem: "podr"},
+	"traer":      {yo: "traigo", preteriteStem: "traj"},
+	"caer":       {yo: "caigo"},
+	"andar":      {preteriteStem: "anduv"},
+	"caber":      {yo: "quepo", preteriteStem: "cup", futureStem: "cabr"},
+	"valer":      {yo: "valgo", futureStem: "valdr"},
+	"conducir":   {preteriteStem: "conduj"},
+	"producir":   {preteriteStem: "produj"},
+	"traducir":   {preteriteStem: "traduj"},
+	"introducir": {preteriteStem: "introduj"},
+	"reducir":    {preteriteStem: "reduj"},
+}
+
+// spanishStemChanges are the stem changing verbs, by their change, see spanishIrregular.
+var spanishStemChanges = map[string][]string{
+	"ie": {"cerrar", "comenzar", "convertir", "defender", "despertar", "divertir", "empezar", "encender",
+		"entender", "mentir", "pensar", "perder", "preferir", "querer", "sentar", "sentir", "tener", "venir"},
+	"ue": {"almorzar", "contar", "costar", "dormir", "encontrar", "jugar", "llover", "morir", "mostrar",
+		"mover", "poder", "probar", "recordar", "soñar", "volar", "volver"},
+	"i": {"competir", "conseguir", "decir", "elegir", "medir", "pedir", "repetir", "seguir", "servir", "vestir"},
+}
+
+func init() {
+	for change, verbs := range spanishStemChanges {
+		for _, infinitive := range verbs {
+			irregular := spanishIrregulars[infinitive]
+			if irregular == nil {
+				irregular = &spanishIrregular{}
+				spanishIrregulars[infinitive] = irregular
+			}
+			irregular.stemChange = change
+		}
+	}
+}
+
+// spanishVerb is an infinitive broken into the parts the patterns are built from.
+type spanishVerb struct {
+	infinitive string
+	stem       string
+	class      string
+	reflexive  bool
+}
+
+// Conjugate returns the forms of a Spanish infinitive for every tense and person, in table
+// order, see Tenses and Persons.
+//
+// Parameters:
+// - infinitive: The infinitive, e.g. "hablar" or "lavarse".
+//
+// Returns:
+// - The conjugated forms, flagged irregular where they differ from the regular pattern.
+// - An error if the text is not a single word ending in -ar, -er or -ir.
+//
+// Usage example:
+// forms, err := conj.Spanish{}.Conjugate("tener")
+//
+//	if err != nil {
+//	    log.Printf("Failed to conjugate: %v", err)
+//	}
+func (Spanish) Conjugate(infinitive string) ([]Form, error) {
+	verb, err := parseSpanishVerb(infinitive)
+	if err != nil {
+		return nil, err
+	}
+
+	irregular := spanishIrregulars[verb.infinitive]
+	table := verb.conjugate(irregular)
+	regular := verb.conjugate(nil)
+
+	forms := make([]Form, 0, len(Tenses)*len(Persons))
+	for _, tense := range Tenses {
+		for p, person := range Persons {
+			text := table[tense][p]
+			if verb.reflexive {
+				text = spanishReflexivePronouns[p] + " " + text
+			}
+			forms = append(forms, Form{
+				Tense:     tense,
+				Person:    person,
+				Form:      text,
+				Irregular: table[tense][p] != regular[tense][p],
+			})
+		}
+	}
+
+	return forms, nil
+}
+
+// parseSpanishVerb splits an infinitive into its stem and class, removing a reflexive se.
+func parseSpanishVerb(infinitive string) (*spanishVerb, error) {
+	text := strings.ToLower(strings.TrimSpace(infinitive))
+	if len(strings.Fields(text)) != 1 {
+		return nil, fmt.Errorf("infinitive %q must be a single word", infinitive)
+	}
+
+	verb := &spanishVerb{infinitive: text}
+	if base, found := strings.CutSuffix(text, "se"); found && spanishClass(base) != "" {
+		verb.infinitive = base
+		verb.reflexive = true
+	}
+
+	verb.class = spanishClass(verb.infinitive)
+	if verb.class == "" {
+		return nil, fmt.Errorf("%s is not a Spanish infinitive ending in -ar, -er or -ir", infinitive)
+	}
+	verb.stem = strings.TrimSuffix(strings.TrimSuffix(verb.infinitive, "ír"), verb.class)
+
+	return verb, nil
+}
+
+// spanishClass returns the verb class of an infinitive, "ar", "er" or "ir", or empty when it is
+// not one. Infinitives ending in an accented ír, such as oír, conjugate as -ir verbs.
+func spanishClass(infinitive string) string {
+	if len([]rune(infinitive)) < 2 {
+		return ""
+	}
+	for _, class := range []string{"ar", "er", "ir"} {
+		if strings.HasSuffix(infinitive, class) {
+			return class
+		}
+	}
+	if strings.HasSuffix(infinitive, "ír") {
+		return "ir"
+	}
+	return ""
+}
+
+// conjugate builds every tense of the verb. Without irregular details it gives the forms of
+// the regular pattern, which the irregular forms are compared against.
+func (v *spanishVerb) conjugate(irregular *spanishIrregular) map[string][6]string {
+	if irregular == nil {
+		irregular = &spanishIrregular{}
+	}
+
+	override := func(tense string, forms [6]string) [6]string {
+		if replaced, found := irregular.forms[tense]; found {
+			return replaced
+		}
+		return forms
+	}
+
+	table := make(map[string][6]string)
+	table[Present] = override(Present, v.present(irregular))
+	table[Preterite] = override(Preterite, v.preterite(irregular))
+	table[Imperfect] = override(Imperfect, v.imperfect())
+
+	futureStem := v.infinitive
+	if len(irregular.futureStem) > 0 {
+		futureStem = irregular.futureStem
+	}
+	table[Future] = override(Future, withEndings(futureStem, spanishFutureEndings))
+	table[Conditional] = override(Conditional, withEndings(futureStem, spanishConditionalEndings))
+
+	// The subjunctives are derived from the finished present and preterite.
+	table[PresentSubjunctive] = override(PresentSubjunctive, v.presentSubjunctive(irregular, table[Present]))
+	table[ImperfectSubjunctive] = override(ImperfectSubjunctive, imperfectSubjunctive(table[Preterite][5]))
+
+	return table
+}
+
+func (v *spanishVerb) present(irregular *spanishIrregular) (forms [6]string) {
+	for p, ending := range spanishEndings[Present][v.class] {
+		stem := v.stem
+		if isStressedPerson(p) && len(irregular.stemChange) > 0 {
+			stem = changeStem(stem, irregular.stemChange, false)
+		}
+		if v.insertsY() && !startsWithI(ending) {
+			stem += "y"
+		}
+		if p == 0 && v.class != "ar" {
+			stem = softenBeforeBackVowel(stem)
+		}
+		forms[p] = stem + ending
+	}
+
+	if len(irregular.yo) > 0 {
+		forms[0] = irregular.yo
+	}
+
+	return
+}
+
+func (v *spanishVerb) preterite(irregular *spanishIrregular) (forms [6]string) {
+	if stem := irregular.preteriteStem; len(stem) > 0 {
+		for p, ending := range spanishStrongPreteriteEndings {
+			switch {
+			case p == 5 && strings.HasSuffix(stem, "j"):
+				ending = "eron"
+			case p == 2 && strings.HasSuffix(stem, "c"):
+				// A c before o is written z, hacer gives hizo.
+				forms[p] = strings.TrimSuffix(stem, "c") + "zo"
+				continue
+			}
+			forms[p] = stem + ending
+		}
+		return
+	}
+
+	for p, ending := range spanishEndings[Preterite][v.class] {
+		stem := v.stem
+		switch {
+		case v.class == "ar" && p == 0:
+			stem = softenBeforeFrontVowel(stem)
+		case v.class == "ir" && (p == 2 || p == 5) && len(irregular.stemChange) > 0:
+			stem = changeStem(stem, irregular.stemChange, true)
+		}
+
+		// An unstressed i between vowels is written y, leer gives leyó, and a stressed one
+		// carries an accent, leíste, except after the u of -uir verbs.
+		if v.class != "ar" && endsWithVowel(stem) && !strings.HasSuffix(stem, "gu") && !strings.HasSuffix(stem, "qu") {
+			switch {
+			case p == 2:
+				ending = "yó"
+			case p == 5:
+				ending = "yeron"
+			case !v.insertsY() && strings.HasPrefix(ending, "i"):
+				ending = "í" + strings.TrimPrefix(ending, "i")
+			}
+		}
+
+		forms[p] = stem + ending
+	}
+
+	return
+}
+
+func (v *spanishVerb) imperfect() [6]string {
+	return withEndings(v.stem, spanishEndings[Imperfect][v.class])
+}
+
+// presentSubjunctive builds the present subjunctive on the stem of the first person present,
+// so tengo gives tenga. Stem changing verbs keep the unchanged stem in the first and second
+// person plural, where -ir verbs take the weak change instead, dormir gives durmamos.
+func (v *spanishVerb) presentSubjunctive(irregular *spanishIrregular, present [6]string) (forms [6]string) {
+	yoStem := strings.TrimSuffix(present[0], "o")
+
+	for p, ending := range spanishEndings[PresentSubjunctive][v.class] {
+		stem := yoStem
+		if !isStressedPerson(p) && len(irregular.stemChange) > 0 && len(irregular.yo) == 0 {
+			stem = v.stem
+			if v.class == "ir" {
+				stem = changeStem(stem, irregular.stemChange, true)
+			}
+			if v.class != "ar" {
+				stem = softenBeforeBackVowel(stem)
+			}
+		}
+		if v.class == "ar" {
+			stem = softenBeforeFrontVowel(stem)
+		}
+		forms[p] = stem + ending
+	}
+
+	return
+}
+
+// imperfectSubjunctive builds the -ra imperfect subjunctive on the third person plural
+// preterite, hablaron gives hablara, accenting the first person plural, habláramos.
+func imperfectSubjunctive(preterite3pl string) (forms [6]string) {
+	stem := strings.TrimSuffix(preterite3pl, "ron")
+
+	for p, ending := range spanishImperfectSubjunctiveEndings {
+		if p == 3 {
+			forms[p] = accentLastVowel(stem) + ending
+			continue
+		}
+		forms[p] = stem + ending
+	}
+
+	return
+}
+
+// insertsY reports whether the verb is a -uir verb, such as construir, which adds a y before
+// endings that do not start with i.
+func (v *spanishVerb) insertsY() bool {
+	return v.class == "ir" && strings.HasSuffix(v.stem, "u") &&
+		!strings.HasSuffix(v.stem, "gu") && !strings.HasSuffix(v.stem, "qu")
+}
+
+// isStressedPerson reports whether the stem is stressed in the present, every person except
+// the first and second plural.
+func isStressedPerson(p int) bool {
+	return p != 3 && p != 4
+}
+
+// changeStem applies a stem vowel change to the last matching vowel of the stem. The weak
+// change is the one -ir verbs take where the strong change does not apply, e to i and o to u.
+func changeStem(stem string, change string, weak bool) string {
+	from, to := "e", change
+	switch change {
+	case "ue":
+		from = "o"
+		if !strings.Contains(stem, "o") {
+			from = "u"
+		}
+		if weak {
+			to = "u"
+		}
+	case "ie":
+		if weak {
+			to = "i"
+		}
+	}
+
+	i := strings.LastIndex(stem, from)
+	if i < 0 {
+		return stem
+	}
+	return stem[:i] + to + stem[i+len(from):]
+}
+
+// softenBeforeFrontVowel keeps the sound of an -ar stem before an ending starting with e,
+// buscar gives busqué, pagar pagué, empezar empecé and averiguar averigüé.
+func softenBeforeFrontVowel(stem string) string {
+	switch {
+	case strings.HasSuffix(stem, "gu"):
+		return strings.TrimSuffix(stem, "gu") + "gü"
+	case strings.HasSuffix(stem, "c"):
+		return strings.TrimSuffix(stem, "c") + "qu"
+	case strings.HasSuffix(stem, "g"):
+		return stem + "u"
+	case strings.HasSuffix(stem, "z"):
+		return strings.TrimSuffix(stem, "z") + "c"
+	}
+	return stem
+}
+
+// softenBeforeBackVowel keeps the sound of an -er or -ir stem before an ending starting with
+// o or a, coger gives cojo, seguir sigo, vencer venzo and conocer conozco.
+func softenBeforeBackVowel(stem string) string {
+	switch {
+	case strings.HasSuffix(stem, "gu"):
+		return strings.TrimSuffix(stem, "u")
+	case strings.HasSuffix(stem, "g"):
+		return strings.TrimSuffix(stem, "g") + "j"
+	case strings.HasSuffix(stem, "c"):
+		base := strings.TrimSuffix(stem, "c")
+		if endsWithVowel(base) {
+			return base + "zc"
+		}
+		return base + "z"
+	}
+	return stem
+}
+
+func withEndings(stem string, endings [6]string) (forms [6]string) {
+	for p, ending := range endings {
+		forms[p] = stem + ending
+	}
+	return
+}
+
+// accentLastVowel writes the last vowel of the text with an acute accent.
+func accentLastVowel(text string) string {
+	runes := []rune(text)
+	for i := len(runes) - 1; i >= 0; i-- {
+		if accented, found := spanishAccents[runes[i]]; found {
+			runes[i] = accented
+			break
+		}
+	}
+	return string(runes)
+}
+
+var spanishAccents = map[rune]rune{'a': 'á', 'e': 'é', 'i': 'í', 'o': 'ó', 'u': 'ú'}
+
+func endsWithVowel(text string) bool {
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return false
+	}
+	_, found := spanishAccents[runes[len(runes)-1]]
+	return found
+}
+
+func startsWithI(ending string) bool {
+	return strings.HasPrefix(ending, "i") || strings.HasPrefix(ending, "í")
+}
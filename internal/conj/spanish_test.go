@@ -0,0 +1,94 @@
+package conj
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSpanish_Conjugate(t *testing.T) {
+	tests := []struct {
+		name          string
+		infinitive    string
+		tense         string
+		want          []string
+		wantIrregular []bool
+	}{
+		{name: "Regular ar present", infinitive: "hablar", tense: Present,
+			want: []string{"hablo", "hablas", "habla", "hablamos", "habláis", "hablan"}},
+		{name: "Regular er preterite", infinitive: "comer", tense: Preterite,
+			want: []string{"comí", "comiste", "comió", "comimos", "comisteis", "comieron"}},
+		{name: "Regular ir imperfect subjunctive", infinitive: "vivir", tense: ImperfectSubjunctive,
+			want: []string{"viviera", "vivieras", "viviera", "viviéramos", "vivierais", "vivieran"}},
+		{name: "Spelling change car", infinitive: "buscar", tense: PresentSubjunctive,
+			want: []string{"busque", "busques", "busque", "busquemos", "busquéis", "busquen"}},
+		{name: "Spelling change ger", infinitive: "coger", tense: Present,
+			want: []string{"cojo", "coges", "coge", "cogemos", "cogéis", "cogen"}},
+		{name: "Vowel stem preterite", infinitive: "leer", tense: Preterite,
+			want: []string{"leí", "leíste", "leyó", "leímos", "leísteis", "leyeron"}},
+		{name: "Uir present", infinitive: "construir", tense: Present,
+			want: []string{"construyo", "construyes", "construye", "construimos", "construís", "construyen"}},
+		{name: "Stem change ue", infinitive: "dormir", tense: PresentSubjunctive,
+			want:          []string{"duerma", "duermas", "duerma", "durmamos", "durmáis", "duerman"},
+			wantIrregular: []bool{true, true, true, true, true, true}},
+		{name: "Stem change and spelling change", infinitive: "empezar", tense: Present,
+			want:          []string{"empiezo", "empiezas", "empieza", "empezamos", "empezáis", "empiezan"},
+			wantIrregular: []bool{true, true, true, false, false, true}},
+		{name: "Irregular yo", infinitive: "tener", tense: Present,
+			want: []string{"tengo", "tienes", "tiene", "tenemos", "tenéis", "tienen"}},
+		{name: "Strong preterite", infinitive: "hacer", tense: Preterite,
+			want: []string{"hice", "hiciste", "hizo", "hicimos", "hicisteis", "hicieron"}},
+		{name: "Strong j preterite", infinitive: "decir", tense: ImperfectSubjunctive,
+			want: []string{"dijera", "dijeras", "dijera", "dijéramos", "dijerais", "dijeran"}},
+		{name: "Irregular future stem", infinitive: "poner", tense: Conditional,
+			want: []string{"pondría", "pondrías", "pondría", "pondríamos", "pondríais", "pondrían"}},
+		{name: "Whole tense override", infinitive: "ser", tense: Present,
+			want: []string{"soy", "eres", "es", "somos", "sois", "son"}},
+		{name: "Reflexive", infinitive: "Lavarse", tense: Future,
+			want:          []string{"me lavaré", "te lavarás", "se lavará", "nos lavaremos", "os lavaréis", "se lavarán"},
+			wantIrregular: []bool{false, false, false, false, false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forms, err := Spanish{}.Conjugate(tt.infinitive)
+			if err != nil {
+				t.Fatalf("Conjugate() error = %v", err)
+			}
+			if len(forms) != len(Tenses)*len(Persons) {
+				t.Fatalf("Conjugate() returned %d forms", len(forms))
+			}
+
+			var got []string
+			var irregular []bool
+			for _, form := range forms {
+				if form.Tense == tt.tense {
+					got = append(got, form.Form)
+					irregular = append(irregular, form.Irregular)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Conjugate() %s = %v, want %v", tt.tense, got, tt.want)
+			}
+			if tt.wantIrregular != nil && !reflect.DeepEqual(irregular, tt.wantIrregular) {
+				t.Errorf("Conjugate() %s irregular = %v, want %v", tt.tense, irregular, tt.wantIrregular)
+			}
+		})
+	}
+}
+
+func TestSpanish_ConjugateInvalid(t *testing.T) {
+	for _, infinitive := range []string{"", "perro", "ir a", "r"} {
+		if _, err := (Spanish{}).Conjugate(infinitive); err == nil {
+			t.Errorf("Conjugate(%q) expected an error", infinitive)
+		}
+	}
+}
+
+func TestForLanguage(t *testing.T) {
+	if _, err := ForLanguage("es"); err != nil {
+		t.Errorf("ForLanguage(es) error = %v", err)
+	}
+	if _, err := ForLanguage("xx"); err == nil {
+		t.Errorf("ForLanguage(xx) expected an error")
+	}
+}
@@ -13,10 +13,16 @@ func AuditToGql(from *mdl.Audit) (*model.Audit, error) {
 		return nil, fmt.Errorf("expected an audit record but found nothing")
 	}
 
+	action, err := auditActionToGql(from.Action)
+	if err != nil {
+		return nil, err
+	}
+
 	return &model.Audit{
 		ID:        strconv.Itoa(from.ID),
 		ObjectID:  strconv.Itoa(from.ObjectID),
 		TableName: from.TableName,
+		Action:    action,
 		Diff:      from.Diff,
 		Before:    from.Before,
 		After:     from.After,
@@ -45,30 +51,79 @@ func AuditsToGql(from *[]mdl.Audit) ([]*model.Audit, error) {
 	return result, nil
 }
 
-// AuditQueryMapper converts GraphQL query parameters for an audit search into internal representations.
-// It parses the objectID from a string to an integer and converts startTime and endTime from
-// GraphQL DateTime strings to a mdl.Duration struct representing the time range of interest.
+// AuditQueryMapper converts GraphQL query parameters for an audit search into a mdl.AuditFilter.
+// Every parameter is optional, an omitted one matches every audit. It parses the objectID from
+// a string to an integer and converts startTime and endTime from GraphQL DateTime strings to a
+// mdl.Duration struct representing the time range of interest, see GqlDateTimeToDuration for
+// the default of the one omitted. When both are omitted audits of any time match.
 //
 // Parameters:
+// - tableName: The table of the audited records.
 // - objectID: The ID of the object associated with the audits as a string.
 // - startTime: The start of the search period as a GraphQL DateTime string (ISO 8601 format).
 // - endTime: The end of the search period as a GraphQL DateTime string (ISO 8601 format).
+// - createdBy: The user or process that made the changes.
+// - action: The change recorded.
+// - changedField: A key of the audit diff whose value changed.
 //
 // Returns:
-// - The object ID as an integer.
-// - A pointer to a mdl.Duration struct representing the time range.
-// - An error if the conversion of objectID or the parsing of start and end times fails.
-func AuditQueryMapper(objectID string, startTime string, endTime string) (int, *mdl.Duration, error) {
+// - A pointer to the mdl.AuditFilter.
+// - An error if the conversion of objectID or action, or the parsing of start and end times fails.
+func AuditQueryMapper(tableName *string, objectID *string, startTime *string, endTime *string, createdBy *string,
+	action *model.AuditAction, changedField *string) (*mdl.AuditFilter, error) {
 
-	aObjectID, err := strconv.Atoi(objectID)
-	if err != nil {
-		return 0, nil, err
+	filter := &mdl.AuditFilter{
+		TableName:    stringValue(tableName),
+		CreatedBy:    stringValue(createdBy),
+		ChangedField: stringValue(changedField),
 	}
 
-	duration, err := GqlDateTimeToDuration(startTime, endTime)
-	if err != nil {
-		return 0, nil, err
+	if objectID != nil {
+		id, err := strconv.Atoi(*objectID)
+		if err != nil {
+			return nil, err
+		}
+		filter.ObjectID = id
 	}
 
-	return aObjectID, duration, nil
+	if startTime != nil || endTime != nil {
+		duration, err := GqlDateTimeToDuration(stringValue(startTime), stringValue(endTime))
+		if err != nil {
+			return nil, err
+		}
+		filter.Duration = duration
+	}
+
+	if action != nil {
+		switch *action {
+		case model.AuditActionCreated:
+			filter.Action = mdl.AuditCreated
+		case model.AuditActionUpdated:
+			filter.Action = mdl.AuditUpdated
+		case model.AuditActionDeleted:
+			filter.Action = mdl.AuditDeleted
+		case model.AuditActionReverted:
+			filter.Action = mdl.AuditReverted
+		default:
+			return nil, fmt.Errorf("invalid audit action: %s", *action)
+		}
+	}
+
+	return filter, nil
+}
+
+// auditActionToGql converts the audit action from the internal model to GraphQL.
+func auditActionToGql(action string) (model.AuditAction, error) {
+	switch action {
+	case mdl.AuditCreated:
+		return model.AuditActionCreated, nil
+	case mdl.AuditUpdated:
+		return model.AuditActionUpdated, nil
+	case mdl.AuditDeleted:
+		return model.AuditActionDeleted, nil
+	case mdl.AuditReverted:
+		return model.AuditActionReverted, nil
+	default:
+		return "", fmt.Errorf("unknown audit action: %s", action)
+	}
 }
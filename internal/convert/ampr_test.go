@@ -23,6 +23,7 @@ func TestAuditsToGql(t *testing.T) {
 					ID:        1,
 					ObjectID:  101,
 					TableName: "TestTable1",
+					Action:    mdl.AuditCreated,
 					Diff:      "TestDiff1",
 					Before:    "TestBefore1",
 					After:     "TestAfter1",
@@ -34,6 +35,7 @@ func TestAuditsToGql(t *testing.T) {
 					ID:        2,
 					ObjectID:  102,
 					TableName: "TestTable2",
+					Action:    mdl.AuditUpdated,
 					Diff:      "TestDiff2",
 					Before:    "TestBefore2",
 					After:     "TestAfter2",
@@ -47,6 +49,7 @@ func TestAuditsToGql(t *testing.T) {
 					ID:        "1",
 					ObjectID:  "101",
 					TableName: "TestTable1",
+					Action:    model.AuditActionCreated,
 					Diff:      "TestDiff1",
 					Before:    "TestBefore1",
 					After:     "TestAfter1",
@@ -58,6 +61,7 @@ func TestAuditsToGql(t *testing.T) {
 					ID:        "2",
 					ObjectID:  "102",
 					TableName: "TestTable2",
+					Action:    model.AuditActionUpdated,
 					Diff:      "TestDiff2",
 					Before:    "TestBefore2",
 					After:     "TestAfter2",
@@ -68,6 +72,12 @@ func TestAuditsToGql(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:    "Convert unknown action",
+			from:    &[]mdl.Audit{{ID: 3, Action: "renamed"}},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name:    "Convert nil slice of Audits",
 			from:    nil,
@@ -109,43 +119,59 @@ func TestAuditQueryMapper(t *testing.T) {
 	expectedEnd := expectedStart.Add(24 * time.Hour)
 	expectedDuration := &mdl.Duration{Start: expectedStart, End: expectedEnd}
 
+	tableName, createdBy, changedField := "vocab", "sys", "hint"
+	objectID, invalidID, invalidTime := "123", "not_an_int", "invalid_time"
+	deleted, unknown := model.AuditActionDeleted, model.AuditAction("RENAMED")
+
 	tests := []struct {
 		name         string
-		objectID     string
-		startTime    string
-		endTime      string
+		objectID     *string
+		startTime    *string
+		endTime      *string
+		action       *model.AuditAction
 		wantObjectID int
+		wantAction   string
 		wantDuration *mdl.Duration
 		wantErr      bool
 	}{
 		{
 			name:         "valid inputs",
-			objectID:     "123",
-			startTime:    validStartTime,
-			endTime:      validEndTime,
+			objectID:     &objectID,
+			startTime:    &validStartTime,
+			endTime:      &validEndTime,
+			action:       &deleted,
 			wantObjectID: 123,
+			wantAction:   mdl.AuditDeleted,
 			wantDuration: expectedDuration,
 			wantErr:      false,
 		},
+		{
+			name: "omitted inputs",
+		},
 		{
 			name:      "invalid objectID",
-			objectID:  "not_an_int",
-			startTime: validStartTime,
-			endTime:   validEndTime,
+			objectID:  &invalidID,
+			startTime: &validStartTime,
+			endTime:   &validEndTime,
 			wantErr:   true,
 		},
 		{
 			name:      "invalid duration",
-			objectID:  "123",
-			startTime: "invalid_start_time",
-			endTime:   "invalid_end_time",
+			objectID:  &objectID,
+			startTime: &invalidTime,
+			endTime:   &invalidTime,
 			wantErr:   true,
 		},
+		{
+			name:    "invalid action",
+			action:  &unknown,
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotObjectID, gotDuration, err := AuditQueryMapper(tt.objectID, tt.startTime, tt.endTime)
+			got, err := AuditQueryMapper(&tableName, tt.objectID, tt.startTime, tt.endTime, &createdBy, tt.action, &changedField)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("AuditQueryMapper() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -153,13 +179,23 @@ func TestAuditQueryMapper(t *testing.T) {
 				return
 			}
 
-			if gotObjectID != tt.wantObjectID {
-				t.Errorf("AuditQueryMapper() gotObjectID = %v, want %v", gotObjectID, tt.wantObjectID)
+			if got.TableName != tableName || got.CreatedBy != createdBy || got.ChangedField != changedField {
+				t.Errorf("AuditQueryMapper() got = %+v, want the table name, created by and changed field", got)
+			}
+			if got.ObjectID != tt.wantObjectID || got.Action != tt.wantAction {
+				t.Errorf("AuditQueryMapper() gotObjectID = %v, gotAction = %v, want %v and %v",
+					got.ObjectID, got.Action, tt.wantObjectID, tt.wantAction)
+				return
+			}
+			if tt.wantDuration == nil {
+				if got.Duration != nil {
+					t.Errorf("AuditQueryMapper() gotDuration = %+v, want nil", got.Duration)
+				}
 				return
 			}
-			if !gotDuration.Start.Truncate(time.Second).Equal(tt.wantDuration.Start) || !gotDuration.End.Truncate(time.Second).Equal(tt.wantDuration.End) {
+			if !got.Duration.Start.Truncate(time.Second).Equal(tt.wantDuration.Start) || !got.Duration.End.Truncate(time.Second).Equal(tt.wantDuration.End) {
 				t.Errorf("AuditQueryMapper() gotDuration = &{%v %v}, want &{%v %v}",
-					gotDuration.Start, gotDuration.End, tt.wantDuration.Start, tt.wantDuration.End)
+					got.Duration.Start, got.Duration.End, tt.wantDuration.Start, tt.wantDuration.End)
 			}
 		})
 	}
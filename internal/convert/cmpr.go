@@ -0,0 +1,55 @@
+package convert
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/graph/model"
+	"github.com/heather92115/verdure-admin/internal/conj"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"strconv"
+)
+
+// ConjugationToGql maps a mdl.Conjugation struct to a model.Conjugation struct.
+func ConjugationToGql(from *mdl.Conjugation) (*model.Conjugation, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a conjugation record but found nothing")
+	}
+
+	return &model.Conjugation{
+		ID:        strconv.Itoa(from.ID),
+		VocabID:   strconv.Itoa(from.VocabID),
+		Tense:     from.Tense,
+		Person:    from.Person,
+		Form:      from.Form,
+		Irregular: from.Irregular,
+		Corrected: from.Corrected,
+	}, nil
+}
+
+// ConjugationsToGql maps a slice of mdl.Conjugation structs to a slice of model.Conjugation structs.
+func ConjugationsToGql(from []mdl.Conjugation) ([]*model.Conjugation, error) {
+	result := make([]*model.Conjugation, len(from))
+	for i := range from {
+		gqlConjugation, err := ConjugationToGql(&from[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = gqlConjugation
+	}
+
+	return result, nil
+}
+
+// ConjugatedFormsToGql maps a slice of conj.Form structs to a slice of model.ConjugatedForm structs.
+func ConjugatedFormsToGql(from []conj.Form) []*model.ConjugatedForm {
+	result := make([]*model.ConjugatedForm, len(from))
+	for i, form := range from {
+		result[i] = &model.ConjugatedForm{
+			Tense:     form.Tense,
+			Person:    form.Person,
+			Form:      form.Form,
+			Irregular: form.Irregular,
+		}
+	}
+
+	return result
+}
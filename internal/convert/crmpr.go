@@ -0,0 +1,93 @@
+package convert
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/graph/model"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"strconv"
+)
+
+// ChangeRequestToGql maps a mdl.ChangeRequest struct to a model.ChangeRequest struct.
+func ChangeRequestToGql(from *mdl.ChangeRequest) (*model.ChangeRequest, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a change request record but found nothing")
+	}
+
+	status, err := changeStatusToGql(from.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	var reviewed *string
+	if from.Reviewed != nil {
+		reviewedTime := timeToGQLDateTime(*from.Reviewed)
+		reviewed = &reviewedTime
+	}
+
+	return &model.ChangeRequest{
+		ID:             strconv.Itoa(from.ID),
+		VocabID:        strconv.Itoa(from.VocabID),
+		Status:         status,
+		Before:         from.Before,
+		Proposed:       from.Proposed,
+		Diff:           from.Diff,
+		Fields:         aliasesToGql(from.Fields),
+		Comments:       from.Comments,
+		ReviewComments: from.ReviewComments,
+		CreatedBy:      from.CreatedBy,
+		ReviewedBy:     from.ReviewedBy,
+		Created:        timeToGQLDateTime(from.Created),
+		Reviewed:       reviewed,
+	}, nil
+}
+
+// ChangeRequestsToGql maps a slice of mdl.ChangeRequest structs to a slice of model.ChangeRequest structs.
+func ChangeRequestsToGql(from *[]mdl.ChangeRequest) ([]*model.ChangeRequest, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a list of change request records but found nothing")
+	}
+
+	result := make([]*model.ChangeRequest, len(*from))
+	for i := range *from {
+		gqlRequest, err := ChangeRequestToGql(&(*from)[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = gqlRequest
+	}
+
+	return result, nil
+}
+
+// ChangeStatusFilterFromGql converts an optional change request status filter, an omitted
+// status is empty and matches every status.
+func ChangeStatusFilterFromGql(gqlStatus *model.ChangeRequestStatus) (string, error) {
+	if gqlStatus == nil {
+		return "", nil
+	}
+
+	switch *gqlStatus {
+	case model.ChangeRequestStatusPending:
+		return mdl.ChangePending, nil
+	case model.ChangeRequestStatusApproved:
+		return mdl.ChangeApproved, nil
+	case model.ChangeRequestStatusRejected:
+		return mdl.ChangeRejected, nil
+	default:
+		return "", fmt.Errorf("invalid change request status: %s", *gqlStatus)
+	}
+}
+
+// changeStatusToGql converts the change request status from the internal model to GraphQL.
+func changeStatusToGql(status string) (model.ChangeRequestStatus, error) {
+	switch status {
+	case mdl.ChangePending:
+		return model.ChangeRequestStatusPending, nil
+	case mdl.ChangeApproved:
+		return model.ChangeRequestStatusApproved, nil
+	case mdl.ChangeRejected:
+		return model.ChangeRequestStatusRejected, nil
+	default:
+		return "", fmt.Errorf("unknown change request status: %s", status)
+	}
+}
@@ -0,0 +1,79 @@
+package convert
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/graph/model"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"strconv"
+)
+
+// ExampleSentenceToGql maps a mdl.ExampleSentence struct to a model.ExampleSentence struct.
+func ExampleSentenceToGql(from *mdl.ExampleSentence) (*model.ExampleSentence, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected an example sentence record but found nothing")
+	}
+
+	return &model.ExampleSentence{
+		ID:          strconv.Itoa(from.ID),
+		VocabID:     strconv.Itoa(from.VocabID),
+		Sentence:    from.Sentence,
+		Translation: from.Translation,
+		Source:      from.Source,
+		Tokens:      append([]string{}, from.Tokens...),
+		Highlights:  append([]int{}, from.HighlightList()...),
+		CreatedBy:   from.CreatedBy,
+	}, nil
+}
+
+// ExampleSentencesToGql maps a slice of mdl.ExampleSentence structs to a slice of model.ExampleSentence structs.
+func ExampleSentencesToGql(from []mdl.ExampleSentence) ([]*model.ExampleSentence, error) {
+	result := make([]*model.ExampleSentence, len(from))
+	for i := range from {
+		gqlExample, err := ExampleSentenceToGql(&from[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = gqlExample
+	}
+
+	return result, nil
+}
+
+// ExampleSentenceFromNewGql maps a model.NewExampleSentence struct to a mdl.ExampleSentence struct.
+func ExampleSentenceFromNewGql(from *model.NewExampleSentence) (*mdl.ExampleSentence, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected an example sentence from gql, but found nothing")
+	}
+
+	vocabID, err := strconv.Atoi(from.VocabID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vocab id %v", from.VocabID)
+	}
+
+	return &mdl.ExampleSentence{
+		VocabID:     vocabID,
+		Sentence:    from.Sentence,
+		Translation: from.Translation,
+		Source:      stringValue(from.Source),
+	}, nil
+}
+
+// ExampleSentencePatchFromGql maps a model.UpdateExampleSentence struct to a mdl.ExampleSentencePatch struct.
+// Fields left out of the GraphQL input remain nil so they are not changed.
+func ExampleSentencePatchFromGql(from *model.UpdateExampleSentence) (*mdl.ExampleSentencePatch, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected an example sentence update from gql, but found nothing")
+	}
+
+	id, err := strconv.Atoi(from.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id %v", from.ID)
+	}
+
+	return &mdl.ExampleSentencePatch{
+		ID:          id,
+		Sentence:    from.Sentence,
+		Translation: from.Translation,
+		Source:      from.Source,
+	}, nil
+}
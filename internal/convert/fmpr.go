@@ -107,8 +107,9 @@ func NewFixitFromGql(from *model.NewFixit) (*mdl.Fixit, error) {
 	}, nil
 }
 
-// UpdateFixitFromGql maps a model.UpdateFixit struct to a mdl.Fixit struct.
-func UpdateFixitFromGql(from *model.UpdateFixit) (*mdl.Fixit, error) {
+// FixitPatchFromGql maps a model.UpdateFixit struct to a mdl.FixitPatch struct.
+// Fields left out of the GraphQL input remain nil so they are not changed.
+func FixitPatchFromGql(from *model.UpdateFixit) (*mdl.FixitPatch, error) {
 	if from == nil {
 		return nil, fmt.Errorf("expected an UpdateFixit from gql, but found nothing")
 	}
@@ -118,17 +119,21 @@ func UpdateFixitFromGql(from *model.UpdateFixit) (*mdl.Fixit, error) {
 		return nil, fmt.Errorf("invalid ID %v", from.ID)
 	}
 
-	status, err := FixitStatusFromGql(from.Status)
-	if err != nil {
-		return nil, err
-	}
-
-	return &mdl.Fixit{
+	patch := &mdl.FixitPatch{
 		ID:        id,
-		Status:    status,
 		FieldName: from.FieldName,
 		Comments:  from.Comments,
-	}, nil
+	}
+
+	if from.Status != nil {
+		status, err := FixitStatusFromGql(*from.Status)
+		if err != nil {
+			return nil, err
+		}
+		patch.Status = &status
+	}
+
+	return patch, nil
 }
 
 // FixitStatusFromGql converts the status enum from GraphQL to internal model
@@ -145,6 +150,15 @@ func FixitStatusFromGql(gqlStatus model.Status) (mdl.StatusType, error) {
 	}
 }
 
+// FixitStatusFilterFromGql converts an optional status filter, an omitted status is empty and
+// matches every status.
+func FixitStatusFilterFromGql(gqlStatus *model.Status) (mdl.StatusType, error) {
+	if gqlStatus == nil {
+		return "", nil
+	}
+	return FixitStatusFromGql(*gqlStatus)
+}
+
 // Convert the status enum from internal model to GraphQL
 func fixitStatusToGql(status mdl.StatusType) (model.Status, error) {
 	switch status {
@@ -230,26 +230,44 @@ func TestNewFixitFromGql(t *testing.T) {
 	}
 }
 
-func TestUpdateFixitFromGql(t *testing.T) {
+func TestFixitPatchFromGql(t *testing.T) {
+	completed := model.StatusCompleted
+	unknown := model.Status("UNKNOWN")
+	completedStatus := mdl.Completed
+	fieldName := "Updated Field"
+	comments := "Updated Comment"
+
 	tests := []struct {
 		name    string
 		from    *model.UpdateFixit
-		want    *mdl.Fixit
+		want    *mdl.FixitPatch
 		wantErr bool
 	}{
 		{
 			name: "Valid UpdateFixit conversion",
 			from: &model.UpdateFixit{
 				ID:        "1",
-				Status:    "COMPLETED",
-				FieldName: "Updated Field",
-				Comments:  "Updated Comment",
+				Status:    &completed,
+				FieldName: &fieldName,
+				Comments:  &comments,
 			},
-			want: &mdl.Fixit{
+			want: &mdl.FixitPatch{
 				ID:        1,
-				Status:    mdl.Completed,
-				FieldName: "Updated Field",
-				Comments:  "Updated Comment",
+				Status:    &completedStatus,
+				FieldName: &fieldName,
+				Comments:  &comments,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Partial UpdateFixit conversion",
+			from: &model.UpdateFixit{
+				ID:       "1",
+				Comments: &comments,
+			},
+			want: &mdl.FixitPatch{
+				ID:       1,
+				Comments: &comments,
 			},
 			wantErr: false,
 		},
@@ -263,9 +281,9 @@ func TestUpdateFixitFromGql(t *testing.T) {
 			name: "Invalid ID format",
 			from: &model.UpdateFixit{
 				ID:        "invalid",
-				Status:    "COMPLETED",
-				FieldName: "Updated Field",
-				Comments:  "Updated Comment",
+				Status:    &completed,
+				FieldName: &fieldName,
+				Comments:  &comments,
 			},
 			want:    nil,
 			wantErr: true,
@@ -274,9 +292,9 @@ func TestUpdateFixitFromGql(t *testing.T) {
 			name: "Invalid status value",
 			from: &model.UpdateFixit{
 				ID:        "1",
-				Status:    "UNKNOWN",
-				FieldName: "Updated Field",
-				Comments:  "Updated Comment",
+				Status:    &unknown,
+				FieldName: &fieldName,
+				Comments:  &comments,
 			},
 			want:    nil,
 			wantErr: true,
@@ -285,16 +303,16 @@ func TestUpdateFixitFromGql(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := UpdateFixitFromGql(tt.from)
+			got, err := FixitPatchFromGql(tt.from)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("UpdateFixitFromGql() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("FixitPatchFromGql() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			} else if tt.wantErr {
 				return
 			}
 
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("UpdateFixitFromGql() = %v, want %v", got, tt.want)
+				t.Errorf("FixitPatchFromGql() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -0,0 +1,115 @@
+package convert
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/graph/model"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"github.com/heather92115/verdure-admin/internal/srv"
+	"strconv"
+	"strings"
+)
+
+// LanguageToGql maps a mdl.Language struct to a model.Language struct, listing the hooks
+// registered for its code.
+func LanguageToGql(from *mdl.Language) (*model.Language, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a language record but found nothing")
+	}
+
+	hooks := srv.LanguageHookNames(from.Code)
+	if hooks == nil {
+		hooks = []string{}
+	}
+
+	return &model.Language{
+		ID:               strconv.Itoa(from.ID),
+		Code:             from.Code,
+		Name:             from.Name,
+		Script:           from.Script,
+		Direction:        from.Direction,
+		Enabled:          from.Enabled,
+		DefaultKnown:     from.DefaultKnown,
+		DefaultLearning:  from.DefaultLearning,
+		Elision:          from.Elision,
+		Articles:         aliasesToGql(from.Articles),
+		DefiniteArticles: aliasesToGql(from.DefiniteArticles),
+		Hooks:            hooks,
+	}, nil
+}
+
+// LanguagesToGql maps a slice of mdl.Language structs to a slice of model.Language structs.
+func LanguagesToGql(from *[]mdl.Language) ([]*model.Language, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a list of language records but found nothing")
+	}
+
+	result := make([]*model.Language, len(*from))
+	for i := range *from {
+		gqlLanguage, err := LanguageToGql(&(*from)[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = gqlLanguage
+	}
+
+	return result, nil
+}
+
+// LanguageFromNewGql maps a model.NewLanguage struct to a mdl.Language struct.
+func LanguageFromNewGql(from *model.NewLanguage) (*mdl.Language, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a language from gql, but found nothing")
+	}
+
+	return &mdl.Language{
+		Code:             from.Code,
+		Name:             from.Name,
+		Script:           stringValue(from.Script),
+		Direction:        stringValue(from.Direction),
+		Enabled:          from.Enabled,
+		DefaultKnown:     boolValue(from.DefaultKnown),
+		DefaultLearning:  boolValue(from.DefaultLearning),
+		Elision:          boolValue(from.Elision),
+		Articles:         strings.Join(from.Articles, termAliasesSeparator),
+		DefiniteArticles: strings.Join(from.DefiniteArticles, termAliasesSeparator),
+	}, nil
+}
+
+// LanguagePatchFromGql maps a model.UpdateLanguage struct to a mdl.LanguagePatch struct.
+// Fields left out of the GraphQL input remain nil so they are not changed.
+func LanguagePatchFromGql(from *model.UpdateLanguage) (*mdl.LanguagePatch, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a language update from gql, but found nothing")
+	}
+
+	id, err := strconv.Atoi(from.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id %v", from.ID)
+	}
+
+	patch := &mdl.LanguagePatch{
+		ID:              id,
+		Name:            from.Name,
+		Script:          from.Script,
+		Direction:       from.Direction,
+		Enabled:         from.Enabled,
+		DefaultKnown:    from.DefaultKnown,
+		DefaultLearning: from.DefaultLearning,
+		Elision:         from.Elision,
+	}
+	if from.Articles != nil {
+		articles := strings.Join(from.Articles, termAliasesSeparator)
+		patch.Articles = &articles
+	}
+	if from.DefiniteArticles != nil {
+		definiteArticles := strings.Join(from.DefiniteArticles, termAliasesSeparator)
+		patch.DefiniteArticles = &definiteArticles
+	}
+
+	return patch, nil
+}
+
+// boolValue dereferences an optional GraphQL boolean, false when it is absent.
+func boolValue(from *bool) bool {
+	return from != nil && *from
+}
@@ -0,0 +1,44 @@
+package convert
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/graph/model"
+	"github.com/heather92115/verdure-admin/internal/srv"
+)
+
+// LintResultToGql maps a srv.LintResult struct to a model.LintResult struct.
+func LintResultToGql(from *srv.LintResult) (*model.LintResult, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a lint result but found nothing")
+	}
+
+	vocab, err := VocabToGql(&from.Vocab)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]*model.LintFinding, len(from.Findings))
+	for i, finding := range from.Findings {
+		findings[i] = &model.LintFinding{
+			Rule:      finding.Rule,
+			FieldName: finding.FieldName,
+			Message:   finding.Message,
+		}
+	}
+
+	return &model.LintResult{Vocab: vocab, Findings: findings}, nil
+}
+
+// LintResultsToGql maps a slice of srv.LintResult structs to a slice of model.LintResult structs.
+func LintResultsToGql(from []srv.LintResult) ([]*model.LintResult, error) {
+	result := make([]*model.LintResult, len(from))
+	for i := range from {
+		gqlResult, err := LintResultToGql(&from[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = gqlResult
+	}
+
+	return result, nil
+}
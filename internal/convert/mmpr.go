@@ -0,0 +1,27 @@
+package convert
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/graph/model"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"github.com/heather92115/verdure-admin/internal/media"
+	"strconv"
+)
+
+// AudioAssetToGql maps a mdl.AudioAsset struct to a model.AudioAsset struct, with the URL it is streamed from.
+func AudioAssetToGql(from *mdl.AudioAsset) (*model.AudioAsset, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected an audio record but found nothing")
+	}
+
+	return &model.AudioAsset{
+		ID:         strconv.Itoa(from.ID),
+		Hash:       from.Hash,
+		MimeType:   from.MimeType,
+		Size:       int(from.Size),
+		DurationMs: from.DurationMs,
+		Filename:   from.Filename,
+		URL:        media.AudioURL(from.Hash),
+		CreatedBy:  from.CreatedBy,
+	}, nil
+}
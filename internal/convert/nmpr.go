@@ -0,0 +1,59 @@
+package convert
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/graph/model"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"strconv"
+)
+
+// ConceptToGql maps a mdl.Concept struct to a model.Concept struct. The translations are
+// resolved separately.
+func ConceptToGql(from *mdl.Concept) (*model.Concept, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a concept record but found nothing")
+	}
+
+	return &model.Concept{
+		ID:            strconv.Itoa(from.ID),
+		Gloss:         from.Gloss,
+		GlossLangCode: from.GlossLangCode,
+		Notes:         from.Notes,
+		CreatedBy:     from.CreatedBy,
+		Created:       timeToGQLDateTime(from.Created),
+	}, nil
+}
+
+// ConceptFromNewGql maps a model.NewConcept struct to a mdl.Concept struct.
+func ConceptFromNewGql(from *model.NewConcept) (*mdl.Concept, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a concept from gql, but found nothing")
+	}
+
+	return &mdl.Concept{
+		Gloss:         from.Gloss,
+		GlossLangCode: stringValue(from.GlossLangCode),
+		Notes:         stringValue(from.Notes),
+		CreatedBy:     "sys",
+	}, nil
+}
+
+// ConceptPatchFromGql maps a model.UpdateConcept struct to a mdl.ConceptPatch struct.
+// Fields left out of the GraphQL input remain nil so they are not changed.
+func ConceptPatchFromGql(from *model.UpdateConcept) (*mdl.ConceptPatch, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a concept update from gql, but found nothing")
+	}
+
+	id, err := strconv.Atoi(from.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id %v", from.ID)
+	}
+
+	return &mdl.ConceptPatch{
+		ID:            id,
+		Gloss:         from.Gloss,
+		GlossLangCode: from.GlossLangCode,
+		Notes:         from.Notes,
+	}, nil
+}
@@ -0,0 +1,68 @@
+package convert
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/graph/model"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"github.com/heather92115/verdure-admin/internal/srv"
+	"strconv"
+)
+
+// SnapshotToGql maps a mdl.Snapshot struct to a model.Snapshot struct.
+func SnapshotToGql(from *mdl.Snapshot) (*model.Snapshot, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a snapshot record but found nothing")
+	}
+
+	return &model.Snapshot{
+		ID:               strconv.Itoa(from.ID),
+		LearningLangCode: from.LearningLangCode,
+		Version:          from.Version,
+		Checksum:         from.Checksum,
+		Size:             int(from.Size),
+		VocabCount:       from.VocabCount,
+		ExampleCount:     from.ExampleCount,
+		AuditID:          strconv.Itoa(from.AuditID),
+		Live:             from.Live,
+		CreatedBy:        from.CreatedBy,
+		Created:          timeToGQLDateTime(from.Created),
+	}, nil
+}
+
+// SnapshotsToGql maps a slice of mdl.Snapshot structs to a slice of model.Snapshot structs.
+func SnapshotsToGql(from *[]mdl.Snapshot) ([]*model.Snapshot, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a list of snapshot records but found nothing")
+	}
+
+	result := make([]*model.Snapshot, len(*from))
+	for i := range *from {
+		gqlSnapshot, err := SnapshotToGql(&(*from)[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = gqlSnapshot
+	}
+
+	return result, nil
+}
+
+// SnapshotChangesToGql maps a slice of srv.SnapshotChange structs to a slice of model.SnapshotChange structs.
+func SnapshotChangesToGql(from []srv.SnapshotChange) ([]*model.SnapshotChange, error) {
+	result := make([]*model.SnapshotChange, len(from))
+	for i, change := range from {
+		result[i] = &model.SnapshotChange{
+			AuditID:   strconv.Itoa(change.AuditID),
+			TableName: change.TableName,
+			ObjectID:  strconv.Itoa(change.ObjectID),
+			VocabID:   strconv.Itoa(change.VocabID),
+			Action:    change.Action,
+			Fields:    append([]string{}, change.Fields...),
+			Comments:  change.Comments,
+			CreatedBy: change.CreatedBy,
+			Created:   timeToGQLDateTime(change.Created),
+		}
+	}
+
+	return result, nil
+}
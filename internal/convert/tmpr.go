@@ -0,0 +1,220 @@
+package convert
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/graph/model"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"github.com/heather92115/verdure-admin/internal/srv"
+	"strconv"
+	"strings"
+)
+
+// termAliasesSeparator delimits the aliases stored in a single lookup table column.
+const termAliasesSeparator = ", "
+
+// PartOfSpeechToGql maps a mdl.PartOfSpeech struct to a model.PartOfSpeech struct.
+func PartOfSpeechToGql(from *mdl.PartOfSpeech) (*model.PartOfSpeech, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a part of speech record but found nothing")
+	}
+
+	return &model.PartOfSpeech{
+		ID:          strconv.Itoa(from.ID),
+		Name:        from.Name,
+		Description: from.Description,
+		Aliases:     aliasesToGql(from.Aliases),
+	}, nil
+}
+
+// PartsOfSpeechToGql maps a slice of mdl.PartOfSpeech structs to a slice of model.PartOfSpeech structs.
+func PartsOfSpeechToGql(from *[]mdl.PartOfSpeech) ([]*model.PartOfSpeech, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a list of part of speech records but found nothing")
+	}
+
+	result := make([]*model.PartOfSpeech, len(*from))
+	for i := range *from {
+		gqlPos, err := PartOfSpeechToGql(&(*from)[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = gqlPos
+	}
+
+	return result, nil
+}
+
+// SkillToGql maps a mdl.Skill struct to a model.Skill struct.
+func SkillToGql(from *mdl.Skill) (*model.Skill, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a skill record but found nothing")
+	}
+
+	return &model.Skill{
+		ID:          strconv.Itoa(from.ID),
+		Name:        from.Name,
+		Description: from.Description,
+		Aliases:     aliasesToGql(from.Aliases),
+		ParentID:    optionalIDToGql(from.ParentID),
+		SortOrder:   from.SortOrder,
+	}, nil
+}
+
+// SkillsToGql maps a slice of mdl.Skill structs to a slice of model.Skill structs.
+func SkillsToGql(from *[]mdl.Skill) ([]*model.Skill, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a list of skill records but found nothing")
+	}
+
+	result := make([]*model.Skill, len(*from))
+	for i := range *from {
+		gqlSkill, err := SkillToGql(&(*from)[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = gqlSkill
+	}
+
+	return result, nil
+}
+
+// PartOfSpeechFromNewGql maps a model.NewTerm struct to a mdl.PartOfSpeech struct.
+func PartOfSpeechFromNewGql(from *model.NewTerm) (*mdl.PartOfSpeech, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a part of speech from gql, but found nothing")
+	}
+
+	pos := &mdl.PartOfSpeech{Name: from.Name, Aliases: strings.Join(from.Aliases, termAliasesSeparator)}
+	if from.Description != nil {
+		pos.Description = *from.Description
+	}
+
+	return pos, nil
+}
+
+// SkillFromNewGql maps a model.NewSkill struct to a mdl.Skill struct.
+func SkillFromNewGql(from *model.NewSkill) (*mdl.Skill, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a skill from gql, but found nothing")
+	}
+
+	skill := &mdl.Skill{Name: from.Name, Aliases: strings.Join(from.Aliases, termAliasesSeparator)}
+	if from.Description != nil {
+		skill.Description = *from.Description
+	}
+	if from.SortOrder != nil {
+		skill.SortOrder = *from.SortOrder
+	}
+	if from.ParentID != nil {
+		parentID, err := strconv.Atoi(*from.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parent id %v", *from.ParentID)
+		}
+		skill.ParentID = &parentID
+	}
+
+	return skill, nil
+}
+
+// TermPatchFromGql maps a model.UpdateTerm struct to a mdl.TermPatch struct.
+// Fields left out of the GraphQL input remain nil so they are not changed.
+func TermPatchFromGql(from *model.UpdateTerm) (*mdl.TermPatch, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a term update from gql, but found nothing")
+	}
+
+	id, err := strconv.Atoi(from.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id %v", from.ID)
+	}
+
+	patch := &mdl.TermPatch{ID: id, Name: from.Name, Description: from.Description}
+	if from.Aliases != nil {
+		aliases := strings.Join(from.Aliases, termAliasesSeparator)
+		patch.Aliases = &aliases
+	}
+
+	return patch, nil
+}
+
+// SkillPatchFromGql maps a model.UpdateSkill struct to a mdl.SkillPatch struct.
+// Fields left out of the GraphQL input remain nil so they are not changed.
+func SkillPatchFromGql(from *model.UpdateSkill) (*mdl.SkillPatch, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a skill update from gql, but found nothing")
+	}
+
+	patch, err := TermPatchFromGql(&model.UpdateTerm{ID: from.ID, Name: from.Name, Description: from.Description, Aliases: from.Aliases})
+	if err != nil {
+		return nil, err
+	}
+
+	skillPatch := &mdl.SkillPatch{TermPatch: *patch, SortOrder: from.SortOrder}
+	if from.ParentID != nil {
+		parentID, err := strconv.Atoi(*from.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parent id %v", *from.ParentID)
+		}
+		skillPatch.ParentID = &parentID
+	}
+
+	return skillPatch, nil
+}
+
+// SkillTreeToGql maps a slice of srv.SkillNode structs, and their children, to a slice of model.SkillNode structs.
+func SkillTreeToGql(from []srv.SkillNode) ([]*model.SkillNode, error) {
+	result := make([]*model.SkillNode, len(from))
+	for i := range from {
+		skill, err := SkillToGql(&from[i].Skill)
+		if err != nil {
+			return nil, err
+		}
+
+		children, err := SkillTreeToGql(from[i].Children)
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = &model.SkillNode{
+			Skill:           skill,
+			VocabCount:      from[i].VocabCount,
+			TotalVocabCount: from[i].TotalVocabCount,
+			Children:        children,
+		}
+	}
+
+	return result, nil
+}
+
+// NonConformingTermsToGql maps a slice of srv.NonConformingTerm structs to a slice of model.NonConformingTerm structs.
+func NonConformingTermsToGql(from []srv.NonConformingTerm) []*model.NonConformingTerm {
+	result := make([]*model.NonConformingTerm, len(from))
+	for i, term := range from {
+		result[i] = &model.NonConformingTerm{
+			Field:      term.Field,
+			Value:      term.Value,
+			Count:      term.Count,
+			Suggestion: term.Suggestion,
+		}
+	}
+
+	return result
+}
+
+// optionalIDToGql maps an optional foreign key to a nullable GraphQL ID.
+func optionalIDToGql(id *int) *string {
+	if id == nil {
+		return nil
+	}
+	gqlID := strconv.Itoa(*id)
+	return &gqlID
+}
+
+// aliasesToGql splits the stored aliases into a list, empty when there are none.
+func aliasesToGql(aliases string) []string {
+	list := srv.ParseAlternatives(aliases)
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
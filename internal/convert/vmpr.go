@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/heather92115/verdure-admin/graph/model"
 	"github.com/heather92115/verdure-admin/internal/mdl"
+	"github.com/heather92115/verdure-admin/internal/srv"
 	"strconv"
 )
 
@@ -13,21 +14,45 @@ func VocabToGql(from *mdl.Vocab) (*model.Vocab, error) {
 		return nil, fmt.Errorf("expected a vocab record but found nothing")
 	}
 
+	alternatives, details := alternativesToGql(from.AlternativeList)
+
 	return &model.Vocab{
-		ID:               strconv.Itoa(from.ID), // Convert int ID to string
-		LearningLang:     from.LearningLang,
-		FirstLang:        from.FirstLang,
-		Alternatives:     from.Alternatives,
-		Skill:            from.Skill,
-		Infinitive:       from.Infinitive,
-		Pos:              from.Pos,
-		Hint:             from.Hint,
-		NumLearningWords: from.NumLearningWords,
-		KnownLangCode:    from.KnownLangCode,
-		LearningLangCode: from.LearningLangCode,
+		ID:                 strconv.Itoa(from.ID), // Convert int ID to string
+		LearningLang:       from.LearningLang,
+		FirstLang:          from.FirstLang,
+		Alternatives:       alternatives,
+		AlternativeDetails: details,
+		Skill:              from.Skill,
+		SkillID:            optionalIDToGql(from.SkillID),
+		Infinitive:         from.Infinitive,
+		Pos:                from.Pos,
+		Hint:               from.Hint,
+		Gender:             from.Gender,
+		Plural:             from.Plural,
+		Article:            from.Article,
+		Register:           from.Register,
+		AudioID:            optionalIDToGql(from.AudioID),
+		ConceptID:          optionalIDToGql(from.ConceptID),
+		NumLearningWords:   from.NumLearningWords,
+		KnownLangCode:      from.KnownLangCode,
+		LearningLangCode:   from.LearningLangCode,
 	}, nil
 }
 
+// alternativesToGql maps a vocab's alternatives to both the plain list of alternative
+// texts and the detailed list including notes and position.
+func alternativesToGql(from []mdl.VocabAlternative) (alternatives []string, details []*model.Alternative) {
+	for _, alternative := range from {
+		alternatives = append(alternatives, alternative.Alternative)
+		details = append(details, &model.Alternative{
+			Alternative: alternative.Alternative,
+			Notes:       alternative.Notes,
+			Position:    alternative.Position,
+		})
+	}
+	return
+}
+
 // VocabsToGql maps a slice of mdl.Vocab structs to a slice of model.Vocabs struct.
 // This converts internal vocab structs to the graphql schema form.
 func VocabsToGql(from *[]mdl.Vocab) ([]*model.Vocab, error) {
@@ -47,8 +72,23 @@ func VocabsToGql(from *[]mdl.Vocab) ([]*model.Vocab, error) {
 	return result, nil
 }
 
-// VocabFromGql maps a model.Vocab struct to a mdl.Vocab struct.
-func VocabFromGql(from *model.UpdateVocab) (*mdl.Vocab, error) {
+// DuplicateClustersToGql maps a slice of srv.DuplicateCluster structs to a slice of model.DuplicateCluster structs.
+func DuplicateClustersToGql(from []srv.DuplicateCluster) ([]*model.DuplicateCluster, error) {
+	result := make([]*model.DuplicateCluster, len(from))
+	for i, cluster := range from {
+		vocabs, err := VocabsToGql(&cluster.Vocabs)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = &model.DuplicateCluster{Key: cluster.Key, Vocabs: vocabs}
+	}
+
+	return result, nil
+}
+
+// VocabPatchFromGql maps a model.UpdateVocab struct to a mdl.VocabPatch struct.
+// Fields left out of the GraphQL input remain nil so they are not changed.
+func VocabPatchFromGql(from *model.UpdateVocab) (*mdl.VocabPatch, error) {
 	if from == nil {
 		return nil, fmt.Errorf("expected an vocab from gql, but found nothing")
 	}
@@ -59,34 +99,133 @@ func VocabFromGql(from *model.UpdateVocab) (*mdl.Vocab, error) {
 		return nil, fmt.Errorf("invalid id %v", from.ID)
 	}
 
-	return &mdl.Vocab{
+	return &mdl.VocabPatch{
 		ID:               id,
 		FirstLang:        from.FirstLang,
-		Alternatives:     from.Alternatives,
 		Skill:            from.Skill,
 		Infinitive:       from.Infinitive,
 		Pos:              from.Pos,
 		Hint:             from.Hint,
+		Gender:           from.Gender,
+		Plural:           from.Plural,
+		Article:          from.Article,
+		Register:         from.Register,
 		NumLearningWords: from.NumLearningWords,
 	}, nil
 }
 
+// VocabRenameFromGql maps a model.RenameVocab struct to a mdl.VocabRename struct.
+func VocabRenameFromGql(from *model.RenameVocab) (*mdl.VocabRename, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a vocab rename from gql, but found nothing")
+	}
+
+	id, err := strconv.Atoi(from.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id %v", from.ID)
+	}
+
+	rename := &mdl.VocabRename{
+		ID:           id,
+		LearningLang: from.LearningLang,
+	}
+
+	if from.KnownLangCode != nil {
+		rename.KnownLangCode = *from.KnownLangCode
+	}
+	if from.LearningLangCode != nil {
+		rename.LearningLangCode = *from.LearningLangCode
+	}
+	if from.KeepOldAsAlternative != nil {
+		rename.KeepOldAsAlternative = *from.KeepOldAsAlternative
+	}
+
+	return rename, nil
+}
+
 // VocabFromNewGql maps a model.NewVocab struct to a mdl.Vocab struct.
 func VocabFromNewGql(from *model.NewVocab) (*mdl.Vocab, error) {
 	if from == nil {
 		return nil, fmt.Errorf("expected an vocab from gql, but found nothing")
 	}
 
+	// Zero tells the service no word count was supplied, it is computed from the learning lang.
+	numLearningWords := 0
+	if from.NumLearningWords != nil {
+		numLearningWords = *from.NumLearningWords
+	}
+
+	alternatives := make([]mdl.VocabAlternative, len(from.Alternatives))
+	for i, alternative := range from.Alternatives {
+		alternatives[i] = mdl.VocabAlternative{Alternative: alternative, Position: i, CreatedBy: "sys"}
+	}
+
+	var conceptID *int
+	if from.ConceptID != nil {
+		id, err := strconv.Atoi(*from.ConceptID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid concept id %v", *from.ConceptID)
+		}
+		conceptID = &id
+	}
+
 	return &mdl.Vocab{
 		LearningLang:     from.LearningLang,
 		FirstLang:        from.FirstLang,
-		Alternatives:     from.Alternatives,
+		AlternativeList:  alternatives,
 		Skill:            from.Skill,
 		Infinitive:       from.Infinitive,
 		Pos:              from.Pos,
 		Hint:             from.Hint,
-		NumLearningWords: from.NumLearningWords,
-		KnownLangCode:    from.KnownLangCode,
-		LearningLangCode: from.LearningLangCode,
+		Gender:           stringValue(from.Gender),
+		Plural:           stringValue(from.Plural),
+		Article:          stringValue(from.Article),
+		Register:         stringValue(from.Register),
+		NumLearningWords: numLearningWords,
+		ConceptID:        conceptID,
+		KnownLangCode:    stringValue(from.KnownLangCode),
+		LearningLangCode: stringValue(from.LearningLangCode),
 	}, nil
 }
+
+// GrammarSuggestionToGql maps a srv.GrammarSuggestion struct to a model.GrammarSuggestion struct.
+func GrammarSuggestionToGql(from *srv.GrammarSuggestion) *model.GrammarSuggestion {
+	return &model.GrammarSuggestion{
+		Gender:  from.Gender,
+		Plural:  from.Plural,
+		Article: from.Article,
+		Reason:  from.Reason,
+	}
+}
+
+// VocabFieldsToGql maps a slice of srv.VocabField structs to a slice of model.VocabField structs.
+func VocabFieldsToGql(from []srv.VocabField) []*model.VocabField {
+	result := make([]*model.VocabField, len(from))
+	for i, field := range from {
+		values := field.Values
+		if values == nil {
+			values = []string{}
+		}
+		result[i] = &model.VocabField{
+			Name:      field.Name,
+			Column:    field.Column,
+			Label:     field.Label,
+			Kind:      field.Kind,
+			MaxLength: field.MaxLength,
+			Required:  field.Required,
+			Updatable: field.Updatable,
+			Values:    values,
+			Policy:    field.Policy.PolicyName(),
+		}
+	}
+
+	return result
+}
+
+// stringValue returns the optional GraphQL string, or empty when it is omitted.
+func stringValue(from *string) string {
+	if from == nil {
+		return ""
+	}
+	return *from
+}
@@ -0,0 +1,170 @@
+package convert
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/graph/model"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"strconv"
+	"strings"
+)
+
+// WebhookToGql maps a mdl.Webhook struct to a model.Webhook struct, leaving out its secret.
+func WebhookToGql(from *mdl.Webhook) (*model.Webhook, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a webhook record but found nothing")
+	}
+
+	return &model.Webhook{
+		ID:          strconv.Itoa(from.ID),
+		URL:         from.URL,
+		EventTypes:  aliasesToGql(from.EventTypes),
+		Enabled:     from.Enabled,
+		Description: from.Description,
+		CreatedBy:   from.CreatedBy,
+		Created:     timeToGQLDateTime(from.Created),
+	}, nil
+}
+
+// WebhooksToGql maps a slice of mdl.Webhook structs to a slice of model.Webhook structs.
+func WebhooksToGql(from *[]mdl.Webhook) ([]*model.Webhook, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a list of webhook records but found nothing")
+	}
+
+	result := make([]*model.Webhook, len(*from))
+	for i := range *from {
+		gqlWebhook, err := WebhookToGql(&(*from)[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = gqlWebhook
+	}
+
+	return result, nil
+}
+
+// WebhookFromNewGql maps a model.NewWebhook struct to a mdl.Webhook struct.
+func WebhookFromNewGql(from *model.NewWebhook) (*mdl.Webhook, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a webhook from gql, but found nothing")
+	}
+
+	return &mdl.Webhook{
+		URL:         from.URL,
+		Secret:      from.Secret,
+		EventTypes:  strings.Join(from.EventTypes, termAliasesSeparator),
+		Enabled:     from.Enabled,
+		Description: stringValue(from.Description),
+	}, nil
+}
+
+// WebhookPatchFromGql maps a model.UpdateWebhook struct to a mdl.WebhookPatch struct.
+// Fields left out of the GraphQL input remain nil so they are not changed.
+func WebhookPatchFromGql(from *model.UpdateWebhook) (*mdl.WebhookPatch, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a webhook update from gql, but found nothing")
+	}
+
+	id, err := strconv.Atoi(from.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id %v", from.ID)
+	}
+
+	patch := &mdl.WebhookPatch{
+		ID:          id,
+		URL:         from.URL,
+		Secret:      from.Secret,
+		Enabled:     from.Enabled,
+		Description: from.Description,
+	}
+	if from.EventTypes != nil {
+		eventTypes := strings.Join(from.EventTypes, termAliasesSeparator)
+		patch.EventTypes = &eventTypes
+	}
+
+	return patch, nil
+}
+
+// WebhookDeliveryToGql maps a mdl.WebhookDelivery struct to a model.WebhookDelivery struct.
+func WebhookDeliveryToGql(from *mdl.WebhookDelivery) (*model.WebhookDelivery, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a webhook delivery record but found nothing")
+	}
+
+	status, err := deliveryStatusToGql(from.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	var delivered *string
+	if from.Delivered != nil {
+		deliveredTime := timeToGQLDateTime(*from.Delivered)
+		delivered = &deliveredTime
+	}
+
+	return &model.WebhookDelivery{
+		ID:             strconv.Itoa(from.ID),
+		WebhookID:      strconv.Itoa(from.WebhookID),
+		EventType:      from.EventType,
+		AuditID:        strconv.Itoa(from.AuditID),
+		Payload:        from.Payload,
+		Status:         status,
+		Attempts:       from.Attempts,
+		NextAttempt:    timeToGQLDateTime(from.NextAttempt),
+		ResponseStatus: from.ResponseStatus,
+		LastError:      from.LastError,
+		Created:        timeToGQLDateTime(from.Created),
+		Delivered:      delivered,
+	}, nil
+}
+
+// WebhookDeliveriesToGql maps a slice of mdl.WebhookDelivery structs to a slice of model.WebhookDelivery structs.
+func WebhookDeliveriesToGql(from *[]mdl.WebhookDelivery) ([]*model.WebhookDelivery, error) {
+	if from == nil {
+		return nil, fmt.Errorf("expected a list of webhook delivery records but found nothing")
+	}
+
+	result := make([]*model.WebhookDelivery, len(*from))
+	for i := range *from {
+		gqlDelivery, err := WebhookDeliveryToGql(&(*from)[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = gqlDelivery
+	}
+
+	return result, nil
+}
+
+// DeliveryStatusFilterFromGql converts an optional delivery status filter, an omitted status
+// is empty and matches every status.
+func DeliveryStatusFilterFromGql(gqlStatus *model.DeliveryStatus) (string, error) {
+	if gqlStatus == nil {
+		return "", nil
+	}
+
+	switch *gqlStatus {
+	case model.DeliveryStatusPending:
+		return mdl.DeliveryPending, nil
+	case model.DeliveryStatusDelivered:
+		return mdl.DeliveryDelivered, nil
+	case model.DeliveryStatusFailed:
+		return mdl.DeliveryFailed, nil
+	default:
+		return "", fmt.Errorf("invalid delivery status: %s", *gqlStatus)
+	}
+}
+
+// deliveryStatusToGql converts the delivery status from the internal model to GraphQL.
+func deliveryStatusToGql(status string) (model.DeliveryStatus, error) {
+	switch status {
+	case mdl.DeliveryPending:
+		return model.DeliveryStatusPending, nil
+	case mdl.DeliveryDelivered:
+		return model.DeliveryStatusDelivered, nil
+	case mdl.DeliveryFailed:
+		return model.DeliveryStatusFailed, nil
+	default:
+		return "", fmt.Errorf("unknown delivery status: %s", status)
+	}
+}
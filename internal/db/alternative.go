@@ -0,0 +1,114 @@
+// Package db defines interfaces and implementations for interacting with
+// entities in the database. It includes the AlternativeRepository interface, which outlines
+// operations for querying and mutating VocabAlternative records, and the SQLAlternativeRepository
+// struct, which provides a concrete implementation of the AlternativeRepository using GORM.
+package db
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"gorm.io/gorm"
+	"log"
+)
+
+// AlternativeRepository defines the operations available for a VocabAlternative entity.
+type AlternativeRepository interface {
+	FindAlternatives(vocabIDs []int) (*[]mdl.VocabAlternative, error)
+	CreateAlternative(alternative *mdl.VocabAlternative) error
+	UpdateAlternative(alternative *mdl.VocabAlternative) error
+	DeleteAlternative(id int) error
+}
+
+// SQLAlternativeRepository provides a GORM-based implementation of the AlternativeRepository interface.
+type SQLAlternativeRepository struct {
+	db *gorm.DB
+}
+
+// NewSqlAlternativeRepository initializes a new SQLAlternativeRepository with a database connection.
+func NewSqlAlternativeRepository() (repo *SQLAlternativeRepository, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	repo = &SQLAlternativeRepository{db: db}
+
+	return
+}
+
+// FindAlternatives retrieves the alternatives belonging to any of the given vocab IDs, ordered
+// by vocab and then by position. Fetching several vocabs at once avoids a query per vocab when
+// a list of vocab is returned.
+//
+// Parameters:
+// - vocabIDs: The IDs of the Vocab records whose alternatives are wanted.
+//
+// Returns:
+// - A pointer to a slice of the matching alternatives, empty if none are found.
+// - An error if the database connection or query fails.
+func (repo *SQLAlternativeRepository) FindAlternatives(vocabIDs []int) (alternatives *[]mdl.VocabAlternative, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	alternatives = &[]mdl.VocabAlternative{}
+	if len(vocabIDs) == 0 {
+		return
+	}
+
+	err = db.Where("vocab_id IN ?", vocabIDs).Order("vocab_id, position").Find(alternatives).Error
+	if err != nil {
+		log.Printf("Error finding alternatives for %d vocab records: %v", len(vocabIDs), err)
+	}
+
+	return
+}
+
+// CreateAlternative inserts a new VocabAlternative record into the database.
+// Returns an error if the database connection fails or if the insert operation encounters an error.
+func (repo *SQLAlternativeRepository) CreateAlternative(alternative *mdl.VocabAlternative) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	result := db.Create(alternative)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+// UpdateAlternative updates an existing VocabAlternative record in the database.
+// Returns an error if the database connection fails or if the update operation encounters an error.
+func (repo *SQLAlternativeRepository) UpdateAlternative(alternative *mdl.VocabAlternative) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	result := db.Save(alternative)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+// DeleteAlternative removes the VocabAlternative record with the given ID from the database.
+// Returns an error if the database connection fails or if the delete operation encounters an error.
+func (repo *SQLAlternativeRepository) DeleteAlternative(id int) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	result := db.Delete(&mdl.VocabAlternative{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
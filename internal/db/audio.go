@@ -0,0 +1,175 @@
+// Package db defines interfaces and implementations for interacting with
+// entities in the database. It includes the AudioRepository interface, which outlines
+// operations for stored AudioAsset recordings, and the SQLAudioRepository struct, which
+// provides a concrete implementation of the AudioRepository using GORM.
+package db
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"gorm.io/gorm"
+	"log"
+	"time"
+)
+
+// AudioRepository defines the operations available for an AudioAsset entity.
+type AudioRepository interface {
+	FindAudioAssetByID(id int) (*mdl.AudioAsset, error)
+	FindAudioAssetByHash(hash string) (*mdl.AudioAsset, error)
+	FindAudioAssets() (*[]mdl.AudioAsset, error)
+	FindOrphanAudioAssets(createdBefore time.Time) (*[]mdl.AudioAsset, error)
+	CreateAudioAsset(asset *mdl.AudioAsset) error
+	DeleteOrphanAudioAsset(id int) (bool, error)
+}
+
+// SQLAudioRepository provides a GORM-based implementation of the AudioRepository interface.
+type SQLAudioRepository struct {
+	db *gorm.DB
+}
+
+// NewSqlAudioRepository initializes a new SQLAudioRepository with a database connection.
+func NewSqlAudioRepository() (repo *SQLAudioRepository, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	repo = &SQLAudioRepository{db: db}
+
+	return
+}
+
+// FindAudioAssetByID retrieves an audio asset by its primary ID.
+func (repo *SQLAudioRepository) FindAudioAssetByID(id int) (asset *mdl.AudioAsset, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	asset = &mdl.AudioAsset{}
+	if err = db.First(asset, id).Error; err != nil {
+		return nil, fmt.Errorf("error finding audio with id %d, %v", id, err)
+	}
+
+	return
+}
+
+// FindAudioAssetByHash retrieves an audio asset by the hash of its content.
+func (repo *SQLAudioRepository) FindAudioAssetByHash(hash string) (asset *mdl.AudioAsset, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	asset = &mdl.AudioAsset{}
+	if err = db.Where("hash = ?", hash).First(asset).Error; err != nil {
+		return nil, fmt.Errorf("error finding audio with hash %s, %v", hash, err)
+	}
+
+	return
+}
+
+// FindAudioAssets retrieves every audio asset ordered by ID.
+func (repo *SQLAudioRepository) FindAudioAssets() (assets *[]mdl.AudioAsset, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	assets = &[]mdl.AudioAsset{}
+	err = db.Order("id").Find(assets).Error
+	if err != nil {
+		log.Printf("Error finding audio assets: %v", err)
+	}
+
+	return
+}
+
+// orphanAudioSQL matches the audio assets neither a vocab nor a published snapshot refers to,
+// see mdl.SnapshotAudio.
+const orphanAudioSQL = `NOT EXISTS (SELECT 1 FROM palabras.vocab AS v WHERE v.audio_id = audio_asset.id)
+	AND NOT EXISTS (SELECT 1 FROM palabras.snapshot_audio AS sa WHERE sa.audio_id = audio_asset.id)`
+
+// FindOrphanAudioAssets retrieves the audio assets neither a vocab nor a published snapshot
+// refers to, uploaded before a time. The assets may be attached before they are removed, see
+// DeleteOrphanAudioAsset.
+//
+// Parameters:
+// - createdBefore: Assets uploaded at or after this time are left out, so a recording that
+// was just uploaded is not taken for an orphan before it is attached.
+//
+// Returns:
+// - A pointer to a slice of the orphan assets ordered by ID.
+// - An error if the database connection or query fails.
+func (repo *SQLAudioRepository) FindOrphanAudioAssets(createdBefore time.Time) (assets *[]mdl.AudioAsset, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	assets = &[]mdl.AudioAsset{}
+	err = db.Where("created < ?", createdBefore).
+		Where(orphanAudioSQL).
+		Order("id").Find(assets).Error
+	if err != nil {
+		log.Printf("Error finding orphan audio assets: %v", err)
+	}
+
+	return
+}
+
+// CreateAudioAsset inserts a new audio asset, setting its ID.
+func (repo *SQLAudioRepository) CreateAudioAsset(asset *mdl.AudioAsset) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Create(asset).Error
+}
+
+// DeleteOrphanAudioAsset removes an audio asset by its primary ID, only while neither a vocab
+// nor a published snapshot refers to it, so an asset attached or published since it was found
+// to be an orphan is kept.
+//
+// Returns:
+// - Whether the asset was removed, false when it is referenced or does not exist.
+// - An error if the database connection or delete fails.
+func (repo *SQLAudioRepository) DeleteOrphanAudioAsset(id int) (bool, error) {
+	db, err := GetConnection()
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	result := db.Where(orphanAudioSQL).Delete(&mdl.AudioAsset{}, id)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// AddVocabAudioIDIfNotExists adds the audio_id foreign key column to the vocab table, which
+// is not auto migrated since it is shared with other readers.
+//
+// Parameters:
+// - db: A pointer to a gorm.DB instance representing an established database connection.
+//
+// Returns:
+// - An error if the column or its index cannot be added.
+//
+// Note: The audio asset table must be migrated first, since the column references it.
+func AddVocabAudioIDIfNotExists(db *gorm.DB) error {
+	statements := []string{
+		`ALTER TABLE palabras.vocab ADD COLUMN IF NOT EXISTS audio_id integer REFERENCES palabras.audio_asset (id)`,
+		`CREATE INDEX IF NOT EXISTS idx_vocab_audio_id ON palabras.vocab (audio_id)`,
+	}
+
+	for _, sql := range statements {
+		if err := db.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
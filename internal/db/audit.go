@@ -9,16 +9,26 @@
 package db
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/heather92115/verdure-admin/internal/mdl"
 	"gorm.io/gorm"
 	"log"
 )
 
+// auditDiffSQL is the audit diff as jsonb, the expression of the idx_audit_diff index. The
+// diff of a created audit is empty.
+const auditDiffSQL = "NULLIF(diff, '')::jsonb"
+
+// auditVisibleSQL tells whether the transaction of an audit committed before a snapshot was
+// taken, given the text of the snapshot.
+const auditVisibleSQL = "pg_visible_in_snapshot(tx_id::text::xid8, ?::pg_snapshot)"
+
 // AuditRepository defines the operations available for an Audit entity.
 type AuditRepository interface {
 	FindAuditByID(id int) (*mdl.Audit, error)
-	FindAudits(tableName string, objectId int, duration *mdl.Duration, limit int) (audits *[]mdl.Audit, err error)
+	FindAudits(filter *mdl.AuditFilter, limit int) (audits *[]mdl.Audit, err error)
+	FindAuditsBetween(from *mdl.Snapshot, to *mdl.Snapshot, tableNames []string) (*[]mdl.Audit, error)
 	CreateAudit(Audit *mdl.Audit) error
 }
 
@@ -79,30 +89,29 @@ func (repo *SQLAuditRepository) FindAuditByID(id int) (audit *mdl.Audit, err err
 	return
 }
 
-// FindAudits retrieves a list of Audit records filtered by the specified criteria.
-// It allows filtering by table name and a time duration, and limits the number of
-// returned records. This method is useful for fetching audit logs for specific
-// database tables within a certain time frame.
+// FindAudits retrieves a list of Audit records filtered by the specified criteria, newest
+// first. It allows filtering by table name, object, time duration, who made the changes,
+// the action and a field the changes set, and limits the number of returned records. This
+// method is useful for fetching audit logs for investigations, such as every change by a
+// user or every change to a field in the last week.
 //
 // Parameters:
-//   - tableName: The name of the database table for which to retrieve audit records.
-//     If an empty string is provided, audit records for all tables are considered.
-//   - objectId: Primary key used to narrow results to just changes to a single record.
-//     Must be used in conjunction with the table name filter.
-//     If a zero value is provided, audit records for all tables are considered.
-//   - duration: A pointer to a mdl.Duration struct specifying the start and end time
-//     for the time range filter. If nil, no time-based filtering is applied.
+//   - filter: A pointer to a mdl.AuditFilter, each zero valued field matches every audit.
+//     The object ID must be used in conjunction with the table name filter. The changed
+//     field is matched against the keys of the stored diff, which are indexed by
+//     IndexAuditsIfNotExists.
 //   - limit: The maximum number of audit records to retrieve.
 //
 // Returns:
-//   - A pointer to a slice of mdl.Audit structs containing the retrieved audit records.
+//   - A pointer to a slice of mdl.Audit structs containing the retrieved audit records,
+//     ordered by creation time then ID, both descending.
 //     Returns nil if an error occurs during query execution.
 //   - An error if there is an issue establishing a database connection, executing
 //     the query, or applying the specified filters. Returns nil if the query is
 //     successful.
 //
 // Example usage:
-// audits, err := repo.FindAudits("users", &mdl.Duration{Start: startTime, End: endTime}, 10)
+// audits, err := repo.FindAudits(&mdl.AuditFilter{TableName: "vocab", ChangedField: "hint"}, 10)
 //
 //	if err != nil {
 //	    log.Printf("Failed to find audits: %v", err)
@@ -112,7 +121,7 @@ func (repo *SQLAuditRepository) FindAuditByID(id int) (audit *mdl.Audit, err err
 //	        fmt.Println(audit)
 //	    }
 //	}
-func (repo *SQLAuditRepository) FindAudits(tableName string, objectId int, duration *mdl.Duration, limit int) (audits *[]mdl.Audit, err error) {
+func (repo *SQLAuditRepository) FindAudits(filter *mdl.AuditFilter, limit int) (audits *[]mdl.Audit, err error) {
 	db, err := GetConnection()
 	if err != nil {
 		return
@@ -120,32 +129,93 @@ func (repo *SQLAuditRepository) FindAudits(tableName string, objectId int, durat
 
 	audits = &[]mdl.Audit{}
 
-	query := db.Limit(limit)
+	query := db.Order("created DESC, id DESC").Limit(limit)
 
-	if len(tableName) > 0 {
-		query = query.Where("table_name = ?", tableName)
+	if len(filter.TableName) > 0 {
+		query = query.Where("table_name = ?", filter.TableName)
 
-		if objectId > 0 {
-			query = query.Where("object_id = ?", objectId)
+		if filter.ObjectID > 0 {
+			query = query.Where("object_id = ?", filter.ObjectID)
 		}
-	} else if objectId > 0 {
+	} else if filter.ObjectID > 0 {
 		return nil, fmt.Errorf("invalid audit query, objectId requires table name filter")
 	}
 
-	if duration != nil {
-		query = query.Where("created >= ? and created <= ?", duration.Start, duration.End)
+	if filter.Duration != nil {
+		query = query.Where("created >= ? and created <= ?", filter.Duration.Start, filter.Duration.End)
+	}
+
+	if len(filter.CreatedBy) > 0 {
+		query = query.Where("created_by = ?", filter.CreatedBy)
+	}
+
+	if len(filter.Action) > 0 {
+		query = query.Where("action = ?", filter.Action)
+	}
+
+	if len(filter.ChangedField) > 0 {
+		changed, err := json.Marshal([]map[string]string{{"key": fmt.Sprintf("'%s'", filter.ChangedField)}})
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(auditDiffSQL+" @> ?::jsonb", string(changed))
 	}
 
 	// Execute the query
 	err = query.Find(audits).Error
 	if err != nil {
-		log.Printf("Error finding %d Audit records with tableName '%s': %v", limit, tableName, err)
+		log.Printf("Error finding %d Audit records with filter %+v: %v", limit, *filter, err)
 	}
 
 	return
 }
 
-// CreateAudit inserts a new Audit record into the database.
+// FindAuditsBetween retrieves the audits of the tables saved after one snapshot was taken and
+// before another. An audit is saved before a snapshot when its transaction committed before
+// the snapshot content was read, see mdl.Snapshot.TxSnapshot, and otherwise after it. Snapshots
+// without a TxSnapshot fall back to the ID of their last audit.
+//
+// Parameters:
+// - from: The snapshot the audits are saved after, nil for the audits from the first.
+// - to: The snapshot the audits are saved before, nil for the audits up to the last.
+// - tableNames: The tables of the audits, e.g. "vocab".
+//
+// Returns:
+// - A pointer to the slice of audits found, ordered by ID.
+// - An error if the query fails.
+func (repo *SQLAuditRepository) FindAuditsBetween(from *mdl.Snapshot, to *mdl.Snapshot, tableNames []string) (audits *[]mdl.Audit, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	query := db.Where("table_name IN ?", tableNames).Order("id")
+	if from != nil {
+		if len(from.TxSnapshot) > 0 {
+			query = query.Where("NOT "+auditVisibleSQL, from.TxSnapshot)
+		} else {
+			query = query.Where("id > ?", from.AuditID)
+		}
+	}
+	if to != nil {
+		if len(to.TxSnapshot) > 0 {
+			query = query.Where(auditVisibleSQL, to.TxSnapshot)
+		} else {
+			query = query.Where("id <= ?", to.AuditID)
+		}
+	}
+
+	audits = &[]mdl.Audit{}
+	err = query.Find(audits).Error
+	if err != nil {
+		log.Printf("Error finding audits of %v between snapshots: %v", tableNames, err)
+	}
+
+	return
+}
+
+// CreateAudit inserts a new Audit record into the database along with its audit.created
+// outbox event and the deliveries of the webhooks wanting it, in one transaction.
 // It establishes a database connection, then attempts to insert the provided Audit instance.
 // Returns an error if the database connection fails or if the insert operation encounters an error.
 func (repo *SQLAuditRepository) CreateAudit(audit *mdl.Audit) error {
@@ -154,9 +224,92 @@ func (repo *SQLAuditRepository) CreateAudit(audit *mdl.Audit) error {
 		return fmt.Errorf("failed to connect to the db, error: %v", err)
 	}
 
-	result := db.Create(audit)
-	if result.Error != nil {
-		return result.Error
+	return db.Transaction(func(tx *gorm.DB) error {
+		return createAudits(tx, []*mdl.Audit{audit})
+	})
+}
+
+// AuditBuilder builds the audits of a change once the change is written, within its
+// transaction, so they see the IDs the write assigned and are saved along with it. A nil
+// builder audits nothing.
+type AuditBuilder func() ([]*mdl.Audit, error)
+
+// createBuiltAudits builds the audits of a change written within the transaction and inserts
+// them, see createAudits.
+func createBuiltAudits(tx *gorm.DB, audits AuditBuilder) error {
+	if audits == nil {
+		return nil
+	}
+
+	built, err := audits()
+	if err != nil {
+		return err
+	}
+	return createAudits(tx, built)
+}
+
+// createAudits inserts the audits of a change within its transaction, along with their
+// audit.created outbox events and the deliveries of the enabled webhooks wanting them, so a
+// change is never saved without its audits nor its audits without their deliveries.
+func createAudits(tx *gorm.DB, audits []*mdl.Audit) error {
+	if len(audits) == 0 {
+		return nil
+	}
+
+	var webhooks []mdl.Webhook
+	if err := tx.Where("enabled = ?", true).Order("id").Find(&webhooks).Error; err != nil {
+		return fmt.Errorf("failed to find the webhooks, error: %v", err)
+	}
+
+	var events []mdl.OutboxEvent
+	var deliveries []mdl.WebhookDelivery
+	for _, audit := range audits {
+		if err := tx.Create(audit).Error; err != nil {
+			return fmt.Errorf("failed to audit %s %d, error: %v", audit.TableName, audit.ObjectID, err)
+		}
+		events = append(events, mdl.NewOutboxEvent(mdl.AggregateAudit, audit.ID, "created", audit.JSON()))
+
+		queued, err := mdl.NewWebhookDeliveries(webhooks, audit)
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, queued...)
+	}
+
+	if len(deliveries) > 0 {
+		if err := tx.Create(&deliveries).Error; err != nil {
+			return fmt.Errorf("failed to queue the webhook deliveries, error: %v", err)
+		}
+	}
+
+	return createOutboxEvents(tx, events)
+}
+
+// IndexAuditsIfNotExists prepares the audit table for the audit queries. It sets the action
+// of the audits saved before actions were recorded, from whether they have a before and
+// after state, and creates the GIN index on the keys of the diff the changed field filter
+// of FindAudits uses. The indexes on who made the changes and the action are declared on
+// mdl.Audit. Backfilling only touches audits without an action, so it is cheap to run on
+// every start.
+//
+// Parameters:
+// - db: A pointer to a gorm.DB instance representing an established database connection.
+//
+// Returns:
+// - An error if the backfill or the index fails.
+//
+// Note: The audit table must be migrated first, since the backfill sets its action column.
+func IndexAuditsIfNotExists(db *gorm.DB) error {
+	statements := []string{
+		`UPDATE palabras.audit SET action = CASE WHEN before = '' THEN 'created' WHEN after = '' THEN 'deleted'
+			ELSE 'updated' END WHERE action = ''`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_diff ON palabras.audit USING GIN ((` + auditDiffSQL + `) jsonb_path_ops)`,
+	}
+
+	for _, sql := range statements {
+		if err := db.Exec(sql).Error; err != nil {
+			return err
+		}
 	}
 
 	return nil
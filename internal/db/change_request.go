@@ -0,0 +1,163 @@
+// Package db defines interfaces and implementations for interacting with
+// entities in the database. It includes the ChangeRequestRepository interface, which outlines
+// operations for the vocab changes proposed for review, and the SQLChangeRequestRepository
+// struct, which provides a concrete implementation of the ChangeRequestRepository using GORM.
+package db
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"gorm.io/gorm"
+	"log"
+)
+
+// ChangeRequestRepository defines the operations available for ChangeRequest entities.
+type ChangeRequestRepository interface {
+	FindChangeRequestByID(id int) (*mdl.ChangeRequest, error)
+	FindChangeRequests(vocabID int, status string, limit int) (*[]mdl.ChangeRequest, error)
+	CreateChangeRequest(request *mdl.ChangeRequest) error
+	ReviewChangeRequest(request *mdl.ChangeRequest, audits AuditBuilder) error
+	ApproveChangeRequest(request *mdl.ChangeRequest, vocab *mdl.Vocab, audits AuditBuilder) error
+}
+
+// SQLChangeRequestRepository provides a GORM-based implementation of the ChangeRequestRepository interface.
+type SQLChangeRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewSqlChangeRequestRepository initializes a new SQLChangeRequestRepository with a database connection.
+func NewSqlChangeRequestRepository() (repo *SQLChangeRequestRepository, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	repo = &SQLChangeRequestRepository{db: db}
+
+	return
+}
+
+// FindChangeRequestByID retrieves a change request by its primary ID.
+func (repo *SQLChangeRequestRepository) FindChangeRequestByID(id int) (request *mdl.ChangeRequest, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	request = &mdl.ChangeRequest{}
+	if err = db.First(request, id).Error; err != nil {
+		return nil, fmt.Errorf("error finding change request with id %d, %v", id, err)
+	}
+
+	return
+}
+
+// FindChangeRequests retrieves the change requests of a vocab with a status, newest first.
+//
+// Parameters:
+// - vocabID: The primary ID of the vocab, or 0 for the change requests of every vocab.
+// - status: The review status, e.g. mdl.ChangePending, or empty for every status.
+// - limit: The maximum number of change requests returned.
+//
+// Returns:
+// - A pointer to the slice of change requests found.
+// - An error if the query fails.
+func (repo *SQLChangeRequestRepository) FindChangeRequests(vocabID int, status string, limit int) (list *[]mdl.ChangeRequest, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	query := db.Order("id DESC").Limit(limit)
+	if vocabID > 0 {
+		query = query.Where("vocab_id = ?", vocabID)
+	}
+	if len(status) > 0 {
+		query = query.Where("status = ?", status)
+	}
+
+	list = &[]mdl.ChangeRequest{}
+	err = query.Find(list).Error
+	if err != nil {
+		log.Printf("Error finding change requests: %v", err)
+	}
+
+	return
+}
+
+// CreateChangeRequest inserts a new change request, setting its ID.
+func (repo *SQLChangeRequestRepository) CreateChangeRequest(request *mdl.ChangeRequest) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Create(request).Error
+}
+
+// ReviewChangeRequest saves the review of a change request along with its audits, in one
+// transaction. The review is only saved while the change request is still pending, so of two
+// reviews of the same change request only the first is saved.
+//
+// Parameters:
+// - request: The change request with its review status, comments, reviewer and time set.
+// - audits: Builds the audits of the review, see AuditBuilder.
+//
+// Returns:
+// - An error if the change request is no longer pending or saving fails, in which case nothing is saved.
+func (repo *SQLChangeRequestRepository) ReviewChangeRequest(request *mdl.ChangeRequest, audits AuditBuilder) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := reviewChangeRequest(tx, request); err != nil {
+			return err
+		}
+		return createBuiltAudits(tx, audits)
+	})
+}
+
+// ApproveChangeRequest saves the approval of a change request, the vocab it changes with its
+// vocab.updated outbox event, and the audits of both, in one transaction, so a change request
+// is never approved without its change nor its change applied without the approval. As for
+// ReviewChangeRequest, the approval is only saved while the change request is still pending.
+//
+// Parameters:
+// - request: The change request with its approval set.
+// - vocab: The vocab with the change applied.
+// - audits: Builds the audits of the change and the approval, see AuditBuilder.
+//
+// Returns:
+// - An error if the change request is no longer pending or saving fails, in which case nothing is saved.
+func (repo *SQLChangeRequestRepository) ApproveChangeRequest(request *mdl.ChangeRequest, vocab *mdl.Vocab, audits AuditBuilder) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := reviewChangeRequest(tx, request); err != nil {
+			return err
+		}
+		if err := updateVocab(tx, vocab); err != nil {
+			return err
+		}
+		return createBuiltAudits(tx, audits)
+	})
+}
+
+// reviewChangeRequest saves the review fields of a change request within a transaction when it
+// is still pending, failing when it is not.
+func reviewChangeRequest(tx *gorm.DB, request *mdl.ChangeRequest) error {
+	result := tx.Model(request).Where("status = ?", mdl.ChangePending).
+		Select("status", "review_comments", "reviewed_by", "reviewed").Updates(request)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("change request %d is no longer pending", request.ID)
+	}
+	return nil
+}
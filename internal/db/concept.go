@@ -0,0 +1,228 @@
+// Package db defines interfaces and implementations for interacting with
+// entities in the database. It includes the ConceptRepository interface, which outlines
+// operations for the Concept records grouping vocab translations, and the
+// SQLConceptRepository struct, which provides a concrete implementation of the
+// ConceptRepository using GORM.
+package db
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"gorm.io/gorm"
+	"log"
+)
+
+// ConceptRepository defines the operations available for a Concept entity.
+type ConceptRepository interface {
+	FindConceptByID(id int) (*mdl.Concept, error)
+	FindConceptVocabs(conceptID int) (*[]mdl.Vocab, error)
+	CreateConcept(concept *mdl.Concept) error
+	CreateConceptWithVocab(concept *mdl.Concept, vocab *mdl.Vocab, audits AuditBuilder) error
+	UpdateConcept(concept *mdl.Concept) error
+}
+
+// SQLConceptRepository provides a GORM-based implementation of the ConceptRepository interface.
+type SQLConceptRepository struct {
+	db *gorm.DB
+}
+
+// NewSqlConceptRepository initializes a new SQLConceptRepository with a database connection.
+func NewSqlConceptRepository() (repo *SQLConceptRepository, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	repo = &SQLConceptRepository{db: db}
+
+	return
+}
+
+// FindConceptByID retrieves a concept by its primary ID.
+func (repo *SQLConceptRepository) FindConceptByID(id int) (concept *mdl.Concept, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	concept = &mdl.Concept{}
+	if err = db.First(concept, id).Error; err != nil {
+		return nil, fmt.Errorf("error finding concept with id %d, %v", id, err)
+	}
+
+	return
+}
+
+// FindConceptVocabs retrieves the vocab belonging to a concept, its translations, ordered by
+// learning language code then learning lang.
+//
+// Parameters:
+// - conceptID: The primary ID of the concept.
+//
+// Returns:
+// - A pointer to a slice of the vocab of the concept, empty when it has none.
+// - An error if the database connection or query fails.
+func (repo *SQLConceptRepository) FindConceptVocabs(conceptID int) (vocabs *[]mdl.Vocab, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	vocabs = &[]mdl.Vocab{}
+	err = db.Where("concept_id = ?", conceptID).Order("learning_lang_code, learning_lang").Find(vocabs).Error
+	if err != nil {
+		log.Printf("Error finding vocab of concept %d: %v", conceptID, err)
+	}
+
+	return
+}
+
+// CreateConcept inserts a new concept, setting its ID.
+func (repo *SQLConceptRepository) CreateConcept(concept *mdl.Concept) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Create(concept).Error
+}
+
+// CreateConceptWithVocab inserts a new concept and a new vocab as its first translation, along
+// with the vocab.created outbox event and the audits of both, in one transaction, so a vocab
+// that cannot be created leaves no concept behind. The IDs of both are set, and the concept
+// ID of the vocab.
+func (repo *SQLConceptRepository) CreateConceptWithVocab(concept *mdl.Concept, vocab *mdl.Vocab, audits AuditBuilder) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(concept).Error; err != nil {
+			return err
+		}
+
+		vocab.ConceptID = &concept.ID
+		return createVocab(tx, vocab, audits)
+	})
+}
+
+// UpdateConcept saves every field of an existing concept.
+func (repo *SQLConceptRepository) UpdateConcept(concept *mdl.Concept) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Save(concept).Error
+}
+
+// AddVocabConceptIDIfNotExists adds the concept_id foreign key column to the vocab table, which
+// is not auto migrated since it is shared with other readers.
+//
+// Parameters:
+// - db: A pointer to a gorm.DB instance representing an established database connection.
+//
+// Returns:
+// - An error if the column or its index cannot be added.
+//
+// Note: The concept table must be migrated first, since the column references it.
+func AddVocabConceptIDIfNotExists(db *gorm.DB) error {
+	statements := []string{
+		`ALTER TABLE palabras.vocab ADD COLUMN IF NOT EXISTS concept_id integer REFERENCES palabras.concept (id)`,
+		`CREATE INDEX IF NOT EXISTS idx_vocab_concept_id ON palabras.vocab (concept_id)`,
+	}
+
+	for _, sql := range statements {
+		if err := db.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WrapVocabsInConcepts gives every vocab without a concept a concept of its own, glossed by
+// its first lang in its known language. The vocab created before concepts existed are each
+// the only translation of their concept, they are grouped across languages afterward. Each
+// batch is wrapped in its own transaction so an interrupted migration resumes where it stopped.
+//
+// Parameters:
+// - db: A pointer to a gorm.DB instance representing an established database connection.
+// - batchSize: The number of vocab wrapped in each transaction.
+//
+// Returns:
+// - The number of vocab wrapped.
+// - An error if the vocab cannot be read or a concept cannot be created or linked.
+func WrapVocabsInConcepts(db *gorm.DB, batchSize int) (wrapped int, err error) {
+	for {
+		var vocabs []mdl.Vocab
+		err = db.Select("id", "first_lang", "known_lang_code", "created").
+			Where("concept_id IS NULL").Order("id").Limit(batchSize).Find(&vocabs).Error
+		if err != nil || len(vocabs) == 0 {
+			return
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			for _, vocab := range vocabs {
+				concept := &mdl.Concept{
+					Gloss:         vocab.FirstLang,
+					GlossLangCode: vocab.KnownLangCode,
+					CreatedBy:     "migration",
+					Created:       vocab.Created,
+				}
+				if err := tx.Create(concept).Error; err != nil {
+					return err
+				}
+				err := tx.Model(&mdl.Vocab{}).Where("id = ?", vocab.ID).Update("concept_id", concept.ID).Error
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return
+		}
+
+		wrapped += len(vocabs)
+	}
+}
+
+// ScopeVocabLearningLangToLanguage replaces the unique constraint on the vocab learning_lang
+// with a unique index on the learning_lang within each learning_lang_code, since languages
+// share words, e.g. casa is Spanish and Portuguese. The constraint was created along with the
+// table so its name is looked up, and it is only dropped once the new index exists.
+//
+// Parameters:
+// - db: A pointer to a gorm.DB instance representing an established database connection.
+//
+// Returns:
+// - An error if the index cannot be created or the constraint dropped.
+func ScopeVocabLearningLangToLanguage(db *gorm.DB) error {
+	statements := []string{
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_vocab_learning_lang_code
+			ON palabras.vocab (learning_lang_code, learning_lang)`,
+		`DO $$
+		DECLARE
+			con_name text;
+		BEGIN
+			FOR con_name IN
+				SELECT con.conname FROM pg_constraint AS con
+				WHERE con.conrelid = 'palabras.vocab'::regclass AND con.contype = 'u'
+				AND con.conkey = ARRAY[(SELECT attnum FROM pg_attribute
+					WHERE attrelid = 'palabras.vocab'::regclass AND attname = 'learning_lang')]::int2[]
+			LOOP
+				EXECUTE format('ALTER TABLE palabras.vocab DROP CONSTRAINT %I', con_name);
+			END LOOP;
+		END$$;`,
+	}
+
+	for _, sql := range statements {
+		if err := db.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+// Package db defines interfaces and implementations for interacting with
+// entities in the database. It includes the ConjugationRepository interface, which outlines
+// operations for the Conjugation tables of verb Vocab records, and the SQLConjugationRepository
+// struct, which provides a concrete implementation of the ConjugationRepository using GORM.
+package db
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"gorm.io/gorm"
+	"log"
+)
+
+// ConjugationRepository defines the operations available for a Conjugation entity.
+type ConjugationRepository interface {
+	FindConjugations(vocabID int) (*[]mdl.Conjugation, error)
+	SaveConjugations(conjugations []mdl.Conjugation) error
+}
+
+// SQLConjugationRepository provides a GORM-based implementation of the ConjugationRepository interface.
+type SQLConjugationRepository struct {
+	db *gorm.DB
+}
+
+// NewSqlConjugationRepository initializes a new SQLConjugationRepository with a database connection.
+func NewSqlConjugationRepository() (repo *SQLConjugationRepository, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	repo = &SQLConjugationRepository{db: db}
+
+	return
+}
+
+// FindConjugations retrieves the conjugation table of a vocab, in insert order.
+//
+// Parameters:
+// - vocabID: The ID of the Vocab record whose conjugations are wanted.
+//
+// Returns:
+// - A pointer to a slice of the forms, empty if the vocab has none.
+// - An error if the database connection or query fails.
+func (repo *SQLConjugationRepository) FindConjugations(vocabID int) (conjugations *[]mdl.Conjugation, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	conjugations = &[]mdl.Conjugation{}
+	err = db.Where("vocab_id = ?", vocabID).Order("id").Find(conjugations).Error
+	if err != nil {
+		log.Printf("Error finding conjugations for vocab %d: %v", vocabID, err)
+	}
+
+	return
+}
+
+// SaveConjugations inserts the new forms and updates the existing ones, those with an ID,
+// of a conjugation table in a single transaction.
+//
+// Parameters:
+// - conjugations: The forms to save, the IDs of new forms are set on success.
+//
+// Returns:
+// - An error if the database connection fails or any statement fails, in which case nothing is saved.
+func (repo *SQLConjugationRepository) SaveConjugations(conjugations []mdl.Conjugation) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for i := range conjugations {
+			if err := tx.Save(&conjugations[i]).Error; err != nil {
+				return fmt.Errorf("failed to save conjugation %s %s of vocab %d, error: %v",
+					conjugations[i].Tense, conjugations[i].Person, conjugations[i].VocabID, err)
+			}
+		}
+		return nil
+	})
+}
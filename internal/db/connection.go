@@ -121,6 +121,55 @@ func CreateEnumIfNotExists(db *gorm.DB) error {
 	return db.Exec(sql).Error
 }
 
+// AddVocabGrammarColumnsIfNotExists adds the grammatical metadata columns, gender, plural,
+// article and register, to the vocab table, which is not auto migrated since it is shared
+// with other readers. The columns default to empty text so existing rows and readers that
+// do not know them are unaffected.
+//
+// Parameters:
+// - db: A pointer to a gorm.DB instance representing an established database connection.
+//
+// Returns:
+// - An error if any of the columns cannot be added.
+func AddVocabGrammarColumnsIfNotExists(db *gorm.DB) error {
+	for _, column := range []string{"gender", "plural", "article", "register"} {
+		sql := fmt.Sprintf(`ALTER TABLE palabras.vocab ADD COLUMN IF NOT EXISTS %s text NOT NULL DEFAULT ''`, column)
+		if err := db.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateVocabNormalizedIndexIfNotExists creates a unique index on the normalized form of the
+// vocab learning_lang within each learning_lang_code, see NormalizedLearningLangSQL. This keeps
+// visually identical learning langs, such as NFC and NFD accents or non-breaking spaces, from
+// being stored twice for a language. The index replaces idx_vocab_learning_lang_norm, which
+// was unique across languages, and that index is dropped once it exists.
+//
+// Creating the index fails while existing rows collide after normalization. Rather than stop
+// the application, the failure is logged so the collisions can be listed with the normreport
+// command and resolved, the index is then created on the next start.
+//
+// Parameters:
+// - db: A pointer to a gorm.DB instance representing an established database connection.
+//
+// Note: The normalize function requires PostgreSQL 13 or later with a UTF8 database.
+func CreateVocabNormalizedIndexIfNotExists(db *gorm.DB) {
+	sql := fmt.Sprintf(`CREATE UNIQUE INDEX IF NOT EXISTS idx_vocab_learning_lang_code_norm
+		ON palabras.vocab (learning_lang_code, (%s))`, NormalizedLearningLangSQL)
+
+	if err := db.Exec(sql).Error; err != nil {
+		log.Printf("Unable to create the normalized learning lang index, run cmd/normreport to find collisions: %v", err)
+		return
+	}
+
+	if err := db.Exec(`DROP INDEX IF EXISTS palabras.idx_vocab_learning_lang_norm`).Error; err != nil {
+		log.Printf("Unable to drop the normalized learning lang index shared by all languages: %v", err)
+	}
+}
+
 // MigrateTables performs the necessary database migrations to ensure that the schema
 // matches the expected structure defined by the internal models. This function is
 // typically called during application initialization to prepare the database for use.
@@ -130,11 +179,34 @@ func CreateEnumIfNotExists(db *gorm.DB) error {
 //     creating it if necessary. This ENUM is used by certain table columns.
 //  2. Automatically migrating the database schema to match the structure of the Fixit model.
 //  3. Automatically migrating the database schema to match the structure of the Audit model.
+//  4. Automatically migrating the database schema to match the structure of the VocabAlternative model.
+//  5. Automatically migrating the database schema to match the structure of the VocabArchive model.
+//  6. Automatically migrating the PartOfSpeech and Skill lookup tables, and seeding the default
+//     parts of speech when the table is empty.
+//  7. Automatically migrating the database schema to match the structure of the Conjugation and
+//     ExampleSentence models.
+//  8. Adding the skill_id foreign key column to the vocab table and linking existing vocab
+//     to the managed skills they name.
+//  9. Adding the gender, plural, article and register columns to the vocab table.
+//  10. Automatically migrating the AudioAsset table and adding the audio_id foreign key
+//     column to the vocab table.
+//  11. Automatically migrating the Concept table, adding the concept_id foreign key column to
+//     the vocab table, and wrapping each vocab without a concept in a concept of its own.
+//  12. Scoping the unique vocab learning lang to its learning language code.
+//  13. Automatically migrating the Language table and seeding the default languages, along
+//     with the other language codes used by vocab, when the table is empty.
+//  14. Automatically migrating the Webhook and WebhookDelivery tables.
+//  15. Automatically migrating the OutboxEvent table.
+//  16. Automatically migrating the Snapshot and SnapshotAudio tables.
+//  17. Automatically migrating the ChangeRequest table.
+//  18. Backfilling the action of existing audits and creating the index on the audit diff.
+//  19. Creating the unique index on the normalized vocab learning lang, when no rows collide.
 //
 // Note: This function presumes that the 'vocab' table already exists in the database
 // and that its schema matches the structure defined by the internal models. It does not
-// perform migration for the 'vocab' table. Ensure that any changes to the vocab model
-// are manually reflected in the database or through separate migration scripts.
+// auto migrate the 'vocab' table, columns are only added to it explicitly, as in
+// AddVocabSkillIDIfNotExists. Ensure that any other changes to the vocab model are
+// manually reflected in the database or through separate migration scripts.
 //
 // Returns:
 //   - An error if any part of the migration process fails, otherwise nil if all migrations
@@ -166,5 +238,109 @@ func MigrateTables() (err error) {
 		return err
 	}
 
+	err = globalDb.AutoMigrate(mdl.VocabAlternative{})
+	if err != nil {
+		return err
+	}
+
+	err = globalDb.AutoMigrate(mdl.VocabArchive{})
+	if err != nil {
+		return err
+	}
+
+	err = globalDb.AutoMigrate(mdl.PartOfSpeech{}, mdl.Skill{})
+	if err != nil {
+		return err
+	}
+
+	err = SeedPartsOfSpeechIfEmpty(globalDb)
+	if err != nil {
+		return err
+	}
+
+	err = globalDb.AutoMigrate(mdl.Conjugation{}, mdl.ExampleSentence{})
+	if err != nil {
+		return err
+	}
+
+	err = AddVocabSkillIDIfNotExists(globalDb)
+	if err != nil {
+		return err
+	}
+
+	err = AddVocabGrammarColumnsIfNotExists(globalDb)
+	if err != nil {
+		return err
+	}
+
+	err = globalDb.AutoMigrate(mdl.AudioAsset{})
+	if err != nil {
+		return err
+	}
+
+	err = AddVocabAudioIDIfNotExists(globalDb)
+	if err != nil {
+		return err
+	}
+
+	err = globalDb.AutoMigrate(mdl.Concept{})
+	if err != nil {
+		return err
+	}
+
+	err = AddVocabConceptIDIfNotExists(globalDb)
+	if err != nil {
+		return err
+	}
+
+	wrapped, err := WrapVocabsInConcepts(globalDb, 500)
+	if err != nil {
+		return err
+	} else if wrapped > 0 {
+		log.Printf("Wrapped %d vocab in concepts of their own", wrapped)
+	}
+
+	err = ScopeVocabLearningLangToLanguage(globalDb)
+	if err != nil {
+		return err
+	}
+
+	err = globalDb.AutoMigrate(mdl.Language{})
+	if err != nil {
+		return err
+	}
+
+	err = SeedLanguagesIfEmpty(globalDb)
+	if err != nil {
+		return err
+	}
+
+	err = globalDb.AutoMigrate(mdl.Webhook{}, mdl.WebhookDelivery{})
+	if err != nil {
+		return err
+	}
+
+	err = globalDb.AutoMigrate(mdl.OutboxEvent{})
+	if err != nil {
+		return err
+	}
+
+	err = globalDb.AutoMigrate(mdl.Snapshot{}, mdl.SnapshotAudio{})
+	if err != nil {
+		return err
+	}
+
+	err = globalDb.AutoMigrate(mdl.ChangeRequest{})
+	if err != nil {
+		return err
+	}
+
+	err = IndexAuditsIfNotExists(globalDb)
+	if err != nil {
+		return err
+	}
+
+	CreateVocabNormalizedIndexIfNotExists(globalDb)
+
 	return
 }
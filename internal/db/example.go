@@ -0,0 +1,130 @@
+// Package db defines interfaces and implementations for interacting with
+// entities in the database. It includes the ExampleRepository interface, which outlines
+// operations for the ExampleSentence records of a Vocab, and the SQLExampleRepository
+// struct, which provides a concrete implementation of the ExampleRepository using GORM.
+package db
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"gorm.io/gorm"
+	"log"
+)
+
+// ExampleRepository defines the operations available for an ExampleSentence entity.
+type ExampleRepository interface {
+	FindExampleSentenceByID(id int) (*mdl.ExampleSentence, error)
+	FindExampleSentences(vocabID int) (*[]mdl.ExampleSentence, error)
+	CreateExampleSentence(example *mdl.ExampleSentence, audits AuditBuilder) error
+	UpdateExampleSentence(example *mdl.ExampleSentence, audits AuditBuilder) error
+	DeleteExampleSentence(id int, audits AuditBuilder) error
+}
+
+// SQLExampleRepository provides a GORM-based implementation of the ExampleRepository interface.
+type SQLExampleRepository struct {
+	db *gorm.DB
+}
+
+// NewSqlExampleRepository initializes a new SQLExampleRepository with a database connection.
+func NewSqlExampleRepository() (repo *SQLExampleRepository, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	repo = &SQLExampleRepository{db: db}
+
+	return
+}
+
+// FindExampleSentenceByID retrieves an example sentence by its primary ID.
+//
+// Parameters:
+// - id: The primary ID of the ExampleSentence.
+//
+// Returns:
+// - A pointer to the found ExampleSentence.
+// - An error if the database connection fails or no sentence has the ID.
+func (repo *SQLExampleRepository) FindExampleSentenceByID(id int) (example *mdl.ExampleSentence, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	example = &mdl.ExampleSentence{}
+	if err = db.First(example, id).Error; err != nil {
+		return nil, fmt.Errorf("error finding example sentence with id %d, %v", id, err)
+	}
+
+	return
+}
+
+// FindExampleSentences retrieves the example sentences of a vocab, in insert order.
+//
+// Parameters:
+// - vocabID: The ID of the Vocab record whose sentences are wanted.
+//
+// Returns:
+// - A pointer to a slice of the sentences, empty if the vocab has none.
+// - An error if the database connection or query fails.
+func (repo *SQLExampleRepository) FindExampleSentences(vocabID int) (examples *[]mdl.ExampleSentence, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	examples = &[]mdl.ExampleSentence{}
+	err = db.Where("vocab_id = ?", vocabID).Order("id").Find(examples).Error
+	if err != nil {
+		log.Printf("Error finding example sentences for vocab %d: %v", vocabID, err)
+	}
+
+	return
+}
+
+// CreateExampleSentence inserts a new example sentence along with its audits, in one
+// transaction, setting its ID. The audits are built once it has its ID, see AuditBuilder.
+func (repo *SQLExampleRepository) CreateExampleSentence(example *mdl.ExampleSentence, audits AuditBuilder) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(example).Error; err != nil {
+			return err
+		}
+		return createBuiltAudits(tx, audits)
+	})
+}
+
+// UpdateExampleSentence saves an existing example sentence along with its audits, in one transaction.
+func (repo *SQLExampleRepository) UpdateExampleSentence(example *mdl.ExampleSentence, audits AuditBuilder) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(example).Error; err != nil {
+			return err
+		}
+		return createBuiltAudits(tx, audits)
+	})
+}
+
+// DeleteExampleSentence removes an example sentence by its primary ID along with its audits,
+// in one transaction.
+func (repo *SQLExampleRepository) DeleteExampleSentence(id int, audits AuditBuilder) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&mdl.ExampleSentence{}, id).Error; err != nil {
+			return err
+		}
+		return createBuiltAudits(tx, audits)
+	})
+}
@@ -23,6 +23,7 @@ type FixitRepository interface {
 		vocabID int,
 		duration *mdl.Duration,
 		limit int) (fixits *[]mdl.Fixit, err error)
+	FindFixitsForVocabs(vocabIDs []int) (*[]mdl.Fixit, error)
 
 	CreateFixit(Fixit *mdl.Fixit) error
 	UpdateFixit(fixit *mdl.Fixit) error
@@ -145,7 +146,36 @@ func (repo *SQLFixitRepository) FindFixits(
 	return
 }
 
-// CreateFixit inserts a new Fixit record into the database.
+// FindFixitsForVocabs retrieves every Fixit, whatever its status, belonging to any of the
+// given vocab IDs, ordered by their primary ID.
+//
+// Parameters:
+// - vocabIDs: The IDs of the Vocab records whose fixits are wanted.
+//
+// Returns:
+// - A pointer to a slice of the matching Fixits, empty if none are found.
+// - An error if the database connection or query fails.
+func (repo *SQLFixitRepository) FindFixitsForVocabs(vocabIDs []int) (fixits *[]mdl.Fixit, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	fixits = &[]mdl.Fixit{}
+	if len(vocabIDs) == 0 {
+		return
+	}
+
+	err = db.Where("vocab_id IN ?", vocabIDs).Order("id").Find(fixits).Error
+	if err != nil {
+		log.Printf("Error finding Fixit records for vocab ids %v: %v", vocabIDs, err)
+	}
+
+	return
+}
+
+// CreateFixit inserts a new Fixit record into the database along with its fixit.created
+// outbox event, in one transaction.
 // It establishes a database connection, then attempts to insert the provided Fixit instance.
 // Returns an error if the database connection fails or if the insert operation encounters an error.
 func (repo *SQLFixitRepository) CreateFixit(fixit *mdl.Fixit) error {
@@ -154,15 +184,15 @@ func (repo *SQLFixitRepository) CreateFixit(fixit *mdl.Fixit) error {
 		return fmt.Errorf("failed to connect to the db, error: %v", err)
 	}
 
-	result := db.Create(fixit)
-	if result.Error != nil {
-		return result.Error
-	}
-
-	return nil
+	return saveWithOutbox(db, func(tx *gorm.DB) error {
+		return tx.Create(fixit).Error
+	}, func() []mdl.OutboxEvent {
+		return []mdl.OutboxEvent{mdl.NewOutboxEvent(mdl.AggregateFixit, fixit.ID, "created", fixit.JSON())}
+	})
 }
 
-// UpdateFixit updates an existing Fixit record into the database.
+// UpdateFixit updates an existing Fixit record into the database along with its fixit.updated
+// outbox event, in one transaction.
 // It establishes a database connection, then attempts to find and update the provided Fixit instance.
 // Returns an error if the database connection fails or if the update operation encounters an error.
 func (repo *SQLFixitRepository) UpdateFixit(fixit *mdl.Fixit) error {
@@ -171,10 +201,9 @@ func (repo *SQLFixitRepository) UpdateFixit(fixit *mdl.Fixit) error {
 		return fmt.Errorf("failed to connect to the db, error: %v", err)
 	}
 
-	result := db.Save(fixit)
-	if result.Error != nil {
-		return result.Error
-	}
-
-	return nil
+	return saveWithOutbox(db, func(tx *gorm.DB) error {
+		return tx.Save(fixit).Error
+	}, func() []mdl.OutboxEvent {
+		return []mdl.OutboxEvent{mdl.NewOutboxEvent(mdl.AggregateFixit, fixit.ID, "updated", fixit.JSON())}
+	})
 }
@@ -0,0 +1,131 @@
+// Package db defines interfaces and implementations for interacting with
+// entities in the database. It includes the LanguageRepository interface, which outlines
+// operations for the managed Language table, and the SQLLanguageRepository struct, which
+// provides a concrete implementation of the LanguageRepository using GORM.
+package db
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"gorm.io/gorm"
+	"log"
+)
+
+// LanguageRepository defines the operations available for a Language entity.
+type LanguageRepository interface {
+	FindLanguages() (*[]mdl.Language, error)
+	FindLanguageByID(id int) (*mdl.Language, error)
+	CreateLanguage(language *mdl.Language) error
+	UpdateLanguage(language *mdl.Language) error
+}
+
+// SQLLanguageRepository provides a GORM-based implementation of the LanguageRepository interface.
+type SQLLanguageRepository struct {
+	db *gorm.DB
+}
+
+// NewSqlLanguageRepository initializes a new SQLLanguageRepository with a database connection.
+func NewSqlLanguageRepository() (repo *SQLLanguageRepository, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	repo = &SQLLanguageRepository{db: db}
+
+	return
+}
+
+// FindLanguages retrieves every managed language, ordered by code.
+func (repo *SQLLanguageRepository) FindLanguages() (list *[]mdl.Language, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	list = &[]mdl.Language{}
+	err = db.Order("code").Find(list).Error
+	if err != nil {
+		log.Printf("Error finding languages: %v", err)
+	}
+
+	return
+}
+
+// FindLanguageByID retrieves a managed language by its primary ID.
+func (repo *SQLLanguageRepository) FindLanguageByID(id int) (language *mdl.Language, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	language = &mdl.Language{}
+	if err = db.First(language, id).Error; err != nil {
+		return nil, fmt.Errorf("error finding language with id %d, %v", id, err)
+	}
+
+	return
+}
+
+// CreateLanguage inserts a new managed language, setting its ID.
+func (repo *SQLLanguageRepository) CreateLanguage(language *mdl.Language) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Create(language).Error
+}
+
+// UpdateLanguage saves every field of an existing managed language.
+func (repo *SQLLanguageRepository) UpdateLanguage(language *mdl.Language) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Save(language).Error
+}
+
+// SeedLanguagesIfEmpty loads mdl.DefaultLanguages into the language table when it has no
+// rows, along with every other language code already used by vocab, so existing vocab keep
+// validating once codes are checked against the managed languages. The other codes are named
+// after themselves and keep the default settings until they are edited.
+//
+// Parameters:
+// - db: A pointer to a gorm.DB instance representing an established database connection.
+//
+// Returns:
+// - An error if the tables cannot be read or the languages cannot be inserted.
+func SeedLanguagesIfEmpty(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&mdl.Language{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	languages := make([]mdl.Language, len(mdl.DefaultLanguages))
+	copy(languages, mdl.DefaultLanguages)
+
+	seeded := make(map[string]bool)
+	for _, language := range languages {
+		seeded[language.Code] = true
+	}
+
+	var used []string
+	err := db.Raw(`SELECT known_lang_code FROM palabras.vocab UNION SELECT learning_lang_code FROM palabras.vocab`).
+		Scan(&used).Error
+	if err != nil {
+		return err
+	}
+	for _, code := range used {
+		if len(code) > 0 && !seeded[code] {
+			seeded[code] = true
+			languages = append(languages, mdl.Language{Code: code, Name: code, Direction: "ltr", Enabled: true})
+		}
+	}
+
+	return db.Create(&languages).Error
+}
@@ -0,0 +1,287 @@
+// Package db defines interfaces and implementations for interacting with
+// entities in the database. It includes the LookupRepository interface, which outlines
+// operations for the managed PartOfSpeech and Skill lookup tables, and the SQLLookupRepository
+// struct, which provides a concrete implementation of the LookupRepository using GORM.
+package db
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"gorm.io/gorm"
+	"log"
+)
+
+// LookupRepository defines the operations available for the PartOfSpeech and Skill lookup tables.
+type LookupRepository interface {
+	FindPartsOfSpeech() (*[]mdl.PartOfSpeech, error)
+	FindPartOfSpeechByID(id int) (*mdl.PartOfSpeech, error)
+	CreatePartOfSpeech(pos *mdl.PartOfSpeech) error
+	UpdatePartOfSpeech(pos *mdl.PartOfSpeech) error
+	DeletePartOfSpeech(id int) error
+
+	FindSkills() (*[]mdl.Skill, error)
+	FindSkillByID(id int) (*mdl.Skill, error)
+	CreateSkill(skill *mdl.Skill) error
+	UpdateSkill(skill *mdl.Skill) error
+	DeleteSkill(id int) error
+
+	CountVocabValues(field string) (*[]mdl.TermCount, error)
+	CountVocabSkillIDs() (*[]mdl.SkillCount, error)
+}
+
+// vocabTermFields are the vocab columns whose values can be counted with CountVocabValues.
+var vocabTermFields = map[string]bool{
+	"pos":   true,
+	"skill": true,
+}
+
+// SQLLookupRepository provides a GORM-based implementation of the LookupRepository interface.
+type SQLLookupRepository struct {
+	db *gorm.DB
+}
+
+// NewSqlLookupRepository initializes a new SQLLookupRepository with a database connection.
+func NewSqlLookupRepository() (repo *SQLLookupRepository, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	repo = &SQLLookupRepository{db: db}
+
+	return
+}
+
+// FindPartsOfSpeech retrieves every managed part of speech, ordered by name.
+func (repo *SQLLookupRepository) FindPartsOfSpeech() (list *[]mdl.PartOfSpeech, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	list = &[]mdl.PartOfSpeech{}
+	err = db.Order("name").Find(list).Error
+	if err != nil {
+		log.Printf("Error finding parts of speech: %v", err)
+	}
+
+	return
+}
+
+// FindPartOfSpeechByID retrieves a managed part of speech by its primary ID.
+func (repo *SQLLookupRepository) FindPartOfSpeechByID(id int) (pos *mdl.PartOfSpeech, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	pos = &mdl.PartOfSpeech{}
+	err = db.First(pos, id).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding part of speech with id %d, %v", id, err)
+	}
+
+	return
+}
+
+// CreatePartOfSpeech inserts a new managed part of speech.
+func (repo *SQLLookupRepository) CreatePartOfSpeech(pos *mdl.PartOfSpeech) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Create(pos).Error
+}
+
+// UpdatePartOfSpeech saves changes to an existing managed part of speech.
+func (repo *SQLLookupRepository) UpdatePartOfSpeech(pos *mdl.PartOfSpeech) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Save(pos).Error
+}
+
+// DeletePartOfSpeech removes a managed part of speech by its primary ID.
+func (repo *SQLLookupRepository) DeletePartOfSpeech(id int) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Delete(&mdl.PartOfSpeech{}, id).Error
+}
+
+// FindSkills retrieves every managed skill, ordered by name. The taxonomy is assembled
+// from the parent IDs by the service layer.
+func (repo *SQLLookupRepository) FindSkills() (list *[]mdl.Skill, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	list = &[]mdl.Skill{}
+	err = db.Order("name").Find(list).Error
+	if err != nil {
+		log.Printf("Error finding skills: %v", err)
+	}
+
+	return
+}
+
+// FindSkillByID retrieves a managed skill by its primary ID.
+func (repo *SQLLookupRepository) FindSkillByID(id int) (skill *mdl.Skill, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	skill = &mdl.Skill{}
+	err = db.First(skill, id).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding skill with id %d, %v", id, err)
+	}
+
+	return
+}
+
+// CreateSkill inserts a new managed skill.
+func (repo *SQLLookupRepository) CreateSkill(skill *mdl.Skill) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Create(skill).Error
+}
+
+// UpdateSkill saves changes to an existing managed skill.
+func (repo *SQLLookupRepository) UpdateSkill(skill *mdl.Skill) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Save(skill).Error
+}
+
+// DeleteSkill removes a managed skill by its primary ID.
+func (repo *SQLLookupRepository) DeleteSkill(id int) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Delete(&mdl.Skill{}, id).Error
+}
+
+// CountVocabValues counts the Vocab records using each distinct value of a vocab field.
+//
+// Parameters:
+// - field: The vocab column to count, either "pos" or "skill".
+//
+// Returns:
+// - A pointer to a slice of the values and their counts, ordered by value.
+// - An error if the field is not supported, or the database connection or query fails.
+func (repo *SQLLookupRepository) CountVocabValues(field string) (counts *[]mdl.TermCount, err error) {
+	if !vocabTermFields[field] {
+		return nil, fmt.Errorf("cannot count vocab values of field %s", field)
+	}
+
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	counts = &[]mdl.TermCount{}
+	err = db.Model(&mdl.Vocab{}).
+		Select(field + " AS value, count(*) AS count").
+		Group(field).
+		Order(field).
+		Scan(counts).Error
+	if err != nil {
+		log.Printf("Error counting vocab %s values: %v", field, err)
+	}
+
+	return
+}
+
+// CountVocabSkillIDs counts the Vocab records linked to each managed skill by Vocab.SkillID.
+// Skills without linked vocab are left out.
+//
+// Returns:
+// - A pointer to a slice of the skill IDs and their counts, ordered by skill ID.
+// - An error if the database connection or query fails.
+func (repo *SQLLookupRepository) CountVocabSkillIDs() (counts *[]mdl.SkillCount, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	counts = &[]mdl.SkillCount{}
+	err = db.Model(&mdl.Vocab{}).
+		Select("skill_id, count(*) AS count").
+		Where("skill_id IS NOT NULL").
+		Group("skill_id").
+		Order("skill_id").
+		Scan(counts).Error
+	if err != nil {
+		log.Printf("Error counting vocab skill ids: %v", err)
+	}
+
+	return
+}
+
+// AddVocabSkillIDIfNotExists adds the skill_id foreign key column to the vocab table, which
+// is not auto migrated since it is shared with other readers, and links existing vocab to
+// the managed skill their Skill names. The backfill only touches rows without a skill_id, so
+// it is cheap to run on every start and picks up skills that were loaded since.
+//
+// Parameters:
+// - db: A pointer to a gorm.DB instance representing an established database connection.
+//
+// Returns:
+// - An error if the column, its index, or the backfill fails.
+//
+// Note: The skill table must be migrated first, since the column references it.
+func AddVocabSkillIDIfNotExists(db *gorm.DB) error {
+	statements := []string{
+		`ALTER TABLE palabras.vocab ADD COLUMN IF NOT EXISTS skill_id integer REFERENCES palabras.skill (id)`,
+		`CREATE INDEX IF NOT EXISTS idx_vocab_skill_id ON palabras.vocab (skill_id)`,
+		`UPDATE palabras.vocab AS v SET skill_id = s.id FROM palabras.skill AS s
+			WHERE v.skill_id IS NULL AND v.skill = s.name`,
+	}
+
+	for _, sql := range statements {
+		if err := db.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SeedPartsOfSpeechIfEmpty loads mdl.DefaultPartsOfSpeech into the part of speech table
+// when it has no rows, so vocab validation has a vocabulary to check against from the start.
+//
+// Parameters:
+// - db: A pointer to a gorm.DB instance representing an established database connection.
+//
+// Returns:
+// - An error if the table cannot be counted or the defaults cannot be inserted.
+func SeedPartsOfSpeechIfEmpty(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&mdl.PartOfSpeech{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	defaults := make([]mdl.PartOfSpeech, len(mdl.DefaultPartsOfSpeech))
+	copy(defaults, mdl.DefaultPartsOfSpeech)
+
+	return db.Create(&defaults).Error
+}
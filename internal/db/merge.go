@@ -0,0 +1,118 @@
+// Package db defines interfaces and implementations for interacting with
+// entities in the database. It includes the MergeRepository interface, which outlines
+// saving a merge of duplicate Vocab records, and the SQLMergeRepository struct, which
+// provides a concrete implementation of the MergeRepository using GORM.
+package db
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"gorm.io/gorm"
+)
+
+// MergeRepository defines the operations available for merging Vocab records.
+type MergeRepository interface {
+	MergeVocabs(merge *mdl.VocabMerge) error
+}
+
+// SQLMergeRepository provides a GORM-based implementation of the MergeRepository interface.
+type SQLMergeRepository struct {
+	db *gorm.DB
+}
+
+// NewSqlMergeRepository initializes a new SQLMergeRepository with a database connection.
+func NewSqlMergeRepository() (repo *SQLMergeRepository, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	repo = &SQLMergeRepository{db: db}
+
+	return
+}
+
+// MergeVocabs saves every change of a vocab merge in a single transaction, so either all of
+// them are applied or none are. In order, it:
+//  1. Saves the surviving vocab.
+//  2. Deletes the alternatives of the merged vocab and saves the surviving vocab's alternatives,
+//     which now include them.
+//  3. Saves the re-pointed fixits and moves the example sentences of the merged vocab to the
+//     surviving vocab.
+//  4. Creates the archive entries and deletes the merged vocab along with their conjugations.
+//  5. Creates the outbox events of the merge, then the audit entries along with their events
+//     and the deliveries of the webhooks wanting them.
+//
+// Parameters:
+// - merge: The changes computed by the vocab service for the merge.
+//
+// Returns:
+// - An error if the database connection fails or any statement fails, in which case nothing is saved.
+func (repo *SQLMergeRepository) MergeVocabs(merge *mdl.VocabMerge) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(merge.Keep).Error; err != nil {
+			return fmt.Errorf("failed to save vocab %d, error: %v", merge.Keep.ID, err)
+		}
+
+		err := tx.Where("vocab_id IN ?", merge.MergedIDs).Delete(&mdl.VocabAlternative{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete merged alternatives, error: %v", err)
+		}
+		for i := range merge.Keep.AlternativeList {
+			if err := tx.Save(&merge.Keep.AlternativeList[i]).Error; err != nil {
+				return fmt.Errorf("failed to save alternative %s, error: %v", merge.Keep.AlternativeList[i].Alternative, err)
+			}
+		}
+
+		for i := range merge.Fixits {
+			if err := tx.Save(&merge.Fixits[i]).Error; err != nil {
+				return fmt.Errorf("failed to re-point fixit %d, error: %v", merge.Fixits[i].ID, err)
+			}
+		}
+		err = tx.Model(&mdl.ExampleSentence{}).Where("vocab_id IN ?", merge.MergedIDs).
+			Update("vocab_id", merge.Keep.ID).Error
+		if err != nil {
+			return fmt.Errorf("failed to re-point merged example sentences, error: %v", err)
+		}
+
+		if len(merge.Archives) > 0 {
+			if err := tx.Create(&merge.Archives).Error; err != nil {
+				return fmt.Errorf("failed to archive merged vocab, error: %v", err)
+			}
+		}
+		if err := tx.Where("vocab_id IN ?", merge.MergedIDs).Delete(&mdl.Conjugation{}).Error; err != nil {
+			return fmt.Errorf("failed to delete merged conjugations, error: %v", err)
+		}
+		if err := tx.Delete(&mdl.Vocab{}, merge.MergedIDs).Error; err != nil {
+			return fmt.Errorf("failed to delete merged vocab, error: %v", err)
+		}
+
+		if err := createOutboxEvents(tx, mergeOutboxEvents(merge)); err != nil {
+			return err
+		}
+
+		audits := make([]*mdl.Audit, len(merge.Audits))
+		for i := range merge.Audits {
+			audits[i] = &merge.Audits[i]
+		}
+		return createAudits(tx, audits)
+	})
+}
+
+// mergeOutboxEvents returns the outbox events of a merge, the kept vocab updated, the re-pointed
+// fixits updated, and the merged vocab deleted. The audit events are saved with the audits.
+func mergeOutboxEvents(merge *mdl.VocabMerge) []mdl.OutboxEvent {
+	events := []mdl.OutboxEvent{mdl.NewOutboxEvent(mdl.AggregateVocab, merge.Keep.ID, "updated", merge.Keep.JSON())}
+	for i := range merge.Fixits {
+		events = append(events, mdl.NewOutboxEvent(mdl.AggregateFixit, merge.Fixits[i].ID, "updated", merge.Fixits[i].JSON()))
+	}
+	for i := range merge.Archives {
+		events = append(events, mdl.NewOutboxEvent(mdl.AggregateVocab, merge.Archives[i].VocabID, "deleted", merge.Archives[i].Record))
+	}
+	return events
+}
@@ -0,0 +1,70 @@
+package mock
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"sort"
+)
+
+type MockAlternativeRepository struct {
+	alternatives map[int]*mdl.VocabAlternative
+	seq          int
+}
+
+// NewMockAlternativeRepository initializes and returns a new instance of MockAlternativeRepository.
+func NewMockAlternativeRepository() *MockAlternativeRepository {
+	return &MockAlternativeRepository{
+		alternatives: make(map[int]*mdl.VocabAlternative),
+	}
+}
+
+func (m *MockAlternativeRepository) FindAlternatives(vocabIDs []int) (*[]mdl.VocabAlternative, error) {
+	wanted := make(map[int]bool, len(vocabIDs))
+	for _, id := range vocabIDs {
+		wanted[id] = true
+	}
+
+	result := make([]mdl.VocabAlternative, 0)
+	for _, a := range m.alternatives {
+		if wanted[a.VocabID] {
+			result = append(result, *a)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].VocabID != result[j].VocabID {
+			return result[i].VocabID < result[j].VocabID
+		}
+		return result[i].Position < result[j].Position
+	})
+	return &result, nil
+}
+
+func (m *MockAlternativeRepository) CreateAlternative(alternative *mdl.VocabAlternative) error {
+	for _, a := range m.alternatives {
+		if a.VocabID == alternative.VocabID && a.Alternative == alternative.Alternative {
+			return fmt.Errorf("duplicate alternative %s for vocab %d", alternative.Alternative, alternative.VocabID)
+		}
+	}
+	m.seq += 1
+	alternative.ID = m.seq
+	stored := *alternative
+	m.alternatives[alternative.ID] = &stored
+	return nil
+}
+
+func (m *MockAlternativeRepository) UpdateAlternative(alternative *mdl.VocabAlternative) error {
+	if _, exists := m.alternatives[alternative.ID]; !exists {
+		return fmt.Errorf("error finding alternative with id %d", alternative.ID)
+	}
+	stored := *alternative
+	m.alternatives[alternative.ID] = &stored
+	return nil
+}
+
+func (m *MockAlternativeRepository) DeleteAlternative(id int) error {
+	if _, exists := m.alternatives[id]; !exists {
+		return fmt.Errorf("error finding alternative with id %d", id)
+	}
+	delete(m.alternatives, id)
+	return nil
+}
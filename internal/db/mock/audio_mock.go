@@ -0,0 +1,103 @@
+package mock
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"sort"
+	"time"
+)
+
+type MockAudioRepository struct {
+	assets map[int]*mdl.AudioAsset
+	vocabs *MockVocabRepository
+	// published are the IDs of the assets the snapshots serve, see MockSnapshotRepository
+	published map[int]bool
+	seq       int
+}
+
+// NewMockAudioRepository initializes and returns a new instance of MockAudioRepository.
+// The vocab repository provides the references checked by FindOrphanAudioAssets.
+func NewMockAudioRepository(vocabs *MockVocabRepository) *MockAudioRepository {
+	return &MockAudioRepository{
+		assets:    make(map[int]*mdl.AudioAsset),
+		vocabs:    vocabs,
+		published: make(map[int]bool),
+	}
+}
+
+func (m *MockAudioRepository) FindAudioAssetByID(id int) (*mdl.AudioAsset, error) {
+	if a, exists := m.assets[id]; exists {
+		found := *a
+		return &found, nil
+	}
+	return nil, fmt.Errorf("error finding audio with id %d", id)
+}
+
+func (m *MockAudioRepository) FindAudioAssetByHash(hash string) (*mdl.AudioAsset, error) {
+	for _, a := range m.assets {
+		if a.Hash == hash {
+			found := *a
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("error finding audio with hash %s", hash)
+}
+
+func (m *MockAudioRepository) FindAudioAssets() (*[]mdl.AudioAsset, error) {
+	result := make([]mdl.AudioAsset, 0, len(m.assets))
+	for _, a := range m.assets {
+		result = append(result, *a)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return &result, nil
+}
+
+func (m *MockAudioRepository) FindOrphanAudioAssets(createdBefore time.Time) (*[]mdl.AudioAsset, error) {
+	referenced := make(map[int]bool)
+	for id := range m.published {
+		referenced[id] = true
+	}
+	for _, v := range m.vocabs.vocabs {
+		if v.AudioID != nil {
+			referenced[*v.AudioID] = true
+		}
+	}
+
+	result := make([]mdl.AudioAsset, 0)
+	for _, a := range m.assets {
+		if !referenced[a.ID] && a.Created.Before(createdBefore) {
+			result = append(result, *a)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return &result, nil
+}
+
+func (m *MockAudioRepository) CreateAudioAsset(asset *mdl.AudioAsset) error {
+	for _, a := range m.assets {
+		if a.Hash == asset.Hash {
+			return fmt.Errorf("duplicate audio hash %s", asset.Hash)
+		}
+	}
+	m.seq += 1
+	asset.ID = m.seq
+	if asset.Created.IsZero() {
+		asset.Created = time.Now()
+	}
+	stored := *asset
+	m.assets[asset.ID] = &stored
+	return nil
+}
+
+func (m *MockAudioRepository) DeleteOrphanAudioAsset(id int) (bool, error) {
+	if _, exists := m.assets[id]; !exists || m.published[id] {
+		return false, nil
+	}
+	for _, v := range m.vocabs.vocabs {
+		if v.AudioID != nil && *v.AudioID == id {
+			return false, nil
+		}
+	}
+	delete(m.assets, id)
+	return true, nil
+}
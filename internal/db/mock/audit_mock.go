@@ -1,13 +1,18 @@
 package mock
 
 import (
+	"encoding/json"
 	"errors"
+	"github.com/heather92115/verdure-admin/internal/db"
 	"github.com/heather92115/verdure-admin/internal/mdl"
+	"slices"
+	"sort"
 )
 
 type MockAuditRepository struct {
-	audits map[int]*mdl.Audit
-	seq    int
+	audits   map[int]*mdl.Audit
+	webhooks *MockWebhookRepository
+	seq      int
 }
 
 // NewMockAuditRepository initializes and returns a new instance of MockAuditRepository.
@@ -17,6 +22,14 @@ func NewMockAuditRepository() *MockAuditRepository {
 	}
 }
 
+// NewMockAuditRepositoryWithWebhooks returns a MockAuditRepository queueing the deliveries of
+// each audit created for the webhooks of the repository, as the SQL repository does.
+func NewMockAuditRepositoryWithWebhooks(webhooks *MockWebhookRepository) *MockAuditRepository {
+	repo := NewMockAuditRepository()
+	repo.webhooks = webhooks
+	return repo
+}
+
 func (m *MockAuditRepository) FindAuditByID(id int) (*mdl.Audit, error) {
 	if audit, exists := m.audits[id]; exists {
 		return audit, nil
@@ -24,26 +37,91 @@ func (m *MockAuditRepository) FindAuditByID(id int) (*mdl.Audit, error) {
 	return nil, errors.New("audit not found")
 }
 
-func (m *MockAuditRepository) FindAudits(tableName string, objectId int, duration *mdl.Duration, limit int) (*[]mdl.Audit, error) {
+func (m *MockAuditRepository) FindAudits(filter *mdl.AuditFilter, limit int) (*[]mdl.Audit, error) {
+	if filter.TableName == "" && filter.ObjectID > 0 {
+		return nil, errors.New("invalid audit query, objectId requires table name filter")
+	}
+
+	result := make([]mdl.Audit, 0)
+	for _, a := range m.audits {
+		if (filter.TableName == "" || a.TableName == filter.TableName) &&
+			(filter.ObjectID == 0 || a.ObjectID == filter.ObjectID) &&
+			(filter.Duration == nil || filter.Duration.Contains(a.Created)) &&
+			(filter.CreatedBy == "" || a.CreatedBy == filter.CreatedBy) &&
+			(filter.Action == "" || a.Action == filter.Action) &&
+			(filter.ChangedField == "" || diffChangesField(a.Diff, filter.ChangedField)) {
+			result = append(result, *a)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Created.Equal(result[j].Created) {
+			return result[i].Created.After(result[j].Created)
+		}
+		return result[i].ID > result[j].ID
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return &result, nil
+}
+
+// diffChangesField reports whether the audit diff holds a change to the value of the field.
+func diffChangesField(diff string, field string) bool {
+	var changes []struct {
+		Key string `json:"key"`
+	}
+	_ = json.Unmarshal([]byte(diff), &changes)
+	for _, change := range changes {
+		if change.Key == "'"+field+"'" {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MockAuditRepository) FindAuditsBetween(from *mdl.Snapshot, to *mdl.Snapshot, tableNames []string) (*[]mdl.Audit, error) {
 	result := make([]mdl.Audit, 0)
-	count := 0
 	for _, a := range m.audits {
-		if (tableName == "" || a.TableName == tableName) && (objectId == 0 || a.ObjectID == objectId) &&
-			(duration == nil || (a.Created.After(duration.Start) && a.Created.Before(duration.End))) {
+		if (from == nil || a.ID > from.AuditID) && (to == nil || a.ID <= to.AuditID) && slices.Contains(tableNames, a.TableName) {
 			result = append(result, *a)
-			count++
-			if limit > 0 && count >= limit {
-				break
-			}
 		}
 	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
 	return &result, nil
 }
 
+// createBuilt builds the audits of a change saved by another mock repository and creates them.
+func (m *MockAuditRepository) createBuilt(audits db.AuditBuilder) error {
+	if audits == nil {
+		return nil
+	}
+
+	built, err := audits()
+	if err != nil {
+		return err
+	}
+	for _, audit := range built {
+		if err = m.CreateAudit(audit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *MockAuditRepository) CreateAudit(audit *mdl.Audit) error {
 
 	m.seq += 1
 	audit.ID = m.seq
 	m.audits[audit.ID] = audit
+
+	if m.webhooks == nil {
+		return nil
+	}
+	webhooks, _ := m.webhooks.FindWebhooks()
+	deliveries, err := mdl.NewWebhookDeliveries(*webhooks, audit)
+	if err != nil {
+		return err
+	}
+	m.webhooks.createDeliveries(deliveries)
 	return nil
 }
@@ -0,0 +1,90 @@
+package mock
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"sort"
+	"time"
+)
+
+type MockChangeRequestRepository struct {
+	requests map[int]*mdl.ChangeRequest
+	vocabs   *MockVocabRepository
+	seq      int
+}
+
+// NewMockChangeRequestRepository initializes and returns a new instance of MockChangeRequestRepository
+// applying approved changes to the vocab repository.
+func NewMockChangeRequestRepository(vocabs *MockVocabRepository) *MockChangeRequestRepository {
+	return &MockChangeRequestRepository{requests: make(map[int]*mdl.ChangeRequest), vocabs: vocabs}
+}
+
+func (m *MockChangeRequestRepository) FindChangeRequestByID(id int) (*mdl.ChangeRequest, error) {
+	if r, exists := m.requests[id]; exists {
+		found := *r
+		return &found, nil
+	}
+	return nil, fmt.Errorf("error finding change request with id %d", id)
+}
+
+func (m *MockChangeRequestRepository) FindChangeRequests(vocabID int, status string, limit int) (*[]mdl.ChangeRequest, error) {
+	result := make([]mdl.ChangeRequest, 0)
+	for _, r := range m.requests {
+		if (vocabID == 0 || r.VocabID == vocabID) && (len(status) == 0 || r.Status == status) {
+			result = append(result, *r)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID > result[j].ID })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return &result, nil
+}
+
+func (m *MockChangeRequestRepository) CreateChangeRequest(request *mdl.ChangeRequest) error {
+	m.seq += 1
+	request.ID = m.seq
+	if request.Created.IsZero() {
+		request.Created = time.Now()
+	}
+	stored := *request
+	m.requests[request.ID] = &stored
+	return nil
+}
+
+func (m *MockChangeRequestRepository) ReviewChangeRequest(request *mdl.ChangeRequest, audits db.AuditBuilder) error {
+	if err := m.checkPending(request.ID); err != nil {
+		return err
+	}
+	if err := m.vocabs.audits.createBuilt(audits); err != nil {
+		return err
+	}
+	stored := *request
+	m.requests[request.ID] = &stored
+	return nil
+}
+
+func (m *MockChangeRequestRepository) ApproveChangeRequest(request *mdl.ChangeRequest, vocab *mdl.Vocab, audits db.AuditBuilder) error {
+	if err := m.checkPending(request.ID); err != nil {
+		return err
+	}
+	if err := m.vocabs.UpdateVocab(vocab, audits); err != nil {
+		return err
+	}
+	stored := *request
+	m.requests[request.ID] = &stored
+	return nil
+}
+
+// checkPending fails unless the stored change request is still pending.
+func (m *MockChangeRequestRepository) checkPending(id int) error {
+	stored, exists := m.requests[id]
+	if !exists {
+		return fmt.Errorf("error finding change request with id %d", id)
+	}
+	if stored.Status != mdl.ChangePending {
+		return fmt.Errorf("change request %d is no longer pending", id)
+	}
+	return nil
+}
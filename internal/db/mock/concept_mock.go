@@ -0,0 +1,76 @@
+package mock
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"sort"
+	"time"
+)
+
+type MockConceptRepository struct {
+	concepts map[int]*mdl.Concept
+	vocabs   *MockVocabRepository
+	seq      int
+}
+
+// NewMockConceptRepository initializes and returns a new instance of MockConceptRepository.
+// The vocab repository provides the vocab found by FindConceptVocabs.
+func NewMockConceptRepository(vocabs *MockVocabRepository) *MockConceptRepository {
+	return &MockConceptRepository{
+		concepts: make(map[int]*mdl.Concept),
+		vocabs:   vocabs,
+	}
+}
+
+func (m *MockConceptRepository) FindConceptByID(id int) (*mdl.Concept, error) {
+	if c, exists := m.concepts[id]; exists {
+		found := *c
+		return &found, nil
+	}
+	return nil, fmt.Errorf("error finding concept with id %d", id)
+}
+
+func (m *MockConceptRepository) FindConceptVocabs(conceptID int) (*[]mdl.Vocab, error) {
+	result := make([]mdl.Vocab, 0)
+	for _, v := range m.vocabs.vocabs {
+		if v.ConceptID != nil && *v.ConceptID == conceptID {
+			result = append(result, *v)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].LearningLangCode != result[j].LearningLangCode {
+			return result[i].LearningLangCode < result[j].LearningLangCode
+		}
+		return result[i].LearningLang < result[j].LearningLang
+	})
+	return &result, nil
+}
+
+func (m *MockConceptRepository) CreateConcept(concept *mdl.Concept) error {
+	m.seq += 1
+	concept.ID = m.seq
+	if concept.Created.IsZero() {
+		concept.Created = time.Now()
+	}
+	stored := *concept
+	m.concepts[concept.ID] = &stored
+	return nil
+}
+
+func (m *MockConceptRepository) CreateConceptWithVocab(concept *mdl.Concept, vocab *mdl.Vocab, audits db.AuditBuilder) error {
+	if err := m.CreateConcept(concept); err != nil {
+		return err
+	}
+	vocab.ConceptID = &concept.ID
+	return m.vocabs.CreateVocab(vocab, audits)
+}
+
+func (m *MockConceptRepository) UpdateConcept(concept *mdl.Concept) error {
+	if _, exists := m.concepts[concept.ID]; !exists {
+		return fmt.Errorf("error finding concept with id %d", concept.ID)
+	}
+	stored := *concept
+	m.concepts[concept.ID] = &stored
+	return nil
+}
@@ -0,0 +1,48 @@
+package mock
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"sort"
+)
+
+type MockConjugationRepository struct {
+	conjugations map[int]*mdl.Conjugation
+	seq          int
+}
+
+// NewMockConjugationRepository initializes and returns a new instance of MockConjugationRepository.
+func NewMockConjugationRepository() *MockConjugationRepository {
+	return &MockConjugationRepository{
+		conjugations: make(map[int]*mdl.Conjugation),
+	}
+}
+
+func (m *MockConjugationRepository) FindConjugations(vocabID int) (*[]mdl.Conjugation, error) {
+	result := make([]mdl.Conjugation, 0)
+	for _, c := range m.conjugations {
+		if c.VocabID == vocabID {
+			result = append(result, *c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return &result, nil
+}
+
+func (m *MockConjugationRepository) SaveConjugations(conjugations []mdl.Conjugation) error {
+	for i := range conjugations {
+		conjugation := &conjugations[i]
+		if conjugation.ID == 0 {
+			for _, c := range m.conjugations {
+				if c.VocabID == conjugation.VocabID && c.Tense == conjugation.Tense && c.Person == conjugation.Person {
+					return fmt.Errorf("duplicate conjugation %s %s for vocab %d", conjugation.Tense, conjugation.Person, conjugation.VocabID)
+				}
+			}
+			m.seq += 1
+			conjugation.ID = m.seq
+		}
+		stored := *conjugation
+		m.conjugations[conjugation.ID] = &stored
+	}
+	return nil
+}
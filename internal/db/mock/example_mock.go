@@ -0,0 +1,76 @@
+package mock
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"sort"
+)
+
+type MockExampleRepository struct {
+	examples map[int]*mdl.ExampleSentence
+	audits   *MockAuditRepository
+	seq      int
+}
+
+// NewMockExampleRepository initializes and returns a new instance of MockExampleRepository
+// saving the audits of its changes to the audit repository.
+func NewMockExampleRepository(audits *MockAuditRepository) *MockExampleRepository {
+	return &MockExampleRepository{
+		examples: make(map[int]*mdl.ExampleSentence),
+		audits:   audits,
+	}
+}
+
+func (m *MockExampleRepository) FindExampleSentenceByID(id int) (*mdl.ExampleSentence, error) {
+	if e, exists := m.examples[id]; exists {
+		found := *e
+		return &found, nil
+	}
+	return nil, fmt.Errorf("error finding example sentence with id %d", id)
+}
+
+func (m *MockExampleRepository) FindExampleSentences(vocabID int) (*[]mdl.ExampleSentence, error) {
+	result := make([]mdl.ExampleSentence, 0)
+	for _, e := range m.examples {
+		if e.VocabID == vocabID {
+			result = append(result, *e)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return &result, nil
+}
+
+func (m *MockExampleRepository) CreateExampleSentence(example *mdl.ExampleSentence, audits db.AuditBuilder) error {
+	m.seq += 1
+	example.ID = m.seq
+	if err := m.audits.createBuilt(audits); err != nil {
+		return err
+	}
+	stored := *example
+	m.examples[example.ID] = &stored
+	return nil
+}
+
+func (m *MockExampleRepository) UpdateExampleSentence(example *mdl.ExampleSentence, audits db.AuditBuilder) error {
+	if _, exists := m.examples[example.ID]; !exists {
+		return fmt.Errorf("error finding example sentence with id %d", example.ID)
+	}
+	if err := m.audits.createBuilt(audits); err != nil {
+		return err
+	}
+	stored := *example
+	m.examples[example.ID] = &stored
+	return nil
+}
+
+func (m *MockExampleRepository) DeleteExampleSentence(id int, audits db.AuditBuilder) error {
+	if _, exists := m.examples[id]; !exists {
+		return fmt.Errorf("error finding example sentence with id %d", id)
+	}
+	if err := m.audits.createBuilt(audits); err != nil {
+		return err
+	}
+	delete(m.examples, id)
+	return nil
+}
@@ -3,6 +3,7 @@ package mock
 import (
 	"errors"
 	"github.com/heather92115/verdure-admin/internal/mdl"
+	"sort"
 )
 
 type MockFixitRepository struct {
@@ -41,6 +42,22 @@ func (m *MockFixitRepository) FindFixits(status mdl.StatusType, vocabID int, dur
 	return &result, nil
 }
 
+func (m *MockFixitRepository) FindFixitsForVocabs(vocabIDs []int) (*[]mdl.Fixit, error) {
+	wanted := make(map[int]bool, len(vocabIDs))
+	for _, id := range vocabIDs {
+		wanted[id] = true
+	}
+
+	result := make([]mdl.Fixit, 0)
+	for _, f := range m.fixits {
+		if wanted[f.VocabID] {
+			result = append(result, *f)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return &result, nil
+}
+
 func (m *MockFixitRepository) CreateFixit(fixit *mdl.Fixit) error {
 	m.seq += 1
 	fixit.ID = m.seq
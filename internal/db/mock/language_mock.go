@@ -0,0 +1,61 @@
+package mock
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"sort"
+	"time"
+)
+
+type MockLanguageRepository struct {
+	languages map[int]*mdl.Language
+	seq       int
+}
+
+// NewMockLanguageRepository initializes and returns a new instance of MockLanguageRepository
+// holding the given languages, e.g. mdl.DefaultLanguages.
+func NewMockLanguageRepository(languages ...mdl.Language) *MockLanguageRepository {
+	m := &MockLanguageRepository{languages: make(map[int]*mdl.Language)}
+	for i := range languages {
+		language := languages[i]
+		_ = m.CreateLanguage(&language)
+	}
+	return m
+}
+
+func (m *MockLanguageRepository) FindLanguages() (*[]mdl.Language, error) {
+	result := make([]mdl.Language, 0, len(m.languages))
+	for _, l := range m.languages {
+		result = append(result, *l)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Code < result[j].Code })
+	return &result, nil
+}
+
+func (m *MockLanguageRepository) FindLanguageByID(id int) (*mdl.Language, error) {
+	if l, exists := m.languages[id]; exists {
+		found := *l
+		return &found, nil
+	}
+	return nil, fmt.Errorf("error finding language with id %d", id)
+}
+
+func (m *MockLanguageRepository) CreateLanguage(language *mdl.Language) error {
+	m.seq += 1
+	language.ID = m.seq
+	if language.Created.IsZero() {
+		language.Created = time.Now()
+	}
+	stored := *language
+	m.languages[language.ID] = &stored
+	return nil
+}
+
+func (m *MockLanguageRepository) UpdateLanguage(language *mdl.Language) error {
+	if _, exists := m.languages[language.ID]; !exists {
+		return fmt.Errorf("error finding language with id %d", language.ID)
+	}
+	stored := *language
+	m.languages[language.ID] = &stored
+	return nil
+}
@@ -0,0 +1,149 @@
+package mock
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"sort"
+)
+
+type MockLookupRepository struct {
+	partsOfSpeech map[int]*mdl.PartOfSpeech
+	skills        map[int]*mdl.Skill
+	vocabs        *MockVocabRepository
+	seq           int
+}
+
+// NewMockLookupRepository initializes and returns a new instance of MockLookupRepository.
+// The vocab repository provides the values counted by CountVocabValues.
+func NewMockLookupRepository(vocabs *MockVocabRepository) *MockLookupRepository {
+	return &MockLookupRepository{
+		partsOfSpeech: make(map[int]*mdl.PartOfSpeech),
+		skills:        make(map[int]*mdl.Skill),
+		vocabs:        vocabs,
+	}
+}
+
+func (m *MockLookupRepository) FindPartsOfSpeech() (*[]mdl.PartOfSpeech, error) {
+	result := make([]mdl.PartOfSpeech, 0, len(m.partsOfSpeech))
+	for _, p := range m.partsOfSpeech {
+		result = append(result, *p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return &result, nil
+}
+
+func (m *MockLookupRepository) FindPartOfSpeechByID(id int) (*mdl.PartOfSpeech, error) {
+	if p, exists := m.partsOfSpeech[id]; exists {
+		found := *p
+		return &found, nil
+	}
+	return nil, fmt.Errorf("error finding part of speech with id %d", id)
+}
+
+func (m *MockLookupRepository) CreatePartOfSpeech(pos *mdl.PartOfSpeech) error {
+	for _, p := range m.partsOfSpeech {
+		if p.Name == pos.Name {
+			return fmt.Errorf("duplicate part of speech %s", pos.Name)
+		}
+	}
+	m.seq += 1
+	pos.ID = m.seq
+	stored := *pos
+	m.partsOfSpeech[pos.ID] = &stored
+	return nil
+}
+
+func (m *MockLookupRepository) UpdatePartOfSpeech(pos *mdl.PartOfSpeech) error {
+	if _, exists := m.partsOfSpeech[pos.ID]; !exists {
+		return fmt.Errorf("error finding part of speech with id %d", pos.ID)
+	}
+	stored := *pos
+	m.partsOfSpeech[pos.ID] = &stored
+	return nil
+}
+
+func (m *MockLookupRepository) DeletePartOfSpeech(id int) error {
+	delete(m.partsOfSpeech, id)
+	return nil
+}
+
+func (m *MockLookupRepository) FindSkills() (*[]mdl.Skill, error) {
+	result := make([]mdl.Skill, 0, len(m.skills))
+	for _, s := range m.skills {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return &result, nil
+}
+
+func (m *MockLookupRepository) FindSkillByID(id int) (*mdl.Skill, error) {
+	if s, exists := m.skills[id]; exists {
+		found := *s
+		return &found, nil
+	}
+	return nil, fmt.Errorf("error finding skill with id %d", id)
+}
+
+func (m *MockLookupRepository) CreateSkill(skill *mdl.Skill) error {
+	for _, s := range m.skills {
+		if s.Name == skill.Name {
+			return fmt.Errorf("duplicate skill %s", skill.Name)
+		}
+	}
+	m.seq += 1
+	skill.ID = m.seq
+	stored := *skill
+	m.skills[skill.ID] = &stored
+	return nil
+}
+
+func (m *MockLookupRepository) UpdateSkill(skill *mdl.Skill) error {
+	if _, exists := m.skills[skill.ID]; !exists {
+		return fmt.Errorf("error finding skill with id %d", skill.ID)
+	}
+	stored := *skill
+	m.skills[skill.ID] = &stored
+	return nil
+}
+
+func (m *MockLookupRepository) DeleteSkill(id int) error {
+	delete(m.skills, id)
+	return nil
+}
+
+func (m *MockLookupRepository) CountVocabValues(field string) (*[]mdl.TermCount, error) {
+	counts := make(map[string]int)
+	for _, v := range m.vocabs.vocabs {
+		switch field {
+		case "pos":
+			counts[v.Pos]++
+		case "skill":
+			counts[v.Skill]++
+		default:
+			return nil, fmt.Errorf("cannot count vocab values of field %s", field)
+		}
+	}
+
+	result := make([]mdl.TermCount, 0, len(counts))
+	for value, count := range counts {
+		result = append(result, mdl.TermCount{Value: value, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Value < result[j].Value })
+	return &result, nil
+}
+
+func (m *MockLookupRepository) CountVocabSkillIDs() (*[]mdl.SkillCount, error) {
+	counts := make(map[int]int)
+	for _, v := range m.vocabs.vocabs {
+		if v.SkillID != nil {
+			counts[*v.SkillID]++
+		}
+	}
+
+	result := make([]mdl.SkillCount, 0, len(counts))
+	for skillID, count := range counts {
+		result = append(result, mdl.SkillCount{SkillID: skillID, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].SkillID < result[j].SkillID })
+	return &result, nil
+}
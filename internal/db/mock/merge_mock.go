@@ -0,0 +1,77 @@
+package mock
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+)
+
+// MockMergeRepository applies vocab merges to the mock repositories it is built from.
+type MockMergeRepository struct {
+	vocabs       *MockVocabRepository
+	alternatives *MockAlternativeRepository
+	fixits       *MockFixitRepository
+	audits       *MockAuditRepository
+	Archives     []mdl.VocabArchive
+}
+
+// NewMockMergeRepository initializes and returns a new instance of MockMergeRepository.
+func NewMockMergeRepository(vocabs *MockVocabRepository, alternatives *MockAlternativeRepository,
+	fixits *MockFixitRepository, audits *MockAuditRepository) *MockMergeRepository {
+	return &MockMergeRepository{
+		vocabs:       vocabs,
+		alternatives: alternatives,
+		fixits:       fixits,
+		audits:       audits,
+	}
+}
+
+func (m *MockMergeRepository) MergeVocabs(merge *mdl.VocabMerge) error {
+	if err := m.vocabs.UpdateVocab(merge.Keep, nil); err != nil {
+		return err
+	}
+
+	merged := make(map[int]bool, len(merge.MergedIDs))
+	for _, id := range merge.MergedIDs {
+		if _, exists := m.vocabs.vocabs[id]; !exists {
+			return fmt.Errorf("error finding vocab with id %d", id)
+		}
+		merged[id] = true
+	}
+
+	for id, a := range m.alternatives.alternatives {
+		if merged[a.VocabID] {
+			delete(m.alternatives.alternatives, id)
+		}
+	}
+	for i := range merge.Keep.AlternativeList {
+		alternative := &merge.Keep.AlternativeList[i]
+		var err error
+		if alternative.ID == 0 {
+			err = m.alternatives.CreateAlternative(alternative)
+		} else {
+			err = m.alternatives.UpdateAlternative(alternative)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range merge.Fixits {
+		if err := m.fixits.UpdateFixit(&merge.Fixits[i]); err != nil {
+			return err
+		}
+	}
+
+	m.Archives = append(m.Archives, merge.Archives...)
+	for id := range merged {
+		delete(m.vocabs.vocabs, id)
+	}
+
+	for i := range merge.Audits {
+		if err := m.audits.CreateAudit(&merge.Audits[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package mock
+
+import (
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"sort"
+	"time"
+)
+
+type MockOutboxRepository struct {
+	events map[int]*mdl.OutboxEvent
+	seq    int
+}
+
+// NewMockOutboxRepository initializes and returns a new instance of MockOutboxRepository
+// holding the given events, numbered in order.
+func NewMockOutboxRepository(events ...mdl.OutboxEvent) *MockOutboxRepository {
+	m := &MockOutboxRepository{events: make(map[int]*mdl.OutboxEvent)}
+	for i := range events {
+		m.seq += 1
+		stored := events[i]
+		stored.ID = m.seq
+		if stored.Created.IsZero() {
+			stored.Created = time.Now()
+		}
+		m.events[stored.ID] = &stored
+	}
+	return m
+}
+
+func (m *MockOutboxRepository) ClaimPending(now time.Time, lease time.Duration, limit int) (*[]mdl.OutboxEvent, error) {
+	pending := make([]mdl.OutboxEvent, 0)
+	for _, e := range m.events {
+		if e.Published != nil {
+			continue
+		}
+		if e.ClaimedUntil != nil && e.ClaimedUntil.After(now) {
+			return &[]mdl.OutboxEvent{}, nil
+		}
+		pending = append(pending, *e)
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].ID < pending[j].ID })
+	if limit > 0 && len(pending) > limit {
+		pending = pending[:limit]
+	}
+
+	until := now.Add(lease)
+	for i := range pending {
+		pending[i].ClaimedUntil = &until
+		stored := pending[i]
+		m.events[stored.ID] = &stored
+	}
+	return &pending, nil
+}
+
+func (m *MockOutboxRepository) UpdateClaimed(events *[]mdl.OutboxEvent) error {
+	for i := range *events {
+		(*events)[i].ClaimedUntil = nil
+		stored := (*events)[i]
+		m.events[stored.ID] = &stored
+	}
+	return nil
+}
+
+func (m *MockOutboxRepository) FindOutboxStats() (*mdl.OutboxStats, error) {
+	stats := &mdl.OutboxStats{}
+	for _, e := range m.events {
+		if e.Published != nil {
+			continue
+		}
+		stats.Pending++
+		if stats.OldestPending == nil || e.Created.Before(*stats.OldestPending) {
+			created := e.Created
+			stats.OldestPending = &created
+		}
+	}
+	return stats, nil
+}
+
+func (m *MockOutboxRepository) PurgePublished(before time.Time) (int64, error) {
+	var purged int64
+	for id, e := range m.events {
+		if e.Published != nil && e.Published.Before(before) {
+			delete(m.events, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,145 @@
+package mock
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"sort"
+	"time"
+)
+
+// MockSnapshotRepository keeps snapshots and reads their content from the mock repositories
+// it is built from.
+type MockSnapshotRepository struct {
+	vocabs       *MockVocabRepository
+	alternatives *MockAlternativeRepository
+	examples     *MockExampleRepository
+	audio        *MockAudioRepository
+	audits       *MockAuditRepository
+	snapshots    map[int]*mdl.Snapshot
+	seq          int
+}
+
+// NewMockSnapshotRepository initializes and returns a new instance of MockSnapshotRepository.
+func NewMockSnapshotRepository(vocabs *MockVocabRepository, alternatives *MockAlternativeRepository,
+	examples *MockExampleRepository, audio *MockAudioRepository, audits *MockAuditRepository) *MockSnapshotRepository {
+	return &MockSnapshotRepository{
+		vocabs:       vocabs,
+		alternatives: alternatives,
+		examples:     examples,
+		audio:        audio,
+		audits:       audits,
+		snapshots:    make(map[int]*mdl.Snapshot),
+	}
+}
+
+func (m *MockSnapshotRepository) FindSnapshotContent(learningCode string) (*mdl.SnapshotContent, error) {
+	content := &mdl.SnapshotContent{}
+	inLanguage := make(map[int]bool)
+	for _, v := range m.vocabs.vocabs {
+		if v.LearningLangCode == learningCode {
+			content.Vocabs = append(content.Vocabs, *v)
+			inLanguage[v.ID] = true
+		}
+	}
+	sort.Slice(content.Vocabs, func(i, j int) bool { return content.Vocabs[i].ID < content.Vocabs[j].ID })
+
+	audio := make(map[int]bool)
+	for i := range content.Vocabs {
+		alternatives, _ := m.alternatives.FindAlternatives([]int{content.Vocabs[i].ID})
+		content.Vocabs[i].AlternativeList = *alternatives
+		if audioID := content.Vocabs[i].AudioID; audioID != nil && !audio[*audioID] {
+			audio[*audioID] = true
+			if asset, err := m.audio.FindAudioAssetByID(*audioID); err == nil {
+				content.AudioAssets = append(content.AudioAssets, *asset)
+			}
+		}
+	}
+
+	for _, e := range m.examples.examples {
+		if inLanguage[e.VocabID] {
+			content.Examples = append(content.Examples, *e)
+		}
+	}
+	sort.Slice(content.Examples, func(i, j int) bool { return content.Examples[i].ID < content.Examples[j].ID })
+
+	content.AuditID = m.audits.seq
+	return content, nil
+}
+
+func (m *MockSnapshotRepository) FindSnapshots(learningCode string) (*[]mdl.Snapshot, error) {
+	result := make([]mdl.Snapshot, 0)
+	for _, s := range m.snapshots {
+		if s.LearningLangCode == learningCode {
+			result = append(result, *s)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version > result[j].Version })
+	return &result, nil
+}
+
+func (m *MockSnapshotRepository) FindSnapshot(learningCode string, version int) (*mdl.Snapshot, error) {
+	for _, s := range m.snapshots {
+		if s.LearningLangCode == learningCode && s.Version == version {
+			found := *s
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("error finding snapshot version %d of %s", version, learningCode)
+}
+
+func (m *MockSnapshotRepository) FindLiveSnapshot(learningCode string) (*mdl.Snapshot, error) {
+	for _, s := range m.snapshots {
+		if s.LearningLangCode == learningCode && s.Live {
+			found := *s
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("error finding the live snapshot of %s", learningCode)
+}
+
+func (m *MockSnapshotRepository) CreateSnapshot(snapshot *mdl.Snapshot) error {
+	for _, s := range m.snapshots {
+		if s.LearningLangCode == snapshot.LearningLangCode && s.Version == snapshot.Version {
+			return fmt.Errorf("snapshot version %d of %s already exists", snapshot.Version, snapshot.LearningLangCode)
+		}
+	}
+	for _, id := range snapshot.AudioIDs {
+		if _, exists := m.audio.assets[id]; !exists {
+			return fmt.Errorf("error finding audio with id %d", id)
+		}
+	}
+	if snapshot.Live {
+		m.clearLive(snapshot.LearningLangCode)
+	}
+	for _, id := range snapshot.AudioIDs {
+		m.audio.published[id] = true
+	}
+
+	m.seq += 1
+	snapshot.ID = m.seq
+	if snapshot.Created.IsZero() {
+		snapshot.Created = time.Now()
+	}
+	stored := *snapshot
+	m.snapshots[snapshot.ID] = &stored
+	return nil
+}
+
+func (m *MockSnapshotRepository) SetLiveSnapshot(snapshot *mdl.Snapshot) error {
+	stored, exists := m.snapshots[snapshot.ID]
+	if !exists {
+		return fmt.Errorf("error finding snapshot with id %d", snapshot.ID)
+	}
+	m.clearLive(snapshot.LearningLangCode)
+	stored.Live = true
+	snapshot.Live = true
+	return nil
+}
+
+func (m *MockSnapshotRepository) clearLive(learningCode string) {
+	for _, s := range m.snapshots {
+		if s.LearningLangCode == learningCode {
+			s.Live = false
+		}
+	}
+}
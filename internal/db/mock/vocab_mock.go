@@ -2,18 +2,26 @@ package mock
 
 import (
 	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
 	"github.com/heather92115/verdure-admin/internal/mdl"
+	"sort"
 )
 
 type MockVocabRepository struct {
-	vocabs map[int]*mdl.Vocab
-	seq    int
+	vocabs       map[int]*mdl.Vocab
+	audits       *MockAuditRepository
+	alternatives *MockAlternativeRepository
+	seq          int
 }
 
-// NewMockVocabRepository initializes and returns a new instance of MockVocabRepository.
-func NewMockVocabRepository() *MockVocabRepository {
+// NewMockVocabRepository initializes and returns a new instance of MockVocabRepository saving
+// the audits of its changes to the audit repository and the alternatives of its vocab to the
+// alternative repository.
+func NewMockVocabRepository(audits *MockAuditRepository, alternatives *MockAlternativeRepository) *MockVocabRepository {
 	return &MockVocabRepository{
-		vocabs: make(map[int]*mdl.Vocab),
+		vocabs:       make(map[int]*mdl.Vocab),
+		audits:       audits,
+		alternatives: alternatives,
 	}
 }
 
@@ -24,9 +32,9 @@ func (m *MockVocabRepository) FindVocabByID(id int) (*mdl.Vocab, error) {
 	return nil, fmt.Errorf("error finding vocab with id %d", id)
 }
 
-func (m *MockVocabRepository) FindVocabByLearningLang(learningLang string) (vocab *mdl.Vocab, err error) {
+func (m *MockVocabRepository) FindVocabByLearningLang(learningLang string, learningCode string) (vocab *mdl.Vocab, err error) {
 	for _, v := range m.vocabs {
-		if v.LearningLang == learningLang {
+		if v.LearningLang == learningLang && v.LearningLangCode == learningCode {
 			return v, nil
 		}
 	}
@@ -48,17 +56,98 @@ func (m *MockVocabRepository) FindVocabs(learningCode string, hasFirst bool, lim
 	return &result, nil
 }
 
-func (m *MockVocabRepository) CreateVocab(vocab *mdl.Vocab) error {
+func (m *MockVocabRepository) ScanVocabs(learningCode string, batchSize int, fn func(batch *[]mdl.Vocab) error) error {
+	ids := make([]int, 0, len(m.vocabs))
+	for id, v := range m.vocabs {
+		if learningCode == "" || v.LearningLangCode == learningCode {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	for start := 0; start < len(ids); start += batchSize {
+		end := min(start+batchSize, len(ids))
+		batch := make([]mdl.Vocab, 0, end-start)
+		for _, id := range ids[start:end] {
+			batch = append(batch, *m.vocabs[id])
+		}
+		if err := fn(&batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MockVocabRepository) CreateVocab(vocab *mdl.Vocab, audits db.AuditBuilder) error {
 	m.seq += 1
 	vocab.ID = m.seq
+	if err := m.saveAlternatives(vocab); err != nil {
+		return err
+	}
+	if err := m.audits.createBuilt(audits); err != nil {
+		return err
+	}
 	m.vocabs[vocab.ID] = vocab
 	return nil
 }
 
-func (m *MockVocabRepository) UpdateVocab(vocab *mdl.Vocab) error {
+func (m *MockVocabRepository) UpdateVocab(vocab *mdl.Vocab, audits db.AuditBuilder) error {
 	if _, exists := m.vocabs[vocab.ID]; !exists {
 		return fmt.Errorf("error finding vocab with id %d", vocab.ID)
 	}
+	if err := m.audits.createBuilt(audits); err != nil {
+		return err
+	}
 	m.vocabs[vocab.ID] = vocab
 	return nil
 }
+
+func (m *MockVocabRepository) UpdateVocabAlternatives(vocab *mdl.Vocab, removedIDs []int, audits db.AuditBuilder) error {
+	if _, exists := m.vocabs[vocab.ID]; !exists {
+		return fmt.Errorf("error finding vocab with id %d", vocab.ID)
+	}
+	for _, id := range removedIDs {
+		if err := m.alternatives.DeleteAlternative(id); err != nil {
+			return err
+		}
+	}
+	if err := m.saveAlternatives(vocab); err != nil {
+		return err
+	}
+	return m.UpdateVocab(vocab, audits)
+}
+
+func (m *MockVocabRepository) UpdateVocabs(vocabs []*mdl.Vocab, audits db.AuditBuilder) error {
+	for _, vocab := range vocabs {
+		if _, exists := m.vocabs[vocab.ID]; !exists {
+			return fmt.Errorf("error finding vocab with id %d", vocab.ID)
+		}
+	}
+	if err := m.audits.createBuilt(audits); err != nil {
+		return err
+	}
+	for _, vocab := range vocabs {
+		m.vocabs[vocab.ID] = vocab
+	}
+	return nil
+}
+
+// saveAlternatives creates or updates the alternatives of the vocab, positioned in list order.
+func (m *MockVocabRepository) saveAlternatives(vocab *mdl.Vocab) error {
+	for i := range vocab.AlternativeList {
+		alternative := &vocab.AlternativeList[i]
+		alternative.VocabID = vocab.ID
+		alternative.Position = i
+
+		var err error
+		if alternative.ID == 0 {
+			err = m.alternatives.CreateAlternative(alternative)
+		} else {
+			err = m.alternatives.UpdateAlternative(alternative)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package mock
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"sort"
+	"time"
+)
+
+type MockWebhookRepository struct {
+	webhooks    map[int]*mdl.Webhook
+	deliveries  map[int]*mdl.WebhookDelivery
+	seq         int
+	deliverySeq int
+}
+
+// NewMockWebhookRepository initializes and returns a new instance of MockWebhookRepository.
+func NewMockWebhookRepository() *MockWebhookRepository {
+	return &MockWebhookRepository{
+		webhooks:   make(map[int]*mdl.Webhook),
+		deliveries: make(map[int]*mdl.WebhookDelivery),
+	}
+}
+
+func (m *MockWebhookRepository) FindWebhooks() (*[]mdl.Webhook, error) {
+	result := make([]mdl.Webhook, 0, len(m.webhooks))
+	for _, w := range m.webhooks {
+		result = append(result, *w)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return &result, nil
+}
+
+func (m *MockWebhookRepository) FindWebhookByID(id int) (*mdl.Webhook, error) {
+	if w, exists := m.webhooks[id]; exists {
+		found := *w
+		return &found, nil
+	}
+	return nil, fmt.Errorf("error finding webhook with id %d", id)
+}
+
+func (m *MockWebhookRepository) CreateWebhook(webhook *mdl.Webhook) error {
+	m.seq += 1
+	webhook.ID = m.seq
+	if webhook.Created.IsZero() {
+		webhook.Created = time.Now()
+	}
+	stored := *webhook
+	m.webhooks[webhook.ID] = &stored
+	return nil
+}
+
+func (m *MockWebhookRepository) UpdateWebhook(webhook *mdl.Webhook) error {
+	if _, exists := m.webhooks[webhook.ID]; !exists {
+		return fmt.Errorf("error finding webhook with id %d", webhook.ID)
+	}
+	stored := *webhook
+	m.webhooks[webhook.ID] = &stored
+	return nil
+}
+
+// createDeliveries queues the deliveries of an audit created by a MockAuditRepository.
+func (m *MockWebhookRepository) createDeliveries(deliveries []mdl.WebhookDelivery) {
+	for i := range deliveries {
+		m.deliverySeq += 1
+		deliveries[i].ID = m.deliverySeq
+		if deliveries[i].Created.IsZero() {
+			deliveries[i].Created = time.Now()
+		}
+		stored := deliveries[i]
+		m.deliveries[stored.ID] = &stored
+	}
+}
+
+func (m *MockWebhookRepository) FindDeliveryByID(id int) (*mdl.WebhookDelivery, error) {
+	if d, exists := m.deliveries[id]; exists {
+		found := *d
+		return &found, nil
+	}
+	return nil, fmt.Errorf("error finding webhook delivery with id %d", id)
+}
+
+func (m *MockWebhookRepository) FindDeliveries(webhookID int, status string, limit int) (*[]mdl.WebhookDelivery, error) {
+	result := make([]mdl.WebhookDelivery, 0)
+	for _, d := range m.deliveries {
+		if (webhookID == 0 || d.WebhookID == webhookID) && (status == "" || d.Status == status) {
+			result = append(result, *d)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID > result[j].ID })
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return &result, nil
+}
+
+func (m *MockWebhookRepository) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) (*[]mdl.WebhookDelivery, error) {
+	result := make([]mdl.WebhookDelivery, 0)
+	for _, d := range m.deliveries {
+		if d.Status == mdl.DeliveryPending && !d.NextAttempt.After(now) {
+			result = append(result, *d)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].NextAttempt.Equal(result[j].NextAttempt) {
+			return result[i].ID < result[j].ID
+		}
+		return result[i].NextAttempt.Before(result[j].NextAttempt)
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	for _, d := range result {
+		m.deliveries[d.ID].NextAttempt = now.Add(lease)
+	}
+	return &result, nil
+}
+
+func (m *MockWebhookRepository) UpdateDelivery(delivery *mdl.WebhookDelivery) error {
+	if _, exists := m.deliveries[delivery.ID]; !exists {
+		return fmt.Errorf("error finding webhook delivery with id %d", delivery.ID)
+	}
+	stored := *delivery
+	m.deliveries[delivery.ID] = &stored
+	return nil
+}
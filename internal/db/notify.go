@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/event"
+	"github.com/jackc/pgx/v5"
+	"log"
+	"time"
+)
+
+const (
+	// EventChannel is the Postgres notification channel events are relayed on.
+	EventChannel = "verdure_events"
+
+	// maxNotifyPayload is the largest payload Postgres accepts in a notification, in bytes.
+	maxNotifyPayload = 8000
+
+	// listenRetryInterval is how long the listener waits before reconnecting after a failure.
+	listenRetryInterval = 5 * time.Second
+)
+
+// notification is the payload of an event relayed on EventChannel. The origin identifies
+// the instance that published it, which has already delivered it to its own subscribers.
+type notification struct {
+	Origin string      `json:"origin"`
+	Event  event.Event `json:"event"`
+}
+
+// NotifyRelay is an event.Relay fanning events out to the other instances of the server with
+// Postgres LISTEN and NOTIFY. Every instance forwards the events it publishes with NOTIFY on
+// EventChannel and listens on the channel for the events of the others.
+type NotifyRelay struct {
+	dsn    string
+	origin string
+	bus    *event.Bus
+}
+
+// NewNotifyRelay creates a NotifyRelay delivering the events of other instances to the bus.
+//
+// Parameters:
+// - dsn: The connection string of the database, for the listening connection.
+// - bus: The bus of this instance.
+//
+// Returns:
+// - The relay, or an error if the origin of the instance cannot be generated.
+//
+// Usage example:
+// relay, err := db.NewNotifyRelay(dsn, event.Default())
+//
+//	if err != nil {
+//	    log.Fatalf("Failed to create the relay: %v", err)
+//	}
+//
+// event.Default().SetRelay(relay)
+// go relay.Listen(ctx)
+func NewNotifyRelay(dsn string, bus *event.Bus) (*NotifyRelay, error) {
+	origin := make([]byte, 8)
+	if _, err := rand.Read(origin); err != nil {
+		return nil, err
+	}
+
+	return &NotifyRelay{dsn: dsn, origin: hex.EncodeToString(origin), bus: bus}, nil
+}
+
+// Forward sends the event to the other instances with NOTIFY. Events larger than a notification
+// allows are not relayed, and are only seen by the subscribers of this instance.
+func (r *NotifyRelay) Forward(forwarded event.Event) error {
+	payload, err := json.Marshal(notification{Origin: r.origin, Event: forwarded})
+	if err != nil {
+		return err
+	}
+	if len(payload) >= maxNotifyPayload {
+		return fmt.Errorf("%s event of %d bytes is too large to notify", forwarded.Topic, len(payload))
+	}
+
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Exec("SELECT pg_notify(?, ?)", EventChannel, string(payload)).Error
+}
+
+// Listen delivers the events other instances notify on EventChannel to the bus until the
+// context is done. A dropped connection is logged and reopened, events notified while it is
+// down are missed.
+func (r *NotifyRelay) Listen(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := r.listen(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Event listener failed, reconnecting in %v: %v", listenRetryInterval, err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(listenRetryInterval):
+			}
+		}
+	}
+}
+
+// listen opens a connection listening on EventChannel and delivers its notifications until
+// the connection fails or the context is done.
+func (r *NotifyRelay) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, r.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err = conn.Exec(ctx, "LISTEN "+EventChannel); err != nil {
+		return err
+	}
+
+	for {
+		received, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var relayed notification
+		if err = json.Unmarshal([]byte(received.Payload), &relayed); err != nil {
+			log.Printf("Ignoring malformed event notification: %v", err)
+			continue
+		}
+		if relayed.Origin == r.origin {
+			continue
+		}
+		r.bus.Deliver(relayed.Event)
+	}
+}
@@ -0,0 +1,171 @@
+// Package db defines interfaces and implementations for interacting with
+// entities in the database. It includes the OutboxRepository interface, which outlines
+// the operations of the outbox relay, and the SQLOutboxRepository struct, which provides
+// a concrete implementation of the OutboxRepository using GORM.
+package db
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"gorm.io/gorm"
+	"time"
+)
+
+// outboxRelayLock is the Postgres advisory lock held while the outbox is claimed, so only one
+// relay claims at a time and the events of a record stay in order.
+const outboxRelayLock = 0x76657264
+
+// OutboxRepository defines the operations of the outbox relay on OutboxEvent entities.
+type OutboxRepository interface {
+	ClaimPending(now time.Time, lease time.Duration, limit int) (*[]mdl.OutboxEvent, error)
+	UpdateClaimed(events *[]mdl.OutboxEvent) error
+	FindOutboxStats() (*mdl.OutboxStats, error)
+	PurgePublished(before time.Time) (int64, error)
+}
+
+// SQLOutboxRepository provides a GORM-based implementation of the OutboxRepository interface.
+type SQLOutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewSqlOutboxRepository initializes a new SQLOutboxRepository with a database connection.
+func NewSqlOutboxRepository() (repo *SQLOutboxRepository, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	repo = &SQLOutboxRepository{db: db}
+
+	return
+}
+
+// saveWithOutbox runs the write of a change and then saves the outbox events of the change
+// in the same transaction, so either both are saved or neither is. The events are built once
+// the write has run, so they see the IDs it assigned. Writing the events after the change
+// keeps the row lock of an updated record held while its event gets its ID, so the events of
+// one record are numbered in commit order.
+func saveWithOutbox(db *gorm.DB, write func(tx *gorm.DB) error, events func() []mdl.OutboxEvent) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := write(tx); err != nil {
+			return err
+		}
+		return createOutboxEvents(tx, events())
+	})
+}
+
+// createOutboxEvents inserts the events of a change within its transaction.
+func createOutboxEvents(tx *gorm.DB, events []mdl.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := tx.Create(&events).Error; err != nil {
+		return fmt.Errorf("failed to write the outbox, error: %v", err)
+	}
+	return nil
+}
+
+// ClaimPending claims the oldest pending events for this instance to publish. The claim is
+// taken in a short transaction holding the relay advisory lock, and lasts for the lease, so
+// the events are published with no transaction open. Nothing is claimed while another
+// instance holds the lock or a claim that has not run out, so one relay publishes at a time
+// and the events of a record stay in order. A claim whose outcome is never saved, such as
+// when the instance stops, runs out and the events are claimed again.
+//
+// Parameters:
+// - now: The current time.
+// - lease: How long the claimed events are held.
+// - limit: The maximum number of events claimed.
+//
+// Returns:
+// - A pointer to the slice of claimed events, in ID order, empty when another relay holds them.
+// - An error if the claim fails, in which case nothing is claimed.
+func (repo *SQLOutboxRepository) ClaimPending(now time.Time, lease time.Duration, limit int) (list *[]mdl.OutboxEvent, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	list = &[]mdl.OutboxEvent{}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", outboxRelayLock).Scan(&locked).Error; err != nil || !locked {
+			return err
+		}
+
+		var claimed int64
+		err := tx.Model(&mdl.OutboxEvent{}).Where("published IS NULL AND claimed_until > ?", now).Count(&claimed).Error
+		if err != nil || claimed > 0 {
+			return err
+		}
+
+		if err := tx.Where("published IS NULL").Order("id").Limit(limit).Find(list).Error; err != nil || len(*list) == 0 {
+			return err
+		}
+
+		until := now.Add(lease)
+		ids := make([]int, len(*list))
+		for i := range *list {
+			ids[i] = (*list)[i].ID
+			(*list)[i].ClaimedUntil = &until
+		}
+		return tx.Model(&mdl.OutboxEvent{}).Where("id IN ?", ids).Update("claimed_until", until).Error
+	})
+	if err != nil {
+		return &[]mdl.OutboxEvent{}, err
+	}
+
+	return
+}
+
+// UpdateClaimed saves the outcome the relay recorded on each of the claimed events, such as
+// the published time or the failure of an attempt, and releases the claim, in one transaction.
+// Events published before a failure to save are published again once the claim runs out,
+// delivery is at least once.
+func (repo *SQLOutboxRepository) UpdateClaimed(events *[]mdl.OutboxEvent) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for i := range *events {
+			(*events)[i].ClaimedUntil = nil
+			if err := tx.Save(&(*events)[i]).Error; err != nil {
+				return fmt.Errorf("failed to save outbox event %d, error: %v", (*events)[i].ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// FindOutboxStats counts the pending events and finds when the oldest of them was saved.
+func (repo *SQLOutboxRepository) FindOutboxStats() (stats *mdl.OutboxStats, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	var row struct {
+		Pending       int64
+		OldestPending *time.Time
+	}
+	err = db.Model(&mdl.OutboxEvent{}).Select("COUNT(*) AS pending, MIN(created) AS oldest_pending").
+		Where("published IS NULL").Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &mdl.OutboxStats{Pending: row.Pending, OldestPending: row.OldestPending}, nil
+}
+
+// PurgePublished deletes the events published before a time, returning how many were deleted.
+func (repo *SQLOutboxRepository) PurgePublished(before time.Time) (int64, error) {
+	db, err := GetConnection()
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	result := db.Where("published < ?", before).Delete(&mdl.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
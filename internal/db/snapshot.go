@@ -0,0 +1,210 @@
+// Package db defines interfaces and implementations for interacting with
+// entities in the database. It includes the SnapshotRepository interface, which outlines
+// operations for published snapshots and the content they are built from, and the
+// SQLSnapshotRepository struct, which provides a concrete implementation of the
+// SnapshotRepository using GORM.
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"gorm.io/gorm"
+	"log"
+)
+
+// SnapshotRepository defines the operations available for Snapshot entities.
+type SnapshotRepository interface {
+	FindSnapshotContent(learningCode string) (*mdl.SnapshotContent, error)
+	FindSnapshots(learningCode string) (*[]mdl.Snapshot, error)
+	FindSnapshot(learningCode string, version int) (*mdl.Snapshot, error)
+	FindLiveSnapshot(learningCode string) (*mdl.Snapshot, error)
+	CreateSnapshot(snapshot *mdl.Snapshot) error
+	SetLiveSnapshot(snapshot *mdl.Snapshot) error
+}
+
+// SQLSnapshotRepository provides a GORM-based implementation of the SnapshotRepository interface.
+type SQLSnapshotRepository struct {
+	db *gorm.DB
+}
+
+// NewSqlSnapshotRepository initializes a new SQLSnapshotRepository with a database connection.
+func NewSqlSnapshotRepository() (repo *SQLSnapshotRepository, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	repo = &SQLSnapshotRepository{db: db}
+
+	return
+}
+
+// FindSnapshotContent reads the vocab of a learning language along with their alternatives,
+// example sentences and audio assets, the ID of the last audit and the Postgres snapshot of the
+// reads, in one read only repeatable read transaction. Every read sees the database as of the
+// same moment, so edits saved while the content is read are either wholly in it or wholly left
+// out. Edits are audited in the transaction saving them, so the audits of the transactions
+// visible in the snapshot are exactly those of the edits in the content.
+//
+// Parameters:
+// - learningCode: The learning language code of the vocab, e.g. "es".
+//
+// Returns:
+// - A pointer to the content read.
+// - An error if any of the reads fail.
+func (repo *SQLSnapshotRepository) FindSnapshotContent(learningCode string) (content *mdl.SnapshotContent, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	content = &mdl.SnapshotContent{}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("learning_lang_code = ?", learningCode).Order("id").Find(&content.Vocabs).Error; err != nil {
+			return err
+		}
+		vocabIDs := tx.Model(&mdl.Vocab{}).Select("id").Where("learning_lang_code = ?", learningCode)
+
+		var alternatives []mdl.VocabAlternative
+		if err := tx.Where("vocab_id IN (?)", vocabIDs).Order("vocab_id, position").Find(&alternatives).Error; err != nil {
+			return err
+		}
+		attachAlternatives(content.Vocabs, alternatives)
+
+		if err := tx.Where("vocab_id IN (?)", vocabIDs).Order("id").Find(&content.Examples).Error; err != nil {
+			return err
+		}
+
+		audioIDs := tx.Model(&mdl.Vocab{}).Select("audio_id").
+			Where("learning_lang_code = ? AND audio_id IS NOT NULL", learningCode)
+		if err := tx.Where("id IN (?)", audioIDs).Order("id").Find(&content.AudioAssets).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&mdl.Audit{}).Select("COALESCE(MAX(id), 0)").Scan(&content.AuditID).Error; err != nil {
+			return err
+		}
+		return tx.Raw("SELECT pg_current_snapshot()::text").Scan(&content.TxSnapshot).Error
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		log.Printf("Error reading the snapshot content of learning code '%s': %v", learningCode, err)
+		return nil, err
+	}
+
+	return
+}
+
+// attachAlternatives sets the AlternativeList of each vocab from the alternatives, which are
+// ordered by vocab ID and position.
+func attachAlternatives(vocabs []mdl.Vocab, alternatives []mdl.VocabAlternative) {
+	byVocab := make(map[int][]mdl.VocabAlternative)
+	for _, alternative := range alternatives {
+		byVocab[alternative.VocabID] = append(byVocab[alternative.VocabID], alternative)
+	}
+	for i := range vocabs {
+		vocabs[i].AlternativeList = byVocab[vocabs[i].ID]
+	}
+}
+
+// FindSnapshots retrieves the snapshots of a learning language, newest version first.
+func (repo *SQLSnapshotRepository) FindSnapshots(learningCode string) (list *[]mdl.Snapshot, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	list = &[]mdl.Snapshot{}
+	err = db.Where("learning_lang_code = ?", learningCode).Order("version DESC").Find(list).Error
+	if err != nil {
+		log.Printf("Error finding snapshots of learning code '%s': %v", learningCode, err)
+	}
+
+	return
+}
+
+// FindSnapshot retrieves a version of the snapshots of a learning language.
+func (repo *SQLSnapshotRepository) FindSnapshot(learningCode string, version int) (snapshot *mdl.Snapshot, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	snapshot = &mdl.Snapshot{}
+	err = db.Where("learning_lang_code = ? AND version = ?", learningCode, version).First(snapshot).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding snapshot version %d of %s, %v", version, learningCode, err)
+	}
+
+	return
+}
+
+// FindLiveSnapshot retrieves the snapshot of a learning language served to learners.
+func (repo *SQLSnapshotRepository) FindLiveSnapshot(learningCode string) (snapshot *mdl.Snapshot, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	snapshot = &mdl.Snapshot{}
+	err = db.Where("learning_lang_code = ? AND live", learningCode).First(snapshot).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding the live snapshot of %s, %v", learningCode, err)
+	}
+
+	return
+}
+
+// CreateSnapshot inserts a new snapshot, setting its ID, along with the SnapshotAudio of the
+// audio assets it serves. A live snapshot takes the place of the live snapshot of its learning
+// language, in one transaction. A version that is already taken, such as by a snapshot
+// published at the same time, fails on the unique index, and an audio asset deleted since the
+// content was read fails on its foreign key.
+func (repo *SQLSnapshotRepository) CreateSnapshot(snapshot *mdl.Snapshot) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if snapshot.Live {
+			if err := clearLiveSnapshot(tx, snapshot.LearningLangCode); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(snapshot).Error; err != nil {
+			return err
+		}
+		if len(snapshot.AudioIDs) == 0 {
+			return nil
+		}
+		audio := make([]mdl.SnapshotAudio, len(snapshot.AudioIDs))
+		for i, id := range snapshot.AudioIDs {
+			audio[i] = mdl.SnapshotAudio{SnapshotID: snapshot.ID, AudioID: id}
+		}
+		return tx.Create(&audio).Error
+	})
+}
+
+// SetLiveSnapshot makes the snapshot the one served to learners of its learning language, in
+// place of the live one.
+func (repo *SQLSnapshotRepository) SetLiveSnapshot(snapshot *mdl.Snapshot) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := clearLiveSnapshot(tx, snapshot.LearningLangCode); err != nil {
+			return err
+		}
+		snapshot.Live = true
+		return tx.Model(snapshot).Update("live", true).Error
+	})
+}
+
+// clearLiveSnapshot takes the live flag from the snapshots of the learning language.
+func clearLiveSnapshot(tx *gorm.DB, learningCode string) error {
+	return tx.Model(&mdl.Snapshot{}).Where("learning_lang_code = ? AND live", learningCode).
+		Update("live", false).Error
+}
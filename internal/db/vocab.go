@@ -13,15 +13,39 @@ import (
 	"github.com/heather92115/verdure-admin/internal/mdl"
 	"gorm.io/gorm"
 	"log"
+	"strings"
 )
 
+// NormalizedLearningLangSQL is the SQL form of the service layer text normalization applied
+// to the learning_lang column: NFC composition, typographic quotes and Unicode spaces mapped
+// to their ASCII forms, zero width characters removed, and whitespace runs collapsed and trimmed.
+// It backs the idx_vocab_learning_lang_code_norm unique index and must be kept in step with
+// srv.NormalizeText.
+var NormalizedLearningLangSQL = normalizedTextSQL("learning_lang")
+
+// normalizedTextSQL builds the normalization expression for the given column.
+func normalizedTextSQL(column string) string {
+	// translate maps each character of from to the character at the same position in to,
+	// characters of from beyond the end of to are removed.
+	from := "‘’‚‛′`“”„‟″" +
+		"\u00a0\u1680\u2000\u2001\u2002\u2003\u2004\u2005\u2006\u2007\u2008\u2009\u200a\u202f\u205f\u3000" +
+		"\u200b\u200c\u200d\u2060\ufeff"
+	to := strings.Repeat("'", 6) + strings.Repeat(`"`, 5) + strings.Repeat(" ", 16)
+
+	return fmt.Sprintf(`btrim(regexp_replace(translate(normalize(%s, NFC), '%s', '%s'), '\s+', ' ', 'g'))`,
+		column, strings.ReplaceAll(from, "'", "''"), strings.ReplaceAll(to, "'", "''"))
+}
+
 // VocabRepository defines the operations available for a Vocab entity.
 type VocabRepository interface {
 	FindVocabByID(id int) (*mdl.Vocab, error)
-	FindVocabByLearningLang(learningLang string) (vocab *mdl.Vocab, err error)
+	FindVocabByLearningLang(learningLang string, learningCode string) (vocab *mdl.Vocab, err error)
 	FindVocabs(learningCode string, hasFirst bool, limit int) (*[]mdl.Vocab, error)
-	CreateVocab(vocab *mdl.Vocab) error
-	UpdateVocab(vocab *mdl.Vocab) error
+	ScanVocabs(learningCode string, batchSize int, fn func(batch *[]mdl.Vocab) error) error
+	CreateVocab(vocab *mdl.Vocab, audits AuditBuilder) error
+	UpdateVocab(vocab *mdl.Vocab, audits AuditBuilder) error
+	UpdateVocabAlternatives(vocab *mdl.Vocab, removedIDs []int, audits AuditBuilder) error
+	UpdateVocabs(vocabs []*mdl.Vocab, audits AuditBuilder) error
 }
 
 // SQLVocabRepository provides a GORM-based implementation of the VocabRepository interface.
@@ -83,13 +107,16 @@ func (repo *SQLVocabRepository) FindVocabByID(id int) (vocab *mdl.Vocab, err err
 
 // FindVocabByLearningLang retrieves a Vocab record from the database based on the learning language.
 //
-// This function searches the database for a Vocab record that matches the specified learning language string.
-// The learning language is expected to be unique for each record, hence only one record should match the criteria.
+// This function searches the database for a Vocab record of the learning language code whose normalized
+// learning language matches the specified learning language string, which is expected to already be
+// normalized. The normalized learning language is unique within each learning language code, hence only
+// one record should match the criteria.
 // If the connection to the database cannot be established, or if no record is found matching the given learning language,
 // the function returns an error detailing the issue encountered.
 //
 // Parameters:
 // - learningLang: A string representing the learning language of the Vocab record to retrieve.
+// - learningCode: The code of the learning language, e.g. "es".
 //
 // Returns:
 //   - *mdl.Vocab: A pointer to the retrieved Vocab record. If no record is found or in case of an error, nil is returned.
@@ -98,7 +125,7 @@ func (repo *SQLVocabRepository) FindVocabByID(id int) (vocab *mdl.Vocab, err err
 //     matching the provided learning language. In cases where the operation succeeds, nil is returned for the error.
 //
 // Usage example:
-// vocab, err := FindVocabByLearningLang("English")
+// vocab, err := FindVocabByLearningLang("perro", "es")
 //
 //	if err != nil {
 //	    log.Printf("An error occurred: %v", err)
@@ -106,14 +133,15 @@ func (repo *SQLVocabRepository) FindVocabByID(id int) (vocab *mdl.Vocab, err err
 //
 //	    fmt.Printf("Retrieved vocab: %+v\n", vocab)
 //	}
-func (repo *SQLVocabRepository) FindVocabByLearningLang(learningLang string) (vocab *mdl.Vocab, err error) {
+func (repo *SQLVocabRepository) FindVocabByLearningLang(learningLang string, learningCode string) (vocab *mdl.Vocab, err error) {
 	db, err := GetConnection()
 	if err != nil {
 		return
 	}
 
 	// Use the `Where` method to specify the search condition
-	result := db.Where("learning_lang = ?", learningLang).First(&vocab)
+	result := db.Where("learning_lang_code = ?", learningCode).
+		Where(NormalizedLearningLangSQL+" = ?", learningLang).First(&vocab)
 	if result.Error != nil {
 		err = fmt.Errorf("error finding vocab with learning lang %s: %v", learningLang, result.Error)
 	}
@@ -179,36 +207,177 @@ func (repo *SQLVocabRepository) FindVocabs(learningCode string, hasFirst bool, l
 	return
 }
 
-// CreateVocab inserts a new Vocab record into the database.
-// It establishes a database connection, then attempts to insert the provided Vocab instance.
-// Returns an error if the database connection fails or if the insert operation encounters an error.
-func (repo *SQLVocabRepository) CreateVocab(vocab *mdl.Vocab) error {
+// ScanVocabs walks every Vocab record with the given learning language code in primary key
+// order, handing them to the callback one batch at a time. It is intended for maintenance
+// jobs that need to inspect the whole table without loading it into memory at once.
+//
+// Parameters:
+//   - learningCode: The code of the learning language to filter records by. An empty code scans
+//     every record.
+//   - batchSize: The maximum number of records handed to each callback.
+//   - fn: Called with each batch. Returning an error stops the scan and the error is returned.
+//
+// Returns:
+// - An error if the database connection fails, the query fails, or the callback returns an error.
+//
+// Example of usage:
+//
+//	err := repo.ScanVocabs("es", 500, func(batch *[]mdl.Vocab) error {
+//	    for _, vocab := range *batch {
+//	        fmt.Println(vocab)
+//	    }
+//	    return nil
+//	})
+func (repo *SQLVocabRepository) ScanVocabs(learningCode string, batchSize int, fn func(batch *[]mdl.Vocab) error) error {
 	db, err := GetConnection()
 	if err != nil {
 		return fmt.Errorf("failed to connect to the db, error: %v", err)
 	}
 
-	result := db.Create(vocab)
+	query := db.Order("id")
+	if len(learningCode) > 0 {
+		query = query.Where("learning_lang_code = ?", learningCode)
+	}
+
+	batch := []mdl.Vocab{}
+	result := query.FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(&batch)
+	})
 	if result.Error != nil {
+		log.Printf("Error scanning vocab records with learning code '%s': %v", learningCode, result.Error)
 		return result.Error
 	}
 
 	return nil
 }
 
-// UpdateVocab updates an existing Vocab record in the database.
+// CreateVocab inserts a new Vocab record into the database along with its alternatives, its
+// vocab.created outbox event and its audits, in one transaction.
+// It establishes a database connection, then attempts to insert the provided Vocab instance.
+// The audits are built once the vocab has its ID, see AuditBuilder.
+// Returns an error if the database connection fails or if the insert operation encounters an error.
+func (repo *SQLVocabRepository) CreateVocab(vocab *mdl.Vocab, audits AuditBuilder) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		return createVocab(tx, vocab, audits)
+	})
+}
+
+// createVocab inserts a new Vocab record, its alternatives, its vocab.created outbox event and
+// its audits within a transaction.
+func createVocab(tx *gorm.DB, vocab *mdl.Vocab, audits AuditBuilder) error {
+	if err := tx.Create(vocab).Error; err != nil {
+		return err
+	}
+	if err := saveAlternatives(tx, vocab); err != nil {
+		return err
+	}
+	if err := createOutboxEvents(tx, []mdl.OutboxEvent{mdl.NewOutboxEvent(mdl.AggregateVocab, vocab.ID, "created", vocab.JSON())}); err != nil {
+		return err
+	}
+	return createBuiltAudits(tx, audits)
+}
+
+// UpdateVocab updates an existing Vocab record in the database along with its vocab.updated
+// outbox event and its audits, in one transaction.
 // It establishes a database connection, then attempts to update the Vocab instance based on its ID.
 // Returns an error if the database connection fails or if the update operation encounters an error.
-func (repo *SQLVocabRepository) UpdateVocab(vocab *mdl.Vocab) error {
+func (repo *SQLVocabRepository) UpdateVocab(vocab *mdl.Vocab, audits AuditBuilder) error {
 	db, err := GetConnection()
 	if err != nil {
 		return fmt.Errorf("failed to connect to the db, error: %v", err)
 	}
 
-	result := db.Save(vocab)
-	if result.Error != nil {
-		return result.Error
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := updateVocab(tx, vocab); err != nil {
+			return err
+		}
+		return createBuiltAudits(tx, audits)
+	})
+}
+
+// UpdateVocabAlternatives updates an existing Vocab record whose alternatives changed along
+// with its alternative records, its vocab.updated outbox event and its audits, in one
+// transaction, so the records always match the legacy delimited copy saved with the vocab.
+//
+// Parameters:
+// - vocab: The vocab with its complete AlternativeList, saved in the order of the list.
+// - removedIDs: The IDs of the alternative records no longer in the list, which are deleted.
+// - audits: Builds the audits of the change, see AuditBuilder.
+//
+// Returns:
+// - An error if the database connection fails or any statement fails, in which case nothing is saved.
+func (repo *SQLVocabRepository) UpdateVocabAlternatives(vocab *mdl.Vocab, removedIDs []int, audits AuditBuilder) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
 	}
 
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := updateVocab(tx, vocab); err != nil {
+			return err
+		}
+		if len(removedIDs) > 0 {
+			err := tx.Where("vocab_id = ? AND id IN ?", vocab.ID, removedIDs).Delete(&mdl.VocabAlternative{}).Error
+			if err != nil {
+				return fmt.Errorf("failed to delete alternatives of vocab %d, error: %v", vocab.ID, err)
+			}
+		}
+		if err := saveAlternatives(tx, vocab); err != nil {
+			return err
+		}
+		return createBuiltAudits(tx, audits)
+	})
+}
+
+// UpdateVocabs updates existing Vocab records in the database along with their vocab.updated
+// outbox events and their audits, in one transaction, so a bulk change is saved for every
+// vocab or for none.
+//
+// Parameters:
+// - vocabs: The vocab to save.
+// - audits: Builds the audits of the changes, see AuditBuilder.
+//
+// Returns:
+// - An error if the database connection fails or any statement fails, in which case nothing is saved.
+func (repo *SQLVocabRepository) UpdateVocabs(vocabs []*mdl.Vocab, audits AuditBuilder) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, vocab := range vocabs {
+			if err := updateVocab(tx, vocab); err != nil {
+				return fmt.Errorf("failed to save vocab %d, error: %v", vocab.ID, err)
+			}
+		}
+		return createBuiltAudits(tx, audits)
+	})
+}
+
+// saveAlternatives creates or updates the alternative records of a vocab within a transaction,
+// positioned in the order of its AlternativeList.
+func saveAlternatives(tx *gorm.DB, vocab *mdl.Vocab) error {
+	for i := range vocab.AlternativeList {
+		alternative := &vocab.AlternativeList[i]
+		alternative.VocabID = vocab.ID
+		alternative.Position = i
+		if err := tx.Save(alternative).Error; err != nil {
+			return fmt.Errorf("failed to save alternative %s, error: %v", alternative.Alternative, err)
+		}
+	}
 	return nil
 }
+
+// updateVocab saves an existing Vocab record and its vocab.updated outbox event within a transaction.
+func updateVocab(tx *gorm.DB, vocab *mdl.Vocab) error {
+	if err := tx.Save(vocab).Error; err != nil {
+		return err
+	}
+	return createOutboxEvents(tx, []mdl.OutboxEvent{mdl.NewOutboxEvent(mdl.AggregateVocab, vocab.ID, "updated", vocab.JSON())})
+}
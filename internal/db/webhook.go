@@ -0,0 +1,192 @@
+// Package db defines interfaces and implementations for interacting with
+// entities in the database. It includes the WebhookRepository interface, which outlines
+// operations for registered webhooks and their deliveries, and the SQLWebhookRepository
+// struct, which provides a concrete implementation of the WebhookRepository using GORM.
+package db
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log"
+	"time"
+)
+
+// WebhookRepository defines the operations available for Webhook and WebhookDelivery entities.
+type WebhookRepository interface {
+	FindWebhooks() (*[]mdl.Webhook, error)
+	FindWebhookByID(id int) (*mdl.Webhook, error)
+	CreateWebhook(webhook *mdl.Webhook) error
+	UpdateWebhook(webhook *mdl.Webhook) error
+	FindDeliveryByID(id int) (*mdl.WebhookDelivery, error)
+	FindDeliveries(webhookID int, status string, limit int) (*[]mdl.WebhookDelivery, error)
+	ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) (*[]mdl.WebhookDelivery, error)
+	UpdateDelivery(delivery *mdl.WebhookDelivery) error
+}
+
+// SQLWebhookRepository provides a GORM-based implementation of the WebhookRepository interface.
+type SQLWebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewSqlWebhookRepository initializes a new SQLWebhookRepository with a database connection.
+func NewSqlWebhookRepository() (repo *SQLWebhookRepository, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	repo = &SQLWebhookRepository{db: db}
+
+	return
+}
+
+// FindWebhooks retrieves every registered webhook, ordered by primary ID.
+func (repo *SQLWebhookRepository) FindWebhooks() (list *[]mdl.Webhook, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	list = &[]mdl.Webhook{}
+	err = db.Order("id").Find(list).Error
+	if err != nil {
+		log.Printf("Error finding webhooks: %v", err)
+	}
+
+	return
+}
+
+// FindWebhookByID retrieves a registered webhook by its primary ID.
+func (repo *SQLWebhookRepository) FindWebhookByID(id int) (webhook *mdl.Webhook, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	webhook = &mdl.Webhook{}
+	if err = db.First(webhook, id).Error; err != nil {
+		return nil, fmt.Errorf("error finding webhook with id %d, %v", id, err)
+	}
+
+	return
+}
+
+// CreateWebhook inserts a new webhook, setting its ID.
+func (repo *SQLWebhookRepository) CreateWebhook(webhook *mdl.Webhook) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Create(webhook).Error
+}
+
+// UpdateWebhook saves every field of an existing webhook.
+func (repo *SQLWebhookRepository) UpdateWebhook(webhook *mdl.Webhook) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Save(webhook).Error
+}
+
+// FindDeliveryByID retrieves a webhook delivery by its primary ID.
+func (repo *SQLWebhookRepository) FindDeliveryByID(id int) (delivery *mdl.WebhookDelivery, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	delivery = &mdl.WebhookDelivery{}
+	if err = db.First(delivery, id).Error; err != nil {
+		return nil, fmt.Errorf("error finding webhook delivery with id %d, %v", id, err)
+	}
+
+	return
+}
+
+// FindDeliveries retrieves the deliveries of a webhook with a status, newest first.
+//
+// Parameters:
+// - webhookID: The primary ID of the webhook, or 0 for the deliveries of every webhook.
+// - status: The delivery status, e.g. mdl.DeliveryFailed, or empty for every status.
+// - limit: The maximum number of deliveries returned.
+//
+// Returns:
+// - A pointer to the slice of deliveries found.
+// - An error if the query fails.
+func (repo *SQLWebhookRepository) FindDeliveries(webhookID int, status string, limit int) (list *[]mdl.WebhookDelivery, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return
+	}
+
+	query := db.Order("id DESC").Limit(limit)
+	if webhookID > 0 {
+		query = query.Where("webhook_id = ?", webhookID)
+	}
+	if len(status) > 0 {
+		query = query.Where("status = ?", status)
+	}
+
+	list = &[]mdl.WebhookDelivery{}
+	err = query.Find(list).Error
+	if err != nil {
+		log.Printf("Error finding webhook deliveries: %v", err)
+	}
+
+	return
+}
+
+// ClaimDueDeliveries claims the pending deliveries due by now for this instance. Claimed
+// deliveries have their next attempt moved out by the lease, so other instances polling for
+// due deliveries skip them while they are attempted, and a delivery whose attempt is never
+// recorded, such as when the instance stops, is attempted again once the lease runs out.
+// Rows locked by another instance claiming at the same time are skipped.
+//
+// Parameters:
+// - now: The current time.
+// - lease: How long the claimed deliveries are held.
+// - limit: The maximum number of deliveries claimed.
+//
+// Returns:
+// - A pointer to the slice of claimed deliveries, oldest due first.
+// - An error if the claim fails, in which case nothing is claimed.
+func (repo *SQLWebhookRepository) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) (list *[]mdl.WebhookDelivery, err error) {
+	db, err := GetConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	list = &[]mdl.WebhookDelivery{}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt <= ?", mdl.DeliveryPending, now).
+			Order("next_attempt").Limit(limit).Find(list).Error
+		if err != nil || len(*list) == 0 {
+			return err
+		}
+
+		ids := make([]int, len(*list))
+		for i, delivery := range *list {
+			ids[i] = delivery.ID
+		}
+		return tx.Model(&mdl.WebhookDelivery{}).Where("id IN ?", ids).
+			Update("next_attempt", now.Add(lease)).Error
+	})
+
+	return
+}
+
+// UpdateDelivery saves every field of an existing webhook delivery.
+func (repo *SQLWebhookRepository) UpdateDelivery(delivery *mdl.WebhookDelivery) error {
+	db, err := GetConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the db, error: %v", err)
+	}
+
+	return db.Save(delivery).Error
+}
@@ -0,0 +1,157 @@
+// Package event is an in-process publish and subscribe bus for changes to the admin data,
+// such as updated vocab or new fixits. Services publish an event once a change is saved and
+// GraphQL subscriptions deliver it to clients. A Relay fans events out to the other instances
+// of the server, see db.NotifyRelay.
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+)
+
+// subscriptionBuffer is the number of events held for a subscriber before further events are
+// dropped for it, so a slow client never blocks a publisher.
+const subscriptionBuffer = 32
+
+// Event is a change published on a topic.
+//
+// Fields:
+//   - Topic: The kind of change, e.g. "vocab_changed".
+//   - Payload: The JSON of the changed record.
+type Event struct {
+	Topic   string          `json:"topic"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Relay forwards the events published on this instance to the other instances of the server,
+// which Deliver them to their own subscribers.
+type Relay interface {
+	Forward(event Event) error
+}
+
+// subscription is a subscriber to a topic.
+type subscription struct {
+	topic  string
+	events chan Event
+}
+
+// Bus delivers the events published on a topic to its subscribers. The zero value is not
+// usable, see NewBus.
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions map[*subscription]struct{}
+	relay         Relay
+}
+
+// NewBus creates a Bus without subscribers or relay.
+func NewBus() *Bus {
+	return &Bus{subscriptions: make(map[*subscription]struct{})}
+}
+
+// defaultBus is the bus of the server, shared by the services constructed per request.
+var defaultBus = NewBus()
+
+// Default returns the bus of the server.
+func Default() *Bus {
+	return defaultBus
+}
+
+// SetRelay sets the relay forwarding published events to the other instances, nil for none.
+func (b *Bus) SetRelay(relay Relay) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.relay = relay
+}
+
+// Publish delivers the value, as JSON, to the subscribers of the topic and forwards it through
+// the relay. Publishing happens once the change is saved, so a failure to forward it is logged
+// rather than failing the change.
+//
+// Parameters:
+// - topic: The topic of the event.
+// - value: The changed record, marshalled as the payload.
+//
+// Returns:
+// - An error if the value cannot be marshalled.
+//
+// Usage example:
+// err := event.Default().Publish("fixit_changed", fixit)
+//
+//	if err != nil {
+//	    log.Printf("Failed to publish: %v", err)
+//	}
+func (b *Bus) Publish(topic string, value interface{}) error {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	published := Event{Topic: topic, Payload: payload}
+	b.Deliver(published)
+
+	b.mu.RLock()
+	relay := b.relay
+	b.mu.RUnlock()
+
+	if relay != nil {
+		if err = relay.Forward(published); err != nil {
+			log.Printf("Failed to forward %s event: %v", topic, err)
+		}
+	}
+
+	return nil
+}
+
+// Deliver hands the event to the subscribers of its topic on this instance only. An event is
+// dropped for a subscriber whose buffer is full.
+func (b *Bus) Deliver(delivered Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscriptions {
+		if sub.topic != delivered.Topic {
+			continue
+		}
+		select {
+		case sub.events <- delivered:
+		default:
+			log.Printf("Dropped %s event for a slow subscriber", delivered.Topic)
+		}
+	}
+}
+
+// Subscribe returns the events published on the topic until the context is done, when the
+// channel is closed.
+//
+// Parameters:
+// - ctx: The context of the subscriber, such as the context of a GraphQL subscription.
+// - topic: The topic to receive.
+//
+// Returns:
+// - The channel of events.
+//
+// Usage example:
+// events := event.Default().Subscribe(ctx, "audit_created")
+//
+//	for published := range events {
+//	    log.Printf("Audit created: %s", published.Payload)
+//	}
+func (b *Bus) Subscribe(ctx context.Context, topic string) <-chan Event {
+	sub := &subscription{topic: topic, events: make(chan Event, subscriptionBuffer)}
+
+	b.mu.Lock()
+	b.subscriptions[sub] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subscriptions, sub)
+		b.mu.Unlock()
+		close(sub.events)
+	}()
+
+	return sub.events
+}
@@ -0,0 +1,90 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type mockRelay struct {
+	forwarded []Event
+}
+
+func (r *mockRelay) Forward(event Event) error {
+	r.forwarded = append(r.forwarded, event)
+	return nil
+}
+
+func receive(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case received := <-events:
+		return received
+	case <-time.After(time.Second):
+		t.Fatalf("no event received")
+		return Event{}
+	}
+}
+
+func TestBus_PublishSubscribe(t *testing.T) {
+	bus := NewBus()
+	relay := &mockRelay{}
+	bus.SetRelay(relay)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	vocabs := bus.Subscribe(ctx, "vocab_changed")
+	fixits := bus.Subscribe(ctx, "fixit_changed")
+
+	if err := bus.Publish("vocab_changed", map[string]int{"id": 7}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if received := receive(t, vocabs); received.Topic != "vocab_changed" || string(received.Payload) != `{"id":7}` {
+		t.Errorf("Subscribe() received %+v", received)
+	}
+	select {
+	case received := <-fixits:
+		t.Errorf("Subscribe() received an event of another topic, %+v", received)
+	default:
+	}
+	if len(relay.forwarded) != 1 || relay.forwarded[0].Topic != "vocab_changed" {
+		t.Errorf("Publish() forwarded %+v", relay.forwarded)
+	}
+
+	// Delivered events come from other instances and are not forwarded again
+	bus.Deliver(Event{Topic: "fixit_changed", Payload: []byte(`{"id":3}`)})
+	if received := receive(t, fixits); string(received.Payload) != `{"id":3}` {
+		t.Errorf("Deliver() received %+v", received)
+	}
+	if len(relay.forwarded) != 1 {
+		t.Errorf("Deliver() forwarded %+v", relay.forwarded)
+	}
+
+	cancel()
+	if _, open := <-vocabs; open {
+		t.Errorf("Subscribe() channel open after the context is done")
+	}
+}
+
+func TestBus_SlowSubscriber(t *testing.T) {
+	bus := NewBus()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := bus.Subscribe(ctx, "audit_created")
+
+	// The publisher is never blocked, events beyond the buffer are dropped
+	for i := 0; i < subscriptionBuffer+5; i++ {
+		if err := bus.Publish("audit_created", i); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	if len(events) != subscriptionBuffer {
+		t.Errorf("Subscribe() buffered %d events, want %d", len(events), subscriptionBuffer)
+	}
+	if received := receive(t, events); string(received.Payload) != fmt.Sprint(0) {
+		t.Errorf("Subscribe() first event = %s", received.Payload)
+	}
+}
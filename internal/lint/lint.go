@@ -0,0 +1,103 @@
+// Package lint runs content quality rules over vocab records. Each rule inspects a single
+// mdl.Vocab and reports findings against one of its fields. Rules are plain values, so new
+// ones are added by implementing Rule, or wrapping a function with RuleFunc, and passing
+// them to NewEngine.
+package lint
+
+import (
+	"github.com/heather92115/verdure-admin/internal/mdl"
+)
+
+// Finding is a single problem reported by a rule for a vocab field.
+//
+// Fields:
+//   - Rule: The name of the rule reporting the problem, e.g. "missing-hint".
+//   - FieldName: The vocab field with the problem, named as in a Fixit, e.g. "hint".
+//   - Message: A human readable description of the problem, used as the Fixit comment.
+type Finding struct {
+	Rule      string
+	FieldName string
+	Message   string
+}
+
+// Rule checks a vocab for one kind of problem.
+type Rule interface {
+	// Name identifies the rule in findings, it must be unique within an engine.
+	Name() string
+	// Check returns the problems found in the vocab, or nothing when it passes.
+	Check(vocab *mdl.Vocab) []Finding
+}
+
+// RuleFunc adapts a function to the Rule interface.
+type RuleFunc struct {
+	RuleName string
+	Fn       func(vocab *mdl.Vocab) []Finding
+}
+
+// Name returns the rule name.
+func (r RuleFunc) Name() string {
+	return r.RuleName
+}
+
+// Check calls the wrapped function.
+func (r RuleFunc) Check(vocab *mdl.Vocab) []Finding {
+	return r.Fn(vocab)
+}
+
+// Engine runs a set of rules over vocab.
+type Engine struct {
+	rules         []Rule
+	languageRules func(langCode string) []Rule
+}
+
+// NewEngine creates an engine running the given rules, in order.
+//
+// Usage example:
+// engine := lint.NewEngine(lint.DefaultRules()...)
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{rules: rules}
+}
+
+// Rules returns the rules run by the engine.
+func (e *Engine) Rules() []Rule {
+	return e.rules
+}
+
+// WithLanguageRules makes the engine also run the rules registered for the learning language
+// of each vocab, after its own rules. The lookup returns nil for a language without rules.
+//
+// Usage example:
+// engine := lint.NewEngine(lint.DefaultRules(knownPos)...).WithLanguageRules(srv.LanguageLintRules)
+func (e *Engine) WithLanguageRules(lookup func(langCode string) []Rule) *Engine {
+	e.languageRules = lookup
+	return e
+}
+
+// Lint runs every rule over the vocab and returns their findings, in rule order, followed by
+// the rules of its learning language. Findings without a rule name are given the name of the
+// rule that reported them.
+//
+// Parameters:
+// - vocab: The vocab to check.
+//
+// Returns:
+// - The findings of all rules, empty when the vocab passes them all.
+//
+// Usage example:
+// findings := engine.Lint(&vocab)
+func (e *Engine) Lint(vocab *mdl.Vocab) (findings []Finding) {
+	rules := e.rules
+	if e.languageRules != nil {
+		rules = append(rules[:len(rules):len(rules)], e.languageRules(vocab.LearningLangCode)...)
+	}
+
+	for _, rule := range rules {
+		for _, finding := range rule.Check(vocab) {
+			if len(finding.Rule) == 0 {
+				finding.Rule = rule.Name()
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return
+}
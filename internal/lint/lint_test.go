@@ -0,0 +1,114 @@
+package lint
+
+import (
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"reflect"
+	"testing"
+)
+
+func TestEngine_Lint(t *testing.T) {
+	tests := []struct {
+		name  string
+		vocab mdl.Vocab
+		want  []string
+	}{
+		{
+			name:  "Clean vocab",
+			vocab: mdl.Vocab{LearningLang: "hablar", FirstLang: "to speak", Pos: "Verb", Infinitive: "hablar", Hint: "talk"},
+			want:  nil,
+		},
+		{
+			name:  "Missing hint",
+			vocab: mdl.Vocab{LearningLang: "perro", FirstLang: "dog", Pos: "noun"},
+			want:  []string{"missing-hint/hint"},
+		},
+		{
+			name:  "Unknown pos",
+			vocab: mdl.Vocab{LearningLang: "perro", FirstLang: "dog", Pos: "nuon", Hint: "pet"},
+			want:  []string{"unknown-pos/pos"},
+		},
+		{
+			name:  "Verb without infinitive",
+			vocab: mdl.Vocab{LearningLang: "hablo", FirstLang: "I speak", Pos: "verb", Hint: "yo"},
+			want:  []string{"verb-infinitive/infinitive"},
+		},
+		{
+			name:  "Trailing punctuation",
+			vocab: mdl.Vocab{LearningLang: "perro.", FirstLang: "dog,", Hint: "pet"},
+			want:  []string{"trailing-punctuation/learning_lang", "trailing-punctuation/first_lang"},
+		},
+		{
+			name:  "Question mark allowed",
+			vocab: mdl.Vocab{LearningLang: "¿qué tal?", FirstLang: "how are you?", Hint: "greeting"},
+			want:  nil,
+		},
+		{
+			name:  "First equals learning",
+			vocab: mdl.Vocab{LearningLang: "Chocolate", FirstLang: "chocolate", Hint: "sweet"},
+			want:  []string{"first-equals-learning/first_lang"},
+		},
+	}
+
+	engine := NewEngine(DefaultRules(KnownPos())...)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, finding := range engine.Lint(&tt.vocab) {
+				got = append(got, finding.Rule+"/"+finding.FieldName)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngine_CustomRule(t *testing.T) {
+	rule := RuleFunc{RuleName: "no-skill", Fn: func(vocab *mdl.Vocab) []Finding {
+		if vocab.Skill != "" {
+			return nil
+		}
+		return []Finding{{FieldName: "skill", Message: "skill is missing"}}
+	}}
+
+	findings := NewEngine(rule).Lint(&mdl.Vocab{LearningLang: "perro"})
+	want := []Finding{{Rule: "no-skill", FieldName: "skill", Message: "skill is missing"}}
+	if !reflect.DeepEqual(findings, want) {
+		t.Errorf("Lint() = %+v, want %+v", findings, want)
+	}
+}
+
+func TestEngine_LanguageRules(t *testing.T) {
+	engine := NewEngine(TrailingPunctuation()).WithLanguageRules(func(langCode string) []Rule {
+		if langCode == "es" {
+			return []Rule{InvertedPunctuation()}
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name  string
+		vocab mdl.Vocab
+		want  []string
+	}{
+		{name: "Opened question", vocab: mdl.Vocab{LearningLang: "¿qué tal?", LearningLangCode: "es"}, want: nil},
+		{name: "Unopened question and exclamation", vocab: mdl.Vocab{LearningLang: "qué tal? hola!", LearningLangCode: "es"},
+			want: []string{"inverted-punctuation/learning_lang", "inverted-punctuation/learning_lang"}},
+		{name: "Other language", vocab: mdl.Vocab{LearningLang: "how are you?", LearningLangCode: "en"}, want: nil},
+		{name: "Own rules first", vocab: mdl.Vocab{LearningLang: "vale!.", LearningLangCode: "es"},
+			want: []string{"trailing-punctuation/learning_lang", "inverted-punctuation/learning_lang"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, finding := range engine.Lint(&tt.vocab) {
+				got = append(got, finding.Rule+"/"+finding.FieldName)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
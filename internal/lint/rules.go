@@ -0,0 +1,132 @@
+package lint
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"strings"
+)
+
+// KnownPos returns the names of the default parts of speech, used by the unknown-pos rule
+// when the managed part of speech table is not available.
+func KnownPos() []string {
+	names := make([]string, len(mdl.DefaultPartsOfSpeech))
+	for i, pos := range mdl.DefaultPartsOfSpeech {
+		names[i] = pos.Name
+	}
+	return names
+}
+
+// trailingPunctuation is the punctuation that should not end a vocab text field. Question and
+// exclamation marks are left out since they are part of many phrases.
+const trailingPunctuation = ".,;:"
+
+// DefaultRules returns the rules run by the linter unless configured otherwise.
+//
+// Parameters:
+// - knownPos: The part of speech values accepted by the unknown-pos rule, see KnownPos.
+func DefaultRules(knownPos []string) []Rule {
+	return []Rule{
+		MissingHint(),
+		UnknownPos(knownPos),
+		VerbInfinitive(),
+		TrailingPunctuation(),
+		FirstEqualsLearning(),
+	}
+}
+
+// MissingHint reports vocab without a hint.
+func MissingHint() Rule {
+	return RuleFunc{RuleName: "missing-hint", Fn: func(vocab *mdl.Vocab) []Finding {
+		if len(strings.TrimSpace(vocab.Hint)) > 0 {
+			return nil
+		}
+		return []Finding{{FieldName: "hint", Message: "hint is missing"}}
+	}}
+}
+
+// UnknownPos reports vocab whose part of speech is set but not one of the known values.
+func UnknownPos(known []string) Rule {
+	return RuleFunc{RuleName: "unknown-pos", Fn: func(vocab *mdl.Vocab) []Finding {
+		pos := strings.TrimSpace(vocab.Pos)
+		if len(pos) == 0 {
+			return nil
+		}
+		for _, value := range known {
+			if strings.EqualFold(pos, value) {
+				return nil
+			}
+		}
+		return []Finding{{FieldName: "pos", Message: fmt.Sprintf("pos %s is not a known part of speech", pos)}}
+	}}
+}
+
+// VerbInfinitive reports verbs without an infinitive.
+func VerbInfinitive() Rule {
+	return RuleFunc{RuleName: "verb-infinitive", Fn: func(vocab *mdl.Vocab) []Finding {
+		if !strings.EqualFold(strings.TrimSpace(vocab.Pos), "verb") || len(strings.TrimSpace(vocab.Infinitive)) > 0 {
+			return nil
+		}
+		return []Finding{{FieldName: "infinitive", Message: "verb has no infinitive"}}
+	}}
+}
+
+// TrailingPunctuation reports learning lang, first lang and infinitive values ending in punctuation.
+func TrailingPunctuation() Rule {
+	return RuleFunc{RuleName: "trailing-punctuation", Fn: func(vocab *mdl.Vocab) (findings []Finding) {
+		fields := []struct {
+			name  string
+			value string
+		}{
+			{name: "learning_lang", value: vocab.LearningLang},
+			{name: "first_lang", value: vocab.FirstLang},
+			{name: "infinitive", value: vocab.Infinitive},
+		}
+
+		for _, field := range fields {
+			value := strings.TrimSpace(field.value)
+			if len(value) > 0 && strings.ContainsAny(value[len(value)-1:], trailingPunctuation) {
+				findings = append(findings, Finding{
+					FieldName: field.name,
+					Message:   fmt.Sprintf("%s '%s' ends with punctuation", field.name, value),
+				})
+			}
+		}
+		return
+	}}
+}
+
+// FirstEqualsLearning reports vocab whose first lang is the same as the learning lang, usually
+// a copy and paste mistake rather than a translation.
+func FirstEqualsLearning() Rule {
+	return RuleFunc{RuleName: "first-equals-learning", Fn: func(vocab *mdl.Vocab) []Finding {
+		first := strings.TrimSpace(vocab.FirstLang)
+		if len(first) == 0 || !strings.EqualFold(first, strings.TrimSpace(vocab.LearningLang)) {
+			return nil
+		}
+		return []Finding{{FieldName: "first_lang", Message: "first lang is the same as the learning lang"}}
+	}}
+}
+
+// InvertedPunctuation reports learning langs with a question or exclamation that is not opened
+// by an inverted mark, as Spanish requires. It is registered for the languages that use them
+// rather than run by default.
+func InvertedPunctuation() Rule {
+	return RuleFunc{RuleName: "inverted-punctuation", Fn: func(vocab *mdl.Vocab) (findings []Finding) {
+		for _, mark := range []struct {
+			closing string
+			opening string
+			kind    string
+		}{
+			{closing: "?", opening: "¿", kind: "question"},
+			{closing: "!", opening: "¡", kind: "exclamation"},
+		} {
+			if strings.Contains(vocab.LearningLang, mark.closing) && !strings.Contains(vocab.LearningLang, mark.opening) {
+				findings = append(findings, Finding{
+					FieldName: "learning_lang",
+					Message:   fmt.Sprintf("%s '%s' is missing the opening %s", mark.kind, vocab.LearningLang, mark.opening),
+				})
+			}
+		}
+		return
+	}}
+}
@@ -0,0 +1,27 @@
+package mdl
+
+import "time"
+
+// VocabAlternative represents one additional correct answer for a Vocab record in the
+// learning language, such as a regional variant or a former spelling.
+//
+// Fields:
+//   - ID: The unique identifier for the alternative, automatically incremented.
+//   - VocabID: The ID of the Vocab record this alternative belongs to.
+//   - Alternative: The alternative answer text. Unique per vocab.
+//   - Notes: Optional notes explaining when the alternative applies.
+//   - Position: The display order of the alternative within its vocab, starting at zero.
+//   - CreatedBy: The identifier of the user or process that added the alternative.
+//   - Created: The timestamp when the alternative was added.
+//
+// The vocab table keeps a delimited copy of the alternatives in Vocab.Alternatives for
+// readers of the table that predate this model, it is rewritten whenever the list changes.
+type VocabAlternative struct {
+	ID          int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	VocabID     int       `json:"vocab_id" gorm:"not null;uniqueIndex:idx_vocab_alternative,priority:1"`
+	Alternative string    `json:"alternative" gorm:"not null;uniqueIndex:idx_vocab_alternative,priority:2"`
+	Notes       string    `json:"notes" gorm:"default:''"`
+	Position    int       `json:"position" gorm:"not null;default:0"`
+	CreatedBy   string    `json:"created_by" gorm:"not null"`
+	Created     time.Time `json:"created" gorm:"not null;default:now()"`
+}
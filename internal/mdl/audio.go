@@ -0,0 +1,40 @@
+package mdl
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AudioAsset is a stored audio recording, such as the pronunciation of a Vocab record. The
+// content is held by the media storage under its hash, a vocab refers to the asset by its ID.
+//
+// Fields:
+//   - ID: The unique identifier for the asset, automatically incremented.
+//   - Hash: The hex SHA-256 of the recording, its key in the media storage. Unique.
+//   - MimeType: The detected type of the recording, e.g. "audio/ogg".
+//   - Size: The size of the recording in bytes.
+//   - DurationMs: The playing time of the recording in milliseconds.
+//   - Filename: The name of the file the recording was uploaded as.
+//   - CreatedBy: The identifier of the user or process that uploaded the recording.
+//   - Created: The timestamp when the recording was uploaded.
+type AudioAsset struct {
+	ID         int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Hash       string    `json:"hash" gorm:"not null;uniqueIndex"`
+	MimeType   string    `json:"mime_type" gorm:"not null"`
+	Size       int64     `json:"size" gorm:"not null"`
+	DurationMs int       `json:"duration_ms" gorm:"not null"`
+	Filename   string    `json:"filename" gorm:"default:''"`
+	CreatedBy  string    `json:"created_by" gorm:"not null"`
+	Created    time.Time `json:"created" gorm:"not null;default:now()"`
+}
+
+// JSON Creates a JSON string from an AudioAsset object.
+func (o *AudioAsset) JSON() string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		fmt.Printf("Error: %s", err)
+		return ""
+	}
+	return string(b)
+}
@@ -1,9 +1,19 @@
 package mdl
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 )
 
+// The changes an Audit records, see Audit.Action.
+const (
+	AuditCreated  = "created"
+	AuditUpdated  = "updated"
+	AuditDeleted  = "deleted"
+	AuditReverted = "reverted"
+)
+
 // Audit represents a record of changes made to a database entity. It is designed
 // to track modifications by storing the differences, along with metadata about
 // the change, such as the entity affected, who made the change, and when.
@@ -17,8 +27,16 @@ import (
 //   - Before: The state of the entity before the changes were made, possibly serialized as a string.
 //   - After: The state of the entity after the changes were made, possibly serialized as a string.
 //   - Comments: Optional comments or notes about the changes made.
+//   - Action: The change recorded, AuditCreated, AuditUpdated, AuditDeleted or AuditReverted
+//     when an earlier state was restored.
 //   - CreatedBy: The identifier of the user or process that made the changes.
 //   - Created: The timestamp when the audit record was created.
+//   - TxID: The ID of the transaction that saved the audit, along with the change it records.
+//     Whether it committed before a snapshot was taken is told by Snapshot.TxSnapshot, IDs
+//     are not in commit order.
+//
+// Audits are listed newest first by who made them or by action, so both lead an index with
+// the creation time. See db.IndexAuditsIfNotExists for the index on the diff.
 //
 // This struct is typically used to populate an audit log, allowing for a historical
 // review of changes for accountability and possibly restoration of previous states.
@@ -30,6 +48,49 @@ type Audit struct {
 	Before    string    `json:"before"` // State before the changes
 	After     string    `json:"after"`  // State after the changes
 	Comments  string    `gorm:"default:''"`
-	CreatedBy string    `json:"created_by" gorm:"not null"`
-	Created   time.Time `json:"created" gorm:"index:idx_audit_created,not null;default:now()"`
+	Action    string    `json:"action" gorm:"not null;default:'';index:idx_audit_action_created,priority:1"`
+	CreatedBy string    `json:"created_by" gorm:"not null;index:idx_audit_created_by_created,priority:1"`
+	Created   time.Time `json:"created" gorm:"index:idx_audit_created,not null;default:now();index:idx_audit_action_created,priority:2;index:idx_audit_created_by_created,priority:2"`
+	TxID      int64     `json:"-" gorm:"not null;default:pg_current_xact_id()::text::bigint"`
+}
+
+// AuditFilter narrows the audits found, each zero valued field matches every audit.
+//
+// Fields:
+//   - TableName: The table of the audited records, e.g. "vocab".
+//   - ObjectID: The audited record, requires TableName.
+//   - Duration: The time range the audits were created in.
+//   - CreatedBy: The user or process that made the changes.
+//   - Action: The change recorded, e.g. AuditCreated.
+//   - ChangedField: A key of the audit diff whose value changed, e.g. "hint".
+type AuditFilter struct {
+	TableName    string
+	ObjectID     int
+	Duration     *Duration
+	CreatedBy    string
+	Action       string
+	ChangedField string
+}
+
+// DerivedAction returns the change an audit records from its states, AuditCreated when it has
+// no before state, AuditDeleted when it has no after state, and AuditUpdated otherwise.
+func (o *Audit) DerivedAction() string {
+	switch {
+	case len(o.Before) == 0:
+		return AuditCreated
+	case len(o.After) == 0:
+		return AuditDeleted
+	default:
+		return AuditUpdated
+	}
+}
+
+// JSON Creates a JSON string from an Audit object.
+func (o *Audit) JSON() string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		fmt.Printf("Error: %s", err)
+		return ""
+	}
+	return string(b)
 }
@@ -0,0 +1,78 @@
+package mdl
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// The review statuses of a ChangeRequest.
+const (
+	ChangePending  = "pending"
+	ChangeApproved = "approved"
+	ChangeRejected = "rejected"
+)
+
+// ChangeRequest is an edit to a Vocab record proposed by an editor, which only takes effect
+// once a reviewer approves it. The vocab is kept as it was when the change was proposed and
+// as it would be after the change, so reviewers see the diff and approval can tell whether
+// the vocab has been changed since.
+//
+// Fields:
+//   - ID: The unique identifier for the change request, automatically incremented.
+//   - VocabID: The ID of the Vocab record to change.
+//   - Status: ChangePending, ChangeApproved or ChangeRejected.
+//   - Before: The JSON of the vocab when the change was proposed.
+//   - Proposed: The JSON of the vocab with the change applied.
+//   - Diff: The differences between Before and Proposed, see srv.CompareJSON.
+//   - Fields: Comma separated names of the vocab fields the change sets, e.g. "hint, plural".
+//   - Comments: Optional. Why the editor proposes the change.
+//   - ReviewComments: Optional. Why the reviewer approved or rejected the change.
+//   - CreatedBy: The identifier of the user or process that proposed the change.
+//   - ReviewedBy: The identifier of the user or process that reviewed the change, empty while pending.
+//   - Created: The timestamp when the change was proposed.
+//   - Reviewed: The timestamp when the change was approved or rejected, nil while pending.
+type ChangeRequest struct {
+	ID             int        `json:"id" gorm:"primaryKey;autoIncrement"`
+	VocabID        int        `json:"vocab_id" gorm:"not null;index:idx_change_request_vocab"`
+	Status         string     `json:"status" gorm:"not null;default:'pending';index:idx_change_request_status"`
+	Before         string     `json:"before" gorm:"not null"`
+	Proposed       string     `json:"proposed" gorm:"not null"`
+	Diff           string     `json:"diff" gorm:"not null"`
+	Fields         string     `json:"fields" gorm:"not null"`
+	Comments       string     `json:"comments" gorm:"default:''"`
+	ReviewComments string     `json:"review_comments" gorm:"default:''"`
+	CreatedBy      string     `json:"created_by" gorm:"not null"`
+	ReviewedBy     string     `json:"reviewed_by" gorm:"default:''"`
+	Created        time.Time  `json:"created" gorm:"not null;default:now()"`
+	Reviewed       *time.Time `json:"reviewed"`
+}
+
+// JSON Creates a JSON string from a ChangeRequest object.
+func (o *ChangeRequest) JSON() string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		fmt.Printf("Error: %s", err)
+		return ""
+	}
+	return string(b)
+}
+
+// BeforeVocab decodes the vocab as it was when the change was proposed.
+func (o *ChangeRequest) BeforeVocab() (*Vocab, error) {
+	return decodeChangeVocab(o.Before)
+}
+
+// ProposedVocab decodes the vocab with the change applied.
+func (o *ChangeRequest) ProposedVocab() (*Vocab, error) {
+	return decodeChangeVocab(o.Proposed)
+}
+
+// decodeChangeVocab decodes the JSON of a vocab kept by a change request.
+func decodeChangeVocab(vocabJson string) (*Vocab, error) {
+	vocab := &Vocab{}
+	if err := json.Unmarshal([]byte(vocabJson), vocab); err != nil {
+		return nil, fmt.Errorf("failed to decode change request vocab, error: %v", err)
+	}
+	return vocab, nil
+}
@@ -0,0 +1,55 @@
+package mdl
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Concept is a meaning shared by Vocab records in different learning languages, e.g. the
+// vocab perro (es), chien (fr) and cão (pt-BR) are all translations of the concept "dog".
+// Each vocab belongs to one concept, its translations are the other vocab of the concept.
+//
+// Fields:
+//   - ID: The unique identifier for the concept, automatically incremented.
+//   - Gloss: A short description of the meaning, usually the prompt shown to learners.
+//   - GlossLangCode: The BCP-47 language tag of the gloss, e.g. "en".
+//   - Notes: Optional. Anything that tells the concept apart from others with a similar gloss.
+//   - CreatedBy: The identifier of the user or process that created the concept.
+//   - Created: The timestamp when the concept was created.
+type Concept struct {
+	ID            int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Gloss         string    `json:"gloss" gorm:"not null;index"`
+	GlossLangCode string    `json:"gloss_lang_code" gorm:"not null;default:'en'"`
+	Notes         string    `json:"notes" gorm:"default:''"`
+	CreatedBy     string    `json:"created_by" gorm:"not null"`
+	Created       time.Time `json:"created" gorm:"not null;default:now()"`
+}
+
+// JSON Creates a JSON string from a Concept object.
+func (o *Concept) JSON() string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		fmt.Printf("Error: %s", err)
+		return ""
+	}
+	return string(b)
+}
+
+// ConceptPatch describes a partial update to an existing Concept. Only the fields that are
+// non-nil are applied.
+type ConceptPatch struct {
+	ID            int
+	Gloss         *string
+	GlossLangCode *string
+	Notes         *string
+}
+
+// ApplyTo copies the provided patch fields onto the given Concept and reports whether any
+// of them actually changed it.
+func (p *ConceptPatch) ApplyTo(c *Concept) (changed bool) {
+	changed = patchString(&c.Gloss, p.Gloss) || changed
+	changed = patchString(&c.GlossLangCode, p.GlossLangCode) || changed
+	changed = patchString(&c.Notes, p.Notes) || changed
+	return
+}
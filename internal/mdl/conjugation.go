@@ -0,0 +1,59 @@
+package mdl
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Conjugation is one conjugated form of a verb Vocab record, for a tense and person.
+//
+// Fields:
+//   - ID: The unique identifier for the form, automatically incremented.
+//   - VocabID: The ID of the verb Vocab record the form belongs to.
+//   - Tense: The tense, one of conj.Tenses, e.g. "present".
+//   - Person: The grammatical person, one of conj.Persons, e.g. "1sg".
+//   - Form: The conjugated text, e.g. "hablo". Unique per vocab, tense and person.
+//   - Irregular: Set when the form departs from the regular pattern of the verb.
+//   - Corrected: Set when the form was corrected by hand, it is kept when the table is generated again.
+//   - CreatedBy: The identifier of the user or process that set the form.
+//   - Created: The timestamp when the form was added.
+type Conjugation struct {
+	ID        int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	VocabID   int       `json:"vocab_id" gorm:"not null;uniqueIndex:idx_conjugation,priority:1"`
+	Tense     string    `json:"tense" gorm:"not null;uniqueIndex:idx_conjugation,priority:2"`
+	Person    string    `json:"person" gorm:"not null;uniqueIndex:idx_conjugation,priority:3"`
+	Form      string    `json:"form" gorm:"not null"`
+	Irregular bool      `json:"irregular" gorm:"not null;default:false"`
+	Corrected bool      `json:"corrected" gorm:"not null;default:false"`
+	CreatedBy string    `json:"created_by" gorm:"not null"`
+	Created   time.Time `json:"created" gorm:"not null;default:now()"`
+}
+
+// ConjugationsJSON serializes the conjugation table of a vocab for audits, as a JSON object
+// keyed by tense and person, e.g. {"present.1sg": {"form": "hablo", ...}}, so the audit diff
+// names each changed form, such as 'present.1sg.form'.
+func ConjugationsJSON(conjugations []Conjugation) string {
+	if len(conjugations) == 0 {
+		return ""
+	}
+
+	table := make(map[string]conjugationCell, len(conjugations))
+	for _, c := range conjugations {
+		table[c.Tense+"."+c.Person] = conjugationCell{Form: c.Form, Irregular: c.Irregular, Corrected: c.Corrected}
+	}
+
+	b, err := json.Marshal(table)
+	if err != nil {
+		fmt.Printf("Error: %s", err)
+		return ""
+	}
+	return string(b)
+}
+
+// conjugationCell is the audited state of one form of a conjugation table.
+type conjugationCell struct {
+	Form      string `json:"form"`
+	Irregular bool   `json:"irregular"`
+	Corrected bool   `json:"corrected"`
+}
@@ -0,0 +1,83 @@
+package mdl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExampleSentence gives a Vocab record context, a sentence in the learning language that
+// uses the vocab, with its translation.
+//
+// Fields:
+//   - ID: The unique identifier for the sentence, automatically incremented.
+//   - VocabID: The ID of the Vocab record the sentence is an example of.
+//   - Sentence: The sentence in the learning language.
+//   - Translation: The sentence in the known language.
+//   - Source: Optional. Where the sentence was taken from, e.g. a book or a URL.
+//   - Highlights: The positions of the sentence tokens that are the vocab, comma separated,
+//     see HighlightList. They are found by the service layer when the sentence is saved.
+//   - CreatedBy: The identifier of the user or process that added the sentence.
+//   - Created: The timestamp when the sentence was added.
+//   - Tokens: The words of the sentence the highlights refer to, attached by the service layer.
+type ExampleSentence struct {
+	ID          int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	VocabID     int       `json:"vocab_id" gorm:"not null;index:idx_example_sentence_vocab_id"`
+	Sentence    string    `json:"sentence" gorm:"not null"`
+	Translation string    `json:"translation" gorm:"not null"`
+	Source      string    `json:"source" gorm:"default:''"`
+	Highlights  string    `json:"highlights" gorm:"not null;default:''"`
+	CreatedBy   string    `json:"created_by" gorm:"not null"`
+	Created     time.Time `json:"created" gorm:"not null;default:now()"`
+
+	Tokens []string `json:"-" gorm:"-"`
+}
+
+// JSON Creates a JSON string from an ExampleSentence object.
+func (o *ExampleSentence) JSON() string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		fmt.Printf("Error: %s", err)
+		return ""
+	}
+	return string(b)
+}
+
+// HighlightList returns the highlighted token positions, skipping any that do not parse.
+func (o *ExampleSentence) HighlightList() (positions []int) {
+	for _, field := range strings.Split(o.Highlights, ",") {
+		if position, err := strconv.Atoi(strings.TrimSpace(field)); err == nil {
+			positions = append(positions, position)
+		}
+	}
+	return
+}
+
+// SetHighlightList stores the highlighted token positions.
+func (o *ExampleSentence) SetHighlightList(positions []int) {
+	fields := make([]string, len(positions))
+	for i, position := range positions {
+		fields[i] = strconv.Itoa(position)
+	}
+	o.Highlights = strings.Join(fields, ",")
+}
+
+// ExampleSentencePatch describes a partial update to an existing ExampleSentence. Only the
+// fields that are non-nil are applied. The vocab of a sentence cannot be changed.
+type ExampleSentencePatch struct {
+	ID          int
+	Sentence    *string
+	Translation *string
+	Source      *string
+}
+
+// ApplyTo copies the provided patch fields onto the given ExampleSentence and reports
+// whether any of them actually changed it.
+func (p *ExampleSentencePatch) ApplyTo(o *ExampleSentence) (changed bool) {
+	changed = patchString(&o.Sentence, p.Sentence) || changed
+	changed = patchString(&o.Translation, p.Translation) || changed
+	changed = patchString(&o.Source, p.Source) || changed
+	return
+}
@@ -76,3 +76,25 @@ func (f *Fixit) Clone() *Fixit {
 		Created:   f.Created,
 	}
 }
+
+// FixitPatch describes a partial update to an existing Fixit record. Only the
+// fields that are non-nil are applied, everything else is left untouched.
+type FixitPatch struct {
+	ID        int
+	Status    *StatusType
+	FieldName *string
+	Comments  *string
+}
+
+// ApplyTo copies the provided patch fields onto the given Fixit and reports
+// whether any of them actually changed the record.
+func (p *FixitPatch) ApplyTo(f *Fixit) (changed bool) {
+	if p.Status != nil && *p.Status != f.Status {
+		f.Status = *p.Status
+		changed = true
+	}
+	changed = patchString(&f.FieldName, p.FieldName) || changed
+	changed = patchString(&f.Comments, p.Comments) || changed
+
+	return
+}
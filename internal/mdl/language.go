@@ -0,0 +1,109 @@
+package mdl
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Language is a managed language that vocab can be written in, as their known or learning
+// language. The settings drive the text rules applied to vocab of the language, code level
+// rules such as lint rules are registered for it by code in the service layer.
+//
+// Fields:
+//   - ID: The unique identifier for the language, automatically incremented.
+//   - Code: The BCP-47 language tag, e.g. "es" or "pt-BR". Unique.
+//   - Name: The English name of the language, e.g. "Brazilian Portuguese".
+//   - Script: Optional. The ISO 15924 code of the script it is written in, e.g. "Latn".
+//   - Direction: The writing direction, "ltr" or "rtl".
+//   - Enabled: Whether new vocab may use the language. Existing vocab are kept when it is disabled.
+//   - DefaultKnown: Whether the language is the known language of vocab created without one.
+//   - DefaultLearning: Whether the language is the learning language of vocab created without one.
+//   - Elision: Whether a short word elided with an apostrophe, as in the French "l'homme", is
+//     counted as a word of its own.
+//   - Articles: Comma separated articles ignored at the start of a learning lang when looking
+//     for near duplicates, e.g. "el, la, los, las, un, una".
+//   - DefiniteArticles: Comma separated definite articles, a vocab article must be one of them
+//     when any are listed.
+//   - Created: The timestamp when the language was added.
+type Language struct {
+	ID               int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Code             string    `json:"code" gorm:"not null;uniqueIndex:idx_language_code"`
+	Name             string    `json:"name" gorm:"not null"`
+	Script           string    `json:"script" gorm:"default:''"`
+	Direction        string    `json:"direction" gorm:"not null;default:'ltr'"`
+	Enabled          bool      `json:"enabled" gorm:"not null"`
+	DefaultKnown     bool      `json:"default_known" gorm:"not null"`
+	DefaultLearning  bool      `json:"default_learning" gorm:"not null"`
+	Elision          bool      `json:"elision" gorm:"not null"`
+	Articles         string    `json:"articles" gorm:"default:''"`
+	DefiniteArticles string    `json:"definite_articles" gorm:"default:''"`
+	Created          time.Time `json:"created" gorm:"not null;default:now()"`
+}
+
+// JSON serializes the language for audits.
+func (o *Language) JSON() string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		fmt.Printf("Error: %s", err)
+		return ""
+	}
+	return string(b)
+}
+
+// LanguagePatch describes a partial update to a managed language. Only the non-nil fields
+// are applied. The code is absent since vocab refer to the language by it.
+type LanguagePatch struct {
+	ID               int
+	Name             *string
+	Script           *string
+	Direction        *string
+	Enabled          *bool
+	DefaultKnown     *bool
+	DefaultLearning  *bool
+	Elision          *bool
+	Articles         *string
+	DefiniteArticles *string
+}
+
+// ApplyTo copies the provided patch fields onto the given Language and reports whether any
+// of them actually changed it.
+func (p *LanguagePatch) ApplyTo(l *Language) (changed bool) {
+	changed = patchString(&l.Name, p.Name) || changed
+	changed = patchString(&l.Script, p.Script) || changed
+	changed = patchString(&l.Direction, p.Direction) || changed
+	changed = patchBool(&l.Enabled, p.Enabled) || changed
+	changed = patchBool(&l.DefaultKnown, p.DefaultKnown) || changed
+	changed = patchBool(&l.DefaultLearning, p.DefaultLearning) || changed
+	changed = patchBool(&l.Elision, p.Elision) || changed
+	changed = patchString(&l.Articles, p.Articles) || changed
+	changed = patchString(&l.DefiniteArticles, p.DefiniteArticles) || changed
+	return
+}
+
+// patchBool assigns the patch value to the target when one is provided and it differs.
+func patchBool(target *bool, value *bool) bool {
+	if value == nil || *value == *target {
+		return false
+	}
+	*target = *value
+	return true
+}
+
+// DefaultLanguages are loaded into the language table when it is first created. Their
+// settings are the rules applied before languages were managed.
+var DefaultLanguages = []Language{
+	{Code: "en", Name: "English", Script: "Latn", Direction: "ltr", Enabled: true, DefaultKnown: true,
+		Articles: "the, a, an"},
+	{Code: "es", Name: "Spanish", Script: "Latn", Direction: "ltr", Enabled: true, DefaultLearning: true,
+		Articles: "el, la, los, las, lo, un, una, unos, unas", DefiniteArticles: "el, la, los, las"},
+	{Code: "fr", Name: "French", Script: "Latn", Direction: "ltr", Enabled: true, Elision: true,
+		Articles: "le, la, les, l', un, une, des"},
+	{Code: "it", Name: "Italian", Script: "Latn", Direction: "ltr", Enabled: true, Elision: true,
+		Articles: "il, lo, la, i, gli, le, l', un, uno, una"},
+	{Code: "ca", Name: "Catalan", Script: "Latn", Direction: "ltr", Enabled: true, Elision: true},
+	{Code: "pt", Name: "Portuguese", Script: "Latn", Direction: "ltr", Enabled: true,
+		Articles: "o, a, os, as, um, uma"},
+	{Code: "de", Name: "German", Script: "Latn", Direction: "ltr", Enabled: true,
+		Articles: "der, die, das, ein, eine"},
+}
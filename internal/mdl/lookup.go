@@ -0,0 +1,112 @@
+package mdl
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PartOfSpeech is a managed part of speech value allowed in Vocab.Pos.
+//
+// Fields:
+//   - ID: The unique identifier for the part of speech, automatically incremented.
+//   - Name: The canonical value stored in Vocab.Pos, e.g. "verb". Unique.
+//   - Description: Optional notes on when the part of speech applies.
+//   - Aliases: Comma separated variants accepted for the name and mapped to it, e.g. "v., verbo".
+//   - Created: The timestamp when the part of speech was added.
+type PartOfSpeech struct {
+	ID          int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string    `json:"name" gorm:"not null;uniqueIndex:idx_part_of_speech_name"`
+	Description string    `json:"description" gorm:"default:''"`
+	Aliases     string    `json:"aliases" gorm:"default:''"`
+	Created     time.Time `json:"created" gorm:"not null;default:now()"`
+}
+
+// JSON serializes the part of speech for audits.
+func (o *PartOfSpeech) JSON() string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		fmt.Printf("Error: %s", err)
+		return ""
+	}
+	return string(b)
+}
+
+// Skill is a managed skill value allowed in Vocab.Skill. Skills form the curriculum taxonomy,
+// a skill without a parent is a unit, whose children are lessons containing the skills.
+//
+// Fields:
+//   - ID: The unique identifier for the skill, automatically incremented.
+//   - Name: The canonical value stored in Vocab.Skill, e.g. "Pets". Unique.
+//   - Description: Optional notes on what the skill covers.
+//   - Aliases: Comma separated variants accepted for the name and mapped to it.
+//   - ParentID: Optional. The skill containing this one, nil for a top level unit.
+//   - SortOrder: The position of the skill among its siblings, ties are ordered by name.
+//   - Created: The timestamp when the skill was added.
+type Skill struct {
+	ID          int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string    `json:"name" gorm:"not null;uniqueIndex:idx_skill_name"`
+	Description string    `json:"description" gorm:"default:''"`
+	Aliases     string    `json:"aliases" gorm:"default:''"`
+	ParentID    *int      `json:"parent_id" gorm:"index"`
+	SortOrder   int       `json:"sort_order" gorm:"not null;default:0"`
+	Created     time.Time `json:"created" gorm:"not null;default:now()"`
+
+	Parent *Skill `json:"-" gorm:"foreignKey:ParentID"`
+}
+
+// JSON serializes the skill for audits.
+func (o *Skill) JSON() string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		fmt.Printf("Error: %s", err)
+		return ""
+	}
+	return string(b)
+}
+
+// TermPatch describes a partial update to a managed part of speech or skill. Only the
+// non-nil fields are applied.
+type TermPatch struct {
+	ID          int
+	Name        *string
+	Description *string
+	Aliases     *string
+}
+
+// SkillPatch describes a partial update to a managed skill, including its place in the
+// taxonomy. A ParentID of 0 moves the skill to the top level.
+type SkillPatch struct {
+	TermPatch
+	ParentID  *int
+	SortOrder *int
+}
+
+// SkillCount is the number of Vocab records linked to a skill by Vocab.SkillID.
+type SkillCount struct {
+	SkillID int
+	Count   int
+}
+
+// TermCount is the number of Vocab records using a Pos or Skill value.
+type TermCount struct {
+	Value string
+	Count int
+}
+
+// DefaultPartsOfSpeech are loaded into the part of speech table when it is first created.
+var DefaultPartsOfSpeech = []PartOfSpeech{
+	{Name: "noun", Aliases: "n., n, sustantivo, nombre"},
+	{Name: "proper noun", Aliases: "nombre propio"},
+	{Name: "verb", Aliases: "v., v, verbo"},
+	{Name: "adjective", Aliases: "adj., adj, adjetivo"},
+	{Name: "adverb", Aliases: "adv., adv, adverbio"},
+	{Name: "pronoun", Aliases: "pron., pron, pronombre"},
+	{Name: "preposition", Aliases: "prep., prep, preposición"},
+	{Name: "conjunction", Aliases: "conj., conj, conjunción"},
+	{Name: "interjection", Aliases: "interj., interj, interjección"},
+	{Name: "article", Aliases: "art., art, artículo"},
+	{Name: "determiner", Aliases: "det., det, determinante"},
+	{Name: "numeral", Aliases: "num., num, número"},
+	{Name: "phrase", Aliases: "frase, expression, expresión"},
+}
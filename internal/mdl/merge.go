@@ -0,0 +1,39 @@
+package mdl
+
+import "time"
+
+// VocabArchive keeps a copy of a Vocab record removed from the vocab table by a merge, so
+// the record can be inspected or restored later.
+//
+// Fields:
+//   - ID: The unique identifier for the archive entry, automatically incremented.
+//   - VocabID: The ID the Vocab record had before it was archived.
+//   - MergedIntoID: The ID of the Vocab record that survived the merge.
+//   - Record: The archived Vocab record serialized as JSON.
+//   - ArchivedBy: The identifier of the user or process that archived the record.
+//   - Archived: The timestamp when the record was archived.
+type VocabArchive struct {
+	ID           int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	VocabID      int       `json:"vocab_id" gorm:"not null;index:idx_vocab_archive_vocab_id"`
+	MergedIntoID int       `json:"merged_into_id" gorm:"not null"`
+	Record       string    `json:"record" gorm:"not null"`
+	ArchivedBy   string    `json:"archived_by" gorm:"not null"`
+	Archived     time.Time `json:"archived" gorm:"not null;default:now()"`
+}
+
+// VocabMerge holds every change made when duplicate Vocab records are merged into one, so
+// the repository can save them in a single transaction.
+//
+// Fields:
+//   - Keep: The surviving Vocab record with the folded alternatives and hint.
+//   - MergedIDs: The IDs of the Vocab records removed from the vocab table.
+//   - Fixits: The Fixit records re-pointed to the surviving vocab.
+//   - Archives: The archive entries of the merged Vocab records.
+//   - Audits: The audit entries for every affected row.
+type VocabMerge struct {
+	Keep      *Vocab
+	MergedIDs []int
+	Fixits    []Fixit
+	Archives  []VocabArchive
+	Audits    []Audit
+}
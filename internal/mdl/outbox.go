@@ -0,0 +1,67 @@
+package mdl
+
+import (
+	"time"
+)
+
+// The aggregate types of an OutboxEvent, the table of the changed record.
+const (
+	AggregateVocab = "vocab"
+	AggregateFixit = "fixit"
+	AggregateAudit = "audit"
+)
+
+// OutboxEvent is a change written to the outbox in the same transaction as the change itself,
+// so downstream systems hear of every saved change and only of saved changes. The outbox
+// relay publishes pending events in ID order, those of one record strictly in order.
+//
+// Fields:
+//   - ID: The unique identifier for the event, automatically incremented, in commit order per record.
+//   - AggregateType: The table of the changed record, e.g. AggregateVocab.
+//   - AggregateID: The ID of the changed record.
+//   - EventType: The change, e.g. "vocab.updated".
+//   - Payload: The JSON of the record after the change, or before it when it was deleted.
+//   - Created: The timestamp when the change was saved.
+//   - Published: The timestamp when the event was published, nil while it is pending.
+//   - Attempts: The number of attempts made to publish the event.
+//   - LastError: The reason the last attempt failed, empty once published.
+//   - ClaimedUntil: When the claim of the relaying instance runs out, nil when it is not claimed.
+type OutboxEvent struct {
+	ID            int        `json:"id" gorm:"primaryKey;autoIncrement"`
+	AggregateType string     `json:"aggregate_type" gorm:"not null"`
+	AggregateID   int        `json:"aggregate_id" gorm:"not null"`
+	EventType     string     `json:"event_type" gorm:"not null"`
+	Payload       string     `json:"payload" gorm:"not null"`
+	Created       time.Time  `json:"created" gorm:"not null;default:now()"`
+	Published     *time.Time `json:"published" gorm:"index:idx_outbox_event_published"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	LastError     string     `json:"last_error" gorm:"default:''"`
+	ClaimedUntil  *time.Time `json:"claimed_until"`
+}
+
+// NewOutboxEvent returns the pending event of a change to a record.
+//
+// Parameters:
+// - aggregateType: The table of the changed record, e.g. AggregateVocab.
+// - aggregateID: The ID of the changed record.
+// - action: The change, "created", "updated" or "deleted".
+// - payload: The JSON of the record.
+func NewOutboxEvent(aggregateType string, aggregateID int, action string, payload string) OutboxEvent {
+	return OutboxEvent{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     aggregateType + "." + action,
+		Payload:       payload,
+		Created:       time.Now(),
+	}
+}
+
+// OutboxStats describes the backlog of the outbox.
+//
+// Fields:
+//   - Pending: The number of events not yet published.
+//   - OldestPending: When the oldest pending event was saved, nil when none are pending.
+type OutboxStats struct {
+	Pending       int64
+	OldestPending *time.Time
+}
@@ -0,0 +1,85 @@
+package mdl
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Snapshot is a published, immutable copy of the vocab of a learning language, which the
+// learner app reads instead of the vocab table so edits only reach learners once published.
+// The snapshot content is a JSON file kept in the snapshot storage under its checksum.
+//
+// Fields:
+//   - ID: The unique identifier for the snapshot, automatically incremented.
+//   - LearningLangCode: The learning language code of the vocab in the snapshot, e.g. "es".
+//   - Version: The version of the snapshot, counting up from 1 within the learning language.
+//   - Checksum: The hex SHA-256 of the snapshot file, and its key in the snapshot storage.
+//   - Size: The size of the snapshot file in bytes.
+//   - VocabCount: The number of vocab in the snapshot.
+//   - ExampleCount: The number of example sentences in the snapshot.
+//   - AuditID: The ID of the last audit saved when the snapshot was taken.
+//   - TxSnapshot: The Postgres snapshot the content was read in, the changes audited by the
+//     transactions visible in it are in the snapshot and the others are not. Empty for the
+//     snapshots published before it was recorded, whose changes are told by the AuditID.
+//   - Live: Whether the snapshot is the one served to learners, one per learning language.
+//   - CreatedBy: The identifier of the user or process that published the snapshot.
+//   - Created: The timestamp when the snapshot was published.
+//   - AudioIDs: The IDs of the audio assets the snapshot serves, saved as its SnapshotAudio
+//     when it is published.
+type Snapshot struct {
+	ID               int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	LearningLangCode string    `json:"learning_lang_code" gorm:"not null;uniqueIndex:idx_snapshot_version,priority:1"`
+	Version          int       `json:"version" gorm:"not null;uniqueIndex:idx_snapshot_version,priority:2"`
+	Checksum         string    `json:"checksum" gorm:"not null"`
+	Size             int64     `json:"size" gorm:"not null"`
+	VocabCount       int       `json:"vocab_count" gorm:"not null"`
+	ExampleCount     int       `json:"example_count" gorm:"not null"`
+	AuditID          int       `json:"audit_id" gorm:"not null"`
+	TxSnapshot       string    `json:"-" gorm:"not null;default:''"`
+	Live             bool      `json:"live" gorm:"not null"`
+	CreatedBy        string    `json:"created_by" gorm:"not null"`
+	Created          time.Time `json:"created" gorm:"not null;default:now()"`
+	AudioIDs         []int     `json:"-" gorm:"-"`
+}
+
+// SnapshotAudio records an audio asset a published snapshot serves by its URL. Snapshots are
+// immutable and may be made live again by a rollback, so the asset stays in use as long as the
+// snapshot is kept, even once no vocab refers to it.
+//
+// Fields:
+//   - SnapshotID: The ID of the snapshot.
+//   - AudioID: The ID of the audio asset it serves.
+type SnapshotAudio struct {
+	SnapshotID int         `json:"snapshot_id" gorm:"primaryKey"`
+	AudioID    int         `json:"audio_id" gorm:"primaryKey;index"`
+	Snapshot   *Snapshot   `json:"-" gorm:"foreignKey:SnapshotID"`
+	Audio      *AudioAsset `json:"-" gorm:"foreignKey:AudioID"`
+}
+
+// JSON Creates a JSON string from a Snapshot object.
+func (o *Snapshot) JSON() string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		fmt.Printf("Error: %s", err)
+		return ""
+	}
+	return string(b)
+}
+
+// SnapshotContent is the content of a learning language read at one point in time, which a
+// snapshot is built from.
+//
+// Fields:
+//   - Vocabs: The vocab of the learning language, ordered by ID, with their AlternativeList.
+//   - Examples: The example sentences of the vocab, ordered by ID.
+//   - AudioAssets: The audio assets the vocab use.
+//   - AuditID: The ID of the last audit saved, 0 when there are none.
+//   - TxSnapshot: The Postgres snapshot the content was read in, see Snapshot.TxSnapshot.
+type SnapshotContent struct {
+	Vocabs      []Vocab
+	Examples    []ExampleSentence
+	AudioAssets []AudioAsset
+	AuditID     int
+	TxSnapshot  string
+}
@@ -16,31 +16,50 @@ import (
 // - LearningLang: The word or phrase in the language being learned.
 // - FirstLang: The translation of the word or phrase into the user's first language, used as a prompt.
 // - Created: Timestamp when the vocabulary item was created. It is typically set automatically to the current time.
-// - Alternatives: Optional. Additional correct answers or variations in the learning language.
+// - Alternatives: Optional. Delimited copy of AlternativeList kept for readers of the vocab table.
 // - Skill: Optional. The skill or category associated with the vocabulary item, used for organizing content.
+// - SkillID: Optional. Foreign key of the managed Skill named by Skill, kept in sync with it by the service layer.
 // - Infinitive: Optional. For verbs, the infinitive form of the word. Empty for non-verb vocabulary items.
 // - Pos: Optional. The part of speech of the vocabulary item, aiding in the application of grammatical rules.
 // - Hint: Optional. A hint provided to assist users in translating the word or phrase.
+// - Gender: Optional. The grammatical gender, one of the Gender values, for nouns and other gendered parts of speech.
+// - Plural: Optional. The plural form of a noun or other inflected word when it is not just the learning lang plus s.
+// - Article: Optional. The definite article used with a noun, e.g. "el" for "el agua".
+// - Register: Optional. The register the word belongs to, one of the Register values, e.g. "formal".
+// - AudioID: Optional. Foreign key of the AudioAsset holding the pronunciation of the vocab.
+// - ConceptID: Optional. Foreign key of the Concept the vocab translates, shared with its translations.
 // - NumLearningWords: The number of words contained in the `learning_lang` field, calculated for analytical purposes.
-// - KnownLangCode: Language code for the known language.
-// - LearningLangCode: Language code for the learning language.
+// - KnownLangCode: BCP-47 language tag of the managed Language known to the learner, e.g. "en".
+// - LearningLangCode: BCP-47 language tag of the managed Language being learned, e.g. "pt-BR". The
+// learning lang is unique within each learning language.
+// - AlternativeList: Additional correct answers or variations in the learning language, stored
+// in their own table and attached by the service layer.
 //
 // Usage:
 // This struct is primarily used with GORM for querying and manipulating vocabulary data in a PostgreSQL db.
 // It is annotated with JSON and GORM tags to map it to the `vocab` table and ensure compatibility with the PostgreSQL backend.
 type Vocab struct {
 	ID               int       `json:"id" gorm:"primaryKey;autoIncrement"`
-	LearningLang     string    `json:"learning_lang" gorm:"not null;unique"`
+	LearningLang     string    `json:"learning_lang" gorm:"not null;uniqueIndex:idx_vocab_learning_lang_code,priority:2"`
 	FirstLang        string    `json:"first_lang" gorm:"not null"`
 	Created          time.Time `json:"created" gorm:"not null;default:now()"`
 	Alternatives     string    `json:"alternatives" gorm:"default:''"`
 	Skill            string    `json:"skill" gorm:"default:''"`
+	SkillID          *int      `json:"skill_id" gorm:"index"`
 	Infinitive       string    `json:"infinitive" gorm:"default:''"`
 	Pos              string    `json:"pos" gorm:"default:''"`
 	Hint             string    `json:"hint" gorm:"default:''"`
+	Gender           string    `json:"gender" gorm:"default:''"`
+	Plural           string    `json:"plural" gorm:"default:''"`
+	Article          string    `json:"article" gorm:"default:''"`
+	Register         string    `json:"register" gorm:"default:''"`
+	AudioID          *int      `json:"audio_id" gorm:"index"`
+	ConceptID        *int      `json:"concept_id" gorm:"index"`
 	NumLearningWords int       `json:"num_learning_words" gorm:"not null;default:1;check:num_learning_words >= 1"`
-	KnownLangCode    string    `json:"known_lang_code" gorm:"default:'en'"`
-	LearningLangCode string    `json:"learning_lang_code" gorm:"default:'es'"`
+	KnownLangCode    string    `json:"known_lang_code" gorm:"not null"`
+	LearningLangCode string    `json:"learning_lang_code" gorm:"not null;uniqueIndex:idx_vocab_learning_lang_code,priority:1"`
+
+	AlternativeList []VocabAlternative `json:"-" gorm:"-"`
 }
 
 // JSON Creates a JSON string from a Vocab object.
@@ -62,16 +81,25 @@ func (v *Vocab) Clone() *Vocab {
 		Created:          v.Created,
 		Alternatives:     v.Alternatives,
 		Skill:            v.Skill,
+		SkillID:          v.SkillID,
 		Infinitive:       v.Infinitive,
 		Pos:              v.Pos,
 		Hint:             v.Hint,
+		Gender:           v.Gender,
+		Plural:           v.Plural,
+		Article:          v.Article,
+		Register:         v.Register,
+		AudioID:          v.AudioID,
+		ConceptID:        v.ConceptID,
 		NumLearningWords: v.NumLearningWords,
 		KnownLangCode:    v.KnownLangCode,
 		LearningLangCode: v.LearningLangCode,
+		AlternativeList:  append([]VocabAlternative(nil), v.AlternativeList...),
 	}
 }
 
-// Compare two Vocab instances for equivalence
+// Compare two Vocab instances for equivalence. The AlternativeList is represented
+// by the delimited Alternatives field.
 func (v *Vocab) Compare(other *Vocab) bool {
 	return v.ID == other.ID &&
 		v.LearningLang == other.LearningLang &&
@@ -79,10 +107,85 @@ func (v *Vocab) Compare(other *Vocab) bool {
 		v.Created.Equal(other.Created) &&
 		v.Alternatives == other.Alternatives &&
 		v.Skill == other.Skill &&
+		equalIntPtr(v.SkillID, other.SkillID) &&
 		v.Infinitive == other.Infinitive &&
 		v.Pos == other.Pos &&
 		v.Hint == other.Hint &&
+		v.Gender == other.Gender &&
+		v.Plural == other.Plural &&
+		v.Article == other.Article &&
+		v.Register == other.Register &&
+		equalIntPtr(v.AudioID, other.AudioID) &&
+		equalIntPtr(v.ConceptID, other.ConceptID) &&
 		v.NumLearningWords == other.NumLearningWords &&
 		v.KnownLangCode == other.KnownLangCode &&
 		v.LearningLangCode == other.LearningLangCode
 }
+
+// VocabPatch describes a partial update to an existing Vocab record. Only the
+// fields that are non-nil are applied, everything else is left untouched. The
+// learning lang and language codes are intentionally absent since they key the
+// record and are not editable through an update. Alternatives have their own
+// add and remove operations.
+type VocabPatch struct {
+	ID               int
+	FirstLang        *string
+	Skill            *string
+	Infinitive       *string
+	Pos              *string
+	Hint             *string
+	Gender           *string
+	Plural           *string
+	Article          *string
+	Register         *string
+	NumLearningWords *int
+}
+
+// ApplyTo copies the provided patch fields onto the given Vocab and reports
+// whether any of them actually changed the record.
+func (p *VocabPatch) ApplyTo(v *Vocab) (changed bool) {
+	changed = patchString(&v.FirstLang, p.FirstLang) || changed
+	changed = patchString(&v.Skill, p.Skill) || changed
+	changed = patchString(&v.Infinitive, p.Infinitive) || changed
+	changed = patchString(&v.Pos, p.Pos) || changed
+	changed = patchString(&v.Hint, p.Hint) || changed
+	changed = patchString(&v.Gender, p.Gender) || changed
+	changed = patchString(&v.Plural, p.Plural) || changed
+	changed = patchString(&v.Article, p.Article) || changed
+	changed = patchString(&v.Register, p.Register) || changed
+
+	if p.NumLearningWords != nil && *p.NumLearningWords != v.NumLearningWords {
+		v.NumLearningWords = *p.NumLearningWords
+		changed = true
+	}
+
+	return
+}
+
+// equalIntPtr reports whether two optional ints are both nil or hold the same value.
+func equalIntPtr(a *int, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// patchString assigns the patch value to the target when one is provided and it differs.
+func patchString(target *string, value *string) bool {
+	if value == nil || *value == *target {
+		return false
+	}
+	*target = *value
+	return true
+}
+
+// VocabRename describes a correction to the keying fields of an existing Vocab record.
+// Empty language codes are left unchanged. When KeepOldAsAlternative is set, the previous
+// learning lang is recorded as an alternative spelling.
+type VocabRename struct {
+	ID                   int
+	LearningLang         string
+	KnownLangCode        string
+	LearningLangCode     string
+	KeepOldAsAlternative bool
+}
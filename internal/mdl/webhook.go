@@ -0,0 +1,262 @@
+package mdl
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// WebhookFixitCompleted is the event type of a fixit moving to completed, sent along with
+// fixit.created or fixit.updated.
+const WebhookFixitCompleted = "fixit.completed"
+
+// webhookTables are the audited tables webhooks receive the changes of.
+var webhookTables = []string{"vocab", "fixit", "concept", "language", "example_sentence", "skill",
+	"part_of_speech", "audio_asset", "conjugation"}
+
+// webhookActions are the changes audited in each of the webhookTables.
+var webhookActions = []string{AuditCreated, AuditUpdated, AuditDeleted}
+
+// Webhook is an endpoint registered to be told about content changes, such as the cache of
+// the learner app. Each change matching its event types is posted to the URL as JSON signed
+// with the secret, see WebhookDelivery.
+//
+// Fields:
+//   - ID: The unique identifier for the webhook, automatically incremented.
+//   - URL: The http or https URL the changes are posted to.
+//   - Secret: The shared secret the payloads are signed with. Never returned to clients.
+//   - EventTypes: Comma separated event types the webhook receives, e.g. "vocab.created, fixit.completed",
+//     or every event type when empty.
+//   - Enabled: Whether changes are delivered to the webhook.
+//   - Description: Optional notes on who the webhook is for.
+//   - CreatedBy: The identifier of the user or process that registered the webhook.
+//   - Created: The timestamp when the webhook was registered.
+type Webhook struct {
+	ID          int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	URL         string    `json:"url" gorm:"not null"`
+	Secret      string    `json:"-" gorm:"not null"`
+	EventTypes  string    `json:"event_types" gorm:"default:''"`
+	Enabled     bool      `json:"enabled" gorm:"not null"`
+	Description string    `json:"description" gorm:"default:''"`
+	CreatedBy   string    `json:"created_by" gorm:"not null"`
+	Created     time.Time `json:"created" gorm:"not null;default:now()"`
+}
+
+// JSON serializes the webhook for audits, without its secret.
+func (o *Webhook) JSON() string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		fmt.Printf("Error: %s", err)
+		return ""
+	}
+	return string(b)
+}
+
+// Wants reports whether the webhook receives changes of the event type, every event type
+// when it has no filters.
+func (o *Webhook) Wants(eventType string) bool {
+	filters := SplitWebhookEventTypes(o.EventTypes)
+	if len(filters) == 0 {
+		return true
+	}
+
+	table, _, _ := strings.Cut(eventType, ".")
+	for _, filter := range filters {
+		if filter == eventType || filter == table+".*" {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEventTypes returns the event types of an audited change, the table and the action
+// derived from its states, e.g. "vocab.updated". A fixit whose status moves to completed is
+// also WebhookFixitCompleted. Changes to tables outside the webhookTables have none.
+func WebhookEventTypes(audit *Audit) (eventTypes []string) {
+	if !slices.Contains(webhookTables, audit.TableName) {
+		return nil
+	}
+
+	eventTypes = append(eventTypes, audit.TableName+"."+audit.DerivedAction())
+
+	if audit.TableName == "fixit" && fixitStatusOf(audit.After) == Completed &&
+		fixitStatusOf(audit.Before) != Completed {
+		eventTypes = append(eventTypes, WebhookFixitCompleted)
+	}
+	return
+}
+
+// fixitStatusOf returns the status of the audited fixit JSON, empty when there is none.
+func fixitStatusOf(fixitJson string) StatusType {
+	var fixit Fixit
+	if len(fixitJson) == 0 || json.Unmarshal([]byte(fixitJson), &fixit) != nil {
+		return ""
+	}
+	return fixit.Status
+}
+
+// ValidWebhookEventType reports whether a webhook can filter on the event type, one of the
+// event types of the webhookTables, fixit.completed, or every change of a table such as "vocab.*".
+func ValidWebhookEventType(eventType string) bool {
+	if eventType == WebhookFixitCompleted {
+		return true
+	}
+
+	table, action, found := strings.Cut(eventType, ".")
+	return found && slices.Contains(webhookTables, table) &&
+		(action == "*" || slices.Contains(webhookActions, action))
+}
+
+// SplitWebhookEventTypes splits the comma separated event types of a webhook, trimmed and
+// lower cased, without duplicates.
+func SplitWebhookEventTypes(list string) (eventTypes []string) {
+	for _, eventType := range strings.Split(list, ",") {
+		eventType = strings.ToLower(strings.TrimSpace(eventType))
+		if len(eventType) > 0 && !slices.Contains(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	return
+}
+
+// WebhookPatch describes a partial update to a webhook. Only the non-nil fields are applied.
+type WebhookPatch struct {
+	ID          int
+	URL         *string
+	Secret      *string
+	EventTypes  *string
+	Enabled     *bool
+	Description *string
+}
+
+// ApplyTo copies the provided patch fields onto the given Webhook and reports whether any
+// of them actually changed it.
+func (p *WebhookPatch) ApplyTo(w *Webhook) (changed bool) {
+	changed = patchString(&w.URL, p.URL) || changed
+	changed = patchString(&w.Secret, p.Secret) || changed
+	changed = patchString(&w.EventTypes, p.EventTypes) || changed
+	changed = patchBool(&w.Enabled, p.Enabled) || changed
+	changed = patchString(&w.Description, p.Description) || changed
+	return
+}
+
+// The delivery statuses of a WebhookDelivery.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is one change to post to a webhook. Deliveries are kept, so a failed
+// delivery is retried with a growing delay until it succeeds or runs out of attempts, and
+// the history of a webhook can be inspected.
+//
+// Fields:
+//   - ID: The unique identifier for the delivery, automatically incremented.
+//   - WebhookID: The ID of the webhook the change is posted to.
+//   - EventType: The event type of the change, e.g. "vocab.updated".
+//   - AuditID: The ID of the audit record the payload is derived from.
+//   - Payload: The JSON posted, the same on every attempt.
+//   - Status: DeliveryPending, DeliveryDelivered or DeliveryFailed.
+//   - Attempts: The number of attempts made.
+//   - NextAttempt: When a pending delivery is next attempted.
+//   - ResponseStatus: The HTTP status of the last attempt, 0 when there was no response.
+//   - LastError: The reason the last attempt failed, empty once delivered.
+//   - Created: The timestamp when the delivery was queued.
+//   - Delivered: The timestamp of the successful attempt, nil until then.
+type WebhookDelivery struct {
+	ID             int        `json:"id" gorm:"primaryKey;autoIncrement"`
+	WebhookID      int        `json:"webhook_id" gorm:"not null;index:idx_webhook_delivery_webhook"`
+	EventType      string     `json:"event_type" gorm:"not null"`
+	AuditID        int        `json:"audit_id" gorm:"not null"`
+	Payload        string     `json:"payload" gorm:"not null"`
+	Status         string     `json:"status" gorm:"not null;index:idx_webhook_delivery_due,priority:1"`
+	Attempts       int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttempt    time.Time  `json:"next_attempt" gorm:"not null;index:idx_webhook_delivery_due,priority:2"`
+	ResponseStatus int        `json:"response_status" gorm:"not null;default:0"`
+	LastError      string     `json:"last_error" gorm:"default:''"`
+	Created        time.Time  `json:"created" gorm:"not null;default:now()"`
+	Delivered      *time.Time `json:"delivered"`
+}
+
+// WebhookPayload is the JSON posted to a webhook, derived from the audit of the change. The
+// before, after and diff states are the JSON of the audit, null when it has none.
+type WebhookPayload struct {
+	Event     string          `json:"event"`
+	AuditID   int             `json:"audit_id"`
+	Table     string          `json:"table"`
+	ObjectID  int             `json:"object_id"`
+	Comments  string          `json:"comments"`
+	CreatedBy string          `json:"created_by"`
+	Created   time.Time       `json:"created"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	Diff      json.RawMessage `json:"diff"`
+}
+
+// rawJSON returns the audited JSON state to embed in a payload, null when it is empty or malformed.
+func rawJSON(state string) json.RawMessage {
+	if len(state) == 0 || !json.Valid([]byte(state)) {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(state)
+}
+
+// NewWebhookDeliveries returns the pending deliveries of a saved audit, one for each enabled
+// webhook and each of the WebhookEventTypes of the audit the webhook wants.
+//
+// Parameters:
+// - webhooks: The registered webhooks.
+// - audit: The audit of the change, saved so it has its ID.
+//
+// Returns:
+// - The deliveries to queue, none when no webhook wants the change.
+// - An error if a payload cannot be built.
+func NewWebhookDeliveries(webhooks []Webhook, audit *Audit) (deliveries []WebhookDelivery, err error) {
+	eventTypes := WebhookEventTypes(audit)
+	if len(eventTypes) == 0 {
+		return nil, nil
+	}
+
+	created := audit.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+
+	for i := range webhooks {
+		if !webhooks[i].Enabled {
+			continue
+		}
+		for _, eventType := range eventTypes {
+			if !webhooks[i].Wants(eventType) {
+				continue
+			}
+			payload, err := json.Marshal(WebhookPayload{
+				Event:     eventType,
+				AuditID:   audit.ID,
+				Table:     audit.TableName,
+				ObjectID:  audit.ObjectID,
+				Comments:  audit.Comments,
+				CreatedBy: audit.CreatedBy,
+				Created:   created.UTC(),
+				Before:    rawJSON(audit.Before),
+				After:     rawJSON(audit.After),
+				Diff:      rawJSON(audit.Diff),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to build the %s payload of audit %d, error: %v", eventType, audit.ID, err)
+			}
+			deliveries = append(deliveries, WebhookDelivery{
+				WebhookID:   webhooks[i].ID,
+				EventType:   eventType,
+				AuditID:     audit.ID,
+				Payload:     string(payload),
+				Status:      DeliveryPending,
+				NextAttempt: time.Now(),
+			})
+		}
+	}
+	return
+}
@@ -0,0 +1,62 @@
+package mdl
+
+import (
+	"testing"
+)
+
+func TestWebhookEventTypes(t *testing.T) {
+	pending := `{"id":1,"Status":"pending"}`
+	completed := `{"id":1,"Status":"completed"}`
+
+	tests := []struct {
+		name  string
+		audit Audit
+		want  []string
+	}{
+		{"Created vocab", Audit{TableName: "vocab", After: `{"id":1}`}, []string{"vocab.created"}},
+		{"Updated vocab", Audit{TableName: "vocab", Before: `{"id":1}`, After: `{"id":1}`}, []string{"vocab.updated"}},
+		{"Deleted skill", Audit{TableName: "skill", Before: `{"id":1}`}, []string{"skill.deleted"}},
+		{"Completed fixit", Audit{TableName: "fixit", Before: pending, After: completed}, []string{"fixit.updated", "fixit.completed"}},
+		{"Created completed fixit", Audit{TableName: "fixit", After: completed}, []string{"fixit.created", "fixit.completed"}},
+		{"Still completed fixit", Audit{TableName: "fixit", Before: completed, After: completed}, []string{"fixit.updated"}},
+		{"Unknown table", Audit{TableName: "webhook", After: `{"id":1}`}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WebhookEventTypes(&tt.audit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("WebhookEventTypes() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("WebhookEventTypes() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhook_Wants(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventTypes string
+		eventType  string
+		want       bool
+	}{
+		{"No filters", "", "skill.deleted", true},
+		{"Exact event type", "vocab.created, fixit.completed", "fixit.completed", true},
+		{"Every change of a table", "vocab.*", "vocab.updated", true},
+		{"Other table", "vocab.*", "concept.updated", false},
+		{"Other action", "vocab.created", "vocab.deleted", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := Webhook{EventTypes: tt.eventTypes}
+			if got := webhook.Wants(tt.eventType); got != tt.want {
+				t.Errorf("Wants(%s) = %v, want %v", tt.eventType, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// The audio MIME types assets are stored and served with.
+const (
+	MimeOgg = "audio/ogg"
+	MimeMP3 = "audio/mpeg"
+	MimeWav = "audio/wav"
+)
+
+// mimeAliases are the content types clients declare for each supported audio type.
+var mimeAliases = map[string][]string{
+	MimeOgg: {MimeOgg, "application/ogg", "audio/opus", "audio/vorbis"},
+	MimeMP3: {MimeMP3, "audio/mp3", "audio/mpeg3", "audio/x-mpeg"},
+	MimeWav: {MimeWav, "audio/wave", "audio/x-wav", "audio/vnd.wave"},
+}
+
+// AudioInfo is what Inspect finds out about an audio recording.
+//
+// Fields:
+//   - MimeType: One of MimeOgg, MimeMP3 or MimeWav.
+//   - Duration: The playing time of the recording.
+type AudioInfo struct {
+	MimeType string
+	Duration time.Duration
+}
+
+// DetectAudioType returns the MIME type of an audio recording from its first bytes, or empty
+// when it is not one of the supported types.
+func DetectAudioType(header []byte) string {
+	switch {
+	case bytes.HasPrefix(header, []byte("OggS")):
+		return MimeOgg
+	case len(header) >= 12 && bytes.Equal(header[:4], []byte("RIFF")) && bytes.Equal(header[8:12], []byte("WAVE")):
+		return MimeWav
+	case bytes.HasPrefix(header, []byte("ID3")), len(header) >= 2 && header[0] == 0xFF && header[1]&0xE0 == 0xE0:
+		return MimeMP3
+	}
+	return ""
+}
+
+// MatchesMimeType reports whether a content type declared by a client is one of the names of
+// the detected MIME type. An empty or generic declared type matches anything.
+func MatchesMimeType(declared string, detected string) bool {
+	if len(declared) == 0 || declared == "application/octet-stream" {
+		return true
+	}
+	for _, alias := range mimeAliases[detected] {
+		if declared == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// Inspect detects the type of an audio recording and reads its duration from the container,
+// the WAV data chunk, the granule position of the last Ogg page, or the MP3 frames.
+//
+// Parameters:
+// - data: The whole recording.
+//
+// Returns:
+// - The type and duration of the recording.
+// - An error if the recording is not an ogg, mp3 or wav file, or it is malformed.
+//
+// Usage example:
+// info, err := media.Inspect(data)
+//
+//	if err != nil {
+//	    log.Printf("Unsupported audio: %v", err)
+//	}
+func Inspect(data []byte) (info AudioInfo, err error) {
+	info.MimeType = DetectAudioType(data)
+
+	switch info.MimeType {
+	case MimeWav:
+		info.Duration, err = wavDuration(data)
+	case MimeOgg:
+		info.Duration, err = oggDuration(data)
+	case MimeMP3:
+		info.Duration, err = mp3Duration(data)
+	default:
+		err = fmt.Errorf("audio must be an ogg, mp3 or wav file")
+	}
+
+	return
+}
+
+// wavDuration divides the size of the data chunk by the byte rate of the fmt chunk.
+func wavDuration(data []byte) (time.Duration, error) {
+	var byteRate uint32
+	for offset := 12; offset+8 <= len(data); {
+		id := string(data[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(data[offset+4 : offset+8]))
+		body := offset + 8
+
+		switch id {
+		case "fmt ":
+			if size < 16 || body+16 > len(data) {
+				return 0, fmt.Errorf("wav fmt chunk is truncated")
+			}
+			byteRate = binary.LittleEndian.Uint32(data[body+8 : body+12])
+		case "data":
+			if byteRate == 0 {
+				return 0, fmt.Errorf("wav data chunk comes before a valid fmt chunk")
+			}
+			if body+size > len(data) {
+				size = len(data) - body
+			}
+			return time.Duration(float64(size) / float64(byteRate) * float64(time.Second)), nil
+		}
+
+		// Chunks are padded to an even size.
+		offset = body + size + size%2
+	}
+
+	return 0, fmt.Errorf("wav file has no data chunk")
+}
+
+// oggDuration reads the sample rate from the identification header of the first page and
+// divides the granule position of the last page by it. Vorbis and Opus streams are supported.
+func oggDuration(data []byte) (time.Duration, error) {
+	if len(data) < 27 {
+		return 0, fmt.Errorf("ogg page is truncated")
+	}
+
+	// The first packet starts after the page header and its segment table.
+	packet := data[27+int(data[26]):]
+
+	var rate, preSkip int64
+	switch {
+	case bytes.HasPrefix(packet, []byte("\x01vorbis")) && len(packet) >= 16:
+		rate = int64(binary.LittleEndian.Uint32(packet[12:16]))
+	case bytes.HasPrefix(packet, []byte("OpusHead")) && len(packet) >= 12:
+		// Opus granule positions always count 48 kHz samples.
+		rate, preSkip = 48000, int64(binary.LittleEndian.Uint16(packet[10:12]))
+	default:
+		return 0, fmt.Errorf("ogg stream must be vorbis or opus")
+	}
+	if rate == 0 {
+		return 0, fmt.Errorf("ogg stream has no sample rate")
+	}
+
+	last := bytes.LastIndex(data, []byte("OggS"))
+	if last+14 > len(data) {
+		return 0, fmt.Errorf("ogg page is truncated")
+	}
+	granule := int64(binary.LittleEndian.Uint64(data[last+6 : last+14]))
+	if granule < preSkip {
+		return 0, fmt.Errorf("ogg stream has no audio")
+	}
+
+	return time.Duration(float64(granule-preSkip) / float64(rate) * float64(time.Second)), nil
+}
+
+// MPEG audio layer III bitrates in kbps and sample rates in Hz, by header index.
+var (
+	mp3Bitrates = map[bool][]int{
+		true:  {0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+		false: {0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+	}
+	mp3SampleRates = map[int][]int{
+		3: {44100, 48000, 32000}, // MPEG 1
+		2: {22050, 24000, 16000}, // MPEG 2
+		0: {11025, 12000, 8000},  // MPEG 2.5
+	}
+)
+
+// mp3Duration skips an ID3v2 tag and adds up the playing time of the frames that follow,
+// which handles constant and variable bitrate files alike.
+func mp3Duration(data []byte) (time.Duration, error) {
+	offset := 0
+	if bytes.HasPrefix(data, []byte("ID3")) && len(data) >= 10 {
+		// The tag size is a syncsafe integer, seven bits per byte.
+		size := int(data[6])<<21 | int(data[7])<<14 | int(data[8])<<7 | int(data[9])
+		offset = 10 + size
+		if data[5]&0x10 != 0 {
+			offset += 10
+		}
+	}
+
+	var seconds float64
+	frames := 0
+	for offset+4 <= len(data) {
+		header := binary.BigEndian.Uint32(data[offset : offset+4])
+		if header>>21 != 0x7FF {
+			break
+		}
+
+		version := int(header>>19) & 0x3
+		layer := int(header>>17) & 0x3
+		bitrateIndex := int(header>>12) & 0xF
+		rateIndex := int(header>>10) & 0x3
+		padding := int(header>>9) & 0x1
+
+		rates, found := mp3SampleRates[version]
+		if !found || layer != 1 || bitrateIndex == 0 || bitrateIndex == 15 || rateIndex == 3 {
+			return 0, fmt.Errorf("mp3 frame %d is not a valid layer III frame", frames)
+		}
+
+		mpeg1 := version == 3
+		bitrate := mp3Bitrates[mpeg1][bitrateIndex] * 1000
+		sampleRate := rates[rateIndex]
+
+		samples, coefficient := 576, 72
+		if mpeg1 {
+			samples, coefficient = 1152, 144
+		}
+
+		offset += coefficient*bitrate/sampleRate + padding
+		seconds += float64(samples) / float64(sampleRate)
+		frames++
+	}
+
+	if frames == 0 {
+		return 0, fmt.Errorf("mp3 file has no audio frames")
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
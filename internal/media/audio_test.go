@@ -0,0 +1,125 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestInspect(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         []byte
+		wantMimeType string
+		wantDuration time.Duration
+		wantErr      bool
+	}{
+		{name: "Wav", data: testWav(16000, 48000), wantMimeType: MimeWav, wantDuration: 1500 * time.Millisecond},
+		{name: "Ogg vorbis", data: testOgg(false, 44100, 88200), wantMimeType: MimeOgg, wantDuration: 2 * time.Second},
+		{name: "Ogg opus with pre-skip", data: testOgg(true, 48000, 48000+312), wantMimeType: MimeOgg, wantDuration: time.Second},
+		{name: "Mp3 with ID3 tag", data: testMp3(38, true), wantMimeType: MimeMP3, wantDuration: 992653061 * time.Nanosecond},
+		{name: "Mp3 without tag", data: testMp3(1, false), wantMimeType: MimeMP3, wantDuration: 26122448 * time.Nanosecond},
+		{name: "Not audio", data: []byte("%PDF-1.4"), wantErr: true},
+		{name: "Wav without data", data: testWav(16000, 0)[:36], wantErr: true},
+		{name: "Ogg flac", data: append(testOgg(false, 44100, 1)[:28], []byte("\x7fFLAC")...), wantErr: true},
+		{name: "Mp3 bad frame", data: []byte{0xFF, 0xFF, 0xFF, 0xFF}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := Inspect(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Inspect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if info.MimeType != tt.wantMimeType || info.Duration != tt.wantDuration {
+				t.Errorf("Inspect() = %+v, want %s %v", info, tt.wantMimeType, tt.wantDuration)
+			}
+		})
+	}
+}
+
+func TestMatchesMimeType(t *testing.T) {
+	tests := []struct {
+		declared string
+		detected string
+		want     bool
+	}{
+		{declared: "", detected: MimeOgg, want: true},
+		{declared: "application/octet-stream", detected: MimeWav, want: true},
+		{declared: "audio/x-wav", detected: MimeWav, want: true},
+		{declared: "audio/mp3", detected: MimeMP3, want: true},
+		{declared: "audio/ogg", detected: MimeMP3, want: false},
+		{declared: "image/png", detected: MimeOgg, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchesMimeType(tt.declared, tt.detected); got != tt.want {
+			t.Errorf("MatchesMimeType(%q, %q) = %v, want %v", tt.declared, tt.detected, got, tt.want)
+		}
+	}
+}
+
+// testWav builds a 16 bit mono wav file with the given number of zero bytes of samples.
+func testWav(rate uint32, dataSize uint32) []byte {
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	_ = binary.Write(&b, binary.LittleEndian, 36+dataSize)
+	b.WriteString("WAVEfmt ")
+	for _, field := range []any{uint32(16), uint16(1), uint16(1), rate, rate * 2, uint16(2), uint16(16)} {
+		_ = binary.Write(&b, binary.LittleEndian, field)
+	}
+	b.WriteString("data")
+	_ = binary.Write(&b, binary.LittleEndian, dataSize)
+	b.Write(make([]byte, dataSize))
+	return b.Bytes()
+}
+
+// testOgg builds an identification page and a last page with the given granule position.
+func testOgg(opus bool, rate uint32, granule uint64) []byte {
+	var packet bytes.Buffer
+	if opus {
+		packet.WriteString("OpusHead")
+		packet.Write([]byte{1, 1})
+		_ = binary.Write(&packet, binary.LittleEndian, uint16(312))
+		_ = binary.Write(&packet, binary.LittleEndian, rate)
+	} else {
+		packet.WriteString("\x01vorbis")
+		_ = binary.Write(&packet, binary.LittleEndian, uint32(0))
+		packet.WriteByte(1)
+		_ = binary.Write(&packet, binary.LittleEndian, rate)
+	}
+
+	page := func(granule uint64, body []byte) []byte {
+		var p bytes.Buffer
+		p.WriteString("OggS")
+		p.Write([]byte{0, 0})
+		_ = binary.Write(&p, binary.LittleEndian, granule)
+		p.Write(make([]byte, 12))
+		p.Write([]byte{1, byte(len(body))})
+		p.Write(body)
+		return p.Bytes()
+	}
+
+	return append(page(0, packet.Bytes()), page(granule, make([]byte, 10))...)
+}
+
+// testMp3 builds MPEG 1 layer III frames at 128 kbps and 44.1 kHz, 417 bytes each.
+func testMp3(frames int, tagged bool) []byte {
+	var b bytes.Buffer
+	if tagged {
+		b.WriteString("ID3")
+		b.Write([]byte{4, 0, 0, 0, 0, 1, 0})
+		b.Write(make([]byte, 128))
+	}
+	for i := 0; i < frames; i++ {
+		frame := make([]byte, 417)
+		copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+		b.Write(frame)
+	}
+	b.WriteString("TAG")
+	return b.Bytes()
+}
@@ -0,0 +1,58 @@
+package media
+
+import (
+	"io"
+	"net/http"
+	"strings"
+)
+
+// AudioPath is the URL path audio assets are served under, followed by their key.
+const AudioPath = "/admin/media/audio/"
+
+// AudioURL returns the URL path an audio asset is served at.
+func AudioURL(key string) string {
+	return AudioPath + key
+}
+
+// Handler serves the audio assets of a storage under AudioPath. Range requests are supported
+// so players can seek, and since a key always refers to the same content, responses may be
+// cached for good.
+//
+// Usage example:
+//
+//	http.Handle(media.AudioPath, media.Handler(storage))
+func Handler(storage Storage) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, AudioPath)
+		file, object, err := storage.Open(key)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		defer file.Close()
+
+		header := make([]byte, 12)
+		n, _ := io.ReadFull(file, header)
+		if _, err = file.Seek(0, io.SeekStart); err != nil {
+			http.Error(w, "failed to read media", http.StatusInternalServerError)
+			return
+		}
+
+		mimeType := DetectAudioType(header[:n])
+		if len(mimeType) == 0 {
+			mimeType = "application/octet-stream"
+		}
+
+		w.Header().Set("Content-Type", mimeType)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("ETag", `"`+object.Key+`"`)
+		http.ServeContent(w, r, "", object.Modified, file)
+	})
+}
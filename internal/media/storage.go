@@ -0,0 +1,193 @@
+// Package media stores and inspects the media assets attached to vocab, such as audio
+// pronunciation recordings. Assets are content addressed, the key of an asset is the hex
+// SHA-256 of its bytes, so uploading the same recording twice stores it once and a key
+// always refers to the same content.
+package media
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMediaRoot    = "media"
+	defaultSnapshotRoot = "snapshots"
+)
+
+// keyPattern matches a valid asset key, a hex SHA-256.
+var keyPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Object describes a stored asset.
+//
+// Fields:
+//   - Key: The hex SHA-256 of the asset content.
+//   - Size: The size of the asset in bytes.
+//   - Modified: When the asset was stored.
+type Object struct {
+	Key      string
+	Size     int64
+	Modified time.Time
+}
+
+// Storage holds the content of media assets by key.
+type Storage interface {
+	// Put stores the content and returns its key. Storing content that is already stored
+	// returns the same key and leaves the stored copy as it is.
+	Put(content io.Reader) (Object, error)
+	// Open returns the content of an asset for reading and seeking.
+	Open(key string) (io.ReadSeekCloser, Object, error)
+	// Delete removes an asset, deleting a missing asset is not an error.
+	Delete(key string) error
+	// List returns every stored asset.
+	List() ([]Object, error)
+}
+
+// LocalStorage is a Storage on the local filesystem. Assets are kept under the root in a
+// directory named by the first two characters of their key, to keep directories small.
+type LocalStorage struct {
+	root string
+}
+
+// NewLocalStorage creates a LocalStorage under the root directory, creating it when missing.
+//
+// Parameters:
+// - root: The directory assets are stored in.
+//
+// Returns:
+// - The storage.
+// - An error if the root directory cannot be created.
+func NewLocalStorage(root string) (*LocalStorage, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create media root %s, error: %v", root, err)
+	}
+	return &LocalStorage{root: root}, nil
+}
+
+// StorageFromEnv creates the LocalStorage under the MEDIA_ROOT env var, or the media directory
+// of the working directory when it is not set.
+func StorageFromEnv() (Storage, error) {
+	root := os.Getenv("MEDIA_ROOT")
+	if len(root) == 0 {
+		root = defaultMediaRoot
+	}
+	return NewLocalStorage(root)
+}
+
+// SnapshotStorageFromEnv creates the LocalStorage published snapshots are kept in, under the
+// SNAPSHOT_ROOT env var, or the snapshots directory of the working directory when it is not
+// set. Snapshots are kept apart from the media assets, which are cleaned up when no vocab
+// uses them.
+func SnapshotStorageFromEnv() (Storage, error) {
+	root := os.Getenv("SNAPSHOT_ROOT")
+	if len(root) == 0 {
+		root = defaultSnapshotRoot
+	}
+	return NewLocalStorage(root)
+}
+
+// ValidKey reports whether the key is a well formed asset key.
+func ValidKey(key string) bool {
+	return keyPattern.MatchString(key)
+}
+
+// Put writes the content to a temporary file while hashing it, then moves the file to its key.
+func (s *LocalStorage) Put(content io.Reader) (Object, error) {
+	tmp, err := os.CreateTemp(s.root, "upload-*")
+	if err != nil {
+		return Object{}, fmt.Errorf("failed to create media file, error: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return Object{}, fmt.Errorf("failed to write media file, error: %v", err)
+	}
+
+	key := hex.EncodeToString(hash.Sum(nil))
+	path := s.path(key)
+	if info, err := os.Stat(path); err == nil {
+		return Object{Key: key, Size: info.Size(), Modified: info.ModTime()}, nil
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return Object{}, fmt.Errorf("failed to create media directory, error: %v", err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return Object{}, fmt.Errorf("failed to store media %s, error: %v", key, err)
+	}
+
+	return Object{Key: key, Size: size, Modified: time.Now()}, nil
+}
+
+// Open opens the file of an asset.
+func (s *LocalStorage) Open(key string) (io.ReadSeekCloser, Object, error) {
+	if !ValidKey(key) {
+		return nil, Object{}, fmt.Errorf("invalid media key %s", key)
+	}
+
+	file, err := os.Open(s.path(key))
+	if err != nil {
+		return nil, Object{}, fmt.Errorf("media %s not found", key)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, Object{}, fmt.Errorf("failed to read media %s, error: %v", key, err)
+	}
+
+	return file, Object{Key: key, Size: info.Size(), Modified: info.ModTime()}, nil
+}
+
+// Delete removes the file of an asset.
+func (s *LocalStorage) Delete(key string) error {
+	if !ValidKey(key) {
+		return fmt.Errorf("invalid media key %s", key)
+	}
+
+	if err := os.Remove(s.path(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete media %s, error: %v", key, err)
+	}
+	return nil
+}
+
+// List walks the root for asset files, skipping temporary uploads and anything else that is
+// not named by a key.
+func (s *LocalStorage) List() (objects []Object, err error) {
+	err = filepath.WalkDir(s.root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !ValidKey(entry.Name()) || !strings.HasPrefix(entry.Name(), filepath.Base(filepath.Dir(path))) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, Object{Key: entry.Name(), Size: info.Size(), Modified: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list media, error: %v", err)
+	}
+
+	return
+}
+
+// path returns the file path of a key.
+func (s *LocalStorage) path(key string) string {
+	return filepath.Join(s.root, key[:2], key)
+}
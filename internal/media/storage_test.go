@@ -0,0 +1,103 @@
+package media
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorage(t *testing.T) {
+	storage, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStorage() error = %v", err)
+	}
+
+	content := testWav(8000, 800)
+	object, err := storage.Put(bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if !ValidKey(object.Key) || object.Size != int64(len(content)) {
+		t.Errorf("Put() = %+v", object)
+	}
+
+	again, err := storage.Put(bytes.NewReader(content))
+	if err != nil || again.Key != object.Key {
+		t.Errorf("Put() of the same content = %+v, %v", again, err)
+	}
+
+	file, _, err := storage.Open(object.Key)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	read, _ := io.ReadAll(file)
+	file.Close()
+	if !bytes.Equal(read, content) {
+		t.Errorf("Open() read %d bytes, want %d", len(read), len(content))
+	}
+
+	objects, err := storage.List()
+	if err != nil || len(objects) != 1 || objects[0].Key != object.Key {
+		t.Errorf("List() = %+v, %v", objects, err)
+	}
+
+	if _, _, err = storage.Open("../../etc/passwd"); err == nil {
+		t.Errorf("Open() expected an error for an invalid key")
+	}
+
+	if err = storage.Delete(object.Key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err = storage.Delete(object.Key); err != nil {
+		t.Errorf("Delete() of a missing asset error = %v", err)
+	}
+	if _, _, err = storage.Open(object.Key); err == nil {
+		t.Errorf("Open() expected an error for a deleted asset")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	storage, _ := NewLocalStorage(t.TempDir())
+	content := testWav(8000, 800)
+	object, _ := storage.Put(bytes.NewReader(content))
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		rangeValue string
+		wantStatus int
+		wantLength int
+	}{
+		{name: "Whole asset", method: http.MethodGet, path: AudioURL(object.Key), wantStatus: http.StatusOK, wantLength: len(content)},
+		{name: "Range", method: http.MethodGet, path: AudioURL(object.Key), rangeValue: "bytes=0-99",
+			wantStatus: http.StatusPartialContent, wantLength: 100},
+		{name: "Missing asset", method: http.MethodGet, path: AudioURL(strings.Repeat("0", 64)), wantStatus: http.StatusNotFound},
+		{name: "Invalid key", method: http.MethodGet, path: AudioPath + "..%2F..%2Fsecret", wantStatus: http.StatusNotFound},
+		{name: "Wrong method", method: http.MethodPost, path: AudioURL(object.Key), wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(tt.method, tt.path, nil)
+			if len(tt.rangeValue) > 0 {
+				request.Header.Set("Range", tt.rangeValue)
+			}
+			recorder := httptest.NewRecorder()
+
+			Handler(storage).ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("ServeHTTP() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if tt.wantLength > 0 {
+				if recorder.Body.Len() != tt.wantLength || recorder.Header().Get("Content-Type") != MimeWav {
+					t.Errorf("ServeHTTP() = %d bytes of %s", recorder.Body.Len(), recorder.Header().Get("Content-Type"))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package outbox
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"log"
+	"time"
+)
+
+const (
+	// relayBatch is the number of pending events relayed at once, and relayLease how long
+	// they are claimed, long enough for each of them to time out in turn.
+	relayBatch = 100
+	relayLease = relayBatch*httpSinkTimeout + time.Minute
+
+	// publishedRetention is how long published events are kept before they are purged.
+	publishedRetention = 7 * 24 * time.Hour
+
+	maxRelayErrorLen = 500
+)
+
+// metrics are the outbox metrics served with the other expvar variables on /debug/vars:
+//   - published: The number of events published by this instance.
+//   - failed: The number of failed attempts to publish an event.
+//   - pending: The number of events waiting to be published.
+//   - lag_seconds: How long the oldest pending event has waited, 0 when none are pending.
+//   - publish_lag_seconds: How long the last event published waited to be published.
+var metrics = expvar.NewMap("outbox")
+
+var (
+	publishedCount    = new(expvar.Int)
+	failedCount       = new(expvar.Int)
+	pendingCount      = new(expvar.Int)
+	lagSeconds        = new(expvar.Float)
+	publishLagSeconds = new(expvar.Float)
+)
+
+func init() {
+	metrics.Set("published", publishedCount)
+	metrics.Set("failed", failedCount)
+	metrics.Set("pending", pendingCount)
+	metrics.Set("lag_seconds", lagSeconds)
+	metrics.Set("publish_lag_seconds", publishLagSeconds)
+}
+
+// Relay publishes the pending outbox events to a sink.
+type Relay struct {
+	repo db.OutboxRepository
+	sink Sink
+}
+
+// NewRelay creates a Relay publishing the outbox to the sink.
+//
+// Usage example:
+// sink, err := outbox.SinkFromEnv()
+//
+//	if err != nil {
+//	    log.Fatalf("Failed outbox sink: %v", err)
+//	}
+//
+// relay, err := outbox.NewRelay(sink)
+// go relay.Run(ctx, time.Second)
+func NewRelay(sink Sink) (*Relay, error) {
+	repo, err := db.NewSqlOutboxRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Relay{repo: repo, sink: sink}, nil
+}
+
+// RelayPending claims the oldest pending events and publishes them in ID order, with no
+// transaction open, then saves the outcomes. When an event fails to publish, the later
+// events of its record are held back so they are not published ahead of it, and it is
+// attempted again, first, in the next round. The events of other records go on.
+//
+// Parameters:
+// - ctx: The context of the round, a done context fails the event being published.
+//
+// Returns:
+// - The number of events published.
+// - An error if the outbox cannot be claimed or the outcome cannot be saved.
+func (r *Relay) RelayPending(ctx context.Context) (published int, err error) {
+	pending, err := r.repo.ClaimPending(time.Now(), relayLease, relayBatch)
+	if err != nil || len(*pending) == 0 {
+		return 0, err
+	}
+
+	held := make(map[string]bool)
+	for i := range *pending {
+		event := &(*pending)[i]
+		record := fmt.Sprintf("%s:%d", event.AggregateType, event.AggregateID)
+		if held[record] {
+			continue
+		}
+
+		event.Attempts++
+		if failure := r.sink.Publish(ctx, event); failure != nil {
+			held[record] = true
+			event.LastError = failure.Error()
+			if runes := []rune(event.LastError); len(runes) > maxRelayErrorLen {
+				event.LastError = string(runes[:maxRelayErrorLen])
+			}
+			failedCount.Add(1)
+			log.Printf("Failed to publish outbox event %d, attempt %d: %v", event.ID, event.Attempts, failure)
+			continue
+		}
+
+		now := time.Now()
+		event.Published = &now
+		event.LastError = ""
+		published++
+		publishedCount.Add(1)
+		publishLagSeconds.Set(now.Sub(event.Created).Seconds())
+	}
+
+	err = r.repo.UpdateClaimed(pending)
+	return
+}
+
+// recordLag updates the pending and lag_seconds metrics from the outbox.
+func (r *Relay) recordLag(now time.Time) error {
+	stats, err := r.repo.FindOutboxStats()
+	if err != nil {
+		return err
+	}
+
+	pendingCount.Set(stats.Pending)
+	if stats.OldestPending == nil {
+		lagSeconds.Set(0)
+	} else {
+		lagSeconds.Set(now.Sub(*stats.OldestPending).Seconds())
+	}
+	return nil
+}
+
+// Run relays the outbox every interval until the context is done, going on at once while
+// full batches are published. Rounds that fail are logged and tried again at the next
+// interval. Published events are purged once they are older than publishedRetention.
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastPurge := time.Time{}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for ctx.Err() == nil {
+			published, err := r.RelayPending(ctx)
+			if err != nil {
+				log.Printf("Failed to relay the outbox: %v", err)
+			}
+			if err != nil || published < relayBatch {
+				break
+			}
+		}
+
+		now := time.Now()
+		if err := r.recordLag(now); err != nil {
+			log.Printf("Failed to measure the outbox lag: %v", err)
+		}
+
+		if now.Sub(lastPurge) > time.Hour {
+			lastPurge = now
+			if _, err := r.repo.PurgePublished(now.Add(-publishedRetention)); err != nil {
+				log.Printf("Failed to purge the outbox: %v", err)
+			}
+		}
+	}
+}
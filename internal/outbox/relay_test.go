@@ -0,0 +1,118 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"github.com/heather92115/verdure-admin/internal/db/mock"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"testing"
+	"time"
+)
+
+// recordingSink records the IDs of the events published, failing those of the records it is told to.
+type recordingSink struct {
+	published []int
+	failing   map[int]bool
+}
+
+func (s *recordingSink) Publish(_ context.Context, event *mdl.OutboxEvent) error {
+	if s.failing[event.AggregateID] {
+		return errors.New("sink unavailable")
+	}
+	s.published = append(s.published, event.ID)
+	return nil
+}
+
+func TestRelay_RelayPending(t *testing.T) {
+	repo := mock.NewMockOutboxRepository(
+		mdl.NewOutboxEvent(mdl.AggregateVocab, 1, "created", `{"id":1}`),
+		mdl.NewOutboxEvent(mdl.AggregateVocab, 2, "created", `{"id":2}`),
+		mdl.NewOutboxEvent(mdl.AggregateVocab, 1, "updated", `{"id":1}`),
+		mdl.NewOutboxEvent(mdl.AggregateFixit, 1, "created", `{"id":1}`),
+		mdl.NewOutboxEvent(mdl.AggregateVocab, 2, "updated", `{"id":2}`),
+	)
+	sink := &recordingSink{failing: map[int]bool{2: true}}
+	relay := &Relay{repo: repo, sink: sink}
+
+	published, err := relay.RelayPending(context.Background())
+	if err != nil || published != 3 {
+		t.Fatalf("RelayPending() = %d, %v, want 3", published, err)
+	}
+	// Vocab 2 fails, its update is held back rather than published ahead of its creation
+	if want := []int{1, 3, 4}; !equalIDs(sink.published, want) {
+		t.Errorf("published = %v, want %v", sink.published, want)
+	}
+
+	if err = relay.recordLag(time.Now()); err != nil {
+		t.Fatalf("recordLag() error = %v", err)
+	}
+	if pendingCount.Value() != 2 || lagSeconds.Value() < 0 {
+		t.Errorf("pending = %d, lag = %v", pendingCount.Value(), lagSeconds.Value())
+	}
+
+	// Once the sink recovers the held events go out in order, the failed one first
+	sink.failing = nil
+	sink.published = nil
+	if published, err = relay.RelayPending(context.Background()); err != nil || published != 2 {
+		t.Fatalf("RelayPending() after recovery = %d, %v, want 2", published, err)
+	}
+	if want := []int{2, 5}; !equalIDs(sink.published, want) {
+		t.Errorf("published after recovery = %v, want %v", sink.published, want)
+	}
+
+	if err = relay.recordLag(time.Now()); err != nil || pendingCount.Value() != 0 || lagSeconds.Value() != 0 {
+		t.Errorf("recordLag() = %v, pending = %d, lag = %v", err, pendingCount.Value(), lagSeconds.Value())
+	}
+
+	// Published events are kept until they are old enough to purge
+	if purged, _ := repo.PurgePublished(time.Now().Add(-publishedRetention)); purged != 0 {
+		t.Errorf("PurgePublished() of recent events = %d", purged)
+	}
+	if purged, _ := repo.PurgePublished(time.Now().Add(time.Second)); purged != 5 {
+		t.Errorf("PurgePublished() = %d, want 5", purged)
+	}
+}
+
+func TestRelay_RelayPendingClaimed(t *testing.T) {
+	repo := mock.NewMockOutboxRepository(
+		mdl.NewOutboxEvent(mdl.AggregateVocab, 1, "created", `{"id":1}`),
+		mdl.NewOutboxEvent(mdl.AggregateVocab, 1, "updated", `{"id":1}`),
+	)
+	sink := &recordingSink{}
+	relay := &Relay{repo: repo, sink: sink}
+
+	// Another instance holds a claim, so nothing is published until it runs out
+	if claimed, err := repo.ClaimPending(time.Now(), relayLease, relayBatch); err != nil || len(*claimed) != 2 {
+		t.Fatalf("ClaimPending() = %v, %v, want 2", claimed, err)
+	}
+	if published, err := relay.RelayPending(context.Background()); err != nil || published != 0 {
+		t.Fatalf("RelayPending() while claimed = %d, %v, want 0", published, err)
+	}
+
+	// Once it runs out the events are claimed again, and released when their outcome is saved
+	claimed, _ := repo.ClaimPending(time.Now().Add(relayLease+time.Second), relayLease, relayBatch)
+	if len(*claimed) != 2 {
+		t.Fatalf("ClaimPending() once the claim ran out = %v, want 2", claimed)
+	}
+	if err := repo.UpdateClaimed(claimed); err != nil {
+		t.Fatalf("UpdateClaimed() error = %v", err)
+	}
+	if published, err := relay.RelayPending(context.Background()); err != nil || published != 2 {
+		t.Fatalf("RelayPending() once released = %d, %v, want 2", published, err)
+	}
+	if want := []int{1, 2}; !equalIDs(sink.published, want) {
+		t.Errorf("published = %v, want %v", sink.published, want)
+	}
+}
+
+func equalIDs(got []int, want []int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
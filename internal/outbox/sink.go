@@ -0,0 +1,149 @@
+// Package outbox relays the change events written to the outbox table to downstream systems.
+// The repositories write an event in the same transaction as each vocab, fixit and audit
+// change, and the Relay publishes the pending events to a Sink, such as a JSON lines file or
+// an HTTP endpoint. Delivery is at least once, consumers drop the events whose ID they have
+// seen, and the events of one record are published in the order they were saved.
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const httpSinkTimeout = 10 * time.Second
+
+// Message is the JSON an event is published as. The payload is the JSON of the changed record.
+type Message struct {
+	ID            int             `json:"id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   int             `json:"aggregate_id"`
+	EventType     string          `json:"event_type"`
+	Created       time.Time       `json:"created"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// NewMessage returns the message an event is published as. A payload that is not JSON is
+// published as a JSON string.
+func NewMessage(event *mdl.OutboxEvent) Message {
+	payload := json.RawMessage(event.Payload)
+	if !json.Valid(payload) {
+		payload, _ = json.Marshal(event.Payload)
+	}
+
+	return Message{
+		ID:            event.ID,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		EventType:     event.EventType,
+		Created:       event.Created.UTC(),
+		Payload:       payload,
+	}
+}
+
+// Sink is where the relay publishes events. Publish returns once the event is safely handed
+// over, an error leaves the event pending to be published again.
+type Sink interface {
+	Publish(ctx context.Context, event *mdl.OutboxEvent) error
+}
+
+// WriterSink publishes each event as a line of JSON to a writer, such as stdout or a file.
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink creates a WriterSink writing to w.
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// NewFileSink creates a WriterSink appending to the file at the path, creating it when missing.
+func NewFileSink(path string) (*WriterSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outbox file %s, error: %v", path, err)
+	}
+	return NewWriterSink(file), nil
+}
+
+// Publish writes the event as a line of JSON.
+func (s *WriterSink) Publish(_ context.Context, event *mdl.OutboxEvent) error {
+	line, err := json.Marshal(NewMessage(event))
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
+
+// HTTPSink publishes each event as a JSON POST to a URL. The event ID is sent in the
+// Idempotency-Key header so the endpoint can drop events it has already received.
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPSink creates an HTTPSink posting to the url.
+func NewHTTPSink(url string) *HTTPSink {
+	return &HTTPSink{url: url, client: &http.Client{Timeout: httpSinkTimeout}}
+}
+
+// Publish posts the event, it is published once the endpoint answers with a 2xx status.
+func (s *HTTPSink) Publish(ctx context.Context, event *mdl.OutboxEvent) error {
+	body, err := json.Marshal(NewMessage(event))
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Idempotency-Key", "outbox-"+strconv.Itoa(event.ID))
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("outbox sink answered with status %s", response.Status)
+	}
+	return nil
+}
+
+// SinkFromEnv creates the sink named by the OUTBOX_SINK env var: stdout, file:<path> for a
+// JSON lines file, or an http or https URL. It returns nil when the env var is not set and
+// the outbox is not relayed.
+func SinkFromEnv() (Sink, error) {
+	target := strings.TrimSpace(os.Getenv("OUTBOX_SINK"))
+
+	switch {
+	case len(target) == 0:
+		return nil, nil
+	case target == "stdout":
+		return NewWriterSink(os.Stdout), nil
+	case strings.HasPrefix(target, "file:"):
+		return NewFileSink(strings.TrimPrefix(target, "file:"))
+	case strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://"):
+		return NewHTTPSink(target), nil
+	default:
+		return nil, fmt.Errorf("unknown OUTBOX_SINK %s, expected stdout, file:<path> or an http URL", target)
+	}
+}
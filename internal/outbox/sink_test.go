@@ -0,0 +1,111 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("NewFileSink() error = %v", err)
+	}
+
+	events := []mdl.OutboxEvent{
+		mdl.NewOutboxEvent(mdl.AggregateVocab, 7, "created", `{"id":7,"learning_lang":"el gato"}`),
+		mdl.NewOutboxEvent(mdl.AggregateAudit, 9, "created", "not json"),
+	}
+	for i := range events {
+		events[i].ID = i + 1
+		if err = sink.Publish(context.Background(), &events[i]); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer file.Close()
+
+	var messages []Message
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var message Message
+		if err = json.Unmarshal(scanner.Bytes(), &message); err != nil {
+			t.Fatalf("line %s, error = %v", scanner.Text(), err)
+		}
+		messages = append(messages, message)
+	}
+
+	if len(messages) != 2 || messages[0].ID != 1 || messages[0].EventType != "vocab.created" ||
+		messages[0].AggregateID != 7 || string(messages[0].Payload) != `{"id":7,"learning_lang":"el gato"}` {
+		t.Fatalf("messages = %+v", messages)
+	}
+	if string(messages[1].Payload) != `"not json"` {
+		t.Errorf("payload that is not JSON = %s", messages[1].Payload)
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	status := http.StatusAccepted
+	var received Message
+	var idempotencyKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		idempotencyKey = r.Header.Get("Idempotency-Key")
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.URL)
+	event := mdl.NewOutboxEvent(mdl.AggregateFixit, 3, "updated", `{"id":3}`)
+	event.ID = 42
+
+	if err := sink.Publish(context.Background(), &event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if received.ID != 42 || received.EventType != "fixit.updated" || idempotencyKey != "outbox-42" {
+		t.Errorf("received %+v with key %s", received, idempotencyKey)
+	}
+
+	status = http.StatusBadGateway
+	if err := sink.Publish(context.Background(), &event); err == nil {
+		t.Errorf("Publish() expected an error for status %d", status)
+	}
+}
+
+func TestSinkFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		wantNil bool
+		wantErr bool
+	}{
+		{"Not relayed", "", true, false},
+		{"Stdout", "stdout", false, false},
+		{"File", "file:" + filepath.Join(t.TempDir(), "outbox.jsonl"), false, false},
+		{"HTTP", "https://events.example.com/verdure", false, false},
+		{"Unknown", "kafka://events", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OUTBOX_SINK", tt.target)
+			sink, err := SinkFromEnv()
+			if (err != nil) != tt.wantErr || (sink == nil) != tt.wantNil {
+				t.Errorf("SinkFromEnv() = %v, %v", sink, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package srv
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// ActorHeader names the request header holding the user making an admin API request, set by
+// the proxy authenticating the admin users in front of the server.
+const ActorHeader = "X-Verdure-User"
+
+// SystemActor records the changes made by the server itself and the command line tools, and by
+// the admin API requests that do not name their user.
+const SystemActor = "sys"
+
+// actorKey is the context key of the user making a request.
+type actorKey struct{}
+
+// WithActor returns a copy of the context naming the user making the request.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the user making the request named by the context, or SystemActor when it
+// names none.
+func ActorFrom(ctx context.Context) string {
+	if actor, _ := ctx.Value(actorKey{}).(string); len(actor) > 0 {
+		return actor
+	}
+	return SystemActor
+}
+
+// ActorHandler names the user of the ActorHeader in the context of each request to the next
+// handler, so the services record who made a change.
+//
+// Usage example:
+//
+//	http.Handle("/admin", srv.ActorHandler(gqlHandler))
+func ActorHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor := strings.TrimSpace(r.Header.Get(ActorHeader))
+		next.ServeHTTP(w, r.WithContext(WithActor(r.Context(), actor)))
+	})
+}
+
+// checkActor ensures the user making a change is named, for the changes that must be recorded
+// under the name of a user rather than SystemActor.
+func checkActor(actor string) error {
+	if len(actor) == 0 || actor == SystemActor {
+		return invalidField("actor", RuleRequired, 0, "the user making the change must be named by the %s header", ActorHeader)
+	}
+	return nil
+}
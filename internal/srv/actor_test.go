@@ -0,0 +1,42 @@
+package srv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestActorHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "Named", header: " ana ", want: "ana"},
+		{name: "Not named", header: "", want: SystemActor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := ActorHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = ActorFrom(r.Context())
+			}))
+
+			r := httptest.NewRequest(http.MethodPost, "/admin", nil)
+			if len(tt.header) > 0 {
+				r.Header.Set(ActorHeader, tt.header)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+
+			if got != tt.want {
+				t.Errorf("ActorFrom() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := ActorFrom(context.Background()); got != SystemActor {
+		t.Errorf("ActorFrom() = %q, want %q", got, SystemActor)
+	}
+}
@@ -0,0 +1,353 @@
+package srv
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"strings"
+)
+
+const (
+	// alternativesSeparator delimits the entries of the legacy Vocab.Alternatives copy.
+	alternativesSeparator  = ", "
+	maxAlternativeLen      = 40
+	maxAlternativeNotesLen = 255
+)
+
+// ParseAlternatives splits the legacy delimited alternatives text into its entries. Entries
+// may be separated by commas or semicolons, surrounding whitespace is trimmed, and empty or
+// repeated entries are dropped.
+//
+// Parameters:
+// - text: The delimited alternatives text, as stored in Vocab.Alternatives.
+//
+// Returns:
+// - The alternatives in the order they appear.
+//
+// Usage example:
+// alternatives := ParseAlternatives("comenzar; iniciar, comenzar") // ["comenzar", "iniciar"]
+func ParseAlternatives(text string) (alternatives []string) {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ';'
+	})
+
+	for _, field := range fields {
+		alternative := strings.TrimSpace(field)
+		if len(alternative) == 0 || containsFold(alternatives, alternative) {
+			continue
+		}
+		alternatives = append(alternatives, alternative)
+	}
+
+	return
+}
+
+// JoinAlternatives builds the legacy delimited alternatives text from a list of alternatives.
+func JoinAlternatives(list []mdl.VocabAlternative) string {
+	texts := make([]string, len(list))
+	for i, alternative := range list {
+		texts[i] = alternative.Alternative
+	}
+	return strings.Join(texts, alternativesSeparator)
+}
+
+// AddAlternative adds an alternative answer to an existing Vocab record. The alternative
+// must not repeat the learning lang or another alternative of the same vocab. It is placed
+// after the existing alternatives, the legacy delimited copy is refreshed, and an audit
+// entry is written for the vocab.
+//
+// Parameters:
+// - vocabID: The primary ID of the Vocab record.
+// - alternative: The alternative answer text.
+// - notes: Optional notes explaining when the alternative applies.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the updated mdl.Vocab record including its alternatives.
+// - An error if the vocab cannot be found, the alternative is invalid or a duplicate, or saving fails.
+//
+// Usage example:
+// vocab, err := vocabService.AddAlternative(123, "comenzar", "more formal", srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to add alternative: %v", err)
+//	}
+func (s *VocabService) AddAlternative(vocabID int, alternative string, notes string, actor string) (vocab *mdl.Vocab, err error) {
+
+	before, err := s.FindVocabByID(vocabID)
+	if err != nil {
+		return
+	}
+
+	alternative = NormalizeLearningText(alternative, before.LearningLangCode)
+	notes = NormalizeText(notes)
+
+	vocab = before.Clone()
+	if err = addAlternative(vocab, alternative, notes, actor); err != nil {
+		return nil, err
+	}
+
+	err = s.saveVocabAlternatives(before, vocab, fmt.Sprintf("added alternative %s", alternative), actor)
+	if err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// RemoveAlternative removes an alternative answer from an existing Vocab record, refreshes
+// the legacy delimited copy, and writes an audit entry for the vocab.
+//
+// Parameters:
+// - vocabID: The primary ID of the Vocab record.
+// - alternative: The alternative answer text to remove.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the updated mdl.Vocab record including its remaining alternatives.
+// - An error if the vocab cannot be found, it has no such alternative, or saving fails.
+//
+// Usage example:
+// vocab, err := vocabService.RemoveAlternative(123, "comenzar", srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to remove alternative: %v", err)
+//	}
+func (s *VocabService) RemoveAlternative(vocabID int, alternative string, actor string) (vocab *mdl.Vocab, err error) {
+
+	before, err := s.FindVocabByID(vocabID)
+	if err != nil {
+		return
+	}
+
+	alternative = NormalizeLearningText(alternative, before.LearningLangCode)
+
+	vocab = before.Clone()
+	if !removeAlternative(vocab, alternative) {
+		return nil, fmt.Errorf("vocab %d has no alternative %s", vocabID, alternative)
+	}
+
+	err = s.saveVocabAlternatives(before, vocab, fmt.Sprintf("removed alternative %s", alternative), actor)
+	if err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// MigrateAlternatives moves the alternatives still only held in the legacy delimited
+// Vocab.Alternatives text into their own records. Vocab that already have alternative
+// records are left alone, so the migration can safely be run more than once.
+//
+// Returns:
+// - The number of alternative records created.
+// - An error if the scan fails or an alternative cannot be saved.
+//
+// Usage example:
+// migrated, err := vocabService.MigrateAlternatives()
+//
+//	if err != nil {
+//	    log.Printf("Failed to migrate alternatives: %v", err)
+//	}
+func (s *VocabService) MigrateAlternatives() (migrated int, err error) {
+
+	err = s.repo.ScanVocabs("", scanBatchSize, func(batch *[]mdl.Vocab) error {
+		vocabs := make([]*mdl.Vocab, len(*batch))
+		for i := range *batch {
+			vocabs[i] = &(*batch)[i]
+		}
+
+		if err := s.attachAlternatives(vocabs...); err != nil {
+			return err
+		}
+
+		for _, vocab := range vocabs {
+			for i := range vocab.AlternativeList {
+				alternative := &vocab.AlternativeList[i]
+				if alternative.ID != 0 {
+					continue
+				}
+				alternative.VocabID = vocab.ID
+				alternative.Position = i
+				if err := s.altRepo.CreateAlternative(alternative); err != nil {
+					return err
+				}
+				migrated++
+			}
+		}
+		return nil
+	})
+
+	return
+}
+
+// saveVocabAlternatives validates and persists a vocab whose alternatives changed along with
+// its alternative records and its audit entry.
+func (s *VocabService) saveVocabAlternatives(before *mdl.Vocab, vocab *mdl.Vocab, comments string, actor string) (err error) {
+
+	if err = validateAlternatives(vocab); err != nil {
+		return
+	}
+	if err = validateVocabUpdate(vocab); err != nil {
+		return
+	}
+
+	var audits auditTrail
+	err = s.repo.UpdateVocabAlternatives(vocab, removedAlternatives(before, vocab), audits.vocab(comments, actor, before, vocab))
+	if err != nil {
+		return
+	}
+
+	audits.publish()
+	publishVocabChanged(vocab)
+	return
+}
+
+// attachAlternatives loads the alternative records of the given vocabs into their
+// AlternativeList. Vocab without any records, whose alternatives only exist in the legacy
+// delimited text, get unsaved entries parsed from that text so nothing is lost when the
+// list is next saved.
+func (s *VocabService) attachAlternatives(vocabs ...*mdl.Vocab) error {
+	ids := make([]int, len(vocabs))
+	for i, vocab := range vocabs {
+		ids[i] = vocab.ID
+	}
+
+	found, err := s.altRepo.FindAlternatives(ids)
+	if err != nil {
+		return err
+	}
+
+	byVocab := make(map[int][]mdl.VocabAlternative)
+	for _, alternative := range *found {
+		byVocab[alternative.VocabID] = append(byVocab[alternative.VocabID], alternative)
+	}
+
+	for _, vocab := range vocabs {
+		vocab.AlternativeList = byVocab[vocab.ID]
+		if len(vocab.AlternativeList) == 0 {
+			vocab.AlternativeList = legacyAlternatives(vocab)
+		}
+	}
+
+	return nil
+}
+
+// removedAlternatives returns the IDs of the alternative records of the before vocab that are
+// no longer in the AlternativeList of the after vocab.
+func removedAlternatives(before *mdl.Vocab, after *mdl.Vocab) (ids []int) {
+	for _, alternative := range before.AlternativeList {
+		if alternative.ID != 0 && indexOfAlternativeID(after.AlternativeList, alternative.ID) < 0 {
+			ids = append(ids, alternative.ID)
+		}
+	}
+	return
+}
+
+// indexOfAlternativeID returns the index of the alternative record in the list, or -1.
+func indexOfAlternativeID(list []mdl.VocabAlternative, id int) int {
+	for i, alternative := range list {
+		if alternative.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// legacyAlternatives builds unsaved alternative entries from the legacy delimited text,
+// skipping any entry that repeats the learning lang.
+func legacyAlternatives(vocab *mdl.Vocab) (list []mdl.VocabAlternative) {
+	for _, text := range ParseAlternatives(vocab.Alternatives) {
+		if strings.EqualFold(text, vocab.LearningLang) {
+			continue
+		}
+		list = append(list, mdl.VocabAlternative{
+			VocabID:     vocab.ID,
+			Alternative: text,
+			Position:    len(list),
+			CreatedBy:   SystemActor,
+		})
+	}
+	return
+}
+
+// addAlternative appends an alternative to the vocab list and refreshes the legacy delimited copy.
+func addAlternative(vocab *mdl.Vocab, alternative string, notes string, createdBy string) error {
+	alternative = strings.TrimSpace(alternative)
+	if strings.EqualFold(alternative, vocab.LearningLang) {
+		return invalidField("alternative", RuleUnique, 0, "alternative %s repeats the learning lang", alternative)
+	}
+	if indexOfAlternative(vocab.AlternativeList, alternative) >= 0 {
+		return invalidField("alternative", RuleUnique, 0, "vocab %d already has alternative %s", vocab.ID, alternative)
+	}
+
+	vocab.AlternativeList = append(vocab.AlternativeList, mdl.VocabAlternative{
+		VocabID:     vocab.ID,
+		Alternative: alternative,
+		Notes:       notes,
+		Position:    len(vocab.AlternativeList),
+		CreatedBy:   createdBy,
+	})
+	vocab.Alternatives = JoinAlternatives(vocab.AlternativeList)
+
+	return nil
+}
+
+// removeAlternative drops an alternative from the vocab list and refreshes the legacy
+// delimited copy. It reports whether the alternative was found.
+func removeAlternative(vocab *mdl.Vocab, alternative string) bool {
+	index := indexOfAlternative(vocab.AlternativeList, strings.TrimSpace(alternative))
+	if index < 0 {
+		return false
+	}
+
+	list := append([]mdl.VocabAlternative(nil), vocab.AlternativeList[:index]...)
+	vocab.AlternativeList = append(list, vocab.AlternativeList[index+1:]...)
+	vocab.Alternatives = JoinAlternatives(vocab.AlternativeList)
+
+	return true
+}
+
+// indexOfAlternative returns the index of the alternative in the list, ignoring case, or -1.
+func indexOfAlternative(list []mdl.VocabAlternative, alternative string) int {
+	for i, existing := range list {
+		if strings.EqualFold(existing.Alternative, alternative) {
+			return i
+		}
+	}
+	return -1
+}
+
+// containsFold reports whether the text is in the list, ignoring case.
+func containsFold(list []string, text string) bool {
+	for _, existing := range list {
+		if strings.EqualFold(existing, text) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateAlternatives checks each alternative of a vocab for length and restricted characters,
+// and ensures none is empty, repeats the learning lang, or repeats another alternative. Every
+// failure is reported at the path of its alternative, e.g. "alternatives[1]", see ValidationError.
+func validateAlternatives(vocab *mdl.Vocab) error {
+	errs := &ValidationError{}
+
+	for i, alternative := range vocab.AlternativeList {
+		path := fmt.Sprintf("alternatives[%d]", i)
+		if len(strings.TrimSpace(alternative.Alternative)) == 0 {
+			errs.add(path, invalidField("", RuleRequired, 0, "alternative text is required"))
+			continue
+		}
+		errs.add(path, validateFieldContent(alternative.Alternative, "Alternative", maxAlternativeLen))
+		errs.add(path+".notes", validateFieldContent(alternative.Notes, "Alternative notes", maxAlternativeNotesLen))
+		if strings.EqualFold(alternative.Alternative, vocab.LearningLang) {
+			errs.add(path, invalidField("", RuleUnique, 0, "alternative %s repeats the learning lang", alternative.Alternative))
+		}
+		if indexOfAlternative(vocab.AlternativeList[:i], alternative.Alternative) >= 0 {
+			errs.add(path, invalidField("", RuleUnique, 0, "vocab %d already has alternative %s", vocab.ID, alternative.Alternative))
+		}
+	}
+
+	return errs.errOrNil()
+}
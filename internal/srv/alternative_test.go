@@ -0,0 +1,120 @@
+package srv
+
+import (
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"reflect"
+	"testing"
+)
+
+func TestParseAlternatives(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "Empty", text: "", want: nil},
+		{name: "Single", text: "comenzar", want: []string{"comenzar"}},
+		{name: "Comma and semicolon", text: "comenzar, iniciar;empezar ", want: []string{"comenzar", "iniciar", "empezar"}},
+		{name: "Duplicates and blanks", text: "comenzar,, Comenzar ;", want: []string{"comenzar"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseAlternatives(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAlternatives() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVocabService_AddRemoveAlternative(t *testing.T) {
+	vocabService := createMockVocabService()
+
+	vocab := &mdl.Vocab{
+		LearningLang:     "empezar",
+		FirstLang:        "to begin",
+		Alternatives:     "comenzar",
+		LearningLangCode: "es",
+		KnownLangCode:    "en",
+	}
+	if err := vocabService.CreateVocab(vocab, false, "sys"); err != nil {
+		t.Fatalf("Unexpected error on create: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		add         bool
+		alternative string
+		want        []string
+		wantErr     bool
+		errMsg      string
+	}{
+		{name: "Add alternative", add: true, alternative: "iniciar", want: []string{"comenzar", "iniciar"}},
+		{name: "Add duplicate alternative", add: true, alternative: "Comenzar", wantErr: true, errMsg: "vocab 1 already has alternative Comenzar"},
+		{name: "Add learning lang", add: true, alternative: "empezar", wantErr: true, errMsg: "alternative empezar repeats the learning lang"},
+		{name: "Remove alternative", add: false, alternative: "comenzar", want: []string{"iniciar"}},
+		{name: "Remove missing alternative", add: false, alternative: "comenzar", wantErr: true, errMsg: "vocab 1 has no alternative comenzar"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *mdl.Vocab
+			var err error
+			if tt.add {
+				updated, err = vocabService.AddAlternative(vocab.ID, tt.alternative, "", "sys")
+			} else {
+				updated, err = vocabService.RemoveAlternative(vocab.ID, tt.alternative, "sys")
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil {
+				if err.Error() != tt.errMsg {
+					t.Errorf("error = %v, wantErrMsg %v", err, tt.errMsg)
+				}
+				return
+			}
+
+			found, _ := vocabService.FindVocabByID(vocab.ID)
+			for _, v := range []*mdl.Vocab{updated, found} {
+				var got []string
+				for i, alternative := range v.AlternativeList {
+					got = append(got, alternative.Alternative)
+					if alternative.Position != i {
+						t.Errorf("alternative %s position = %d, want %d", alternative.Alternative, alternative.Position, i)
+					}
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("alternatives = %q, want %q", got, tt.want)
+				}
+				if v.Alternatives != JoinAlternatives(v.AlternativeList) {
+					t.Errorf("legacy alternatives = %q, want %q", v.Alternatives, JoinAlternatives(v.AlternativeList))
+				}
+			}
+		})
+	}
+}
+
+func TestVocabService_MigrateAlternatives(t *testing.T) {
+	vocabService := createMockVocabService()
+
+	// Seed legacy records directly in the repository, bypassing the service.
+	_ = vocabService.repo.CreateVocab(&mdl.Vocab{LearningLang: "empezar", Alternatives: "comenzar; iniciar", LearningLangCode: "es"}, nil)
+	_ = vocabService.repo.CreateVocab(&mdl.Vocab{LearningLang: "perro", Alternatives: "perro", LearningLangCode: "es"}, nil)
+
+	migrated, err := vocabService.MigrateAlternatives()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if migrated != 2 {
+		t.Errorf("MigrateAlternatives() migrated = %d, want 2", migrated)
+	}
+
+	migrated, err = vocabService.MigrateAlternatives()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if migrated != 0 {
+		t.Errorf("MigrateAlternatives() second run migrated = %d, want 0", migrated)
+	}
+}
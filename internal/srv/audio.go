@@ -0,0 +1,320 @@
+package srv
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"github.com/heather92115/verdure-admin/internal/media"
+	"io"
+	"path/filepath"
+	"time"
+)
+
+const (
+	maxAudioSize     = 2 << 20
+	maxAudioDuration = 20 * time.Second
+	minAudioDuration = 100 * time.Millisecond
+	maxFilenameLen   = 255
+)
+
+// AudioService handles business logic for the audio recordings attached to Vocab records.
+type AudioService struct {
+	repo         db.AudioRepository
+	storage      media.Storage
+	auditService AuditService
+}
+
+// NewAudioService creates a new instance of AudioService, storing recordings in the media
+// storage named by the MEDIA_ROOT env var.
+func NewAudioService() (*AudioService, error) {
+
+	repo, err := db.NewSqlAudioRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	storage, err := media.StorageFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := NewAuditService()
+	if err != nil {
+		return nil, err
+	}
+
+	return &AudioService{repo: repo, storage: storage, auditService: *auditService}, nil
+}
+
+// AudioCleanup lists what CleanupOrphanAudio found, and removed when applied.
+//
+// Fields:
+//   - Assets: The audio assets no vocab refers to.
+//   - StrayKeys: The keys of stored recordings without an audio asset, left by failed uploads.
+type AudioCleanup struct {
+	Assets    []mdl.AudioAsset
+	StrayKeys []string
+}
+
+// FindAudioAssetByID retrieves an audio asset by its primary ID.
+func (s *AudioService) FindAudioAssetByID(id int) (*mdl.AudioAsset, error) {
+	return s.repo.FindAudioAssetByID(id)
+}
+
+// UploadAudio validates and stores an audio recording. The recording must be an ogg, mp3 or
+// wav file, matching the declared content type, of at most 2 MB and between 0.1 and 20
+// seconds long. A recording already stored is not stored again, its asset is returned.
+// New assets are audited.
+//
+// Parameters:
+// - content: The recording.
+// - filename: The name of the uploaded file, kept for reference.
+// - contentType: The content type declared by the client, empty when unknown.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the mdl.AudioAsset of the recording.
+// - An error if the recording fails validation or cannot be stored.
+//
+// Usage example:
+// asset, err := audioService.UploadAudio(file, "perro.ogg", "audio/ogg", srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to upload audio: %v", err)
+//	}
+func (s *AudioService) UploadAudio(content io.Reader, filename string, contentType string, actor string) (*mdl.AudioAsset, error) {
+
+	filename = NormalizeText(filepath.Base(filename))
+	if err := validateFieldContent(filename, "Filename", maxFilenameLen); err != nil {
+		errs := &ValidationError{}
+		errs.add("file", err)
+		return nil, errs
+	}
+
+	data, err := io.ReadAll(io.LimitReader(content, maxAudioSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio, error: %v", err)
+	}
+	if len(data) == 0 {
+		return nil, invalidField("file", RuleRequired, 0, "audio file is empty")
+	}
+	if len(data) > maxAudioSize {
+		return nil, invalidField("file", RuleMaxLength, maxAudioSize, "audio must be at most %d bytes", maxAudioSize)
+	}
+
+	info, err := media.Inspect(data)
+	if err != nil {
+		return nil, err
+	}
+	if !media.MatchesMimeType(contentType, info.MimeType) {
+		return nil, invalidField("file", RuleFormat, 0, "audio declared as %s is %s", contentType, info.MimeType)
+	}
+	if info.Duration < minAudioDuration || info.Duration > maxAudioDuration {
+		return nil, invalidField("file", RuleInvalid, 0, "audio must be between %v and %v long, not %v", minAudioDuration,
+			maxAudioDuration, info.Duration.Round(time.Millisecond))
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	if existing, err := s.repo.FindAudioAssetByHash(hash); err == nil {
+		return existing, nil
+	}
+
+	object, err := s.storage.Put(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	asset := &mdl.AudioAsset{
+		Hash:       object.Key,
+		MimeType:   info.MimeType,
+		Size:       object.Size,
+		DurationMs: int(info.Duration.Milliseconds()),
+		Filename:   filename,
+		CreatedBy:  actor,
+	}
+	if err = s.repo.CreateAudioAsset(asset); err != nil {
+		return nil, err
+	}
+
+	err = s.auditService.CreateAudit("audio_asset", asset.ID, "uploaded audio", actor, "", asset.JSON())
+	if err != nil {
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+// CleanupOrphanAudio finds the audio assets no vocab refers to, and the stored recordings
+// without an asset, that are older than the grace period. When applied, both are removed
+// from storage and the removed assets are audited. An asset attached while the cleanup runs
+// is kept, and left out of the assets returned.
+//
+// Parameters:
+// - grace: How long a recording is kept before it counts as an orphan, so uploads waiting to be attached are kept.
+// - apply: When set the orphans are removed, otherwise they are only reported.
+//
+// Returns:
+// - The orphans found.
+// - An error if the orphans cannot be listed or removed.
+//
+// Usage example:
+// cleanup, err := audioService.CleanupOrphanAudio(24*time.Hour, true)
+//
+//	if err != nil {
+//	    log.Printf("Failed to clean up audio: %v", err)
+//	}
+func (s *AudioService) CleanupOrphanAudio(grace time.Duration, apply bool) (*AudioCleanup, error) {
+
+	cutoff := time.Now().Add(-grace)
+
+	orphans, err := s.repo.FindOrphanAudioAssets(cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	assets, err := s.repo.FindAudioAssets()
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool)
+	for _, asset := range *assets {
+		known[asset.Hash] = true
+	}
+
+	objects, err := s.storage.List()
+	if err != nil {
+		return nil, err
+	}
+
+	cleanup := &AudioCleanup{Assets: *orphans}
+	for _, object := range objects {
+		if !known[object.Key] && object.Modified.Before(cutoff) {
+			cleanup.StrayKeys = append(cleanup.StrayKeys, object.Key)
+		}
+	}
+
+	if !apply {
+		return cleanup, nil
+	}
+
+	// An asset attached since it was found is not deleted, and keeps its recording.
+	var deleted []mdl.AudioAsset
+	for _, asset := range cleanup.Assets {
+		removed, err := s.repo.DeleteOrphanAudioAsset(asset.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !removed {
+			continue
+		}
+		deleted = append(deleted, asset)
+
+		if err = s.storage.Delete(asset.Hash); err != nil {
+			return nil, err
+		}
+		if err = s.auditService.CreateAudit("audio_asset", asset.ID, "deleted orphan audio", SystemActor, asset.JSON(), ""); err != nil {
+			return nil, err
+		}
+	}
+	cleanup.Assets = deleted
+
+	for _, key := range cleanup.StrayKeys {
+		if err = s.storage.Delete(key); err != nil {
+			return nil, err
+		}
+	}
+
+	return cleanup, nil
+}
+
+// AttachAudio sets the pronunciation recording of a vocab, replacing any it had, and writes
+// an audit entry. A replaced recording is left for CleanupOrphanAudio.
+//
+// Parameters:
+// - vocabID: The primary ID of the Vocab record.
+// - audioID: The primary ID of the AudioAsset to attach.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the updated mdl.Vocab record.
+// - An error if the vocab or audio cannot be found, the audio is already attached, or saving fails.
+//
+// Usage example:
+// vocab, err := vocabService.AttachAudio(123, 7, srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to attach audio: %v", err)
+//	}
+func (s *VocabService) AttachAudio(vocabID int, audioID int, actor string) (*mdl.Vocab, error) {
+
+	vocab, err := s.FindVocabByID(vocabID)
+	if err != nil {
+		return nil, err
+	}
+
+	asset, err := s.audioRepo.FindAudioAssetByID(audioID)
+	if err != nil {
+		return nil, err
+	}
+
+	if vocab.AudioID != nil && *vocab.AudioID == asset.ID {
+		return nil, fmt.Errorf("audio %d is already attached to vocab %d", asset.ID, vocab.ID)
+	}
+
+	before := vocab.Clone()
+	vocab.AudioID = &asset.ID
+
+	comments := fmt.Sprintf("attached audio %d", asset.ID)
+	if before.AudioID != nil {
+		comments = fmt.Sprintf("replaced audio %d with audio %d", *before.AudioID, asset.ID)
+	}
+
+	var audits auditTrail
+	if err = s.repo.UpdateVocab(vocab, audits.vocab(comments, actor, before, vocab)); err != nil {
+		return nil, err
+	}
+
+	audits.publish()
+	publishVocabChanged(vocab)
+	return vocab, nil
+}
+
+// DetachAudio removes the pronunciation recording of a vocab and writes an audit entry. The
+// recording is left for CleanupOrphanAudio.
+//
+// Parameters:
+// - vocabID: The primary ID of the Vocab record.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the updated mdl.Vocab record.
+// - An error if the vocab cannot be found, has no audio, or saving fails.
+func (s *VocabService) DetachAudio(vocabID int, actor string) (*mdl.Vocab, error) {
+
+	vocab, err := s.FindVocabByID(vocabID)
+	if err != nil {
+		return nil, err
+	}
+
+	if vocab.AudioID == nil {
+		return nil, fmt.Errorf("vocab %d has no audio", vocab.ID)
+	}
+
+	before := vocab.Clone()
+	vocab.AudioID = nil
+
+	comments := fmt.Sprintf("detached audio %d", *before.AudioID)
+
+	var audits auditTrail
+	if err = s.repo.UpdateVocab(vocab, audits.vocab(comments, actor, before, vocab)); err != nil {
+		return nil, err
+	}
+
+	audits.publish()
+	publishVocabChanged(vocab)
+	return vocab, nil
+}
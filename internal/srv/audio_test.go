@@ -0,0 +1,162 @@
+package srv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"github.com/heather92115/verdure-admin/internal/media"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAudioService_UploadAudio(t *testing.T) {
+	audioService, _ := createMockAudioService(t)
+
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		wantID      int
+		wantErr     bool
+		errMsg      string
+	}{
+		{name: "Wav", data: testWavAudio(1000), contentType: "audio/wav", wantID: 1},
+		{name: "Same recording", data: testWavAudio(1000), contentType: "audio/x-wav", wantID: 1},
+		{name: "Another recording", data: testWavAudio(1500), wantID: 2},
+		{name: "Empty", data: nil, wantErr: true, errMsg: "audio file is empty"},
+		{name: "Not audio", data: []byte("hello"), wantErr: true, errMsg: "audio must be an ogg, mp3 or wav file"},
+		{name: "Declared as another type", data: testWavAudio(1000), contentType: "audio/ogg", wantErr: true,
+			errMsg: "audio declared as audio/ogg is audio/wav"},
+		{name: "Too short", data: testWavAudio(50), wantErr: true, errMsg: "audio must be between 100ms and 20s long, not 50ms"},
+		{name: "Too long", data: testWavAudio(25000), wantErr: true, errMsg: "audio must be at most 2097152 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asset, err := audioService.UploadAudio(bytes.NewReader(tt.data), "dir/perro.wav", tt.contentType, "sys")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UploadAudio() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil && err.Error() != tt.errMsg {
+				t.Errorf("UploadAudio() error = %v, wantErrMsg %v", err, tt.errMsg)
+			} else if err == nil && (asset.ID != tt.wantID || asset.MimeType != media.MimeWav || asset.Filename != "perro.wav") {
+				t.Errorf("UploadAudio() = %+v", asset)
+			}
+		})
+	}
+
+	audits, _ := audioService.auditService.FindAudits("audio_asset", 1, nil, 0)
+	if len(*audits) != 1 {
+		t.Errorf("Expected 1 upload audit, got %d", len(*audits))
+	}
+}
+
+func TestVocabService_AttachAudio(t *testing.T) {
+	audioService, vocabService := createMockAudioService(t)
+
+	_ = vocabService.repo.CreateVocab(&mdl.Vocab{LearningLang: "perro", FirstLang: "dog", LearningLangCode: "es", KnownLangCode: "en"}, nil)
+	_, _ = audioService.UploadAudio(bytes.NewReader(testWavAudio(1000)), "perro.wav", "", "sys")
+	_, _ = audioService.UploadAudio(bytes.NewReader(testWavAudio(1200)), "perro2.wav", "", "sys")
+
+	if _, err := vocabService.DetachAudio(1, "sys"); err == nil || err.Error() != "vocab 1 has no audio" {
+		t.Errorf("DetachAudio() error = %v", err)
+	}
+	if _, err := vocabService.AttachAudio(1, 9, "sys"); err == nil {
+		t.Errorf("AttachAudio() expected an error for unknown audio")
+	}
+
+	vocab, err := vocabService.AttachAudio(1, 1, "sys")
+	if err != nil || vocab.AudioID == nil || *vocab.AudioID != 1 {
+		t.Fatalf("AttachAudio() = %+v, %v", vocab, err)
+	}
+	if _, err = vocabService.AttachAudio(1, 1, "sys"); err == nil {
+		t.Errorf("AttachAudio() expected an error for audio already attached")
+	}
+	if _, err = vocabService.AttachAudio(1, 2, "sys"); err != nil {
+		t.Fatalf("AttachAudio() error = %v", err)
+	}
+
+	// The replaced recording is an orphan, the attached one is kept.
+	cleanup, err := audioService.CleanupOrphanAudio(-time.Minute, true)
+	if err != nil || len(cleanup.Assets) != 1 || cleanup.Assets[0].ID != 1 {
+		t.Errorf("CleanupOrphanAudio() = %+v, %v", cleanup, err)
+	}
+	if _, err = audioService.FindAudioAssetByID(1); err == nil {
+		t.Errorf("CleanupOrphanAudio() kept the orphan audio")
+	}
+
+	vocab, err = vocabService.DetachAudio(1, "sys")
+	if err != nil || vocab.AudioID != nil {
+		t.Fatalf("DetachAudio() = %+v, %v", vocab, err)
+	}
+
+	audits, _ := vocabService.auditService.FindAudits("vocab", 1, nil, 0)
+	sort.Slice(*audits, func(i, j int) bool { return (*audits)[i].ID < (*audits)[j].ID })
+	var comments []string
+	for _, audit := range *audits {
+		comments = append(comments, audit.Comments)
+	}
+	if strings.Join(comments, ", ") != "attached audio 1, replaced audio 1 with audio 2, detached audio 2" {
+		t.Errorf("Vocab audits = %v", comments)
+	}
+}
+
+func TestAudioService_CleanupOrphanAudio(t *testing.T) {
+	audioService, _ := createMockAudioService(t)
+
+	_, _ = audioService.UploadAudio(bytes.NewReader(testWavAudio(1000)), "perro.wav", "", "sys")
+	stray, _ := audioService.storage.Put(bytes.NewReader(testWavAudio(300)))
+
+	kept, err := audioService.CleanupOrphanAudio(time.Hour, true)
+	if err != nil || len(kept.Assets) != 0 || len(kept.StrayKeys) != 0 {
+		t.Errorf("CleanupOrphanAudio() within the grace period = %+v, %v", kept, err)
+	}
+
+	found, err := audioService.CleanupOrphanAudio(-time.Minute, false)
+	if err != nil || len(found.Assets) != 1 || len(found.StrayKeys) != 1 || found.StrayKeys[0] != stray.Key {
+		t.Fatalf("CleanupOrphanAudio() = %+v, %v", found, err)
+	}
+
+	if _, err = audioService.CleanupOrphanAudio(-time.Minute, true); err != nil {
+		t.Fatalf("CleanupOrphanAudio() error = %v", err)
+	}
+	objects, _ := audioService.storage.List()
+	if len(objects) != 0 {
+		t.Errorf("CleanupOrphanAudio() left %d stored files", len(objects))
+	}
+}
+
+func createMockAudioService(t *testing.T) (AudioService, VocabService) {
+	storage, err := media.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStorage() error = %v", err)
+	}
+
+	vocabService := createMockVocabService()
+	audioService := AudioService{
+		repo:         vocabService.audioRepo,
+		storage:      storage,
+		auditService: vocabService.auditService,
+	}
+
+	return audioService, vocabService
+}
+
+// testWavAudio builds a 16 bit mono 44.1 kHz wav recording of silence.
+func testWavAudio(ms int) []byte {
+	rate := uint32(44100)
+	size := uint32(ms) * rate * 2 / 1000
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	_ = binary.Write(&b, binary.LittleEndian, 36+size)
+	b.WriteString("WAVEfmt ")
+	for _, field := range []any{uint32(16), uint16(1), uint16(1), rate, rate * 2, uint16(2), uint16(16)} {
+		_ = binary.Write(&b, binary.LittleEndian, field)
+	}
+	b.WriteString("data")
+	_ = binary.Write(&b, binary.LittleEndian, size)
+	b.Write(make([]byte, size))
+	return b.Bytes()
+}
@@ -8,6 +8,9 @@ import (
 	"sort"
 )
 
+// maxAuditFieldLen is the longest key path the changed field filter of the audit queries accepts.
+const maxAuditFieldLen = 100
+
 // AuditService handles business logic for Audit entities.
 type AuditService struct {
 	repo db.AuditRepository
@@ -88,7 +91,52 @@ func (s *AuditService) FindAuditByID(id int) (*mdl.Audit, error) {
 //	    }
 //	}
 func (s *AuditService) FindAudits(tableName string, objectId int, duration *mdl.Duration, limit int) (Audits *[]mdl.Audit, err error) {
-	return s.repo.FindAudits(tableName, objectId, duration, limit)
+	return s.repo.FindAudits(&mdl.AuditFilter{TableName: tableName, ObjectID: objectId, Duration: duration}, limit)
+}
+
+// FindAuditsMatching retrieves the audits matching a filter, newest first, answering questions
+// such as every change by a user, every change to the hint in the last week, or every create.
+//
+// Parameters:
+//   - filter: A pointer to a mdl.AuditFilter, each zero valued field matches every audit. The
+//     action must be one of mdl.AuditCreated, mdl.AuditUpdated, mdl.AuditDeleted or
+//     mdl.AuditReverted, and the changed field a key of the audited JSON, e.g. "hint".
+//   - limit: The maximum number of audits returned, from 1 to 500.
+//
+// Returns:
+//   - A pointer to the slice of audits found.
+//   - An error if the filter or limit is invalid or the query fails.
+//
+// Example usage:
+// week := mdl.NewDuration(time.Now().AddDate(0, 0, -7), time.Now())
+// audits, err := auditService.FindAuditsMatching(&mdl.AuditFilter{TableName: "vocab", Duration: week, ChangedField: "hint"}, 100)
+//
+//	if err != nil {
+//	    log.Printf("Error retrieving audits: %v", err)
+//	}
+func (s *AuditService) FindAuditsMatching(filter *mdl.AuditFilter, limit int) (*[]mdl.Audit, error) {
+
+	errs := &ValidationError{}
+	switch filter.Action {
+	case "", mdl.AuditCreated, mdl.AuditUpdated, mdl.AuditDeleted, mdl.AuditReverted:
+	default:
+		errs.add("action", invalidField("", RuleOneOf, 0, "action %s must be one of %s, %s, %s, %s", filter.Action,
+			mdl.AuditCreated, mdl.AuditUpdated, mdl.AuditDeleted, mdl.AuditReverted))
+	}
+	if filter.ObjectID > 0 && len(filter.TableName) == 0 {
+		errs.add("object_id", invalidField("", RuleRequired, 0, "object id %d requires a table name", filter.ObjectID))
+	}
+	if err := validateFieldContent(filter.ChangedField, "changed_field", maxAuditFieldLen); err != nil {
+		errs.add("changed_field", err)
+	}
+	if limit < 1 || limit > 500 {
+		errs.add("limit", invalidField("", RuleInvalid, 500, "limit %d must be from 1 to 500", limit))
+	}
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindAudits(filter, limit)
 }
 
 // CreateVocabAudit records an audit trail for vocabulary modifications. This function
@@ -119,14 +167,23 @@ func (s *AuditService) FindAudits(tableName string, objectId int, duration *mdl.
 //	}
 func (s *AuditService) CreateVocabAudit(comments string, createdBy string, before *mdl.Vocab, after *mdl.Vocab) (err error) {
 
-	if after == nil {
-		err = fmt.Errorf("after value for vocab is required")
+	audit, err := buildVocabAudit(comments, createdBy, before, after)
+	if err != nil {
 		return
 	}
 
+	return s.saveAudit(audit)
+}
+
+// buildVocabAudit builds the audit of a vocab change without saving it, see CreateVocabAudit.
+func buildVocabAudit(comments string, createdBy string, before *mdl.Vocab, after *mdl.Vocab) (*mdl.Audit, error) {
+
+	if after == nil {
+		return nil, fmt.Errorf("after value for vocab is required")
+	}
+
 	if before != nil && before.ID != after.ID {
-		err = fmt.Errorf("audit before id %d and after id %d mismatch", before.ID, after.ID)
-		return
+		return nil, fmt.Errorf("audit before id %d and after id %d mismatch", before.ID, after.ID)
 	}
 	afterJson := after.JSON()
 
@@ -135,8 +192,42 @@ func (s *AuditService) CreateVocabAudit(comments string, createdBy string, befor
 		beforeJson = before.JSON()
 	}
 
-	err = s.CreateAudit("vocab", after.ID, comments, createdBy, beforeJson, afterJson)
-	return
+	return buildAudit("vocab", after.ID, comments, createdBy, beforeJson, afterJson)
+}
+
+// auditTrail collects the audits a repository builds within the transaction of a change, see
+// db.AuditBuilder, so they are published once the change commits.
+type auditTrail []*mdl.Audit
+
+// of returns the builder of the audits of a change, adding them to the trail as they are built.
+func (t *auditTrail) of(builds ...func() (*mdl.Audit, error)) db.AuditBuilder {
+	return func() ([]*mdl.Audit, error) {
+		audits := make([]*mdl.Audit, 0, len(builds))
+		for _, build := range builds {
+			audit, err := build()
+			if err != nil {
+				return nil, err
+			}
+			audits = append(audits, audit)
+		}
+		*t = append(*t, audits...)
+		return audits, nil
+	}
+}
+
+// vocab returns the builder of the audit of a vocab change, built once the vocab is written so
+// a created vocab has its ID. See CreateVocabAudit for the parameters.
+func (t *auditTrail) vocab(comments string, createdBy string, before *mdl.Vocab, after *mdl.Vocab) db.AuditBuilder {
+	return t.of(func() (*mdl.Audit, error) {
+		return buildVocabAudit(comments, createdBy, before, after)
+	})
+}
+
+// publish publishes the audits of the trail, once their change has committed.
+func (t auditTrail) publish() {
+	for _, audit := range t {
+		publishAuditCreated(audit)
+	}
 }
 
 // CreateFixitAudit records a fixit trail for vocabulary modifications. This function
@@ -205,21 +296,61 @@ func (s *AuditService) CreateFixitAudit(comments string, createdBy string, befor
 //
 // The function ensures that the 'comments' field does not exceed 1000 characters and utilizes
 // CompareJSON to generate a 'diff' field if 'beforeJson' is provided, encapsulating the changes
-// made to the audited object. The new audit entry is then persisted through the repository layer.
+// made to the audited object. The new audit entry is then persisted through the repository layer,
+// published, and queued for the webhooks wanting it.
 func (s *AuditService) CreateAudit(tableName string, objectId int, comments string, createdBy string, beforeJson string, afterJson string) (err error) {
 
-	// validate the comments
-	if err = validateFieldContent(comments, "comments", 1000); err != nil {
+	audit, err := buildAudit(tableName, objectId, comments, createdBy, beforeJson, afterJson)
+	if err != nil {
+		return err
+	}
+
+	return s.saveAudit(audit)
+}
+
+// CreateRevertAudit logs the audit of a change restoring an earlier state of a record, such
+// as a rollback, so it is found by the reverted action rather than as an update. See
+// CreateAudit for the parameters.
+func (s *AuditService) CreateRevertAudit(tableName string, objectId int, comments string, createdBy string, beforeJson string, afterJson string) error {
+
+	audit, err := buildAudit(tableName, objectId, comments, createdBy, beforeJson, afterJson)
+	if err != nil {
+		return err
+	}
+	audit.Action = mdl.AuditReverted
+
+	return s.saveAudit(audit)
+}
+
+// saveAudit persists a built audit, along with the deliveries of the webhooks wanting it,
+// and publishes it.
+func (s *AuditService) saveAudit(audit *mdl.Audit) error {
+
+	if err := s.repo.CreateAudit(audit); err != nil {
 		return err
 	}
 
+	publishAuditCreated(audit)
+	return nil
+}
+
+// buildAudit validates the comments and builds an audit record, including the diff between
+// the before and after states, without saving it. It lets changes saved together in one
+// transaction carry their audit records with them, see CreateAudit for the parameters.
+func buildAudit(tableName string, objectId int, comments string, createdBy string, beforeJson string, afterJson string) (*mdl.Audit, error) {
+
+	// validate the comments
+	if err := validateFieldContent(comments, "comments", 1000); err != nil {
+		return nil, err
+	}
+
 	diff := ""
 
 	if len(beforeJson) > 0 {
 		diff = CompareJSON(beforeJson, afterJson)
 	}
 
-	audit := mdl.Audit{
+	audit := &mdl.Audit{
 		TableName: tableName,
 		ObjectID:  objectId,
 		Comments:  comments,
@@ -228,10 +359,9 @@ func (s *AuditService) CreateAudit(tableName string, objectId int, comments stri
 		Diff:      diff,
 		CreatedBy: createdBy,
 	}
+	audit.Action = audit.DerivedAction()
 
-	err = s.repo.CreateAudit(&audit)
-
-	return
+	return audit, nil
 }
 
 type DiffResult struct {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/heather92115/verdure-admin/internal/db/mock"
 	"github.com/heather92115/verdure-admin/internal/mdl"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -148,6 +149,60 @@ func TestAuditService_FindAudits(t *testing.T) {
 	}
 }
 
+// TestAuditService_FindAuditsMatching tests the filters and order of FindAuditsMatching.
+func TestAuditService_FindAuditsMatching(t *testing.T) {
+	service := &AuditService{repo: mock.NewMockAuditRepository()}
+
+	perro := `{"id":1,"learning_lang":"perro","hint":""}`
+	hinted := `{"id":1,"learning_lang":"perro","hint":"a pet"}`
+	gato := `{"id":2,"learning_lang":"gato","hint":""}`
+	_ = service.CreateAudit("vocab", 1, "", "ana", "", perro)
+	_ = service.CreateAudit("vocab", 2, "", "luis", "", gato)
+	_ = service.CreateAudit("vocab", 1, "", "ana", perro, hinted)
+	_ = service.CreateAudit("vocab", 2, "", "ana", gato, "")
+	_ = service.CreateRevertAudit("vocab", 1, "", "luis", hinted, perro)
+
+	tests := []struct {
+		name    string
+		filter  mdl.AuditFilter
+		limit   int
+		wantIDs []int
+		wantErr bool
+	}{
+		{name: "Newest first", filter: mdl.AuditFilter{TableName: "vocab"}, limit: 10, wantIDs: []int{5, 4, 3, 2, 1}},
+		{name: "Limit", filter: mdl.AuditFilter{}, limit: 2, wantIDs: []int{5, 4}},
+		{name: "Created by", filter: mdl.AuditFilter{CreatedBy: "ana"}, limit: 10, wantIDs: []int{4, 3, 1}},
+		{name: "Creates", filter: mdl.AuditFilter{Action: mdl.AuditCreated}, limit: 10, wantIDs: []int{2, 1}},
+		{name: "Deletes", filter: mdl.AuditFilter{Action: mdl.AuditDeleted}, limit: 10, wantIDs: []int{4}},
+		{name: "Reverts", filter: mdl.AuditFilter{Action: mdl.AuditReverted}, limit: 10, wantIDs: []int{5}},
+		{name: "Changed field", filter: mdl.AuditFilter{TableName: "vocab", ChangedField: "hint"}, limit: 10, wantIDs: []int{5, 3}},
+		{name: "Changed field by user", filter: mdl.AuditFilter{CreatedBy: "ana", ChangedField: "hint"}, limit: 10, wantIDs: []int{3}},
+		{name: "Object", filter: mdl.AuditFilter{TableName: "vocab", ObjectID: 2}, limit: 10, wantIDs: []int{4, 2}},
+		{name: "Unknown action", filter: mdl.AuditFilter{Action: "renamed"}, limit: 10, wantErr: true},
+		{name: "Object without table", filter: mdl.AuditFilter{ObjectID: 1}, limit: 10, wantErr: true},
+		{name: "Invalid limit", filter: mdl.AuditFilter{}, limit: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audits, err := service.FindAuditsMatching(&tt.filter, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FindAuditsMatching() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil {
+				return
+			}
+
+			var ids []int
+			for _, audit := range *audits {
+				ids = append(ids, audit.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("FindAuditsMatching() ids = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
 // TestAuditService_FindAuditByID tests the functionality of FindAuditByID method.
 func TestAuditService_FindAuditByID(t *testing.T) {
 	// Initialize the mock repository and service
@@ -0,0 +1,388 @@
+package srv
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	maxChangeCommentsLen = 1000
+
+	// changeFieldsSeparator separates the names of the fields a change request sets.
+	changeFieldsSeparator = ", "
+)
+
+// reviewFieldsFromEnv reads the VOCAB_REVIEW_FIELDS environment variable, the comma separated
+// names of the vocab fields whose changes must be approved, e.g. "first_lang, hint", or "*"
+// for every field updateVocab changes. No field requires review when it is not set. Names
+// that are not fields updateVocab changes are logged and ignored.
+func reviewFieldsFromEnv() []string {
+	return parseReviewFields(os.Getenv("VOCAB_REVIEW_FIELDS"))
+}
+
+// parseReviewFields parses the field names of the VOCAB_REVIEW_FIELDS env var, see reviewFieldsFromEnv.
+func parseReviewFields(list string) (names []string) {
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if len(name) == 0 {
+			continue
+		}
+
+		if name == "*" {
+			names = nil
+			for _, field := range VocabFields {
+				if field.Updatable {
+					names = append(names, field.Name)
+				}
+			}
+			return
+		}
+
+		if field, found := FindVocabField(name); !found || !field.Updatable {
+			log.Printf("Ignoring review field %s, it is not a vocab field updateVocab changes", name)
+			continue
+		}
+		if indexOfString(names, name) < 0 {
+			names = append(names, name)
+		}
+	}
+	return
+}
+
+// changedVocabFields returns the fields updateVocab changes whose values differ between two
+// versions of a vocab, in form order.
+func changedVocabFields(before *mdl.Vocab, after *mdl.Vocab) (fields []VocabField) {
+	for _, field := range VocabFields {
+		if field.Updatable && field.value(before) != field.value(after) {
+			fields = append(fields, field)
+		}
+	}
+	return
+}
+
+// checkReviewFields refuses a direct update of the vocab that changes a field requiring
+// review, naming each of them.
+func (s *VocabService) checkReviewFields(before *mdl.Vocab, after *mdl.Vocab) error {
+	errs := &ValidationError{}
+	for _, field := range changedVocabFields(before, after) {
+		if indexOfString(s.reviewFields, field.Name) >= 0 {
+			errs.add(field.Name, invalidField("", RuleReviewRequired, 0,
+				"%s changes must be approved, submit them as a change request", field.Label))
+		}
+	}
+	return errs.errOrNil()
+}
+
+// FindChangeRequestByID retrieves a change request by its primary ID.
+func (s *VocabService) FindChangeRequestByID(id int) (*mdl.ChangeRequest, error) {
+	return s.changeRepo.FindChangeRequestByID(id)
+}
+
+// FindChangeRequests retrieves the change requests of a vocab with a status, newest first.
+//
+// Parameters:
+// - vocabID: The primary ID of the vocab, or 0 for the change requests of every vocab.
+// - status: The review status, e.g. mdl.ChangePending, or empty for every status.
+// - limit: The maximum number of change requests returned, from 1 to 500.
+//
+// Returns:
+// - A pointer to the slice of change requests found.
+// - An error if the status or limit is invalid or the query fails.
+//
+// Usage example:
+// requests, err := vocabService.FindChangeRequests(0, mdl.ChangePending, 50)
+//
+//	if err != nil {
+//	    log.Printf("Failed to find change requests: %v", err)
+//	}
+func (s *VocabService) FindChangeRequests(vocabID int, status string, limit int) (*[]mdl.ChangeRequest, error) {
+	if len(status) > 0 && status != mdl.ChangePending && status != mdl.ChangeApproved && status != mdl.ChangeRejected {
+		return nil, invalidField("status", RuleOneOf, 0, "status %s must be one of %s, %s, %s", status,
+			mdl.ChangePending, mdl.ChangeApproved, mdl.ChangeRejected)
+	}
+	if limit < 1 || limit > 500 {
+		return nil, invalidField("limit", RuleInvalid, 500, "limit %d must be from 1 to 500", limit)
+	}
+
+	return s.changeRepo.FindChangeRequests(vocabID, status, limit)
+}
+
+// SubmitChangeRequest proposes a change to a vocab for review, without changing the vocab.
+// The patch is normalized and validated as UpdateVocab would, so only changes that could be
+// applied are proposed, and the change request keeps the vocab before and after the change
+// with their diff. Any field may be proposed, whether or not it requires review. The change
+// request is audited.
+//
+// Parameters:
+// - patch: A pointer to the mdl.VocabPatch describing the vocab ID and the fields to change.
+// - comments: Optional. Why the change is proposed.
+// - actor: The user proposing the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the pending mdl.ChangeRequest.
+// - An error if the vocab cannot be found, the patch holds no changes, or validation or saving fails.
+//
+// Usage example:
+// hint := "past tense"
+// request, err := vocabService.SubmitChangeRequest(&mdl.VocabPatch{ID: 123, Hint: &hint}, "clearer hint", srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to submit change request: %v", err)
+//	}
+func (s *VocabService) SubmitChangeRequest(patch *mdl.VocabPatch, comments string, actor string) (*mdl.ChangeRequest, error) {
+
+	if err := checkActor(actor); err != nil {
+		return nil, err
+	}
+
+	comments = NormalizeText(comments)
+	if err := validateFieldContent(comments, "Comments", maxChangeCommentsLen); err != nil {
+		errs := &ValidationError{}
+		errs.add("comments", err)
+		return nil, errs
+	}
+
+	before, err := s.FindVocabByID(patch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	proposed, err := s.patchVocab(before, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, field := range changedVocabFields(before, proposed) {
+		names = append(names, field.Name)
+	}
+
+	beforeJson, proposedJson := before.JSON(), proposed.JSON()
+	request := &mdl.ChangeRequest{
+		VocabID:   before.ID,
+		Status:    mdl.ChangePending,
+		Before:    beforeJson,
+		Proposed:  proposedJson,
+		Diff:      CompareJSON(beforeJson, proposedJson),
+		Fields:    strings.Join(names, changeFieldsSeparator),
+		Comments:  comments,
+		CreatedBy: actor,
+	}
+	if err = s.changeRepo.CreateChangeRequest(request); err != nil {
+		return nil, err
+	}
+
+	err = s.auditService.CreateAudit("change_request", request.ID, "submitted change request", actor, "", request.JSON())
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// ApproveChangeRequest applies a pending change request to its vocab through the same checks
+// as UpdateVocab, bypassing only the review rule. The vocab audit names the change request.
+// When a field the change sets has been changed since it was proposed, the change request
+// cannot be approved, so a later edit is never silently overwritten; reject it and propose
+// the change again. A change request cannot be approved by the user who submitted it. The
+// review is audited.
+//
+// Parameters:
+// - id: The primary ID of the change request.
+// - comments: Optional. Why the change is approved.
+// - actor: The user approving the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the approved mdl.ChangeRequest.
+// - An error if the change request cannot be found, is not pending, is out of date, was
+// submitted by the actor, or validation or saving fails.
+//
+// Usage example:
+// request, err := vocabService.ApproveChangeRequest(12, "", srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to approve change request: %v", err)
+//	}
+func (s *VocabService) ApproveChangeRequest(id int, comments string, actor string) (*mdl.ChangeRequest, error) {
+
+	request, err := s.pendingChangeRequest(id, &comments, actor)
+	if err != nil {
+		return nil, err
+	}
+	if request.CreatedBy == actor {
+		return nil, invalidField("actor", RuleNotAllowed, 0, "change request %d cannot be approved by %s, who submitted it",
+			id, actor)
+	}
+
+	before, err := request.BeforeVocab()
+	if err != nil {
+		return nil, err
+	}
+	proposed, err := request.ProposedVocab()
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.FindVocabByID(request.VocabID)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := &ValidationError{}
+	var fields []VocabField
+	for _, name := range splitChangeFields(request.Fields) {
+		field, found := FindVocabField(name)
+		if !found {
+			return nil, fmt.Errorf("change request %d sets unknown vocab field %s", id, name)
+		}
+		if field.value(current) != field.value(before) {
+			errs.add(field.Name, invalidField("", RuleInvalid, 0, "%s has changed since change request %d was submitted",
+				field.Label, id))
+		}
+		fields = append(fields, field)
+	}
+	if err = errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	vocab, err := s.patchVocab(current, patchFromVocab(current.ID, proposed, fields))
+	if err != nil {
+		return nil, err
+	}
+
+	// The change, the approval and the audits of both are saved together, and only while the
+	// change request is still pending
+	vocabAudit := func() (*mdl.Audit, error) {
+		return buildVocabAudit(fmt.Sprintf("applied change request %d", id), actor, current, vocab)
+	}
+	var audits auditTrail
+	err = s.changeRepo.ApproveChangeRequest(request, vocab, audits.of(vocabAudit, markReviewed(request, mdl.ChangeApproved, comments, actor)))
+	if err != nil {
+		return nil, err
+	}
+
+	audits.publish()
+	publishVocabChanged(vocab)
+	return request, nil
+}
+
+// RejectChangeRequest closes a pending change request without changing its vocab. The review
+// is audited.
+//
+// Parameters:
+// - id: The primary ID of the change request.
+// - comments: Optional. Why the change is rejected.
+// - actor: The user rejecting the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the rejected mdl.ChangeRequest.
+// - An error if the change request cannot be found, is not pending, or saving fails.
+//
+// Usage example:
+// request, err := vocabService.RejectChangeRequest(12, "the hint gives the answer away", srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to reject change request: %v", err)
+//	}
+func (s *VocabService) RejectChangeRequest(id int, comments string, actor string) (*mdl.ChangeRequest, error) {
+
+	request, err := s.pendingChangeRequest(id, &comments, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	var audits auditTrail
+	if err = s.changeRepo.ReviewChangeRequest(request, audits.of(markReviewed(request, mdl.ChangeRejected, comments, actor))); err != nil {
+		return nil, err
+	}
+
+	audits.publish()
+	return request, nil
+}
+
+// pendingChangeRequest finds a change request to review, ensuring it is pending, and
+// normalizes and validates the review comments and the reviewer.
+func (s *VocabService) pendingChangeRequest(id int, comments *string, actor string) (*mdl.ChangeRequest, error) {
+
+	if err := checkActor(actor); err != nil {
+		return nil, err
+	}
+
+	*comments = NormalizeText(*comments)
+	if err := validateFieldContent(*comments, "Comments", maxChangeCommentsLen); err != nil {
+		errs := &ValidationError{}
+		errs.add("comments", err)
+		return nil, errs
+	}
+
+	request, err := s.changeRepo.FindChangeRequestByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != mdl.ChangePending {
+		return nil, invalidField("id", RuleInvalid, 0, "change request %d is already %s", id, request.Status)
+	}
+
+	return request, nil
+}
+
+// markReviewed records the review on a change request and returns the build of its audit, run
+// by the repository saving the review.
+func markReviewed(request *mdl.ChangeRequest, status string, comments string, actor string) func() (*mdl.Audit, error) {
+
+	beforeJson := request.JSON()
+
+	reviewed := time.Now()
+	request.Status = status
+	request.ReviewComments = comments
+	request.ReviewedBy = actor
+	request.Reviewed = &reviewed
+
+	return func() (*mdl.Audit, error) {
+		return buildAudit("change_request", request.ID, status+" change request", actor, beforeJson, request.JSON())
+	}
+}
+
+// splitChangeFields splits the field names a change request sets.
+func splitChangeFields(list string) (names []string) {
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			names = append(names, name)
+		}
+	}
+	return
+}
+
+// patchFromVocab builds the patch setting the fields of a vocab to their values in the proposed vocab.
+func patchFromVocab(id int, proposed *mdl.Vocab, fields []VocabField) *mdl.VocabPatch {
+	patch := &mdl.VocabPatch{ID: id}
+	for _, field := range fields {
+		switch field.Name {
+		case "first_lang":
+			patch.FirstLang = &proposed.FirstLang
+		case "skill":
+			patch.Skill = &proposed.Skill
+		case "infinitive":
+			patch.Infinitive = &proposed.Infinitive
+		case "pos":
+			patch.Pos = &proposed.Pos
+		case "hint":
+			patch.Hint = &proposed.Hint
+		case "gender":
+			patch.Gender = &proposed.Gender
+		case "plural":
+			patch.Plural = &proposed.Plural
+		case "article":
+			patch.Article = &proposed.Article
+		case "register":
+			patch.Register = &proposed.Register
+		case "num_learning_words":
+			patch.NumLearningWords = &proposed.NumLearningWords
+		}
+	}
+	return patch
+}
@@ -0,0 +1,156 @@
+package srv
+
+import (
+	"errors"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseReviewFields(t *testing.T) {
+	var updatable []string
+	for _, field := range VocabFields {
+		if field.Updatable {
+			updatable = append(updatable, field.Name)
+		}
+	}
+
+	tests := []struct {
+		name string
+		list string
+		want []string
+	}{
+		{name: "Not set", list: "", want: nil},
+		{name: "Names", list: "hint, First_Lang,hint", want: []string{"hint", "first_lang"}},
+		{name: "Fields updateVocab does not change are ignored", list: "learning_lang, hint, colour", want: []string{"hint"}},
+		{name: "Every field", list: "*", want: updatable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseReviewFields(tt.list); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseReviewFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVocabService_UpdateVocabReviewFields(t *testing.T) {
+	vocabService := createMockVocabService()
+	vocabService.reviewFields = []string{"hint"}
+	_ = vocabService.CreateVocab(&mdl.Vocab{LearningLang: "perro", FirstLang: "dog", Pos: "noun",
+		LearningLangCode: "es", KnownLangCode: "en"}, false, "sys")
+
+	hint := "a pet"
+	_, err := vocabService.UpdateVocab(&mdl.VocabPatch{ID: 1, Hint: &hint}, "sys")
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Errors[0].Field != "hint" ||
+		validationErr.Errors[0].Rule != RuleReviewRequired {
+		t.Errorf("UpdateVocab() error = %v, want hint to require review", err)
+	}
+
+	plural := "perros"
+	if vocab, err := vocabService.UpdateVocab(&mdl.VocabPatch{ID: 1, Plural: &plural}, "sys"); err != nil || vocab.Plural != plural {
+		t.Errorf("UpdateVocab() error = %v, want plural updated without review", err)
+	}
+}
+
+func TestVocabService_ChangeRequests(t *testing.T) {
+	vocabService := createMockVocabService()
+	vocabService.reviewFields = []string{"hint"}
+	_ = vocabService.CreateVocab(&mdl.Vocab{LearningLang: "perro", FirstLang: "dog", Pos: "noun",
+		LearningLangCode: "es", KnownLangCode: "en"}, false, "sys")
+
+	hint, otherHint, plural := "a pet", "a friend", "perros"
+	submitted, err := vocabService.SubmitChangeRequest(&mdl.VocabPatch{ID: 1, Hint: &hint, Plural: &plural}, "clearer hint", "ana")
+	if err != nil {
+		t.Fatalf("SubmitChangeRequest() error = %v", err)
+	}
+	if submitted.Status != mdl.ChangePending || submitted.Fields != "hint, plural" || !strings.Contains(submitted.Diff, "'hint'") ||
+		submitted.CreatedBy != "ana" {
+		t.Errorf("SubmitChangeRequest() = %+v, want pending with the hint and plural changed", submitted)
+	}
+	if vocab, _ := vocabService.FindVocabByID(1); len(vocab.Hint) > 0 {
+		t.Errorf("SubmitChangeRequest() changed the vocab hint to %s", vocab.Hint)
+	}
+
+	stale, _ := vocabService.SubmitChangeRequest(&mdl.VocabPatch{ID: 1, Hint: &otherHint}, "", "ana")
+	rejected, _ := vocabService.SubmitChangeRequest(&mdl.VocabPatch{ID: 1, Hint: &otherHint}, "", "ana")
+
+	if _, err = vocabService.SubmitChangeRequest(&mdl.VocabPatch{ID: 1}, "", "ana"); err == nil {
+		t.Errorf("SubmitChangeRequest() expected an error for no changes")
+	}
+	if _, err = vocabService.SubmitChangeRequest(&mdl.VocabPatch{ID: 1, Hint: &otherHint}, "", ""); err == nil ||
+		!strings.Contains(err.Error(), ActorHeader) {
+		t.Errorf("SubmitChangeRequest() error = %v, want the actor required", err)
+	}
+
+	tests := []struct {
+		name       string
+		id         int
+		actor      string
+		approve    bool
+		wantStatus string
+		wantErr    bool
+		errMsg     string
+	}{
+		{name: "Reject", id: rejected.ID, actor: "ben", wantStatus: mdl.ChangeRejected},
+		{name: "Approved by the submitter", id: submitted.ID, actor: "ana", approve: true, wantErr: true,
+			errMsg: "change request 1 cannot be approved by ana, who submitted it"},
+		{name: "Reviewer not named", id: submitted.ID, approve: true, wantErr: true,
+			errMsg: "the user making the change must be named"},
+		{name: "Approve", id: submitted.ID, actor: "ben", approve: true, wantStatus: mdl.ChangeApproved},
+		{name: "Already reviewed", id: submitted.ID, actor: "ben", approve: true, wantErr: true,
+			errMsg: "change request 1 is already approved"},
+		{name: "Changed since submitted", id: stale.ID, actor: "ben", approve: true, wantErr: true,
+			errMsg: "Hint has changed since change request 2 was submitted"},
+		{name: "Missing", id: 99, actor: "ben", wantErr: true, errMsg: "error finding change request with id 99"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request *mdl.ChangeRequest
+			var err error
+			if tt.approve {
+				request, err = vocabService.ApproveChangeRequest(tt.id, "", tt.actor)
+			} else {
+				request, err = vocabService.RejectChangeRequest(tt.id, "", tt.actor)
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("review error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil && !strings.HasPrefix(err.Error(), tt.errMsg) {
+				t.Errorf("review error = %v, wantErrMsg %v", err, tt.errMsg)
+			} else if err == nil && (request.Status != tt.wantStatus || request.Reviewed == nil || request.ReviewedBy != tt.actor) {
+				t.Errorf("review status = %s by %s, want %s by %s", request.Status, request.ReviewedBy, tt.wantStatus, tt.actor)
+			}
+		})
+	}
+
+	vocab, _ := vocabService.FindVocabByID(1)
+	if vocab.Hint != hint || vocab.Plural != plural {
+		t.Errorf("ApproveChangeRequest() vocab hint = %s, plural = %s, want %s and %s", vocab.Hint, vocab.Plural, hint, plural)
+	}
+
+	audits, _ := vocabService.auditService.repo.FindAuditsBetween(nil, nil, []string{"vocab"})
+	if last := (*audits)[len(*audits)-1]; last.Comments != "applied change request 1" || last.CreatedBy != "ben" {
+		t.Errorf("ApproveChangeRequest() audit comments = %s by %s, want the change request named by ben", last.Comments, last.CreatedBy)
+	}
+
+	pending, _ := vocabService.FindChangeRequests(1, mdl.ChangePending, 10)
+	if len(*pending) != 1 || (*pending)[0].ID != stale.ID {
+		t.Errorf("FindChangeRequests() = %+v, want the stale change request pending", *pending)
+	}
+
+	// A review racing another one, having read the change request while it was still pending
+	racing, _ := vocabService.FindChangeRequestByID(stale.ID)
+	if _, err = vocabService.RejectChangeRequest(stale.ID, "", "ben"); err != nil {
+		t.Fatalf("RejectChangeRequest() error = %v", err)
+	}
+	markReviewed(racing, mdl.ChangeApproved, "", "ben")
+	if err = vocabService.changeRepo.ReviewChangeRequest(racing, nil); err == nil ||
+		err.Error() != "change request 2 is no longer pending" {
+		t.Errorf("ReviewChangeRequest() error = %v, want the racing review refused", err)
+	}
+}
@@ -0,0 +1,314 @@
+package srv
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+)
+
+const (
+	maxGlossLen        = 100
+	maxConceptNotesLen = 255
+)
+
+// ConceptService handles business logic for the Concept records grouping vocab translations.
+type ConceptService struct {
+	repo         db.ConceptRepository
+	auditService AuditService
+}
+
+// NewConceptService creates a new instance of ConceptService.
+func NewConceptService() (*ConceptService, error) {
+
+	repo, err := db.NewSqlConceptRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := NewAuditService()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConceptService{repo: repo, auditService: *auditService}, nil
+}
+
+// FindConceptByID retrieves a concept by its primary ID.
+func (s *ConceptService) FindConceptByID(id int) (*mdl.Concept, error) {
+	return s.repo.FindConceptByID(id)
+}
+
+// CreateConcept creates a concept for vocab in different learning languages to share, and
+// writes an audit entry. The gloss is required, its language defaults to the default known language.
+//
+// Parameters:
+// - concept: A pointer to the mdl.Concept to create, its ID is set on success.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - An error if validation or saving fails.
+//
+// Usage example:
+// err := conceptService.CreateConcept(&mdl.Concept{Gloss: "dog", Notes: "the animal"}, srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to create concept: %v", err)
+//	}
+func (s *ConceptService) CreateConcept(concept *mdl.Concept, actor string) error {
+
+	if len(NormalizeText(concept.Gloss)) == 0 {
+		return invalidField("gloss", RuleRequired, 0, "gloss field is required")
+	}
+
+	return createConcept(s.repo, &s.auditService, concept, actor)
+}
+
+// UpdateConcept applies a partial update to a concept and writes an audit entry.
+//
+// Parameters:
+// - patch: The fields to change, only the non-nil ones are applied.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the updated mdl.Concept.
+// - An error if it cannot be found, the patch holds no changes, or validation or saving fails.
+func (s *ConceptService) UpdateConcept(patch *mdl.ConceptPatch, actor string) (*mdl.Concept, error) {
+
+	before, err := s.repo.FindConceptByID(patch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range []*string{patch.Gloss, patch.Notes} {
+		if field != nil {
+			*field = NormalizeText(*field)
+		}
+	}
+	if patch.GlossLangCode != nil {
+		*patch.GlossLangCode = CanonicalLangCode(*patch.GlossLangCode)
+	}
+
+	after := *before
+	concept := &after
+	if !patch.ApplyTo(concept) {
+		return nil, fmt.Errorf("update for concept %d has no changes", patch.ID)
+	}
+
+	if len(concept.Gloss) == 0 {
+		return nil, invalidField("gloss", RuleRequired, 0, "gloss field is required")
+	}
+	if err = validateConcept(concept); err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.UpdateConcept(concept); err != nil {
+		return nil, err
+	}
+
+	err = s.auditService.CreateAudit("concept", concept.ID, "updated concept", actor, before.JSON(), concept.JSON())
+	if err != nil {
+		return nil, err
+	}
+
+	return concept, nil
+}
+
+// createConcept normalizes, validates, stores and audits a new concept, see prepareConcept.
+func createConcept(repo db.ConceptRepository, auditService *AuditService, concept *mdl.Concept, actor string) error {
+
+	if len(concept.CreatedBy) == 0 {
+		concept.CreatedBy = actor
+	}
+	if err := prepareConcept(concept); err != nil {
+		return err
+	}
+
+	if err := repo.CreateConcept(concept); err != nil {
+		return err
+	}
+
+	return auditService.CreateAudit("concept", concept.ID, "created concept", actor, "", concept.JSON())
+}
+
+// prepareConcept normalizes and validates a new concept before it is stored. It is shared by
+// CreateConcept and CreateVocab, which wraps a vocab created without a concept in its own.
+func prepareConcept(concept *mdl.Concept) error {
+
+	concept.Gloss = NormalizeText(concept.Gloss)
+	concept.Notes = NormalizeText(concept.Notes)
+	concept.GlossLangCode = CanonicalLangCode(concept.GlossLangCode)
+	if len(concept.GlossLangCode) == 0 {
+		concept.GlossLangCode, _ = DefaultLangCodes()
+	}
+	if len(concept.CreatedBy) == 0 {
+		concept.CreatedBy = SystemActor
+	}
+
+	return validateConcept(concept)
+}
+
+// validateConcept checks the lengths and content of the concept fields and its gloss language,
+// collecting the failures in a *ValidationError.
+func validateConcept(concept *mdl.Concept) error {
+	errs := &ValidationError{}
+	errs.add("gloss", validateFieldContent(concept.Gloss, "Gloss", maxGlossLen))
+	errs.add("notes", validateFieldContent(concept.Notes, "Notes", maxConceptNotesLen))
+	if !ValidLangCode(concept.GlossLangCode) {
+		errs.add("gloss_lang_code", invalidField("", RuleFormat, 0, errFmtStrLangCode, "Gloss language code"))
+	}
+
+	return errs.errOrNil()
+}
+
+// FindConceptTranslations retrieves the vocab of a concept, the translations of its meaning
+// into each learning language, including their alternatives.
+//
+// Parameters:
+// - conceptID: The primary ID of the concept.
+// - learningCode: Optional. Only the vocab of this learning language code are returned.
+//
+// Returns:
+// - The vocab ordered by learning language code, then learning lang.
+// - An error if the concept cannot be found or the vocab cannot be read.
+//
+// Usage example:
+// vocabs, err := vocabService.FindConceptTranslations(42, "pt-BR")
+//
+//	if err != nil {
+//	    log.Printf("Failed to find translations: %v", err)
+//	}
+func (s *VocabService) FindConceptTranslations(conceptID int, learningCode string) (*[]mdl.Vocab, error) {
+
+	if _, err := s.conceptRepo.FindConceptByID(conceptID); err != nil {
+		return nil, err
+	}
+
+	found, err := s.conceptRepo.FindConceptVocabs(conceptID)
+	if err != nil {
+		return nil, err
+	}
+
+	learningCode = CanonicalLangCode(learningCode)
+	vocabs := make([]mdl.Vocab, 0, len(*found))
+	for _, vocab := range *found {
+		if len(learningCode) == 0 || vocab.LearningLangCode == learningCode {
+			vocabs = append(vocabs, vocab)
+		}
+	}
+
+	list := make([]*mdl.Vocab, len(vocabs))
+	for i := range vocabs {
+		list[i] = &vocabs[i]
+	}
+	if err = s.attachAlternatives(list...); err != nil {
+		return nil, err
+	}
+
+	return &vocabs, nil
+}
+
+// FindVocabTranslations retrieves the other vocab sharing the concept of a vocab, its
+// translations into the other learning languages and its synonyms in its own.
+//
+// Parameters:
+// - vocabID: The primary ID of the Vocab record.
+//
+// Returns:
+// - The other vocab of the concept, empty when the vocab has no concept.
+// - An error if the vocab cannot be found or the translations cannot be read.
+func (s *VocabService) FindVocabTranslations(vocabID int) (*[]mdl.Vocab, error) {
+
+	vocab, err := s.FindVocabByID(vocabID)
+	if err != nil {
+		return nil, err
+	}
+	if vocab.ConceptID == nil {
+		return &[]mdl.Vocab{}, nil
+	}
+
+	found, err := s.FindConceptTranslations(*vocab.ConceptID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	vocabs := make([]mdl.Vocab, 0, len(*found))
+	for _, translation := range *found {
+		if translation.ID != vocab.ID {
+			vocabs = append(vocabs, translation)
+		}
+	}
+
+	return &vocabs, nil
+}
+
+// MoveVocabsToConcept makes Vocab records translations of a concept in bulk, for example to
+// group the vocab of a new course with the vocab of an existing one sharing their prompts, and
+// writes an audit entry for every vocab moved. Vocab already in the concept are returned
+// unchanged. Every vocab is found before any is saved, so an unknown ID moves nothing. The
+// concepts left without vocab are kept.
+//
+// Parameters:
+// - vocabIDs: The primary IDs of the Vocab records to move.
+// - conceptID: The primary ID of the concept to move them to.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - The vocab, in the order of the IDs given, including their alternatives.
+// - An error if the IDs are invalid, the concept or any vocab cannot be found, or saving fails.
+//
+// Usage example:
+// vocabs, err := vocabService.MoveVocabsToConcept([]int{123, 456}, 42, srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to move vocabs: %v", err)
+//	}
+func (s *VocabService) MoveVocabsToConcept(vocabIDs []int, conceptID int, actor string) (moved []mdl.Vocab, err error) {
+
+	if len(vocabIDs) == 0 {
+		return nil, fmt.Errorf("at least one vocab to move is required")
+	}
+
+	concept, err := s.conceptRepo.FindConceptByID(conceptID)
+	if err != nil {
+		return
+	}
+
+	seen := make(map[int]bool)
+	vocabs := make([]*mdl.Vocab, len(vocabIDs))
+	for i, id := range vocabIDs {
+		if seen[id] {
+			return nil, fmt.Errorf("vocab %d is listed more than once", id)
+		}
+		seen[id] = true
+
+		if vocabs[i], err = s.FindVocabByID(id); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, vocab := range vocabs {
+		if vocab.ConceptID != nil && *vocab.ConceptID == concept.ID {
+			moved = append(moved, *vocab)
+			continue
+		}
+
+		before := vocab.Clone()
+		vocab.ConceptID = &concept.ID
+
+		comments := fmt.Sprintf("moved vocab to concept %d", concept.ID)
+		if before.ConceptID != nil {
+			comments = fmt.Sprintf("moved vocab from concept %d to concept %d", *before.ConceptID, concept.ID)
+		}
+
+		var audits auditTrail
+		if err = s.repo.UpdateVocab(vocab, audits.vocab(comments, actor, before, vocab)); err != nil {
+			return nil, err
+		}
+		audits.publish()
+		publishVocabChanged(vocab)
+
+		moved = append(moved, *vocab)
+	}
+
+	return
+}
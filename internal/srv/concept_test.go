@@ -0,0 +1,126 @@
+package srv
+
+import (
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"strings"
+	"testing"
+)
+
+func TestVocabService_CreateVocabConcept(t *testing.T) {
+	vocabService := createMockVocabService()
+
+	perro := &mdl.Vocab{LearningLang: "perro", FirstLang: "dog", LearningLangCode: "es", KnownLangCode: "en"}
+	if err := vocabService.CreateVocab(perro, false, "sys"); err != nil {
+		t.Fatalf("CreateVocab() error = %v", err)
+	}
+	if perro.ConceptID == nil {
+		t.Fatalf("CreateVocab() did not wrap the vocab in a concept")
+	}
+	concept, err := vocabService.conceptRepo.FindConceptByID(*perro.ConceptID)
+	if err != nil || concept.Gloss != "dog" || concept.GlossLangCode != "en" {
+		t.Errorf("CreateVocab() concept = %+v, %v", concept, err)
+	}
+
+	// Both are audited in the transaction creating them, once they have their IDs
+	audits, _ := vocabService.auditService.FindAudits("vocab", perro.ID, nil, 0)
+	if len(*audits) != 1 || (*audits)[0].Comments != "created vocab" || !strings.Contains((*audits)[0].After, `"id":1,`) {
+		t.Errorf("CreateVocab() vocab audits = %+v", audits)
+	}
+	audits, _ = vocabService.auditService.FindAudits("concept", concept.ID, nil, 0)
+	if len(*audits) != 1 || (*audits)[0].Comments != "created concept" {
+		t.Errorf("CreateVocab() concept audits = %+v", audits)
+	}
+
+	// The same learning lang is another vocab in another learning language.
+	tests := []struct {
+		name    string
+		vocab   *mdl.Vocab
+		wantErr bool
+		errMsg  string
+	}{
+		{name: "Joins the concept", vocab: &mdl.Vocab{LearningLang: "cão", FirstLang: "dog", LearningLangCode: "PT_br",
+			KnownLangCode: "en", ConceptID: perro.ConceptID}},
+		{name: "Same learning lang in another language", vocab: &mdl.Vocab{LearningLang: "perro", FirstLang: "dog",
+			LearningLangCode: "es-419", KnownLangCode: "en", ConceptID: perro.ConceptID}},
+		{name: "Same learning lang and language", vocab: &mdl.Vocab{LearningLang: "perro", FirstLang: "dog",
+			LearningLangCode: "es", KnownLangCode: "en"}, wantErr: true,
+			errMsg: "vocab with learning lang perro and id 1 already exists"},
+		{name: "Unknown concept", vocab: &mdl.Vocab{LearningLang: "chien", FirstLang: "dog", LearningLangCode: "fr",
+			KnownLangCode: "en", ConceptID: intPtr(99)}, wantErr: true, errMsg: "error finding concept with id 99"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := vocabService.CreateVocab(tt.vocab, true, "sys")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateVocab() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil && err.Error() != tt.errMsg {
+				t.Errorf("CreateVocab() error = %v, wantErrMsg %v", err, tt.errMsg)
+			}
+		})
+	}
+
+	translations, err := vocabService.FindConceptTranslations(*perro.ConceptID, "")
+	if err != nil || len(*translations) != 3 || (*translations)[1].LearningLangCode != "es-419" ||
+		(*translations)[2].LearningLangCode != "pt-BR" {
+		t.Errorf("FindConceptTranslations() = %+v, %v", translations, err)
+	}
+
+	translations, err = vocabService.FindConceptTranslations(*perro.ConceptID, "pt-br")
+	if err != nil || len(*translations) != 1 || (*translations)[0].LearningLang != "cão" {
+		t.Errorf("FindConceptTranslations() of pt-BR = %+v, %v", translations, err)
+	}
+
+	translations, err = vocabService.FindVocabTranslations(perro.ID)
+	if err != nil || len(*translations) != 2 {
+		t.Errorf("FindVocabTranslations() = %+v, %v", translations, err)
+	}
+}
+
+func TestVocabService_MoveVocabsToConcept(t *testing.T) {
+	vocabService := createMockVocabService()
+	conceptService := ConceptService{repo: vocabService.conceptRepo, auditService: vocabService.auditService}
+
+	perro := &mdl.Vocab{LearningLang: "perro", FirstLang: "dog", LearningLangCode: "es", KnownLangCode: "en"}
+	chien := &mdl.Vocab{LearningLang: "chien", FirstLang: "dog", LearningLangCode: "fr", KnownLangCode: "en"}
+	_ = vocabService.CreateVocab(perro, false, "sys")
+	_ = vocabService.CreateVocab(chien, false, "sys")
+
+	if _, err := vocabService.MoveVocabsToConcept([]int{chien.ID, 99}, *perro.ConceptID, "sys"); err == nil {
+		t.Errorf("MoveVocabsToConcept() expected an error for an unknown vocab")
+	}
+	if _, err := vocabService.MoveVocabsToConcept([]int{chien.ID, chien.ID}, *perro.ConceptID, "sys"); err == nil {
+		t.Errorf("MoveVocabsToConcept() expected an error for a repeated vocab")
+	}
+
+	moved, err := vocabService.MoveVocabsToConcept([]int{perro.ID, chien.ID}, *perro.ConceptID, "sys")
+	if err != nil || len(moved) != 2 || *moved[1].ConceptID != *perro.ConceptID {
+		t.Fatalf("MoveVocabsToConcept() = %+v, %v", moved, err)
+	}
+
+	audits, _ := vocabService.auditService.FindAudits("vocab", chien.ID, nil, 0)
+	found := false
+	for _, audit := range *audits {
+		found = found || audit.Comments == "moved vocab from concept 2 to concept 1"
+	}
+	if !found {
+		t.Errorf("MoveVocabsToConcept() did not audit the move")
+	}
+
+	gloss, notes, code := "dog (animal)", "not the verb", "EN"
+	concept, err := conceptService.UpdateConcept(&mdl.ConceptPatch{ID: *perro.ConceptID, Gloss: &gloss, Notes: &notes,
+		GlossLangCode: &code}, "sys")
+	if err != nil || concept.Gloss != gloss || concept.GlossLangCode != "en" {
+		t.Errorf("UpdateConcept() = %+v, %v", concept, err)
+	}
+	if _, err = conceptService.UpdateConcept(&mdl.ConceptPatch{ID: *perro.ConceptID, Gloss: &gloss}, "sys"); err == nil {
+		t.Errorf("UpdateConcept() expected an error for no changes")
+	}
+
+	if err = conceptService.CreateConcept(&mdl.Concept{Gloss: " "}, "sys"); err == nil || err.Error() != "gloss field is required" {
+		t.Errorf("CreateConcept() error = %v", err)
+	}
+	if err = conceptService.CreateConcept(&mdl.Concept{Gloss: "cat", GlossLangCode: "english"}, "sys"); err == nil {
+		t.Errorf("CreateConcept() expected an error for an invalid gloss language code")
+	}
+}
@@ -0,0 +1,271 @@
+package srv
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/conj"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"sort"
+)
+
+const maxConjugationLen = 60
+
+// ConjugationService handles business logic for the conjugation tables of verb Vocab records.
+type ConjugationService struct {
+	repo         db.ConjugationRepository
+	vocabRepo    db.VocabRepository
+	auditService AuditService
+}
+
+// NewConjugationService creates a new instance of ConjugationService.
+func NewConjugationService() (*ConjugationService, error) {
+
+	repo, err := db.NewSqlConjugationRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	vocabRepo, err := db.NewSqlVocabRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := NewAuditService()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConjugationService{repo: repo, vocabRepo: vocabRepo, auditService: *auditService}, nil
+}
+
+// FindConjugations retrieves the conjugation table of a vocab in table order, tense by tense,
+// see conj.Tenses and conj.Persons.
+//
+// Parameters:
+// - vocabID: The primary ID of the Vocab record.
+//
+// Returns:
+// - The conjugated forms, empty when none have been generated.
+// - An error if the conjugations cannot be read.
+func (s *ConjugationService) FindConjugations(vocabID int) ([]mdl.Conjugation, error) {
+	found, err := s.repo.FindConjugations(vocabID)
+	if err != nil {
+		return nil, err
+	}
+
+	conjugations := *found
+	sort.SliceStable(conjugations, func(i, j int) bool {
+		return conj.Position(conjugations[i].Tense, conjugations[i].Person) <
+			conj.Position(conjugations[j].Tense, conjugations[j].Person)
+	})
+
+	return conjugations, nil
+}
+
+// PreviewConjugations conjugates an infinitive without saving anything, to check what
+// GenerateConjugations would store.
+//
+// Parameters:
+// - langCode: The learning language code of the infinitive, e.g. "es".
+// - infinitive: The infinitive to conjugate.
+//
+// Returns:
+// - The conjugated forms in table order.
+// - An error if the language has no generator or the infinitive cannot be conjugated.
+func (s *ConjugationService) PreviewConjugations(langCode string, infinitive string) ([]conj.Form, error) {
+	generator, err := conj.ForLanguage(langCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return generator.Conjugate(NormalizeText(infinitive))
+}
+
+// GenerateConjugations builds the conjugation table of a verb vocab with the generator of its
+// learning language and saves it. The infinitive is the vocab Infinitive, or the learning
+// lang of a vocab whose part of speech is verb. Forms corrected by hand are kept unless the
+// table is reset. The change is audited when anything differs from the saved table.
+//
+// Parameters:
+// - vocabID: The primary ID of the Vocab record.
+// - reset: When set, forms corrected by hand are replaced by the generated ones.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - The saved conjugation table in table order.
+// - An error if the vocab cannot be found, is not a verb, cannot be conjugated, or saving fails.
+//
+// Usage example:
+// conjugations, err := conjugationService.GenerateConjugations(123, false, srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to generate conjugations: %v", err)
+//	}
+func (s *ConjugationService) GenerateConjugations(vocabID int, reset bool, actor string) ([]mdl.Conjugation, error) {
+
+	vocab, err := s.vocabRepo.FindVocabByID(vocabID)
+	if err != nil {
+		return nil, err
+	}
+
+	infinitive, err := conjugatedInfinitive(vocab)
+	if err != nil {
+		return nil, err
+	}
+
+	forms, err := s.PreviewConjugations(vocab.LearningLangCode, infinitive)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := s.FindConjugations(vocabID)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]mdl.Conjugation)
+	for _, c := range before {
+		existing[conjugationKey(c.Tense, c.Person)] = c
+	}
+
+	var after, changed []mdl.Conjugation
+	for _, form := range forms {
+		c, found := existing[conjugationKey(form.Tense, form.Person)]
+		if !found {
+			c = mdl.Conjugation{VocabID: vocabID, Tense: form.Tense, Person: form.Person}
+		}
+		if c.Corrected && !reset {
+			after = append(after, c)
+			continue
+		}
+
+		updated := c
+		updated.Form, updated.Irregular, updated.Corrected, updated.CreatedBy = form.Form, form.Irregular, false, actor
+		if !found || updated != c {
+			changed = append(changed, updated)
+		}
+		after = append(after, updated)
+	}
+
+	if len(changed) == 0 {
+		return before, nil
+	}
+
+	if err = s.repo.SaveConjugations(changed); err != nil {
+		return nil, err
+	}
+
+	// Copy the IDs of the new forms into the table.
+	saved := make(map[string]mdl.Conjugation)
+	for _, c := range changed {
+		saved[conjugationKey(c.Tense, c.Person)] = c
+	}
+	for i, c := range after {
+		if found, ok := saved[conjugationKey(c.Tense, c.Person)]; ok {
+			after[i] = found
+		}
+	}
+
+	comments := fmt.Sprintf("generated conjugations of %s", infinitive)
+	if reset {
+		comments = fmt.Sprintf("reset conjugations of %s", infinitive)
+	}
+	err = s.auditService.CreateAudit("conjugation", vocabID, comments, actor, mdl.ConjugationsJSON(before), mdl.ConjugationsJSON(after))
+
+	return after, err
+}
+
+// CorrectConjugation sets a form of a vocab conjugation table by hand. A corrected form is
+// kept when the table is generated again, unless it is reset. The change is audited.
+//
+// Parameters:
+// - vocabID: The primary ID of the Vocab record.
+// - tense: The tense of the form, one of conj.Tenses.
+// - person: The person of the form, one of conj.Persons.
+// - form: The corrected conjugated text.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the saved mdl.Conjugation.
+// - An error if the vocab cannot be found, the tense, person or form is invalid, nothing
+// changes, or saving fails.
+//
+// Usage example:
+// conjugation, err := conjugationService.CorrectConjugation(123, "present", "1sg", "quepo", srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to correct conjugation: %v", err)
+//	}
+func (s *ConjugationService) CorrectConjugation(vocabID int, tense string, person string, form string, actor string) (*mdl.Conjugation, error) {
+
+	errs := &ValidationError{}
+	if !conj.IsTense(tense) {
+		errs.add("tense", invalidField("", RuleOneOf, 0, "tense %s is not one of the conjugated tenses", tense))
+	}
+	if !conj.IsPerson(person) {
+		errs.add("person", invalidField("", RuleOneOf, 0, "person %s is not one of the conjugated persons", person))
+	}
+
+	form = NormalizeText(form)
+	if len(form) == 0 {
+		errs.add("form", invalidField("", RuleRequired, 0, "conjugation form is required"))
+	} else {
+		errs.add("form", validateFieldContent(form, "Conjugation", maxConjugationLen))
+	}
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.vocabRepo.FindVocabByID(vocabID); err != nil {
+		return nil, err
+	}
+
+	before, err := s.FindConjugations(vocabID)
+	if err != nil {
+		return nil, err
+	}
+
+	after := append([]mdl.Conjugation(nil), before...)
+	index := -1
+	for i, c := range after {
+		if c.Tense == tense && c.Person == person {
+			index = i
+		}
+	}
+	if index < 0 {
+		after = append(after, mdl.Conjugation{VocabID: vocabID, Tense: tense, Person: person})
+		index = len(after) - 1
+	}
+
+	corrected := &after[index]
+	if corrected.Corrected && corrected.Form == form {
+		return nil, fmt.Errorf("conjugation %s %s of vocab %d is already %s", tense, person, vocabID, form)
+	}
+	corrected.Form, corrected.Corrected, corrected.CreatedBy = form, true, actor
+
+	if err = s.repo.SaveConjugations(after[index : index+1]); err != nil {
+		return nil, err
+	}
+
+	comments := fmt.Sprintf("corrected conjugation %s %s", tense, person)
+	err = s.auditService.CreateAudit("conjugation", vocabID, comments, actor, mdl.ConjugationsJSON(before), mdl.ConjugationsJSON(after))
+	if err != nil {
+		return nil, err
+	}
+
+	return corrected, nil
+}
+
+// conjugatedInfinitive returns the infinitive a vocab is conjugated from.
+func conjugatedInfinitive(vocab *mdl.Vocab) (string, error) {
+	if len(vocab.Infinitive) > 0 {
+		return vocab.Infinitive, nil
+	}
+	if vocab.Pos == "verb" {
+		return vocab.LearningLang, nil
+	}
+	return "", fmt.Errorf("vocab %d is not a verb with an infinitive", vocab.ID)
+}
+
+func conjugationKey(tense string, person string) string {
+	return tense + "/" + person
+}
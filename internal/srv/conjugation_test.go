@@ -0,0 +1,132 @@
+package srv
+
+import (
+	"github.com/heather92115/verdure-admin/internal/db/mock"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"strings"
+	"testing"
+)
+
+func TestConjugationService_GenerateConjugations(t *testing.T) {
+	conjugationService := createMockConjugationService()
+
+	for _, v := range []mdl.Vocab{
+		{LearningLang: "tener", FirstLang: "to have", Pos: "verb", LearningLangCode: "es", KnownLangCode: "en"},
+		{LearningLang: "perro", FirstLang: "dog", Pos: "noun", LearningLangCode: "es", KnownLangCode: "en"},
+		{LearningLang: "to have", FirstLang: "tener", Pos: "verb", LearningLangCode: "en", KnownLangCode: "es"},
+		{LearningLang: "tengo", FirstLang: "I have", Infinitive: "tener", LearningLangCode: "es", KnownLangCode: "en"},
+	} {
+		vocab := v
+		_ = conjugationService.vocabRepo.CreateVocab(&vocab, nil)
+	}
+
+	tests := []struct {
+		name    string
+		vocabID int
+		wantErr bool
+	}{
+		{name: "Unknown vocab", vocabID: 999, wantErr: true},
+		{name: "Not a verb", vocabID: 2, wantErr: true},
+		{name: "No generator", vocabID: 3, wantErr: true},
+		{name: "Verb", vocabID: 1},
+		{name: "Conjugated form with infinitive", vocabID: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conjugations, err := conjugationService.GenerateConjugations(tt.vocabID, false, "sys")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateConjugations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(conjugations) != 42 || conjugations[0].Form != "tengo" || !conjugations[0].Irregular {
+				t.Errorf("GenerateConjugations() = %d forms, first %+v", len(conjugations), conjugations[0])
+			}
+		})
+	}
+
+	// Generating again changes nothing, so it is not audited again.
+	if _, err := conjugationService.GenerateConjugations(1, false, "sys"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	audits, _ := conjugationService.auditService.FindAudits("conjugation", 1, nil, 0)
+	if len(*audits) != 1 {
+		t.Errorf("Expected 1 conjugation audit, got %d", len(*audits))
+	}
+}
+
+func TestConjugationService_CorrectConjugation(t *testing.T) {
+	conjugationService := createMockConjugationService()
+
+	_ = conjugationService.vocabRepo.CreateVocab(&mdl.Vocab{LearningLang: "satisfacer", FirstLang: "to satisfy", Pos: "verb",
+		LearningLangCode: "es", KnownLangCode: "en"}, nil)
+	if _, err := conjugationService.GenerateConjugations(1, false, "sys"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		tense   string
+		person  string
+		form    string
+		wantErr bool
+		errMsg  string
+	}{
+		{name: "Unknown tense", tense: "pluperfect", person: "1sg", form: "x", wantErr: true,
+			errMsg: "tense pluperfect is not one of the conjugated tenses"},
+		{name: "Unknown person", tense: "present", person: "4sg", form: "x", wantErr: true,
+			errMsg: "person 4sg is not one of the conjugated persons"},
+		{name: "Form required", tense: "present", person: "1sg", form: " ", wantErr: true,
+			errMsg: "conjugation form is required"},
+		{name: "Corrected", tense: "present", person: "1sg", form: "satisfago"},
+		{name: "Unchanged", tense: "present", person: "1sg", form: "satisfago", wantErr: true,
+			errMsg: "conjugation present 1sg of vocab 1 is already satisfago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			corrected, err := conjugationService.CorrectConjugation(1, tt.tense, tt.person, tt.form, "sys")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CorrectConjugation() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil && err.Error() != tt.errMsg {
+				t.Errorf("CorrectConjugation() error = %v, wantErrMsg %v", err, tt.errMsg)
+			} else if err == nil && (!corrected.Corrected || corrected.Form != tt.form) {
+				t.Errorf("CorrectConjugation() = %+v", corrected)
+			}
+		})
+	}
+
+	kept, _ := conjugationService.GenerateConjugations(1, false, "sys")
+	if kept[0].Form != "satisfago" {
+		t.Errorf("GenerateConjugations() replaced the corrected form with %s", kept[0].Form)
+	}
+
+	// The generator follows the pattern of conocer, which is why the form needed correcting.
+	reset, _ := conjugationService.GenerateConjugations(1, true, "sys")
+	if reset[0].Form != "satisfazco" || reset[0].Corrected {
+		t.Errorf("GenerateConjugations() with reset kept %+v", reset[0])
+	}
+
+	audits, _ := conjugationService.auditService.FindAudits("conjugation", 1, nil, 0)
+	if len(*audits) != 3 {
+		t.Fatalf("Expected generate, correct and reset audits, got %d", len(*audits))
+	}
+
+	// Newest first, the reset then the correction.
+	for _, audit := range (*audits)[:2] {
+		if !strings.Contains(audit.Diff, `"key":"'present.1sg.form'"`) || !strings.Contains(audit.Diff, `"satisfago"`) {
+			t.Errorf("Expected the %s audit diff to name the present 1sg form, got %s", audit.Comments, audit.Diff)
+		}
+	}
+}
+
+func createMockConjugationService() ConjugationService {
+	auditRepo := mock.NewMockAuditRepository()
+	return ConjugationService{
+		repo:         mock.NewMockConjugationRepository(),
+		vocabRepo:    mock.NewMockVocabRepository(auditRepo, mock.NewMockAlternativeRepository()),
+		auditService: AuditService{repo: auditRepo},
+	}
+}
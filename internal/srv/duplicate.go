@@ -0,0 +1,244 @@
+package srv
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"golang.org/x/text/unicode/norm"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DuplicateVocabError is returned by CreateVocab when existing vocab are near duplicates of
+// the new one. The vocab can still be created by calling CreateVocab with force.
+type DuplicateVocabError struct {
+	LearningLang string
+	CandidateIDs []int
+}
+
+func (e *DuplicateVocabError) Error() string {
+	return fmt.Sprintf("vocab with learning lang %s may duplicate vocab %s, use force to create it anyway",
+		e.LearningLang, joinIDs(e.CandidateIDs))
+}
+
+// DuplicateCluster groups Vocab records that are near duplicates of each other.
+type DuplicateCluster struct {
+	Key    string
+	Vocabs []mdl.Vocab
+}
+
+// DuplicateKey reduces a learning lang to the form compared when looking for near duplicates.
+// The text is normalized, lower cased and stripped of accents, punctuation is dropped, and a
+// leading article of the learning language, see mdl.Language, is removed when other words follow it.
+//
+// Parameters:
+// - text: The learning lang text.
+// - langCode: The learning language code, e.g. "es".
+//
+// Returns:
+// - The comparison key.
+//
+// Usage example:
+// key := DuplicateKey("¡El Perró!", "es") // "perro"
+func DuplicateKey(text string, langCode string) string {
+	words := TokenizeLearningLang(strings.ToLower(NormalizeText(text)), langCode)
+	if language := lookupLanguage(langCode); language != nil && len(words) > 1 && language.articles[words[0]] {
+		words = words[1:]
+	}
+
+	return foldAccents(strings.Join(words, " "))
+}
+
+// foldAccents removes the combining marks left after decomposing the text, so "hablár" becomes "hablar".
+func foldAccents(text string) string {
+	return norm.NFC.String(strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, norm.NFD.String(text)))
+}
+
+// DamerauDistance returns the optimal string alignment distance between two strings, the
+// number of rune insertions, deletions, substitutions and adjacent transpositions needed to
+// turn one into the other.
+func DamerauDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// Three rows are enough since a transposition only looks two rows back.
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(rb)]
+}
+
+// maxDuplicateDistance is the largest edit distance at which two keys are near duplicates.
+// Short keys must match exactly, since a single edit turns many short words into others.
+func maxDuplicateDistance(a string, b string) int {
+	shortest := min(len([]rune(a)), len([]rune(b)))
+	switch {
+	case shortest <= 4:
+		return 0
+	case shortest <= 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// isNearDuplicate reports whether two duplicate keys are close enough to be near duplicates.
+func isNearDuplicate(a string, b string) bool {
+	limit := maxDuplicateDistance(a, b)
+	if abs(len([]rune(a))-len([]rune(b))) > limit {
+		return false
+	}
+	return DamerauDistance(a, b) <= limit
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// FindDuplicateCandidates scans the Vocab records sharing the learning language code of the
+// given vocab and returns those that are near duplicates of it, see DuplicateKey.
+//
+// Parameters:
+// - vocab: The vocab to check, its own record is skipped when it has an ID.
+//
+// Returns:
+// - The near duplicate vocab, in primary key order.
+// - An error if the scan fails.
+//
+// Usage example:
+// candidates, err := vocabService.FindDuplicateCandidates(&vocab)
+//
+//	if err != nil {
+//	    log.Printf("Failed to find duplicate candidates: %v", err)
+//	}
+func (s *VocabService) FindDuplicateCandidates(vocab *mdl.Vocab) (candidates []mdl.Vocab, err error) {
+
+	key := DuplicateKey(vocab.LearningLang, vocab.LearningLangCode)
+
+	err = s.repo.ScanVocabs(vocab.LearningLangCode, scanBatchSize, func(batch *[]mdl.Vocab) error {
+		for _, existing := range *batch {
+			if existing.ID == vocab.ID {
+				continue
+			}
+			if isNearDuplicate(key, DuplicateKey(existing.LearningLang, existing.LearningLangCode)) {
+				candidates = append(candidates, existing)
+			}
+		}
+		return nil
+	})
+
+	return
+}
+
+// FindDuplicateClusters scans every Vocab record for the learning language code and groups
+// the near duplicates into clusters for cleanup. Near duplicates are linked transitively, so
+// a cluster may hold records that are only near duplicates through another member.
+//
+// Parameters:
+// - learningCode: The learning language code to scan.
+//
+// Returns:
+// - The clusters of two or more vocab, ordered by the primary ID of their first vocab.
+// - An error if the scan fails.
+//
+// Usage example:
+// clusters, err := vocabService.FindDuplicateClusters("es")
+//
+//	if err != nil {
+//	    log.Printf("Failed to find duplicate clusters: %v", err)
+//	}
+func (s *VocabService) FindDuplicateClusters(learningCode string) (clusters []DuplicateCluster, err error) {
+
+	var vocabs []mdl.Vocab
+	var keys []string
+
+	err = s.repo.ScanVocabs(learningCode, scanBatchSize, func(batch *[]mdl.Vocab) error {
+		for _, vocab := range *batch {
+			vocabs = append(vocabs, vocab)
+			keys = append(keys, DuplicateKey(vocab.LearningLang, vocab.LearningLangCode))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Comparing keys in length order lets the inner loop stop once lengths differ too much.
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len([]rune(keys[order[i]])) < len([]rune(keys[order[j]]))
+	})
+
+	parent := make([]int, len(vocabs))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for x, i := range order {
+		for _, j := range order[x+1:] {
+			if len([]rune(keys[j]))-len([]rune(keys[i])) > 2 {
+				break
+			}
+			if isNearDuplicate(keys[i], keys[j]) {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	// The scan returns vocab in primary key order, so grouping in that order keeps clusters sorted.
+	index := make(map[int]int)
+	for i, vocab := range vocabs {
+		root := find(i)
+		if c, exists := index[root]; exists {
+			clusters[c].Vocabs = append(clusters[c].Vocabs, vocab)
+			continue
+		}
+		index[root] = len(clusters)
+		clusters = append(clusters, DuplicateCluster{Key: keys[root], Vocabs: []mdl.Vocab{vocab}})
+	}
+
+	found := clusters[:0]
+	for _, cluster := range clusters {
+		if len(cluster.Vocabs) > 1 {
+			found = append(found, cluster)
+		}
+	}
+
+	return found, nil
+}
@@ -0,0 +1,110 @@
+package srv
+
+import (
+	"errors"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"reflect"
+	"testing"
+)
+
+func TestDuplicateKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		langCode string
+		want     string
+	}{
+		{name: "Plain word", text: "perro", langCode: "es", want: "perro"},
+		{name: "Spanish article", text: "el perro", langCode: "es", want: "perro"},
+		{name: "Accent folded", text: "hablár", langCode: "es", want: "hablar"},
+		{name: "Case and punctuation", text: "¡El Perro!", langCode: "es", want: "perro"},
+		{name: "Lone article kept", text: "la", langCode: "es", want: "la"},
+		{name: "French elided article", text: "l'homme", langCode: "fr", want: "homme"},
+		{name: "Article of another language kept", text: "the dog", langCode: "es", want: "the dog"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DuplicateKey(tt.text, tt.langCode); got != tt.want {
+				t.Errorf("DuplicateKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDamerauDistance(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{a: "perro", b: "perro", want: 0},
+		{a: "perro", b: "perros", want: 1},
+		{a: "perro", b: "pero", want: 1},
+		{a: "hablar", b: "hbalar", want: 1},
+		{a: "año", b: "ano", want: 1},
+		{a: "", b: "gato", want: 4},
+		{a: "gato", b: "perro", want: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			if got := DamerauDistance(tt.a, tt.b); got != tt.want {
+				t.Errorf("DamerauDistance() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVocabService_CreateVocabNearDuplicate(t *testing.T) {
+	vocabService := createMockVocabService()
+
+	_ = vocabService.CreateVocab(&mdl.Vocab{LearningLang: "perro", FirstLang: "dog", LearningLangCode: "es", KnownLangCode: "en"}, false, "sys")
+	_ = vocabService.CreateVocab(&mdl.Vocab{LearningLang: "hablar", FirstLang: "to speak", LearningLangCode: "es", KnownLangCode: "en"}, false, "sys")
+
+	err := vocabService.CreateVocab(&mdl.Vocab{LearningLang: "el perro", FirstLang: "the dog", LearningLangCode: "es", KnownLangCode: "en"}, false, "sys")
+	var duplicate *DuplicateVocabError
+	if !errors.As(err, &duplicate) {
+		t.Fatalf("CreateVocab() error = %v, want a DuplicateVocabError", err)
+	}
+	if !reflect.DeepEqual(duplicate.CandidateIDs, []int{1}) {
+		t.Errorf("CreateVocab() candidates = %v, want [1]", duplicate.CandidateIDs)
+	}
+
+	err = vocabService.CreateVocab(&mdl.Vocab{LearningLang: "el perro", FirstLang: "the dog", LearningLangCode: "es", KnownLangCode: "en"}, true, "sys")
+	if err != nil {
+		t.Errorf("CreateVocab() with force error = %v", err)
+	}
+
+	err = vocabService.CreateVocab(&mdl.Vocab{LearningLang: "gato", FirstLang: "cat", LearningLangCode: "es", KnownLangCode: "en"}, false, "sys")
+	if err != nil {
+		t.Errorf("CreateVocab() of a distinct vocab error = %v", err)
+	}
+}
+
+func TestVocabService_FindDuplicateClusters(t *testing.T) {
+	vocabService := createMockVocabService()
+
+	for _, learningLang := range []string{"perro", "hablar", "el perro", "gato", "hablár", "perros"} {
+		_ = vocabService.repo.CreateVocab(&mdl.Vocab{LearningLang: learningLang, LearningLangCode: "es", KnownLangCode: "en"}, nil)
+	}
+
+	clusters, err := vocabService.FindDuplicateClusters("es")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var got [][]int
+	for _, cluster := range clusters {
+		var ids []int
+		for _, vocab := range cluster.Vocabs {
+			ids = append(ids, vocab.ID)
+		}
+		got = append(got, ids)
+	}
+
+	want := [][]int{{1, 3, 6}, {2, 5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDuplicateClusters() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,143 @@
+package srv
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/heather92115/verdure-admin/internal/event"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"log"
+)
+
+// The topics the services publish their changes on, see event.Bus.
+const (
+	TopicVocabChanged = "vocab_changed"
+	TopicFixitChanged = "fixit_changed"
+	TopicAuditCreated = "audit_created"
+)
+
+// vocabEvent is the payload of a vocab change, the vocab along with its alternatives which
+// the vocab JSON leaves out.
+type vocabEvent struct {
+	mdl.Vocab
+	AlternativeList []mdl.VocabAlternative `json:"alternative_list"`
+}
+
+// publish publishes a change once it is saved. A change that cannot be published is logged,
+// it is saved and audited either way.
+func publish(topic string, value interface{}) {
+	if err := event.Default().Publish(topic, value); err != nil {
+		log.Printf("Failed to publish %s: %v", topic, err)
+	}
+}
+
+// publishVocabChanged publishes a created or changed vocab on TopicVocabChanged.
+func publishVocabChanged(vocab *mdl.Vocab) {
+	publish(TopicVocabChanged, vocabEvent{Vocab: *vocab, AlternativeList: vocab.AlternativeList})
+}
+
+// publishFixitChanged publishes a created or changed fixit on TopicFixitChanged.
+func publishFixitChanged(fixit *mdl.Fixit) {
+	publish(TopicFixitChanged, fixit)
+}
+
+// publishAuditCreated publishes a saved audit on TopicAuditCreated.
+func publishAuditCreated(audit *mdl.Audit) {
+	publish(TopicAuditCreated, audit)
+}
+
+// subscribe decodes the events of the topic and sends those the filter keeps until the context
+// is done, when the returned channel is closed. Events that cannot be decoded are logged and skipped.
+func subscribe[T any](ctx context.Context, topic string, keep func(value *T) bool) <-chan *T {
+	events := event.Default().Subscribe(ctx, topic)
+	values := make(chan *T, 1)
+
+	go func() {
+		defer close(values)
+		for received := range events {
+			value := new(T)
+			if err := json.Unmarshal(received.Payload, value); err != nil {
+				log.Printf("Ignoring malformed %s event: %v", topic, err)
+				continue
+			}
+			if !keep(value) {
+				continue
+			}
+			select {
+			case values <- value:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return values
+}
+
+// SubscribeVocabChanges returns the vocab created or changed until the context is done.
+//
+// Parameters:
+// - ctx: The context of the subscriber.
+// - learningCode: Optional. Only the vocab of this learning language code, or of its regional
+// variants, are returned.
+//
+// Returns:
+// - The channel of changed vocab, closed when the context is done.
+//
+// Usage example:
+// changes := srv.SubscribeVocabChanges(ctx, "es")
+//
+//	for vocab := range changes {
+//	    log.Printf("Vocab %d changed", vocab.ID)
+//	}
+func SubscribeVocabChanges(ctx context.Context, learningCode string) <-chan *mdl.Vocab {
+	learningCode = CanonicalLangCode(learningCode)
+
+	changes := subscribe(ctx, TopicVocabChanged, func(changed *vocabEvent) bool {
+		return len(learningCode) == 0 || changed.LearningLangCode == learningCode ||
+			baseLangCode(changed.LearningLangCode) == learningCode
+	})
+
+	vocabs := make(chan *mdl.Vocab)
+	go func() {
+		defer close(vocabs)
+		for changed := range changes {
+			vocab := changed.Vocab
+			vocab.AlternativeList = changed.AlternativeList
+			select {
+			case vocabs <- &vocab:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return vocabs
+}
+
+// SubscribeFixitChanges returns the fixits created or changed until the context is done.
+//
+// Parameters:
+// - ctx: The context of the subscriber.
+// - status: Optional. Only the fixits with this status after the change are returned.
+//
+// Returns:
+// - The channel of changed fixits, closed when the context is done.
+func SubscribeFixitChanges(ctx context.Context, status mdl.StatusType) <-chan *mdl.Fixit {
+	return subscribe(ctx, TopicFixitChanged, func(fixit *mdl.Fixit) bool {
+		return len(status) == 0 || fixit.Status == status
+	})
+}
+
+// SubscribeAuditsCreated returns the audits created until the context is done.
+//
+// Parameters:
+// - ctx: The context of the subscriber.
+// - tableName: Optional. Only the audits of this table, such as "vocab", are returned.
+//
+// Returns:
+// - The channel of created audits, closed when the context is done.
+func SubscribeAuditsCreated(ctx context.Context, tableName string) <-chan *mdl.Audit {
+	return subscribe(ctx, TopicAuditCreated, func(audit *mdl.Audit) bool {
+		return len(tableName) == 0 || audit.TableName == tableName
+	})
+}
@@ -0,0 +1,103 @@
+package srv
+
+import (
+	"context"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"testing"
+	"time"
+)
+
+// receiveNext returns the next value of a subscription, failing the test when none arrives.
+func receiveNext[T any](t *testing.T, values <-chan *T) *T {
+	t.Helper()
+	select {
+	case value := <-values:
+		return value
+	case <-time.After(time.Second):
+		t.Fatalf("no change received")
+		return nil
+	}
+}
+
+// expectNone fails the test when the subscription has a value.
+func expectNone[T any](t *testing.T, values <-chan *T) {
+	t.Helper()
+	select {
+	case value := <-values:
+		t.Errorf("unexpected change received, %+v", value)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSubscribeVocabChanges(t *testing.T) {
+	vocabService := createMockVocabService()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	spanish := SubscribeVocabChanges(ctx, "es")
+	french := SubscribeVocabChanges(ctx, "fr")
+	audits := SubscribeAuditsCreated(ctx, "vocab")
+
+	vocab := &mdl.Vocab{LearningLang: "el gato", FirstLang: "the cat", LearningLangCode: "es-419",
+		AlternativeList: []mdl.VocabAlternative{{Alternative: "la gata", Notes: "feminine"}}}
+	if err := vocabService.CreateVocab(vocab, false, "sys"); err != nil {
+		t.Fatalf("CreateVocab() error = %v", err)
+	}
+
+	changed := receiveNext(t, spanish)
+	if changed.ID != vocab.ID || changed.LearningLang != "el gato" || len(changed.AlternativeList) != 1 ||
+		changed.AlternativeList[0].Notes != "feminine" {
+		t.Errorf("SubscribeVocabChanges() = %+v", changed)
+	}
+	expectNone(t, french)
+
+	audit := receiveNext(t, audits)
+	if audit.TableName != "vocab" || audit.ObjectID != vocab.ID || audit.Comments != "created vocab" {
+		t.Errorf("SubscribeAuditsCreated() = %+v", audit)
+	}
+
+	hint := "a pet"
+	if _, err := vocabService.UpdateVocab(&mdl.VocabPatch{ID: vocab.ID, Hint: &hint}, "sys"); err != nil {
+		t.Fatalf("UpdateVocab() error = %v", err)
+	}
+	if changed = receiveNext(t, spanish); changed.Hint != "a pet" {
+		t.Errorf("SubscribeVocabChanges() after update = %+v", changed)
+	}
+
+	// A change that fails validation is not published
+	if err := vocabService.CreateVocab(&mdl.Vocab{FirstLang: "the dog"}, false, "sys"); err == nil {
+		t.Fatalf("CreateVocab() expected an error")
+	}
+	expectNone(t, spanish)
+
+	cancel()
+	for range spanish {
+	}
+}
+
+func TestSubscribeFixitChanges(t *testing.T) {
+	fixitService := createMockFixitService()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	completed := SubscribeFixitChanges(ctx, mdl.Completed)
+	all := SubscribeFixitChanges(ctx, "")
+
+	fixit := &mdl.Fixit{VocabID: 101, Status: mdl.Pending, FieldName: "hint", Comments: "add a hint", CreatedBy: "tester"}
+	if err := fixitService.CreateFixit(fixit, "sys"); err != nil {
+		t.Fatalf("CreateFixit() error = %v", err)
+	}
+	if changed := receiveNext(t, all); changed.ID != fixit.ID || changed.Status != mdl.Pending {
+		t.Errorf("SubscribeFixitChanges() = %+v", changed)
+	}
+	expectNone(t, completed)
+
+	status := mdl.Completed
+	if _, err := fixitService.UpdateFixit(&mdl.FixitPatch{ID: fixit.ID, Status: &status}, "sys"); err != nil {
+		t.Fatalf("UpdateFixit() error = %v", err)
+	}
+	if changed := receiveNext(t, completed); changed.ID != fixit.ID || changed.Status != mdl.Completed {
+		t.Errorf("SubscribeFixitChanges(completed) = %+v", changed)
+	}
+	receiveNext(t, all)
+}
@@ -0,0 +1,317 @@
+package srv
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"sort"
+	"strings"
+)
+
+const (
+	maxSentenceLen    = 500
+	maxTranslationLen = 500
+	maxSourceLen      = 255
+)
+
+// ExampleService handles business logic for the example sentences of Vocab records.
+type ExampleService struct {
+	repo            db.ExampleRepository
+	vocabRepo       db.VocabRepository
+	conjugationRepo db.ConjugationRepository
+	auditService    AuditService
+}
+
+// NewExampleService creates a new instance of ExampleService.
+func NewExampleService() (*ExampleService, error) {
+
+	repo, err := db.NewSqlExampleRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	vocabRepo, err := db.NewSqlVocabRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	conjugationRepo, err := db.NewSqlConjugationRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := NewAuditService()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = loadManagedLanguages(); err != nil {
+		return nil, err
+	}
+
+	return &ExampleService{repo: repo, vocabRepo: vocabRepo, conjugationRepo: conjugationRepo, auditService: *auditService}, nil
+}
+
+// FindExampleSentenceByID retrieves an example sentence by its primary ID, with its tokens attached.
+func (s *ExampleService) FindExampleSentenceByID(id int) (*mdl.ExampleSentence, error) {
+	example, err := s.repo.FindExampleSentenceByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	vocab, err := s.vocabRepo.FindVocabByID(example.VocabID)
+	if err != nil {
+		return nil, err
+	}
+	example.Tokens = TokenizeLearningLang(example.Sentence, vocab.LearningLangCode)
+
+	return example, nil
+}
+
+// FindExampleSentences retrieves the example sentences of a vocab, in the order they were
+// added, with their tokens attached.
+func (s *ExampleService) FindExampleSentences(vocabID int) ([]mdl.ExampleSentence, error) {
+	vocab, err := s.vocabRepo.FindVocabByID(vocabID)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := s.repo.FindExampleSentences(vocabID)
+	if err != nil {
+		return nil, err
+	}
+
+	examples := *found
+	for i := range examples {
+		examples[i].Tokens = TokenizeLearningLang(examples[i].Sentence, vocab.LearningLangCode)
+	}
+
+	return examples, nil
+}
+
+// CreateExampleSentence adds an example sentence to a vocab. The sentence must contain the
+// vocab, as its learning lang, one of its alternatives, its plural or one of its conjugated
+// forms, and the tokens that match are stored as the sentence highlights. The new sentence
+// is audited.
+//
+// Parameters:
+// - example: A pointer to the mdl.ExampleSentence to add, its ID and highlights are set on success.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - An error if the vocab cannot be found, validation fails, the sentence does not contain
+// the vocab, or saving fails.
+//
+// Usage example:
+// err := exampleService.CreateExampleSentence(&mdl.ExampleSentence{VocabID: 123, Sentence: "Tengo un perro.", Translation: "I have a dog."}, srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to create example sentence: %v", err)
+//	}
+func (s *ExampleService) CreateExampleSentence(example *mdl.ExampleSentence, actor string) (err error) {
+
+	normalizeExample(example)
+	example.CreatedBy = actor
+
+	if err = s.highlightExample(example); err != nil {
+		return
+	}
+
+	var audits auditTrail
+	err = s.repo.CreateExampleSentence(example, audits.of(func() (*mdl.Audit, error) {
+		return buildAudit("example_sentence", example.ID, "created example sentence", actor, "", example.JSON())
+	}))
+	if err != nil {
+		return
+	}
+
+	audits.publish()
+	return
+}
+
+// UpdateExampleSentence applies a partial update to an existing example sentence. The
+// sentence is checked again and its highlights found again before it is saved and audited.
+//
+// Parameters:
+// - patch: A pointer to the mdl.ExampleSentencePatch with the sentence ID and the fields to change.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the updated mdl.ExampleSentence.
+// - An error if the sentence cannot be found, the patch holds no changes, validation fails,
+// the sentence no longer contains the vocab, or saving fails.
+//
+// Usage example:
+// translation := "I have a small dog."
+// example, err := exampleService.UpdateExampleSentence(&mdl.ExampleSentencePatch{ID: 7, Translation: &translation}, srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to update example sentence: %v", err)
+//	}
+func (s *ExampleService) UpdateExampleSentence(patch *mdl.ExampleSentencePatch, actor string) (*mdl.ExampleSentence, error) {
+
+	before, err := s.repo.FindExampleSentenceByID(patch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	example := *before
+	for _, field := range []*string{patch.Sentence, patch.Translation, patch.Source} {
+		if field != nil {
+			*field = NormalizeText(*field)
+		}
+	}
+	if !patch.ApplyTo(&example) {
+		return nil, fmt.Errorf("update for example sentence %d has no changes", patch.ID)
+	}
+
+	if err = s.highlightExample(&example); err != nil {
+		return nil, err
+	}
+
+	var audits auditTrail
+	err = s.repo.UpdateExampleSentence(&example, audits.of(func() (*mdl.Audit, error) {
+		return buildAudit("example_sentence", example.ID, "updated example sentence", actor, before.JSON(), example.JSON())
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	audits.publish()
+	return &example, nil
+}
+
+// DeleteExampleSentence removes an example sentence and writes an audit entry.
+func (s *ExampleService) DeleteExampleSentence(id int, actor string) error {
+
+	before, err := s.repo.FindExampleSentenceByID(id)
+	if err != nil {
+		return err
+	}
+
+	var audits auditTrail
+	err = s.repo.DeleteExampleSentence(id, audits.of(func() (*mdl.Audit, error) {
+		return buildAudit("example_sentence", id, "deleted example sentence", actor, before.JSON(), "")
+	}))
+	if err != nil {
+		return err
+	}
+
+	audits.publish()
+	return nil
+}
+
+// highlightExample validates an example sentence, applies the rules of its learning language to
+// it, and sets its tokens and its highlights, the tokens that match the forms of its vocab.
+func (s *ExampleService) highlightExample(example *mdl.ExampleSentence) error {
+
+	if err := validateExample(example); err != nil {
+		return err
+	}
+
+	vocab, err := s.vocabRepo.FindVocabByID(example.VocabID)
+	if err != nil {
+		return err
+	}
+
+	// The sentence is written in the learning language of the vocab, so it takes its rules too
+	example.Sentence = NormalizeLearningText(example.Sentence, vocab.LearningLangCode)
+
+	conjugations, err := s.conjugationRepo.FindConjugations(vocab.ID)
+	if err != nil {
+		return err
+	}
+
+	tokens, highlights := FindVocabInSentence(example.Sentence, vocab, *conjugations)
+	if len(highlights) == 0 {
+		return invalidField("sentence", RuleInvalid, 0, "example sentence does not contain %s or one of its forms", vocab.LearningLang)
+	}
+	example.Tokens = tokens
+	example.SetHighlightList(highlights)
+
+	return nil
+}
+
+// FindVocabInSentence finds the tokens of a sentence that are a vocab, its learning lang,
+// one of its alternatives, its plural or one of its conjugated forms. Sentences are split into
+// tokens with TokenizeLearningLang, forms of more than one word match consecutive tokens, and
+// matching ignores case.
+//
+// Parameters:
+// - sentence: The sentence in the learning language of the vocab.
+// - vocab: The vocab to find, its Alternatives are used for the alternatives.
+// - conjugations: The conjugated forms of the vocab, if any.
+//
+// Returns:
+// - The tokens of the sentence.
+// - The positions of the matching tokens in ascending order, empty when the vocab is not found.
+//
+// Usage example:
+// tokens, positions := FindVocabInSentence("Tengo un perro.", &vocab, nil) // [Tengo un perro], [2]
+func FindVocabInSentence(sentence string, vocab *mdl.Vocab, conjugations []mdl.Conjugation) (tokens []string, positions []int) {
+
+	forms := []string{vocab.LearningLang, vocab.Plural}
+	forms = append(forms, ParseAlternatives(vocab.Alternatives)...)
+	for _, c := range conjugations {
+		forms = append(forms, c.Form)
+	}
+
+	tokens = TokenizeLearningLang(sentence, vocab.LearningLangCode)
+	highlighted := make(map[int]bool)
+	for _, form := range forms {
+		formTokens := TokenizeLearningLang(form, vocab.LearningLangCode)
+		if len(formTokens) == 0 {
+			continue
+		}
+		for start := 0; start+len(formTokens) <= len(tokens); start++ {
+			if matchTokens(tokens[start:start+len(formTokens)], formTokens) {
+				for i := range formTokens {
+					highlighted[start+i] = true
+				}
+			}
+		}
+	}
+
+	for position := range highlighted {
+		positions = append(positions, position)
+	}
+	sort.Ints(positions)
+
+	return
+}
+
+// matchTokens reports whether two token lists are equal ignoring case.
+func matchTokens(tokens []string, form []string) bool {
+	for i := range form {
+		if !strings.EqualFold(tokens[i], form[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeExample applies NormalizeText to the text fields of an example sentence.
+func normalizeExample(example *mdl.ExampleSentence) {
+	example.Sentence = NormalizeText(example.Sentence)
+	example.Translation = NormalizeText(example.Translation)
+	example.Source = NormalizeText(example.Source)
+}
+
+// validateExample checks the text fields of an example sentence, collecting the failures in a
+// *ValidationError.
+func validateExample(example *mdl.ExampleSentence) error {
+	errs := &ValidationError{}
+	if len(example.Sentence) == 0 {
+		errs.add("sentence", invalidField("", RuleRequired, 0, "example sentence is required"))
+	} else {
+		errs.add("sentence", validateFieldContent(example.Sentence, "Sentence", maxSentenceLen))
+	}
+	if len(example.Translation) == 0 {
+		errs.add("translation", invalidField("", RuleRequired, 0, "example sentence translation is required"))
+	} else {
+		errs.add("translation", validateFieldContent(example.Translation, "Translation", maxTranslationLen))
+	}
+	errs.add("source", validateFieldContent(example.Source, "Source", maxSourceLen))
+
+	return errs.errOrNil()
+}
@@ -0,0 +1,117 @@
+package srv
+
+import (
+	"github.com/heather92115/verdure-admin/internal/db/mock"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExampleService_CreateExampleSentence(t *testing.T) {
+	exampleService := createMockExampleService()
+
+	_ = exampleService.vocabRepo.CreateVocab(&mdl.Vocab{LearningLang: "perro", FirstLang: "dog", Pos: "noun",
+		Plural: "perros", Alternatives: "can", LearningLangCode: "es", KnownLangCode: "en"}, nil)
+	_ = exampleService.vocabRepo.CreateVocab(&mdl.Vocab{LearningLang: "lavarse", FirstLang: "to wash oneself", Pos: "verb",
+		LearningLangCode: "es", KnownLangCode: "en"}, nil)
+	_ = exampleService.conjugationRepo.SaveConjugations([]mdl.Conjugation{
+		{VocabID: 2, Tense: "present", Person: "1sg", Form: "me lavo"},
+	})
+
+	tests := []struct {
+		name           string
+		example        mdl.ExampleSentence
+		wantHighlights []int
+		wantErr        bool
+		errMsg         string
+	}{
+		{name: "Learning lang", example: mdl.ExampleSentence{VocabID: 1, Sentence: "¿Tienes un perro?", Translation: "Do you have a dog?"},
+			wantHighlights: []int{2}},
+		{name: "Plural and case", example: mdl.ExampleSentence{VocabID: 1, Sentence: "Perros y gatos.", Translation: "Dogs and cats."},
+			wantHighlights: []int{0}},
+		{name: "Alternative", example: mdl.ExampleSentence{VocabID: 1, Sentence: "Cuidado con el can.", Translation: "Beware of the dog."},
+			wantHighlights: []int{3}},
+		{name: "Conjugated form of two words", example: mdl.ExampleSentence{VocabID: 2, Sentence: "Siempre me lavo las manos.",
+			Translation: "I always wash my hands."}, wantHighlights: []int{1, 2}},
+		{name: "Missing vocab", example: mdl.ExampleSentence{VocabID: 1, Sentence: "Tengo un gato.", Translation: "I have a cat."},
+			wantErr: true, errMsg: "example sentence does not contain perro or one of its forms"},
+		{name: "Part of a word", example: mdl.ExampleSentence{VocabID: 1, Sentence: "Una perrera.", Translation: "A kennel."},
+			wantErr: true, errMsg: "example sentence does not contain perro or one of its forms"},
+		{name: "Translation required", example: mdl.ExampleSentence{VocabID: 1, Sentence: "Un perro."},
+			wantErr: true, errMsg: "example sentence translation is required"},
+		{name: "Too long", example: mdl.ExampleSentence{VocabID: 1, Sentence: strings.Repeat("perro ", 100), Translation: "dogs"},
+			wantErr: true, errMsg: "Sentence must be shorter than 500 characters"},
+		{name: "Unknown vocab", example: mdl.ExampleSentence{VocabID: 99, Sentence: "Un perro.", Translation: "A dog."},
+			wantErr: true, errMsg: "error finding vocab with id 99"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			example := tt.example
+			err := exampleService.CreateExampleSentence(&example, "sys")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateExampleSentence() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil && !strings.HasPrefix(err.Error(), tt.errMsg) {
+				t.Errorf("CreateExampleSentence() error = %v, wantErrMsg %v", err, tt.errMsg)
+			} else if err == nil && !reflect.DeepEqual(example.HighlightList(), tt.wantHighlights) {
+				t.Errorf("CreateExampleSentence() highlights = %v, want %v", example.HighlightList(), tt.wantHighlights)
+			}
+		})
+	}
+}
+
+func TestExampleService_UpdateAndDeleteExampleSentence(t *testing.T) {
+	exampleService := createMockExampleService()
+
+	_ = exampleService.vocabRepo.CreateVocab(&mdl.Vocab{LearningLang: "perro", FirstLang: "dog", Pos: "noun",
+		LearningLangCode: "es", KnownLangCode: "en"}, nil)
+	example := &mdl.ExampleSentence{VocabID: 1, Sentence: "Un perro.", Translation: "A dog."}
+	if err := exampleService.CreateExampleSentence(example, "sys"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	moved, missing, same := "Mi perro es grande.", "Mi gato es grande.", "A dog."
+
+	if _, err := exampleService.UpdateExampleSentence(&mdl.ExampleSentencePatch{ID: 1, Translation: &same}, "sys"); err == nil {
+		t.Errorf("UpdateExampleSentence() expected an error for no changes")
+	}
+	if _, err := exampleService.UpdateExampleSentence(&mdl.ExampleSentencePatch{ID: 1, Sentence: &missing}, "sys"); err == nil {
+		t.Errorf("UpdateExampleSentence() expected an error for a sentence without the vocab")
+	}
+
+	updated, err := exampleService.UpdateExampleSentence(&mdl.ExampleSentencePatch{ID: 1, Sentence: &moved}, "sys")
+	if err != nil {
+		t.Fatalf("UpdateExampleSentence() error = %v", err)
+	}
+	if updated.Highlights != "1" || updated.Translation != "A dog." {
+		t.Errorf("UpdateExampleSentence() = %+v", updated)
+	}
+
+	found, _ := exampleService.FindExampleSentences(1)
+	if len(found) != 1 || !reflect.DeepEqual(found[0].Tokens, []string{"Mi", "perro", "es", "grande"}) {
+		t.Errorf("FindExampleSentences() = %+v", found)
+	}
+
+	if err = exampleService.DeleteExampleSentence(1, "sys"); err != nil {
+		t.Fatalf("DeleteExampleSentence() error = %v", err)
+	}
+	if err = exampleService.DeleteExampleSentence(1, "sys"); err == nil {
+		t.Errorf("DeleteExampleSentence() expected an error for a deleted sentence")
+	}
+
+	audits, _ := exampleService.auditService.FindAudits("example_sentence", 1, nil, 0)
+	if len(*audits) != 3 {
+		t.Errorf("Expected create, update and delete audits, got %d", len(*audits))
+	}
+}
+
+func createMockExampleService() ExampleService {
+	auditRepo := mock.NewMockAuditRepository()
+	return ExampleService{
+		repo:            mock.NewMockExampleRepository(auditRepo),
+		vocabRepo:       mock.NewMockVocabRepository(auditRepo, mock.NewMockAlternativeRepository()),
+		conjugationRepo: mock.NewMockConjugationRepository(),
+		auditService:    AuditService{repo: auditRepo},
+	}
+}
@@ -0,0 +1,141 @@
+package srv
+
+import (
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"strconv"
+	"strings"
+)
+
+// The kinds of vocab field, see VocabField.
+const (
+	FieldKindText   = "text"
+	FieldKindNumber = "number"
+)
+
+// VocabField describes an editable field of a Vocab record. The registry of fields, see
+// VocabFields, names the fields a Fixit may point at, validates vocab content and describes
+// the vocab form to clients.
+//
+// Fields:
+//   - Name: The GraphQL name of the field, also the name a Fixit or lint finding uses, e.g. "first_lang".
+//   - Column: The vocab table column holding the field.
+//   - Label: The name of the field in validation errors, e.g. "First language".
+//   - Kind: FieldKindText or FieldKindNumber.
+//   - MaxLength: The maximum length of a text field in characters, 0 for other kinds.
+//   - Required: Whether every vocab must have a value.
+//   - Updatable: Whether updateVocab changes the field, the others have mutations of their own
+//     such as renameVocab or addAlternative, or are computed.
+//   - Values: The values the field is limited to, empty when any text is allowed.
+//   - Policy: The markup a text field may hold, PlainText unless given, see TextPolicy.
+type VocabField struct {
+	Name      string
+	Column    string
+	Label     string
+	Kind      string
+	MaxLength int
+	Required  bool
+	Updatable bool
+	Values    []string
+	Policy    TextPolicy
+	value     func(vocab *mdl.Vocab) string
+	validate  func(value string) error
+}
+
+// VocabFields is the registry of editable vocab fields, in form order.
+var VocabFields = []VocabField{
+	{Name: "learning_lang", Column: "learning_lang", Label: "Learning language", Kind: FieldKindText,
+		MaxLength: maxLearningLangLen, Required: true,
+		value: func(v *mdl.Vocab) string { return v.LearningLang }},
+	{Name: "first_lang", Column: "first_lang", Label: "First language", Kind: FieldKindText,
+		MaxLength: maxFirstLangLen, Updatable: true,
+		value: func(v *mdl.Vocab) string { return v.FirstLang }},
+	{Name: "alternatives", Column: "alternatives", Label: "Alternatives", Kind: FieldKindText,
+		MaxLength: maxAlternativesLen,
+		value:     func(v *mdl.Vocab) string { return v.Alternatives }},
+	{Name: "skill", Column: "skill", Label: "Skill", Kind: FieldKindText,
+		MaxLength: maxSkillLen, Updatable: true,
+		value: func(v *mdl.Vocab) string { return v.Skill }},
+	{Name: "infinitive", Column: "infinitive", Label: "Infinitive", Kind: FieldKindText,
+		MaxLength: maxInfinitiveLen, Updatable: true,
+		value: func(v *mdl.Vocab) string { return v.Infinitive }},
+	{Name: "pos", Column: "pos", Label: "Part of speech", Kind: FieldKindText,
+		MaxLength: maxPosLen, Updatable: true,
+		value: func(v *mdl.Vocab) string { return v.Pos }},
+	{Name: "hint", Column: "hint", Label: "Hint", Kind: FieldKindText,
+		MaxLength: maxHintLen, Updatable: true, Policy: LimitedMarkup,
+		value: func(v *mdl.Vocab) string { return v.Hint }},
+	{Name: "gender", Column: "gender", Label: "Gender", Kind: FieldKindText,
+		MaxLength: maxGenderLen, Updatable: true, Values: Genders,
+		value: func(v *mdl.Vocab) string { return v.Gender }},
+	{Name: "plural", Column: "plural", Label: "Plural", Kind: FieldKindText,
+		MaxLength: maxPluralLen, Updatable: true,
+		value: func(v *mdl.Vocab) string { return v.Plural }},
+	{Name: "article", Column: "article", Label: "Article", Kind: FieldKindText,
+		MaxLength: maxArticleLen, Updatable: true,
+		value: func(v *mdl.Vocab) string { return v.Article }},
+	{Name: "register", Column: "register", Label: "Register", Kind: FieldKindText,
+		MaxLength: maxRegisterLen, Updatable: true, Values: Registers,
+		value: func(v *mdl.Vocab) string { return v.Register }},
+	{Name: "num_learning_words", Column: "num_learning_words", Label: "Num learning words", Kind: FieldKindNumber,
+		Updatable: true,
+		value:     func(v *mdl.Vocab) string { return strconv.Itoa(v.NumLearningWords) }},
+	{Name: "known_lang_code", Column: "known_lang_code", Label: "Known language code", Kind: FieldKindText,
+		MaxLength: maxLangCodeLen, Required: true,
+		value:    func(v *mdl.Vocab) string { return v.KnownLangCode },
+		validate: validateLangCodeField("Known language code")},
+	{Name: "learning_lang_code", Column: "learning_lang_code", Label: "Learning language code", Kind: FieldKindText,
+		MaxLength: maxLangCodeLen, Required: true,
+		value:    func(v *mdl.Vocab) string { return v.LearningLangCode },
+		validate: validateLangCodeField("Learning language code")},
+}
+
+// FindVocabField returns the registered vocab field with the name, and false when there is none.
+func FindVocabField(name string) (VocabField, bool) {
+	for _, field := range VocabFields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return VocabField{}, false
+}
+
+// validateLangCodeField returns a validator requiring a BCP-47 language tag, see ValidLangCode.
+func validateLangCodeField(label string) func(value string) error {
+	return func(value string) error {
+		if !ValidLangCode(value) {
+			return invalidField("", RuleFormat, 0, errFmtStrLangCode, label)
+		}
+		return nil
+	}
+}
+
+// validateVocabField checks the value of one vocab field: its length and content under its
+// policy, that a required field has a value, that a limited field holds one of its values,
+// and the field's own validator. The first failure is returned as a *FieldError. Number fields are not
+// checked, their values are computed.
+func validateVocabField(field VocabField, vocab *mdl.Vocab) error {
+	if field.Kind != FieldKindText {
+		return nil
+	}
+
+	value := field.value(vocab)
+	if err := validateText(value, field.Label, field.MaxLength, field.Policy); err != nil {
+		return err
+	}
+
+	if len(value) == 0 {
+		if field.Required {
+			return invalidField(field.Name, RuleRequired, 0, "%s field is required", strings.ReplaceAll(field.Name, "_", " "))
+		}
+		return nil
+	}
+
+	if len(field.Values) > 0 && indexOfString(field.Values, value) < 0 {
+		return invalidField(field.Name, RuleOneOf, 0, "%s %s must be one of %s", field.Name, value, strings.Join(field.Values, ", "))
+	}
+
+	if field.validate != nil {
+		return field.validate(value)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package srv
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"strings"
+	"testing"
+)
+
+func TestVocabFields(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, field := range VocabFields {
+		if seen[field.Name] {
+			t.Errorf("field %s is registered twice", field.Name)
+		}
+		seen[field.Name] = true
+
+		if field.Kind == FieldKindText && field.MaxLength == 0 {
+			t.Errorf("text field %s has no max length", field.Name)
+		}
+		if field.value == nil {
+			t.Errorf("field %s cannot be read", field.Name)
+		}
+	}
+
+	// Lint findings name the fields they are about
+	for _, name := range []string{"learning_lang", "first_lang", "hint", "pos", "infinitive"} {
+		if _, found := FindVocabField(name); !found {
+			t.Errorf("FindVocabField(%s) not found", name)
+		}
+	}
+	if _, found := FindVocabField("definition"); found {
+		t.Errorf("FindVocabField(definition) found")
+	}
+}
+
+func TestValidateVocabUpdate(t *testing.T) {
+	valid := func() *mdl.Vocab {
+		return &mdl.Vocab{LearningLang: "perro", FirstLang: "dog", Pos: "noun", KnownLangCode: "en", LearningLangCode: "es"}
+	}
+
+	tests := []struct {
+		name   string
+		change func(vocab *mdl.Vocab)
+		errMsg string
+	}{
+		{name: "Valid vocab", change: func(vocab *mdl.Vocab) {}},
+		{name: "Hint too long", change: func(vocab *mdl.Vocab) { vocab.Hint = strings.Repeat("a", maxHintLen+1) },
+			errMsg: fmt.Sprintf(errFmtStrLen, "Hint", maxHintLen)},
+		{name: "Register too long", change: func(vocab *mdl.Vocab) { vocab.Register = strings.Repeat("a", maxRegisterLen+1) },
+			errMsg: fmt.Sprintf(errFmtStrLen, "Register", maxRegisterLen)},
+		{name: "Unknown gender", change: func(vocab *mdl.Vocab) { vocab.Gender = "male" },
+			errMsg: "gender male must be one of masculine, feminine, neuter, common"},
+		{name: "Missing learning lang code", change: func(vocab *mdl.Vocab) { vocab.LearningLangCode = "" },
+			errMsg: "learning lang code field is required"},
+		{name: "Invalid known lang code", change: func(vocab *mdl.Vocab) { vocab.KnownLangCode = "english" },
+			errMsg: fmt.Sprintf(errFmtStrLangCode, "Known language code")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vocab := valid()
+			tt.change(vocab)
+			err := validateVocabUpdate(vocab)
+			if (err != nil) != (len(tt.errMsg) > 0) || (err != nil && err.Error() != tt.errMsg) {
+				t.Errorf("validateVocabUpdate() error = %v, wantErrMsg %v", err, tt.errMsg)
+			}
+		})
+	}
+}
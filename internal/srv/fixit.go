@@ -68,61 +68,86 @@ func (s *FixitService) FindFixits(
 //
 // Parameters:
 // - fixit: A pointer to the mdl.Fixit struct to be created.
+// - actor: The user making the change, see ActorFrom.
 //
 // Returns:
 //   - An error if validation fails or if there's an error during the creation process. Returns nil if the record is successfully created.
 //
 // Usage example:
-// err := fixitService.CreateFixit(&fixit)
+// err := fixitService.CreateFixit(&fixit, srv.ActorFrom(ctx))
 //
 //	if err != nil {
 //	    log.Printf("Failed to create fixit: %v", err)
 //	}
-func (s *FixitService) CreateFixit(fixit *mdl.Fixit) (err error) {
+func (s *FixitService) CreateFixit(fixit *mdl.Fixit, actor string) (err error) {
 
-	if err = validateFixit(fixit); err != nil {
+	if err = validateFixit(fixit, nil); err != nil {
 		return
 	}
 
 	err = s.repo.CreateFixit(fixit)
+	if err != nil {
+		return
+	}
 
-	err = s.auditService.CreateFixitAudit("created fixit", "sys", nil, fixit)
+	if err = s.auditService.CreateFixitAudit("created fixit", actor, nil, fixit); err != nil {
+		return
+	}
 
+	publishFixitChanged(fixit)
 	return
 }
 
-func (s *FixitService) UpdateFixit(updating *mdl.Fixit) (fixit *mdl.Fixit, err error) {
-
-	if err = validateFixit(updating); err != nil {
-		return
-	}
+// UpdateFixit applies a partial update to an existing Fixit record. Only the fields
+// present in the patch are changed. The patched record is validated before it is
+// saved and an audit entry is written containing just the fields that changed.
+//
+// Parameters:
+// - patch: A pointer to the mdl.FixitPatch describing the record ID and the fields to change.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the updated mdl.Fixit record.
+// - An error if the record cannot be found, the patch holds no changes, or validation or saving fails.
+//
+// Usage example:
+// status := mdl.Completed
+// fixit, err := fixitService.UpdateFixit(&mdl.FixitPatch{ID: 123, Status: &status}, srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to update fixit: %v", err)
+//	}
+func (s *FixitService) UpdateFixit(patch *mdl.FixitPatch, actor string) (fixit *mdl.Fixit, err error) {
 
-	before, err := s.repo.FindFixitByID(updating.ID)
+	before, err := s.repo.FindFixitByID(patch.ID)
 	if err != nil {
 		return
 	} else if before == nil {
-		err = fmt.Errorf("expected to find existing fixit with id %d", updating.ID)
+		err = fmt.Errorf("expected to find existing fixit with id %d", patch.ID)
 		return
 	}
 
 	fixit = before.Clone()
 
 	// Update allowed to change fields
-	if fixit.Status != updating.Status || fixit.FieldName != updating.FieldName || fixit.Comments != updating.Comments {
-		fixit.Status = updating.Status
-		fixit.FieldName = updating.FieldName
-		fixit.Comments = updating.Comments
-	} else {
+	if !patch.ApplyTo(fixit) {
 		return nil, fmt.Errorf("update for fixit %d has no changes", fixit.ID)
 	}
 
+	if err = validateFixit(fixit, before); err != nil {
+		return nil, err
+	}
+
 	err = s.repo.UpdateFixit(fixit)
 	if err != nil {
 		return
 	}
 
-	err = s.auditService.CreateFixitAudit("updated fixit", "sys", before, fixit)
+	if err = s.auditService.CreateFixitAudit("updated fixit", actor, before, fixit); err != nil {
+		return nil, err
+	}
 
+	publishFixitChanged(fixit)
 	return
 }
 
@@ -133,14 +158,19 @@ const (
 
 // validateFixit checks the validity of a Fixit entity's fields against specified constraints.
 // This function ensures that the 'FieldName' and 'Comments' of a Fixit do not exceed their
-// maximum allowed lengths, adhering to the defined maximum length constants.
+// maximum allowed lengths, adhering to the defined maximum length constants, and that a
+// 'FieldName' names a registered vocab field, see VocabFields. A fixit may leave its field
+// name empty when it is about the vocab as a whole.
 //
 // Parameters:
 // - fixit: A pointer to the Fixit struct whose fields are to be validated.
+// - before: The stored Fixit when it is being updated, nil for a new one. A field name kept
+// from before is not checked against the registry, so fixits filed before field names were
+// checked can still be updated.
 //
 // Returns:
-//   - An error if any field fails the validation checks, specifying the nature of the failure.
-//     If all fields pass the validation, nil is returned.
+//   - A *ValidationError holding each field that fails the validation checks, specifying the nature
+//     of the failure. If all fields pass the validation, nil is returned.
 //
 // Usage:
 // The function is typically used before creating or updating a Fixit entity in the database
@@ -148,7 +178,7 @@ const (
 // and ensures consistency across the application.
 //
 // Example:
-// err := validateFixit(fixit)
+// err := validateFixit(fixit, nil)
 //
 //	if err != nil {
 //	    // Handle the validation error
@@ -158,14 +188,17 @@ const (
 // This function relies on validateFieldContent to perform the actual validation of each field,
 // using 'maxFixitFieldNameLen' and 'maxFixitCommitLen' as the maximum length constraints for
 // the 'FieldName' and 'Comments' fields, respectively.
-func validateFixit(fixit *mdl.Fixit) error {
+func validateFixit(fixit *mdl.Fixit, before *mdl.Fixit) error {
 
-	if err := validateFieldContent(fixit.FieldName, "Field Name", maxFixitFieldNameLen); err != nil {
-		return err
-	}
-	if err := validateFieldContent(fixit.Comments, "Commits", maxFixitCommitLen); err != nil {
-		return err
+	errs := &ValidationError{}
+	errs.add("field_name", validateFieldContent(fixit.FieldName, "Field Name", maxFixitFieldNameLen))
+	errs.add("comments", validateFieldContent(fixit.Comments, "Commits", maxFixitCommitLen))
+
+	if len(fixit.FieldName) > 0 && !errs.failed("field_name") && (before == nil || fixit.FieldName != before.FieldName) {
+		if _, found := FindVocabField(fixit.FieldName); !found {
+			errs.add("field_name", invalidField("", RuleOneOf, 0, "field name %s is not a vocab field", fixit.FieldName))
+		}
 	}
 
-	return nil
+	return errs.errOrNil()
 }
@@ -20,7 +20,7 @@ func TestValidateFixit(t *testing.T) {
 		{
 			name: "Valid Fixit",
 			fixit: mdl.Fixit{
-				FieldName: "Valid Field Name",
+				FieldName: "hint",
 				Comments:  "Valid comment within allowed length.",
 			},
 			wantError: false,
@@ -34,10 +34,19 @@ func TestValidateFixit(t *testing.T) {
 			wantError: true,
 			errorMsg:  fmt.Sprintf("Field Name must be shorter than %d characters", maxFixitFieldNameLen),
 		},
+		{
+			name: "FieldName Not A Vocab Field",
+			fixit: mdl.Fixit{
+				FieldName: "Definition",
+				Comments:  "Valid comment.",
+			},
+			wantError: true,
+			errorMsg:  "field name Definition is not a vocab field",
+		},
 		{
 			name: "Comments Exceed Max Length",
 			fixit: mdl.Fixit{
-				FieldName: "Valid Field Name",
+				FieldName: "hint",
 				Comments:  strings.Repeat("b", maxFixitCommitLen+1), // Exceeds max length
 			},
 			wantError: true,
@@ -47,7 +56,7 @@ func TestValidateFixit(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateFixit(&tt.fixit)
+			err := validateFixit(&tt.fixit, nil)
 			if (err != nil) != tt.wantError {
 				t.Errorf("validateFixit() error = %v, wantError %v", err, tt.wantError)
 			}
@@ -66,12 +75,12 @@ func TestFixitService_FindFixitByID(t *testing.T) {
 		ID:        1,
 		VocabID:   100,
 		Status:    "pending",
-		FieldName: "Definition",
+		FieldName: "first_lang",
 		Comments:  "Initial comment",
 		CreatedBy: "tester",
 		Created:   time.Now(),
 	}
-	_ = fixitService.CreateFixit(testFixit)
+	_ = fixitService.CreateFixit(testFixit, "sys")
 
 	fixit, err := fixitService.FindFixitByID(1)
 	if err != nil {
@@ -97,7 +106,7 @@ func TestFixitService_FindFixits(t *testing.T) {
 		ID:        1,
 		VocabID:   101,
 		Status:    "pending",
-		FieldName: "Definition",
+		FieldName: "first_lang",
 		Comments:  "Initial comment",
 		CreatedBy: "tester",
 		Created:   time.Now(),
@@ -111,8 +120,8 @@ func TestFixitService_FindFixits(t *testing.T) {
 		CreatedBy: "tester",
 		Created:   time.Now(),
 	}
-	_ = fixitService.CreateFixit(testFixit1)
-	_ = fixitService.CreateFixit(testFixit2)
+	_ = fixitService.CreateFixit(testFixit1, "sys")
+	_ = fixitService.CreateFixit(testFixit2, "sys")
 
 	// Define test cases
 	tests := []struct {
@@ -171,7 +180,7 @@ func TestFixitService_CreateFixit(t *testing.T) {
 			fixit: &mdl.Fixit{
 				VocabID:   101,
 				Status:    mdl.StatusType("pending"),
-				FieldName: "New field name",
+				FieldName: "hint",
 				Comments:  "Initial comment",
 				CreatedBy: "tester",
 			},
@@ -204,7 +213,7 @@ func TestFixitService_CreateFixit(t *testing.T) {
 	// Execute test cases
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := fixitService.CreateFixit(tt.fixit)
+			err := fixitService.CreateFixit(tt.fixit, "sys")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("%s: CreateFixit() error = %v, wantErr %v", tt.name, err, tt.wantErr)
 			} else if err != nil && !strings.Contains(err.Error(), tt.errMsg) {
@@ -224,53 +233,57 @@ func TestFixitService_UpdateFixit(t *testing.T) {
 		ID:        1,
 		VocabID:   101,
 		Status:    mdl.StatusType("pending"),
-		FieldName: "Existing field name",
+		FieldName: "hint",
 		Comments:  "Existing comment",
 		CreatedBy: "tester",
 	}
-	_ = fixitService.CreateFixit(existingFixit)
+	_ = fixitService.CreateFixit(existingFixit, "sys")
+
+	completed := mdl.Completed
+	updatedFieldName := "first_lang"
+	longFieldName := string(make([]rune, maxFixitFieldNameLen+1)) // Exceeds max length
 
 	// Define test cases
 	tests := []struct {
-		name    string
-		fixit   *mdl.Fixit
-		wantErr bool
-		errMsg  string
+		name          string
+		patch         *mdl.FixitPatch
+		wantFieldName string
+		wantErr       bool
+		errMsg        string
 	}{
 		{
 			name: "Successful fixit update",
-			fixit: &mdl.Fixit{
+			patch: &mdl.FixitPatch{
 				ID:        1,
-				VocabID:   101,
-				Status:    mdl.StatusType("completed"),
-				FieldName: "Updated field name",
-				Comments:  "Updated comment",
-				CreatedBy: "tester",
+				Status:    &completed,
+				FieldName: &updatedFieldName,
 			},
-			wantErr: false,
+			wantFieldName: updatedFieldName,
+			wantErr:       false,
+		},
+		{
+			name: "Patch with no changes",
+			patch: &mdl.FixitPatch{
+				ID:     1,
+				Status: &completed,
+			},
+			wantErr: true,
+			errMsg:  "update for fixit 1 has no changes",
 		},
 		{
 			name: "FieldName exceeds max length",
-			fixit: &mdl.Fixit{
+			patch: &mdl.FixitPatch{
 				ID:        1,
-				VocabID:   101,
-				Status:    mdl.StatusType("completed"),
-				FieldName: string(make([]rune, maxFixitFieldNameLen+1)), // Exceeds max length
-				Comments:  "Should fail due to field name length",
-				CreatedBy: "tester",
+				FieldName: &longFieldName,
 			},
 			wantErr: true,
 			errMsg:  fmt.Sprintf(errFmtStrLen, "Field Name", maxFixitFieldNameLen),
 		},
 		{
 			name: "Fixit does not exist",
-			fixit: &mdl.Fixit{
-				ID:        999, // Non-existing ID
-				VocabID:   102,
-				Status:    mdl.StatusType("in_progress"),
-				FieldName: "Non-existing field name",
-				Comments:  "Non-existing comment",
-				CreatedBy: "tester",
+			patch: &mdl.FixitPatch{
+				ID:     999, // Non-existing ID
+				Status: &completed,
 			},
 			wantErr: true,
 			errMsg:  "fixit not found",
@@ -280,14 +293,17 @@ func TestFixitService_UpdateFixit(t *testing.T) {
 	// Execute test cases
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			updatedFixit, err := fixitService.UpdateFixit(tt.fixit)
+			updatedFixit, err := fixitService.UpdateFixit(tt.patch, "sys")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("%s: UpdateFixit() error = %v, wantErr %v", tt.name, err, tt.wantErr)
 			} else if err != nil && !strings.Contains(err.Error(), tt.errMsg) {
 				t.Errorf("%s: UpdateFixit() error = %v, wantErrMsg to contain %v", tt.name, err, tt.errMsg)
 			}
-			if !tt.wantErr && updatedFixit != nil && updatedFixit.FieldName != tt.fixit.FieldName {
-				t.Errorf("%s: FieldName not updated correctly, got = %v, want = %v", tt.name, updatedFixit.FieldName, tt.fixit.FieldName)
+			if !tt.wantErr && updatedFixit != nil && updatedFixit.FieldName != tt.wantFieldName {
+				t.Errorf("%s: FieldName not updated correctly, got = %v, want = %v", tt.name, updatedFixit.FieldName, tt.wantFieldName)
+			}
+			if !tt.wantErr && updatedFixit != nil && updatedFixit.Comments != existingFixit.Comments {
+				t.Errorf("%s: Comments changed unexpectedly, got = %v, want = %v", tt.name, updatedFixit.Comments, existingFixit.Comments)
 			}
 		})
 	}
@@ -307,3 +323,22 @@ func createMockFixitService() FixitService {
 
 	return fixitService
 }
+
+func TestFixitService_UpdateFixitLegacyFieldName(t *testing.T) {
+	fixitService := createMockFixitService()
+
+	// Fixits filed before field names were checked keep their field name
+	legacy := &mdl.Fixit{VocabID: 101, Status: mdl.Pending, FieldName: "Definition", Comments: "check", CreatedBy: "tester"}
+	_ = fixitService.repo.CreateFixit(legacy)
+
+	completed := mdl.Completed
+	if _, err := fixitService.UpdateFixit(&mdl.FixitPatch{ID: legacy.ID, Status: &completed}, "sys"); err != nil {
+		t.Errorf("UpdateFixit() error = %v", err)
+	}
+
+	meaning := "Meaning"
+	_, err := fixitService.UpdateFixit(&mdl.FixitPatch{ID: legacy.ID, FieldName: &meaning}, "sys")
+	if err == nil || err.Error() != "field name Meaning is not a vocab field" {
+		t.Errorf("UpdateFixit() error = %v", err)
+	}
+}
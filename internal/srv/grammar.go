@@ -0,0 +1,293 @@
+package srv
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+)
+
+const (
+	maxGenderLen   = 20
+	maxPluralLen   = 40
+	maxArticleLen  = 10
+	maxRegisterLen = 20
+)
+
+// Genders lists the grammatical genders a vocab may be given.
+var Genders = []string{"masculine", "feminine", "neuter", "common"}
+
+// Registers lists the registers a vocab may be given.
+var Registers = []string{"formal", "informal", "colloquial", "slang", "vulgar", "literary", "technical"}
+
+// The parts of speech each grammatical field is allowed for. Register applies to any part of speech.
+var (
+	genderPos  = []string{"noun", "proper noun", "pronoun", "adjective", "article", "determiner"}
+	pluralPos  = []string{"noun", "pronoun", "adjective", "article", "determiner"}
+	articlePos = []string{"noun", "proper noun"}
+)
+
+// GrammarSuggestion holds the grammatical metadata guessed for a word from its ending. Fields
+// that cannot be guessed are left empty. The suggestion is a starting point for an editor, it
+// is never applied to a vocab without being reviewed.
+//
+// Fields:
+//   - Gender: The suggested gender, one of Genders.
+//   - Plural: The suggested plural form.
+//   - Article: The suggested definite article.
+//   - Reason: A short description of the rule the gender was guessed by.
+type GrammarSuggestion struct {
+	Gender  string
+	Plural  string
+	Article string
+	Reason  string
+}
+
+// validateGrammar checks the grammatical metadata of a vocab. Gender, plural and article are
+// only allowed for the parts of speech they apply to, and the gender, register and article
+// must be one of the known values.
+//
+// Parameters:
+// - vocab: A pointer to the Vocab struct to validate, after its part of speech is conformed.
+//
+// Returns:
+// - A *ValidationError describing the first failure of each field, or nil when all pass.
+//
+// Usage example:
+// err := validateGrammar(&vocab)
+//
+//	if err != nil {
+//	    log.Printf("Validation failed: %v", err)
+//	}
+func validateGrammar(vocab *mdl.Vocab) error {
+
+	errs := &ValidationError{}
+	errs.add("plural", validateFieldContent(vocab.Plural, "Plural", maxPluralLen))
+	errs.add("article", validateFieldContent(vocab.Article, "Article", maxArticleLen))
+
+	if len(vocab.Gender) > 0 {
+		if indexOfString(Genders, vocab.Gender) < 0 {
+			errs.add("gender", invalidField("", RuleOneOf, 0, "gender %s must be one of %s", vocab.Gender, strings.Join(Genders, ", ")))
+		} else {
+			errs.add("gender", requirePos(vocab, "gender", genderPos))
+		}
+	}
+
+	if len(vocab.Plural) > 0 && !errs.failed("plural") {
+		errs.add("plural", requirePos(vocab, "plural", pluralPos))
+	}
+
+	if len(vocab.Article) > 0 && !errs.failed("article") {
+		// Languages listing their definite articles accept only those, others accept any article text
+		language := lookupLanguage(vocab.LearningLangCode)
+		if err := requirePos(vocab, "article", articlePos); err != nil {
+			errs.add("article", err)
+		} else if language != nil && len(language.definiteArticles) > 0 && indexOfString(language.definiteArticles, vocab.Article) < 0 {
+			errs.add("article", invalidField("", RuleOneOf, 0, "article %s must be one of %s", vocab.Article, strings.Join(language.definiteArticles, ", ")))
+		}
+	}
+
+	if len(vocab.Register) > 0 && indexOfString(Registers, vocab.Register) < 0 {
+		errs.add("register", invalidField("", RuleOneOf, 0, "register %s must be one of %s", vocab.Register, strings.Join(Registers, ", ")))
+	}
+
+	return errs.errOrNil()
+}
+
+// requirePos returns an error when the part of speech of the vocab is not one of those allowed for the field.
+func requirePos(vocab *mdl.Vocab, field string, allowed []string) error {
+	if indexOfString(allowed, vocab.Pos) < 0 {
+		return invalidField(field, RuleNotAllowed, 0, "%s is only allowed for a part of speech of %s, not '%s'", field, strings.Join(allowed, ", "), vocab.Pos)
+	}
+	return nil
+}
+
+// indexOfString returns the position of the value in the list, or -1 when it is absent.
+func indexOfString(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// SuggestGrammar guesses the gender, plural and definite article of a singular noun from its
+// ending. Irregular nouns that are common in learning material are listed as exceptions,
+// other nouns that break the rules get a wrong suggestion, which is why it is only a suggestion.
+//
+// Parameters:
+// - langCode: The learning language code of the word, e.g. "es".
+// - word: The singular noun, without an article.
+//
+// Returns:
+// - The suggestion, with the fields that could not be guessed left empty.
+// - An error if the language has no suggester or the word is not a single word.
+//
+// Usage example:
+// suggestion, err := srv.SuggestGrammar("es", "canción")
+//
+//	if err != nil {
+//	    log.Printf("Failed to suggest grammar: %v", err)
+//	}
+func SuggestGrammar(langCode string, word string) (*GrammarSuggestion, error) {
+	suggest := lookupLanguageHooks(CanonicalLangCode(langCode)).SuggestGrammar
+	if suggest == nil {
+		return nil, fmt.Errorf("grammar is not suggested for language %s", langCode)
+	}
+
+	return suggest(word)
+}
+
+// suggestSpanishGrammar is the grammar suggester registered for Spanish.
+func suggestSpanishGrammar(word string) (*GrammarSuggestion, error) {
+	word = strings.ToLower(NormalizeText(word))
+	if len(word) == 0 || strings.ContainsAny(word, " -") {
+		return nil, fmt.Errorf("grammar is only suggested for a single word, not '%s'", word)
+	}
+
+	suggestion := &GrammarSuggestion{Plural: spanishPlural(word)}
+	suggestion.Gender, suggestion.Reason = spanishGender(word)
+	suggestion.Article = spanishArticle(word, suggestion.Gender)
+
+	return suggestion, nil
+}
+
+// spanishGenderExceptions are common nouns whose gender does not follow their ending.
+var spanishGenderExceptions = map[string]string{
+	"día": "masculine", "mapa": "masculine", "planeta": "masculine", "sofá": "masculine",
+	"problema": "masculine", "tema": "masculine", "sistema": "masculine", "programa": "masculine",
+	"idioma": "masculine", "clima": "masculine", "drama": "masculine", "poema": "masculine",
+	"mano": "feminine", "foto": "feminine", "moto": "feminine", "radio": "feminine",
+	"flor": "feminine", "miel": "feminine", "piel": "feminine", "sal": "feminine",
+	"ley": "feminine", "luz": "feminine", "voz": "feminine", "paz": "feminine",
+	"noche": "feminine", "calle": "feminine", "carne": "feminine", "clase": "feminine",
+	"gente": "feminine", "leche": "feminine", "llave": "feminine", "nube": "feminine",
+	"parte": "feminine", "muerte": "feminine", "suerte": "feminine", "tarde": "feminine",
+	"fuente": "feminine", "frase": "feminine", "mente": "feminine", "sangre": "feminine",
+	"ave": "feminine", "hambre": "feminine",
+}
+
+// spanishGenderEndings are the noun endings that suggest a gender, longest endings first.
+var spanishGenderEndings = []struct {
+	ending string
+	gender string
+}{
+	{"ción", "feminine"}, {"sión", "feminine"}, {"umbre", "feminine"},
+	{"dad", "feminine"}, {"tad", "feminine"}, {"tud", "feminine"}, {"sis", "feminine"}, {"itis", "feminine"},
+	{"ista", "common"},
+	{"aje", "masculine"}, {"ambre", "masculine"}, {"or", "masculine"}, {"án", "masculine"},
+	{"a", "feminine"}, {"o", "masculine"},
+}
+
+// spanishPluralExceptions are common nouns whose plural moves the stress or is unchanged.
+var spanishPluralExceptions = map[string]string{
+	"examen": "exámenes", "joven": "jóvenes", "imagen": "imágenes", "origen": "orígenes",
+	"volumen": "volúmenes", "resumen": "resúmenes", "crimen": "crímenes", "orden": "órdenes",
+	"carácter": "caracteres", "régimen": "regímenes",
+}
+
+// spanishStressedA are feminine nouns starting with a stressed a that take el in the singular.
+var spanishStressedA = map[string]bool{
+	"agua": true, "alma": true, "arma": true, "ala": true, "área": true, "aula": true,
+	"ave": true, "hacha": true, "hambre": true, "águila": true, "hada": true,
+}
+
+// spanishGender returns the gender suggested by the ending of a word and the rule it is based on.
+func spanishGender(word string) (gender string, reason string) {
+	if gender, found := spanishGenderExceptions[word]; found {
+		return gender, "listed exception"
+	}
+
+	for _, e := range spanishGenderEndings {
+		if strings.HasSuffix(word, e.ending) {
+			return e.gender, fmt.Sprintf("ends in -%s", e.ending)
+		}
+	}
+
+	return "", ""
+}
+
+// spanishArticle returns the singular definite article for a word of the gender.
+func spanishArticle(word string, gender string) string {
+	switch gender {
+	case "masculine":
+		return "el"
+	case "feminine":
+		if spanishStressedA[word] {
+			return "el"
+		}
+		return "la"
+	}
+	return ""
+}
+
+// spanishPlural returns the plural of a singular noun.
+func spanishPlural(word string) string {
+	if plural, found := spanishPluralExceptions[word]; found {
+		return plural
+	}
+
+	runes := []rune(word)
+	last := runes[len(runes)-1]
+
+	switch {
+	case last == 'z':
+		return string(runes[:len(runes)-1]) + "ces"
+	case isSpanishVowel(last) && last != 'í' && last != 'ú':
+		return word + "s"
+	case isSpanishVowel(last):
+		return word + "es"
+	case last == 's' || last == 'x':
+		// Words stressed before the last syllable, such as crisis or lunes, do not change.
+		if spanishSyllables(runes) > 1 && !hasAccentedVowel(runes[len(runes)-2:]) {
+			return word
+		}
+	}
+
+	// A final stressed syllable loses its written accent when es is added, canción, canciones.
+	if len(runes) > 1 && hasAccentedVowel(runes[len(runes)-2:len(runes)-1]) {
+		runes[len(runes)-2] = removeAccent(runes[len(runes)-2])
+	}
+	return string(runes) + "es"
+}
+
+// isSpanishVowel reports whether the rune is a vowel, with or without a written accent.
+func isSpanishVowel(r rune) bool {
+	return strings.ContainsRune("aeiouáéíóúü", r)
+}
+
+// hasAccentedVowel reports whether any of the runes is a vowel with a written accent.
+func hasAccentedVowel(runes []rune) bool {
+	for _, r := range runes {
+		if strings.ContainsRune("áéíóú", r) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeAccent returns the vowel without its written accent.
+func removeAccent(r rune) rune {
+	for _, d := range norm.NFD.String(string(r)) {
+		if !unicode.Is(unicode.Mn, d) {
+			return d
+		}
+	}
+	return r
+}
+
+// spanishSyllables counts the vowel groups of a word, a close enough count of its syllables
+// to tell one syllable words from longer ones.
+func spanishSyllables(runes []rune) int {
+	count, inVowel := 0, false
+	for _, r := range runes {
+		vowel := isSpanishVowel(r)
+		if vowel && !inVowel {
+			count++
+		}
+		inVowel = vowel
+	}
+	return count
+}
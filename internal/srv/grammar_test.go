@@ -0,0 +1,103 @@
+package srv
+
+import (
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"testing"
+)
+
+func TestSuggestGrammar(t *testing.T) {
+	tests := []struct {
+		name    string
+		word    string
+		want    GrammarSuggestion
+		wantErr bool
+	}{
+		{name: "Ends in o", word: "perro", want: GrammarSuggestion{Gender: "masculine", Plural: "perros", Article: "el"}},
+		{name: "Ends in a", word: "Casa", want: GrammarSuggestion{Gender: "feminine", Plural: "casas", Article: "la"}},
+		{name: "Accented ending", word: "canción", want: GrammarSuggestion{Gender: "feminine", Plural: "canciones", Article: "la"}},
+		{name: "Ends in z", word: "lápiz", want: GrammarSuggestion{Plural: "lápices"}},
+		{name: "Ends in dad", word: "ciudad", want: GrammarSuggestion{Gender: "feminine", Plural: "ciudades", Article: "la"}},
+		{name: "Exception", word: "problema", want: GrammarSuggestion{Gender: "masculine", Plural: "problemas", Article: "el"}},
+		{name: "Stressed a", word: "agua", want: GrammarSuggestion{Gender: "feminine", Plural: "aguas", Article: "el"}},
+		{name: "Unstressed s", word: "crisis", want: GrammarSuggestion{Gender: "feminine", Plural: "crisis", Article: "la"}},
+		{name: "Stressed s", word: "autobús", want: GrammarSuggestion{Plural: "autobuses"}},
+		{name: "One syllable s", word: "mes", want: GrammarSuggestion{Plural: "meses"}},
+		{name: "Stress moves", word: "examen", want: GrammarSuggestion{Plural: "exámenes"}},
+		{name: "Common", word: "artista", want: GrammarSuggestion{Gender: "common", Plural: "artistas"}},
+		{name: "Phrase", word: "perro caliente", wantErr: true},
+		{name: "Empty", word: " ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SuggestGrammar("es", tt.word)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SuggestGrammar() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got.Reason = ""
+			if *got != tt.want {
+				t.Errorf("SuggestGrammar() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+
+	if _, err := SuggestGrammar("fr", "chien"); err == nil {
+		t.Errorf("SuggestGrammar(fr) expected an error")
+	}
+}
+
+func TestVocabService_GrammarValidation(t *testing.T) {
+	vocabService := createMockVocabService()
+
+	for _, v := range []mdl.Vocab{
+		{LearningLang: "agua", FirstLang: "water", Pos: "noun", LearningLangCode: "es", KnownLangCode: "en"},
+		{LearningLang: "correr", FirstLang: "to run", Pos: "verb", LearningLangCode: "es", KnownLangCode: "en"},
+	} {
+		vocab := v
+		if err := vocabService.CreateVocab(&vocab, true, "sys"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	feminine, neuter, unknown := "feminine", "neuter", "plural"
+	article, badArticle := "el", "le"
+	plural, register, badRegister := "aguas", "formal", "posh"
+
+	tests := []struct {
+		name    string
+		patch   *mdl.VocabPatch
+		wantErr bool
+		errMsg  string
+	}{
+		{name: "Noun grammar", patch: &mdl.VocabPatch{ID: 1, Gender: &feminine, Plural: &plural, Article: &article, Register: &register}},
+		{name: "Unknown gender", patch: &mdl.VocabPatch{ID: 1, Gender: &unknown}, wantErr: true,
+			errMsg: "gender plural must be one of masculine, feminine, neuter, common"},
+		{name: "Unknown article", patch: &mdl.VocabPatch{ID: 1, Article: &badArticle}, wantErr: true,
+			errMsg: "article le must be one of el, la, los, las"},
+		{name: "Unknown register", patch: &mdl.VocabPatch{ID: 1, Register: &badRegister}, wantErr: true,
+			errMsg: "register posh must be one of formal, informal, colloquial, slang, vulgar, literary, technical"},
+		{name: "Gender of a verb", patch: &mdl.VocabPatch{ID: 2, Gender: &neuter}, wantErr: true,
+			errMsg: "gender is only allowed for a part of speech of noun, proper noun, pronoun, adjective, article, determiner, not 'verb'"},
+		{name: "Register of a verb", patch: &mdl.VocabPatch{ID: 2, Register: &register}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := vocabService.UpdateVocab(tt.patch, "sys")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateVocab() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil && err.Error() != tt.errMsg {
+				t.Errorf("UpdateVocab() error = %v, wantErrMsg %v", err, tt.errMsg)
+			}
+		})
+	}
+
+	// Changing the part of speech away from noun leaves the article without a noun.
+	verb := "verb"
+	if _, err := vocabService.UpdateVocab(&mdl.VocabPatch{ID: 1, Pos: &verb}, "sys"); err == nil {
+		t.Errorf("UpdateVocab() expected an error for an article on a verb")
+	}
+}
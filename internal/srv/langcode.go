@@ -0,0 +1,51 @@
+package srv
+
+import (
+	"regexp"
+	"strings"
+)
+
+// maxLangCodeLen is the length of the longest language tag langCodePattern matches, e.g. "yue-Hant-419".
+const maxLangCodeLen = 12
+
+// langCodePattern matches the BCP-47 language tags used for vocab and concepts: a two or
+// three letter language subtag, optionally followed by a four letter script subtag and a two
+// letter or three digit region subtag, e.g. "es", "pt-BR", "es-419" or "zh-Hant-TW". Variant,
+// extension and private use subtags are not accepted since no course tells languages apart
+// by them.
+var langCodePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z][a-z]{3})?(-([A-Z]{2}|[0-9]{3}))?$`)
+
+// CanonicalLangCode puts a language tag in the canonical BCP-47 case, lowercase language,
+// title case script and uppercase region, so tags typed as "PT-br" or "pt_BR" are stored as
+// "pt-BR". The tag is not validated, see ValidLangCode.
+//
+// Parameters:
+// - code: The language tag.
+//
+// Returns:
+// - The language tag in canonical case with hyphens between its subtags.
+//
+// Usage example:
+// code := CanonicalLangCode("zh_hant_tw") // "zh-Hant-TW"
+func CanonicalLangCode(code string) string {
+	subtags := strings.Split(strings.ReplaceAll(strings.TrimSpace(code), "_", "-"), "-")
+
+	for i, subtag := range subtags {
+		switch {
+		case i == 0:
+			subtags[i] = strings.ToLower(subtag)
+		case len(subtag) == 4:
+			subtags[i] = strings.ToUpper(subtag[:1]) + strings.ToLower(subtag[1:])
+		default:
+			subtags[i] = strings.ToUpper(subtag)
+		}
+	}
+
+	return strings.Join(subtags, "-")
+}
+
+// ValidLangCode reports whether the code is a language tag in canonical form accepted for
+// vocab, see langCodePattern.
+func ValidLangCode(code string) bool {
+	return langCodePattern.MatchString(code)
+}
@@ -0,0 +1,34 @@
+package srv
+
+import "testing"
+
+func TestCanonicalLangCode(t *testing.T) {
+	tests := []struct {
+		code      string
+		want      string
+		wantValid bool
+	}{
+		{code: "es", want: "es", wantValid: true},
+		{code: "PT-br", want: "pt-BR", wantValid: true},
+		{code: "pt_BR", want: "pt-BR", wantValid: true},
+		{code: "es-419", want: "es-419", wantValid: true},
+		{code: "zh_hant_tw", want: "zh-Hant-TW", wantValid: true},
+		{code: " fr ", want: "fr", wantValid: true},
+		{code: "", want: "", wantValid: false},
+		{code: "Spanish", want: "spanish", wantValid: false},
+		{code: "123", want: "123", wantValid: false},
+		{code: "pt-", want: "pt-", wantValid: false},
+		{code: "de-DE-1996", want: "de-DE-1996", wantValid: false},
+		{code: "en-x-pirate", want: "en-X-PIRATE", wantValid: false},
+	}
+
+	for _, tt := range tests {
+		got := CanonicalLangCode(tt.code)
+		if got != tt.want {
+			t.Errorf("CanonicalLangCode(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+		if valid := ValidLangCode(got); valid != tt.wantValid {
+			t.Errorf("ValidLangCode(%q) = %v, want %v", got, valid, tt.wantValid)
+		}
+	}
+}
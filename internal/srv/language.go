@@ -0,0 +1,471 @@
+package srv
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/lint"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	maxLanguageNameLen     = 60
+	maxLanguageArticlesLen = 255
+)
+
+// scriptPattern matches an ISO 15924 script code, e.g. "Latn" or "Cyrl".
+var scriptPattern = regexp.MustCompile(`^[A-Z][a-z]{3}$`)
+
+// LanguageHooks are the code level rules of a language, those its settings cannot express.
+// Hooks are registered by language code, see RegisterLanguageHooks, and apply to the tags
+// of that language too, so the hooks of "pt" also apply to "pt-BR" unless it has its own.
+//
+// Fields:
+//   - Tokenize: Optional. Replaces the orthographic word split of TokenizeLearningLang.
+//   - Normalize: Optional. Applied to learning language text after NormalizeText.
+//   - LintRules: Optional. Run after the default lint rules over vocab of the learning language.
+//   - SuggestGrammar: Optional. Guesses the grammar of a singular noun, see SuggestGrammar.
+type LanguageHooks struct {
+	Tokenize       func(text string) []string
+	Normalize      func(text string) string
+	LintRules      []lint.Rule
+	SuggestGrammar func(word string) (*GrammarSuggestion, error)
+}
+
+// names lists the hooks that are set, as shown to admins.
+func (h LanguageHooks) names() (names []string) {
+	if h.Tokenize != nil {
+		names = append(names, "tokenize")
+	}
+	if h.Normalize != nil {
+		names = append(names, "normalize")
+	}
+	if len(h.LintRules) > 0 {
+		names = append(names, "lint")
+	}
+	if h.SuggestGrammar != nil {
+		names = append(names, "grammar")
+	}
+	return
+}
+
+// managedLanguage is a Language as held by the registry, with its article lists parsed.
+type managedLanguage struct {
+	mdl.Language
+	articles         map[string]bool
+	definiteArticles []string
+}
+
+// The registry of managed languages and language hooks. The languages start as
+// mdl.DefaultLanguages and are replaced by the stored languages, see loadLanguages.
+var (
+	languagesMu   sync.RWMutex
+	languages     map[string]*managedLanguage
+	languageHooks = map[string]LanguageHooks{
+		"es": {LintRules: []lint.Rule{lint.InvertedPunctuation()}, SuggestGrammar: suggestSpanishGrammar},
+		"ca": {Normalize: normalizeCatalan},
+	}
+)
+
+func init() {
+	setLanguages(mdl.DefaultLanguages)
+}
+
+// RegisterLanguageHooks sets the hooks of a language code, replacing any registered before.
+//
+// Usage example:
+//
+//	srv.RegisterLanguageHooks("fr", srv.LanguageHooks{LintRules: []lint.Rule{frenchSpacing()}})
+func RegisterLanguageHooks(code string, hooks LanguageHooks) {
+	languagesMu.Lock()
+	defer languagesMu.Unlock()
+
+	languageHooks[CanonicalLangCode(code)] = hooks
+}
+
+// setLanguages replaces the managed languages of the registry.
+func setLanguages(list []mdl.Language) {
+	byCode := make(map[string]*managedLanguage, len(list))
+	for _, language := range list {
+		managed := &managedLanguage{Language: language, articles: make(map[string]bool)}
+		for _, article := range splitArticles(language.Articles) {
+			managed.articles[article] = true
+		}
+		managed.definiteArticles = splitArticles(language.DefiniteArticles)
+		byCode[language.Code] = managed
+	}
+
+	languagesMu.Lock()
+	defer languagesMu.Unlock()
+
+	languages = byCode
+}
+
+// loadLanguages replaces the managed languages of the registry with those stored, so changes
+// made by other instances are seen. The registry is left alone while none are stored.
+func loadLanguages(repo db.LanguageRepository) error {
+	list, err := repo.FindLanguages()
+	if err != nil {
+		return err
+	}
+	if len(*list) > 0 {
+		setLanguages(*list)
+	}
+	return nil
+}
+
+// loadManagedLanguages refreshes the registry from the language table, for the services whose
+// rules depend on the managed languages.
+func loadManagedLanguages() error {
+	repo, err := db.NewSqlLanguageRepository()
+	if err != nil {
+		return err
+	}
+	return loadLanguages(repo)
+}
+
+// baseLangCode returns the language subtag of a language tag, e.g. "pt" for "pt-BR".
+func baseLangCode(code string) string {
+	if i := strings.IndexByte(code, '-'); i > 0 {
+		return code[:i]
+	}
+	return code
+}
+
+// lookupLanguage returns the managed language of a language tag, or of its base language when
+// the tag itself is not managed, and nil when neither is.
+func lookupLanguage(code string) *managedLanguage {
+	languagesMu.RLock()
+	defer languagesMu.RUnlock()
+
+	if language, found := languages[code]; found {
+		return language
+	}
+	return languages[baseLangCode(code)]
+}
+
+// lookupLanguageHooks returns the hooks of a language tag, or of its base language when none
+// are registered for the tag itself.
+func lookupLanguageHooks(code string) LanguageHooks {
+	languagesMu.RLock()
+	defer languagesMu.RUnlock()
+
+	if hooks, found := languageHooks[code]; found {
+		return hooks
+	}
+	return languageHooks[baseLangCode(code)]
+}
+
+// LanguageLintRules returns the lint rules registered for a learning language, for
+// lint.Engine.WithLanguageRules.
+func LanguageLintRules(code string) []lint.Rule {
+	return lookupLanguageHooks(code).LintRules
+}
+
+// LanguageHookNames returns the names of the hooks that apply to a language tag, e.g.
+// ["normalize"] for "ca".
+func LanguageHookNames(code string) []string {
+	return lookupLanguageHooks(code).names()
+}
+
+// DefaultLangCodes returns the codes of the managed languages flagged as the default known and
+// learning languages, given to vocab created without language codes. A code is empty when no
+// language has the flag.
+func DefaultLangCodes() (known string, learning string) {
+	languagesMu.RLock()
+	defer languagesMu.RUnlock()
+
+	for code, language := range languages {
+		if language.DefaultKnown {
+			known = code
+		}
+		if language.DefaultLearning {
+			learning = code
+		}
+	}
+	return
+}
+
+// validateManagedLangCode ensures a language tag is one vocab may use. The tag must be an
+// enabled managed language, or when the tag is not managed, as with a regional tag such as
+// "es-419", its base language must be.
+func validateManagedLangCode(code string, fieldName string) error {
+	language := lookupLanguage(code)
+	if language == nil {
+		return invalidField("", RuleLanguage, 0, "%s %s is not a managed language", fieldName, code)
+	}
+	if !language.Enabled {
+		return invalidField("", RuleLanguage, 0, "%s %s is not enabled", fieldName, language.Code)
+	}
+	return nil
+}
+
+// NormalizeLearningText applies NormalizeText and then the normalize hook of the learning
+// language, if it has one, to text written in that language.
+//
+// Usage example:
+// text := NormalizeLearningText("col.legi", "ca") // "col·legi"
+func NormalizeLearningText(text string, langCode string) string {
+	text = NormalizeText(text)
+	if normalize := lookupLanguageHooks(langCode).Normalize; normalize != nil {
+		text = normalize(text)
+	}
+	return text
+}
+
+// catalanGeminatePattern matches the stand-ins typed for the Catalan middle dot between two
+// l's, such as a period or the hyphenation point.
+var catalanGeminatePattern = regexp.MustCompile(`([lL])[.•‧∙⋅・]([lL])`)
+
+// normalizeCatalan writes the Catalan geminate l as "l·l", the form keyboards do not make easy
+// to type, replacing the legacy ŀ ligature and the look-alike dots used for the middle dot.
+func normalizeCatalan(text string) string {
+	text = strings.NewReplacer("ŀ", "l·", "Ŀ", "L·").Replace(text)
+	return catalanGeminatePattern.ReplaceAllString(text, "$1·$2")
+}
+
+// splitArticles splits a comma separated article list into its lower cased articles.
+func splitArticles(list string) (articles []string) {
+	for _, article := range strings.Split(list, ",") {
+		if article = strings.ToLower(strings.TrimSpace(article)); len(article) > 0 {
+			articles = append(articles, article)
+		}
+	}
+	return
+}
+
+// LanguageService handles business logic for the managed Language table.
+type LanguageService struct {
+	repo         db.LanguageRepository
+	auditService AuditService
+}
+
+// NewLanguageService creates a new instance of LanguageService.
+func NewLanguageService() (*LanguageService, error) {
+
+	repo, err := db.NewSqlLanguageRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := NewAuditService()
+	if err != nil {
+		return nil, err
+	}
+
+	return &LanguageService{repo: repo, auditService: *auditService}, nil
+}
+
+// FindLanguages retrieves the managed languages, ordered by code.
+//
+// Parameters:
+// - enabledOnly: Whether to leave out the disabled languages.
+func (s *LanguageService) FindLanguages(enabledOnly bool) (*[]mdl.Language, error) {
+	list, err := s.repo.FindLanguages()
+	if err != nil || !enabledOnly {
+		return list, err
+	}
+
+	enabled := make([]mdl.Language, 0, len(*list))
+	for _, language := range *list {
+		if language.Enabled {
+			enabled = append(enabled, language)
+		}
+	}
+	return &enabled, nil
+}
+
+// CreateLanguage adds a managed language after checking its code is not already managed, then
+// writes an audit entry. A new default known or learning language takes the flag from the
+// language that had it. The registry is reloaded so the language applies at once.
+//
+// Parameters:
+// - language: A pointer to the mdl.Language to create, its ID is set on success.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - An error if validation fails, the code is taken, or saving fails.
+//
+// Usage example:
+// err := languageService.CreateLanguage(&mdl.Language{Code: "pt-BR", Name: "Brazilian Portuguese", Enabled: true}, srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to create language: %v", err)
+//	}
+func (s *LanguageService) CreateLanguage(language *mdl.Language, actor string) (err error) {
+
+	normalizeLanguage(language)
+	if err = validateLanguage(language); err != nil {
+		return
+	}
+
+	existing, err := s.repo.FindLanguages()
+	if err != nil {
+		return
+	}
+	for _, other := range *existing {
+		if other.Code == language.Code {
+			return invalidField("code", RuleUnique, 0, "language %s already exists with id %d", language.Code, other.ID)
+		}
+	}
+
+	if err = s.repo.CreateLanguage(language); err != nil {
+		return
+	}
+
+	err = s.auditService.CreateAudit("language", language.ID, "created language", actor, "", language.JSON())
+	if err != nil {
+		return
+	}
+
+	if err = s.moveDefaultFlags(language, existing, actor); err != nil {
+		return
+	}
+
+	return loadLanguages(s.repo)
+}
+
+// UpdateLanguage applies a partial update to a managed language and writes an audit entry.
+// Making it the default known or learning language takes the flag from the language that had
+// it, which is audited too. The registry is reloaded so the change applies at once.
+//
+// Parameters:
+// - patch: The fields to change, only the non-nil fields are applied.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the updated mdl.Language.
+// - An error if the language cannot be found, the patch changes nothing, validation fails, or saving fails.
+//
+// Usage example:
+// language, err := languageService.UpdateLanguage(&mdl.LanguagePatch{ID: 3, Enabled: &disabled}, srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to update language: %v", err)
+//	}
+func (s *LanguageService) UpdateLanguage(patch *mdl.LanguagePatch, actor string) (language *mdl.Language, err error) {
+
+	before, err := s.repo.FindLanguageByID(patch.ID)
+	if err != nil {
+		return
+	}
+
+	language = &mdl.Language{}
+	*language = *before
+	if !patch.ApplyTo(language) {
+		return nil, fmt.Errorf("update for language %d has no changes", patch.ID)
+	}
+
+	normalizeLanguage(language)
+	if err = validateLanguage(language); err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.UpdateLanguage(language); err != nil {
+		return nil, err
+	}
+
+	err = s.auditService.CreateAudit("language", language.ID, "updated language", actor, before.JSON(), language.JSON())
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindLanguages()
+	if err != nil {
+		return nil, err
+	}
+	if err = s.moveDefaultFlags(language, existing, actor); err != nil {
+		return nil, err
+	}
+
+	return language, loadLanguages(s.repo)
+}
+
+// moveDefaultFlags clears the default known and learning flags of the other languages when
+// the language has them, so only one language is the default of each.
+func (s *LanguageService) moveDefaultFlags(language *mdl.Language, existing *[]mdl.Language, actor string) error {
+	for _, other := range *existing {
+		if other.ID == language.ID {
+			continue
+		}
+
+		updated := other
+		updated.DefaultKnown = other.DefaultKnown && !language.DefaultKnown
+		updated.DefaultLearning = other.DefaultLearning && !language.DefaultLearning
+		if updated == other {
+			continue
+		}
+
+		if err := s.repo.UpdateLanguage(&updated); err != nil {
+			return err
+		}
+		comments := fmt.Sprintf("default language moved to %s", language.Code)
+		if err := s.auditService.CreateAudit("language", other.ID, comments, actor, other.JSON(), updated.JSON()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeLanguage canonicalizes the code, script and direction of a language and tidies its
+// text fields. Articles are stored lower cased and separated by ", ".
+func normalizeLanguage(language *mdl.Language) {
+	language.Code = CanonicalLangCode(language.Code)
+	language.Name = NormalizeText(language.Name)
+	language.Script = NormalizeText(language.Script)
+	if len(language.Script) > 0 {
+		language.Script = strings.ToUpper(language.Script[:1]) + strings.ToLower(language.Script[1:])
+	}
+	language.Direction = strings.ToLower(NormalizeText(language.Direction))
+	if len(language.Direction) == 0 {
+		language.Direction = "ltr"
+	}
+	language.Articles = strings.Join(splitArticles(NormalizeText(language.Articles)), ", ")
+	language.DefiniteArticles = strings.Join(splitArticles(NormalizeText(language.DefiniteArticles)), ", ")
+}
+
+// validateLanguage checks the fields of a language, collecting the failures in a *ValidationError.
+// The default known and learning languages cannot be disabled, since vocab created without codes
+// would be rejected.
+func validateLanguage(language *mdl.Language) error {
+
+	errs := &ValidationError{}
+	if !ValidLangCode(language.Code) {
+		errs.add("code", invalidField("", RuleFormat, 0, errFmtStrLangCode, "Language code"))
+	}
+
+	if err := validateFieldContent(language.Name, "Name", maxLanguageNameLen); err != nil {
+		errs.add("name", err)
+	} else if len(language.Name) == 0 {
+		errs.add("name", invalidField("", RuleRequired, 0, "name field is required"))
+	}
+
+	if len(language.Script) > 0 && !scriptPattern.MatchString(language.Script) {
+		errs.add("script", invalidField("", RuleFormat, 0, "script %s must be an ISO 15924 code such as Latn", language.Script))
+	}
+
+	if language.Direction != "ltr" && language.Direction != "rtl" {
+		errs.add("direction", invalidField("", RuleOneOf, 0, "direction %s must be one of ltr, rtl", language.Direction))
+	}
+
+	errs.add("articles", validateFieldContent(language.Articles, "Articles", maxLanguageArticlesLen))
+	if err := validateFieldContent(language.DefiniteArticles, "Definite articles", maxLanguageArticlesLen); err != nil {
+		errs.add("definite_articles", err)
+	} else {
+		articles := splitArticles(language.Articles)
+		for _, article := range splitArticles(language.DefiniteArticles) {
+			if len(articles) > 0 && indexOfString(articles, article) < 0 {
+				errs.add("definite_articles", invalidField("", RuleOneOf, 0, "definite article %s must also be one of the articles", article))
+				break
+			}
+		}
+	}
+
+	if !language.Enabled && (language.DefaultKnown || language.DefaultLearning) {
+		errs.add("enabled", invalidField("", RuleInvalid, 0, "language %s cannot be disabled while it is a default language", language.Code))
+	}
+
+	return errs.errOrNil()
+}
@@ -0,0 +1,187 @@
+package srv
+
+import (
+	"github.com/heather92115/verdure-admin/internal/db/mock"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"reflect"
+	"testing"
+)
+
+func createMockLanguageService(t *testing.T) LanguageService {
+	// The service reloads the package registry, which the other tests expect to hold the defaults
+	t.Cleanup(func() { setLanguages(mdl.DefaultLanguages) })
+
+	return LanguageService{
+		repo:         mock.NewMockLanguageRepository(mdl.DefaultLanguages...),
+		auditService: AuditService{repo: mock.NewMockAuditRepository()},
+	}
+}
+
+func TestLanguageService_CreateLanguage(t *testing.T) {
+	languageService := createMockLanguageService(t)
+
+	tests := []struct {
+		name     string
+		language *mdl.Language
+		wantErr  bool
+		errMsg   string
+	}{
+		{name: "Regional language", language: &mdl.Language{Code: "pt_br", Name: "Brazilian Portuguese", Script: "latn",
+			Enabled: true, Articles: "O, a, os,, as"}},
+		{name: "Right to left", language: &mdl.Language{Code: "ar", Name: "Arabic", Script: "Arab", Direction: "RTL", Enabled: true}},
+		{name: "Duplicate code", language: &mdl.Language{Code: "ES", Name: "Spanish", Enabled: true}, wantErr: true,
+			errMsg: "language es already exists with id 2"},
+		{name: "Invalid code", language: &mdl.Language{Code: "Klingon", Name: "Klingon"}, wantErr: true,
+			errMsg: "Language code must be a BCP-47 language tag such as es or pt-BR"},
+		{name: "Missing name", language: &mdl.Language{Code: "nl"}, wantErr: true, errMsg: "name field is required"},
+		{name: "Invalid script", language: &mdl.Language{Code: "nl", Name: "Dutch", Script: "Latin"}, wantErr: true,
+			errMsg: "script Latin must be an ISO 15924 code such as Latn"},
+		{name: "Invalid direction", language: &mdl.Language{Code: "nl", Name: "Dutch", Direction: "ttb"}, wantErr: true,
+			errMsg: "direction ttb must be one of ltr, rtl"},
+		{name: "Definite article not an article", language: &mdl.Language{Code: "nl", Name: "Dutch",
+			Articles: "de, het, een", DefiniteArticles: "de, dat"}, wantErr: true,
+			errMsg: "definite article dat must also be one of the articles"},
+		{name: "Disabled default", language: &mdl.Language{Code: "nl", Name: "Dutch", DefaultLearning: true}, wantErr: true,
+			errMsg: "language nl cannot be disabled while it is a default language"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := languageService.CreateLanguage(tt.language, "sys")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateLanguage() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil && err.Error() != tt.errMsg {
+				t.Errorf("CreateLanguage() error = %v, wantErrMsg %v", err, tt.errMsg)
+			}
+		})
+	}
+
+	created := lookupLanguage("pt-BR")
+	if created == nil || created.Script != "Latn" || created.Direction != "ltr" || created.Articles != "o, a, os, as" {
+		t.Errorf("CreateLanguage() did not load the normalized language, got %+v", created)
+	}
+	if ar := lookupLanguage("ar"); ar == nil || ar.Direction != "rtl" {
+		t.Errorf("CreateLanguage() direction = %+v", ar)
+	}
+
+	enabled, err := languageService.FindLanguages(true)
+	if err != nil || len(*enabled) != len(mdl.DefaultLanguages)+2 {
+		t.Errorf("FindLanguages() = %+v, %v", enabled, err)
+	}
+}
+
+func TestLanguageService_UpdateLanguage(t *testing.T) {
+	languageService := createMockLanguageService(t)
+
+	languages, _ := languageService.FindLanguages(false)
+	ids := make(map[string]int)
+	for _, language := range *languages {
+		ids[language.Code] = language.ID
+	}
+
+	enabled, yes := false, true
+	if _, err := languageService.UpdateLanguage(&mdl.LanguagePatch{ID: ids["es"], Enabled: &enabled}, "sys"); err == nil {
+		t.Errorf("UpdateLanguage() expected an error for disabling a default language")
+	}
+
+	language, err := languageService.UpdateLanguage(&mdl.LanguagePatch{ID: ids["fr"], DefaultLearning: &yes}, "sys")
+	if err != nil || !language.DefaultLearning {
+		t.Fatalf("UpdateLanguage() = %+v, %v", language, err)
+	}
+	if _, learning := DefaultLangCodes(); learning != "fr" {
+		t.Errorf("DefaultLangCodes() learning = %s, want fr", learning)
+	}
+	spanish, _ := languageService.repo.FindLanguageByID(ids["es"])
+	if spanish.DefaultLearning || !spanish.Enabled {
+		t.Errorf("UpdateLanguage() did not move the default from es, got %+v", spanish)
+	}
+	audits, _ := languageService.auditService.FindAudits("language", ids["es"], nil, 0)
+	if len(*audits) != 1 || (*audits)[0].Comments != "default language moved to fr" {
+		t.Errorf("UpdateLanguage() audits of es = %+v", audits)
+	}
+
+	if _, err = languageService.UpdateLanguage(&mdl.LanguagePatch{ID: ids["es"], Enabled: &enabled}, "sys"); err != nil {
+		t.Fatalf("UpdateLanguage() error = %v", err)
+	}
+	if _, err = languageService.UpdateLanguage(&mdl.LanguagePatch{ID: ids["es"], Enabled: &enabled}, "sys"); err == nil {
+		t.Errorf("UpdateLanguage() expected an error for no changes")
+	}
+
+	vocab := &mdl.Vocab{LearningLang: "perro", FirstLang: "dog", LearningLangCode: "es-419", KnownLangCode: "en"}
+	if err = validateVocab(vocab); err == nil || err.Error() != "Learning language es is not enabled" {
+		t.Errorf("validateVocab() error = %v", err)
+	}
+}
+
+func TestValidateManagedLangCode(t *testing.T) {
+	tests := []struct {
+		code    string
+		wantErr bool
+	}{
+		{code: "es"},
+		{code: "pt-BR"},
+		{code: "es-419"},
+		{code: "nl", wantErr: true},
+		{code: "nl-BE", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := validateManagedLangCode(tt.code, "Learning language"); (err != nil) != tt.wantErr {
+			t.Errorf("validateManagedLangCode(%q) error = %v, wantErr %v", tt.code, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNormalizeLearningText(t *testing.T) {
+	tests := []struct {
+		text     string
+		langCode string
+		want     string
+	}{
+		{text: "col.legi", langCode: "ca", want: "col·legi"},
+		{text: "coŀlegi", langCode: "ca", want: "col·legi"},
+		{text: "CoĿLEGI", langCode: "ca", want: "CoL·LEGI"},
+		{text: "il•lusió", langCode: "ca", want: "il·lusió"},
+		{text: "col.legi", langCode: "es", want: "col.legi"},
+		{text: " sol. ", langCode: "ca", want: "sol."},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeLearningText(tt.text, tt.langCode); got != tt.want {
+			t.Errorf("NormalizeLearningText(%q, %q) = %q, want %q", tt.text, tt.langCode, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterLanguageHooks(t *testing.T) {
+	t.Cleanup(func() { delete(languageHooks, "nl") })
+
+	RegisterLanguageHooks("NL", LanguageHooks{Tokenize: func(text string) []string { return []string{text} }})
+
+	if got := TokenizeLearningLang("de hond", "nl-BE"); !reflect.DeepEqual(got, []string{"de hond"}) {
+		t.Errorf("TokenizeLearningLang() = %v, want the hook tokens", got)
+	}
+	if got := LanguageHookNames("nl"); !reflect.DeepEqual(got, []string{"tokenize"}) {
+		t.Errorf("LanguageHookNames() = %v", got)
+	}
+	if got := LanguageHookNames("es"); !reflect.DeepEqual(got, []string{"lint", "grammar"}) {
+		t.Errorf("LanguageHookNames(es) = %v", got)
+	}
+}
+
+func TestVocabService_CreateVocabDefaultLanguages(t *testing.T) {
+	vocabService := createMockVocabService()
+
+	vocab := &mdl.Vocab{LearningLang: "gato", FirstLang: "cat"}
+	if err := vocabService.CreateVocab(vocab, false, "sys"); err != nil {
+		t.Fatalf("CreateVocab() error = %v", err)
+	}
+	if vocab.KnownLangCode != "en" || vocab.LearningLangCode != "es" {
+		t.Errorf("CreateVocab() codes = %s, %s, want en, es", vocab.KnownLangCode, vocab.LearningLangCode)
+	}
+
+	unmanaged := &mdl.Vocab{LearningLang: "kat", FirstLang: "cat", LearningLangCode: "nl"}
+	if err := vocabService.CreateVocab(unmanaged, false, "sys"); err == nil || err.Error() != "Learning language nl is not a managed language" {
+		t.Errorf("CreateVocab() error = %v", err)
+	}
+}
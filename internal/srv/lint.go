@@ -0,0 +1,215 @@
+package srv
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/lint"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"strings"
+)
+
+// linterPrincipal is the CreatedBy of the fixits and audits written by the linter.
+const linterPrincipal = "linter"
+
+// LintResult holds the lint findings for a single Vocab record.
+type LintResult struct {
+	Vocab    mdl.Vocab
+	Findings []lint.Finding
+}
+
+// LintService runs the content lint rules over Vocab records and files Fixits for the findings.
+type LintService struct {
+	vocabRepo    db.VocabRepository
+	fixitRepo    db.FixitRepository
+	auditService AuditService
+	engine       *lint.Engine
+}
+
+// NewLintService creates a new instance of LintService running the default lint rules, with
+// the managed parts of speech as the known values, and the lint rules of each learning language.
+func NewLintService() (*LintService, error) {
+
+	vocabRepo, err := db.NewSqlVocabRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	fixitRepo, err := db.NewSqlFixitRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	lookupRepo, err := db.NewSqlLookupRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := NewAuditService()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = loadManagedLanguages(); err != nil {
+		return nil, err
+	}
+
+	// The managed parts of speech replace the defaults once any are defined
+	knownPos := lint.KnownPos()
+	posList, err := lookupRepo.FindPartsOfSpeech()
+	if err != nil {
+		return nil, err
+	}
+	if len(*posList) > 0 {
+		knownPos = make([]string, len(*posList))
+		for i, pos := range *posList {
+			knownPos[i] = pos.Name
+		}
+	}
+
+	return &LintService{
+		vocabRepo:    vocabRepo,
+		fixitRepo:    fixitRepo,
+		auditService: *auditService,
+		engine:       lint.NewEngine(lint.DefaultRules(knownPos)...).WithLanguageRules(LanguageLintRules),
+	}, nil
+}
+
+// LintVocab runs the lint rules over a single Vocab record.
+//
+// Parameters:
+// - id: The primary ID of the Vocab record to lint.
+//
+// Returns:
+// - A pointer to the LintResult, whose findings are empty when the vocab passes every rule.
+// - An error if the vocab cannot be found.
+//
+// Usage example:
+// result, err := lintService.LintVocab(123)
+//
+//	if err != nil {
+//	    log.Printf("Failed to lint vocab: %v", err)
+//	}
+func (s *LintService) LintVocab(id int) (*LintResult, error) {
+
+	vocab, err := s.vocabRepo.FindVocabByID(id)
+	if err != nil {
+		return nil, err
+	} else if vocab == nil {
+		return nil, fmt.Errorf("expected to find existing vocab with id %d", id)
+	}
+
+	return &LintResult{Vocab: *vocab, Findings: s.engine.Lint(vocab)}, nil
+}
+
+// LintVocabs scans every Vocab record for the learning language code and runs the lint rules
+// over them, returning only the vocab with findings.
+//
+// Parameters:
+// - learningCode: The learning language code to scan, or empty to scan every record.
+//
+// Returns:
+// - The results of the vocab with findings, in primary key order.
+// - An error if the scan fails.
+//
+// Usage example:
+// results, err := lintService.LintVocabs("es")
+//
+//	if err != nil {
+//	    log.Printf("Failed to lint vocabs: %v", err)
+//	}
+func (s *LintService) LintVocabs(learningCode string) (results []LintResult, err error) {
+
+	err = s.vocabRepo.ScanVocabs(learningCode, scanBatchSize, func(batch *[]mdl.Vocab) error {
+		for i := range *batch {
+			vocab := &(*batch)[i]
+			if findings := s.engine.Lint(vocab); len(findings) > 0 {
+				results = append(results, LintResult{Vocab: *vocab, Findings: findings})
+			}
+		}
+		return nil
+	})
+
+	return
+}
+
+// FileFixits creates a pending Fixit, created by the linter, for each finding of the results.
+// A finding is skipped when the vocab already has an open fixit, pending or in progress,
+// filed by the linter for the same rule and field, so running the linter again does not
+// duplicate them. Each created fixit is audited.
+//
+// Parameters:
+// - results: The lint results to file fixits for, see LintVocab and LintVocabs.
+//
+// Returns:
+// - The fixits created.
+// - An error if the open fixits cannot be found or a fixit cannot be saved.
+//
+// Usage example:
+// fixits, err := lintService.FileFixits(results)
+//
+//	if err != nil {
+//	    log.Printf("Failed to file lint fixits: %v", err)
+//	}
+func (s *LintService) FileFixits(results []LintResult) (created []mdl.Fixit, err error) {
+
+	if len(results) == 0 {
+		return
+	}
+
+	vocabIDs := make([]int, len(results))
+	for i, result := range results {
+		vocabIDs[i] = result.Vocab.ID
+	}
+
+	existing, err := s.fixitRepo.FindFixitsForVocabs(vocabIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	open := make(map[string]bool)
+	for _, fixit := range *existing {
+		if fixit.CreatedBy == linterPrincipal && fixit.Status != mdl.Completed {
+			open[lintFixitKey(fixit.VocabID, fixit.FieldName, fixit.Comments)] = true
+		}
+	}
+
+	for _, result := range results {
+		for _, finding := range result.Findings {
+			fixit := mdl.Fixit{
+				VocabID:   result.Vocab.ID,
+				Status:    mdl.Pending,
+				FieldName: finding.FieldName,
+				Comments:  fmt.Sprintf("%s: %s", finding.Rule, finding.Message),
+				CreatedBy: linterPrincipal,
+			}
+
+			key := lintFixitKey(fixit.VocabID, fixit.FieldName, fixit.Comments)
+			if open[key] {
+				continue
+			}
+
+			if err = validateFixit(&fixit, nil); err != nil {
+				return
+			}
+			if err = s.fixitRepo.CreateFixit(&fixit); err != nil {
+				return
+			}
+			comments := fmt.Sprintf("created fixit for lint rule %s", finding.Rule)
+			if err = s.auditService.CreateFixitAudit(comments, linterPrincipal, nil, &fixit); err != nil {
+				return
+			}
+			publishFixitChanged(&fixit)
+
+			open[key] = true
+			created = append(created, fixit)
+		}
+	}
+
+	return
+}
+
+// lintFixitKey identifies the rule and field of a linter fixit, its comments start with the rule name.
+func lintFixitKey(vocabID int, fieldName string, comments string) string {
+	rule, _, _ := strings.Cut(comments, ":")
+	return fmt.Sprintf("%d/%s/%s", vocabID, fieldName, rule)
+}
@@ -0,0 +1,69 @@
+package srv
+
+import (
+	"github.com/heather92115/verdure-admin/internal/db/mock"
+	"github.com/heather92115/verdure-admin/internal/lint"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"testing"
+)
+
+func TestLintService_FileFixits(t *testing.T) {
+	lintService := createMockLintService()
+
+	_ = lintService.vocabRepo.CreateVocab(&mdl.Vocab{LearningLang: "perro", FirstLang: "dog", Pos: "noun", LearningLangCode: "es"}, nil)
+	_ = lintService.vocabRepo.CreateVocab(&mdl.Vocab{LearningLang: "gato", FirstLang: "cat", Pos: "noun", Hint: "pet", LearningLangCode: "es"}, nil)
+	_ = lintService.vocabRepo.CreateVocab(&mdl.Vocab{LearningLang: "hablo", FirstLang: "hablo", Pos: "verb", Hint: "yo", LearningLangCode: "es"}, nil)
+
+	results, err := lintService.LintVocabs("es")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[0].Vocab.ID != 1 || results[1].Vocab.ID != 3 {
+		t.Fatalf("LintVocabs() = %+v, want vocab 1 and 3", results)
+	}
+
+	fixits, err := lintService.FileFixits(results)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(fixits) != 3 {
+		t.Fatalf("FileFixits() created %d fixits, want 3", len(fixits))
+	}
+	for _, fixit := range fixits {
+		if fixit.CreatedBy != linterPrincipal || fixit.Status != mdl.Pending {
+			t.Errorf("FileFixits() created %+v", fixit)
+		}
+	}
+	if fixits[0].FieldName != "hint" || fixits[0].Comments != "missing-hint: hint is missing" {
+		t.Errorf("FileFixits() first fixit = %+v", fixits[0])
+	}
+
+	// Running again must not duplicate the open fixits.
+	fixits, err = lintService.FileFixits(results)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(fixits) != 0 {
+		t.Errorf("FileFixits() duplicated %d open fixits", len(fixits))
+	}
+
+	// Once completed, a finding that persists is filed again.
+	completed, _ := lintService.fixitRepo.FindFixitByID(1)
+	completed.Status = mdl.Completed
+	_ = lintService.fixitRepo.UpdateFixit(completed)
+
+	fixits, _ = lintService.FileFixits(results)
+	if len(fixits) != 1 || fixits[0].VocabID != 1 {
+		t.Errorf("FileFixits() after completion = %+v, want one fixit for vocab 1", fixits)
+	}
+}
+
+func createMockLintService() LintService {
+	auditRepo := mock.NewMockAuditRepository()
+	return LintService{
+		vocabRepo:    mock.NewMockVocabRepository(auditRepo, mock.NewMockAlternativeRepository()),
+		fixitRepo:    mock.NewMockFixitRepository(),
+		auditService: AuditService{repo: auditRepo},
+		engine:       lint.NewEngine(lint.DefaultRules(lint.KnownPos())...),
+	}
+}
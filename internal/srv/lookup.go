@@ -0,0 +1,580 @@
+package srv
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"strings"
+)
+
+const (
+	maxTermDescriptionLen = 255
+	maxTermAliasesLen     = 255
+)
+
+// LookupService handles business logic for the managed PartOfSpeech and Skill lookup tables.
+type LookupService struct {
+	repo         db.LookupRepository
+	auditService AuditService
+}
+
+// NewLookupService creates a new instance of LookupService.
+func NewLookupService() (*LookupService, error) {
+
+	repo, err := db.NewSqlLookupRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := NewAuditService()
+	if err != nil {
+		return nil, err
+	}
+
+	return &LookupService{repo: repo, auditService: *auditService}, nil
+}
+
+// FindPartsOfSpeech retrieves every managed part of speech, ordered by name.
+func (s *LookupService) FindPartsOfSpeech() (*[]mdl.PartOfSpeech, error) {
+	return s.repo.FindPartsOfSpeech()
+}
+
+// FindSkills retrieves every managed skill, ordered by name.
+func (s *LookupService) FindSkills() (*[]mdl.Skill, error) {
+	return s.repo.FindSkills()
+}
+
+// CreatePartOfSpeech adds a managed part of speech after checking that neither its name nor
+// its aliases are already used by another part of speech, then writes an audit entry.
+//
+// Parameters:
+// - pos: A pointer to the mdl.PartOfSpeech to create, its ID is set on success.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - An error if validation fails, the name or an alias is taken, or saving fails.
+//
+// Usage example:
+// err := lookupService.CreatePartOfSpeech(&mdl.PartOfSpeech{Name: "verb", Aliases: "v., verbo"}, srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to create part of speech: %v", err)
+//	}
+func (s *LookupService) CreatePartOfSpeech(pos *mdl.PartOfSpeech, actor string) (err error) {
+
+	normalizeTerm(&pos.Name, &pos.Description, &pos.Aliases)
+
+	existing, err := s.repo.FindPartsOfSpeech()
+	if err != nil {
+		return
+	}
+	if err = validateTerm("Part of speech", maxPosLen, pos.ID, pos.Name, pos.Description, pos.Aliases, posTerms(existing)); err != nil {
+		return
+	}
+
+	if err = s.repo.CreatePartOfSpeech(pos); err != nil {
+		return
+	}
+
+	return s.auditService.CreateAudit("part_of_speech", pos.ID, "created part of speech", actor, "", pos.JSON())
+}
+
+// UpdatePartOfSpeech applies a partial update to a managed part of speech. A part of speech
+// used by vocab cannot be renamed, since the vocab would no longer conform.
+//
+// Parameters:
+// - patch: The fields to change, only the non-nil ones are applied.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the updated mdl.PartOfSpeech.
+// - An error if it cannot be found, is renamed while in use, fails validation, or saving fails.
+//
+// Usage example:
+// pos, err := lookupService.UpdatePartOfSpeech(&mdl.TermPatch{ID: 3, Aliases: &aliases}, srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to update part of speech: %v", err)
+//	}
+func (s *LookupService) UpdatePartOfSpeech(patch *mdl.TermPatch, actor string) (pos *mdl.PartOfSpeech, err error) {
+
+	before, err := s.repo.FindPartOfSpeechByID(patch.ID)
+	if err != nil {
+		return
+	}
+
+	after := *before
+	pos = &after
+	if !applyTermPatch(patch, &pos.Name, &pos.Description, &pos.Aliases) {
+		return nil, fmt.Errorf("update for part of speech %d has no changes", patch.ID)
+	}
+
+	if pos.Name != before.Name {
+		if err = s.checkTermUnused("pos", "part of speech", before.Name, "renamed"); err != nil {
+			return nil, err
+		}
+	}
+
+	existing, err := s.repo.FindPartsOfSpeech()
+	if err != nil {
+		return nil, err
+	}
+	if err = validateTerm("Part of speech", maxPosLen, pos.ID, pos.Name, pos.Description, pos.Aliases, posTerms(existing)); err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.UpdatePartOfSpeech(pos); err != nil {
+		return nil, err
+	}
+
+	err = s.auditService.CreateAudit("part_of_speech", pos.ID, "updated part of speech", actor, before.JSON(), pos.JSON())
+
+	return
+}
+
+// DeletePartOfSpeech removes a managed part of speech that no vocab uses, and writes an audit entry.
+//
+// Parameters:
+// - id: The primary ID of the part of speech.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - An error if it cannot be found, is used by vocab, or deleting fails.
+func (s *LookupService) DeletePartOfSpeech(id int, actor string) (err error) {
+
+	before, err := s.repo.FindPartOfSpeechByID(id)
+	if err != nil {
+		return
+	}
+	if err = s.checkTermUnused("pos", "part of speech", before.Name, "deleted"); err != nil {
+		return
+	}
+
+	if err = s.repo.DeletePartOfSpeech(id); err != nil {
+		return
+	}
+
+	return s.auditService.CreateAudit("part_of_speech", id, "deleted part of speech", actor, before.JSON(), "")
+}
+
+// CreateSkill adds a managed skill after checking that neither its name nor its aliases are
+// already used by another skill, and that its parent exists, then writes an audit entry.
+//
+// Parameters:
+// - skill: A pointer to the mdl.Skill to create, its ID is set on success.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - An error if validation fails, the name or an alias is taken, the parent is unknown, or saving fails.
+//
+// Usage example:
+// err := lookupService.CreateSkill(&mdl.Skill{Name: "Pets", ParentID: &lessonID, SortOrder: 2}, srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to create skill: %v", err)
+//	}
+func (s *LookupService) CreateSkill(skill *mdl.Skill, actor string) (err error) {
+
+	normalizeTerm(&skill.Name, &skill.Description, &skill.Aliases)
+
+	existing, err := s.repo.FindSkills()
+	if err != nil {
+		return
+	}
+	if err = validateTerm("Skill", maxSkillLen, skill.ID, skill.Name, skill.Description, skill.Aliases, skillTerms(existing)); err != nil {
+		return
+	}
+	if err = validateSkillParent(skill, existing); err != nil {
+		return
+	}
+
+	if err = s.repo.CreateSkill(skill); err != nil {
+		return
+	}
+
+	return s.auditService.CreateAudit("skill", skill.ID, "created skill", actor, "", skill.JSON())
+}
+
+// UpdateSkill applies a partial update to a managed skill, including moving it within the
+// taxonomy. A skill used by vocab cannot be renamed, since the vocab would no longer conform,
+// and a skill cannot be moved under itself or one of its descendants.
+//
+// Parameters:
+// - patch: The fields to change, only the non-nil ones are applied.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the updated mdl.Skill.
+// - An error if it cannot be found, is renamed while in use, fails validation, or saving fails.
+func (s *LookupService) UpdateSkill(patch *mdl.SkillPatch, actor string) (skill *mdl.Skill, err error) {
+
+	before, err := s.repo.FindSkillByID(patch.ID)
+	if err != nil {
+		return
+	}
+
+	after := *before
+	skill = &after
+	changed := applyTermPatch(&patch.TermPatch, &skill.Name, &skill.Description, &skill.Aliases)
+	changed = applySkillPatch(patch, skill) || changed
+	if !changed {
+		return nil, fmt.Errorf("update for skill %d has no changes", patch.ID)
+	}
+
+	if skill.Name != before.Name {
+		if err = s.checkTermUnused("skill", "skill", before.Name, "renamed"); err != nil {
+			return nil, err
+		}
+	}
+
+	existing, err := s.repo.FindSkills()
+	if err != nil {
+		return nil, err
+	}
+	if err = validateTerm("Skill", maxSkillLen, skill.ID, skill.Name, skill.Description, skill.Aliases, skillTerms(existing)); err != nil {
+		return nil, err
+	}
+	if err = validateSkillParent(skill, existing); err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.UpdateSkill(skill); err != nil {
+		return nil, err
+	}
+
+	err = s.auditService.CreateAudit("skill", skill.ID, "updated skill", actor, before.JSON(), skill.JSON())
+
+	return
+}
+
+// DeleteSkill removes a managed skill that has no child skills and that no vocab uses, and
+// writes an audit entry.
+//
+// Parameters:
+// - id: The primary ID of the skill.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - An error if it cannot be found, has child skills, is used by vocab, or deleting fails.
+func (s *LookupService) DeleteSkill(id int, actor string) (err error) {
+
+	before, err := s.repo.FindSkillByID(id)
+	if err != nil {
+		return
+	}
+
+	existing, err := s.repo.FindSkills()
+	if err != nil {
+		return
+	}
+	for _, other := range *existing {
+		if other.ParentID != nil && *other.ParentID == id {
+			return fmt.Errorf("skill %s contains skill %s and cannot be deleted", before.Name, other.Name)
+		}
+	}
+
+	if err = s.checkTermUnused("skill", "skill", before.Name, "deleted"); err != nil {
+		return
+	}
+
+	if err = s.repo.DeleteSkill(id); err != nil {
+		return
+	}
+
+	return s.auditService.CreateAudit("skill", id, "deleted skill", actor, before.JSON(), "")
+}
+
+// checkTermUnused returns an error when any vocab uses the value in the given field.
+func (s *LookupService) checkTermUnused(field string, label string, value string, action string) error {
+	counts, err := s.repo.CountVocabValues(field)
+	if err != nil {
+		return err
+	}
+
+	for _, count := range *counts {
+		if count.Value == value && count.Count > 0 {
+			return fmt.Errorf("%s %s is used by %d vocab and cannot be %s", label, value, count.Count, action)
+		}
+	}
+
+	return nil
+}
+
+// NonConformingTerm is a Pos or Skill value used by vocab that is not a managed name,
+// with the managed name it most likely means.
+type NonConformingTerm struct {
+	Field      string
+	Value      string
+	Count      int
+	Suggestion string
+}
+
+// FindNonConformingTerms reports the Pos and Skill values used by vocab that are not the name
+// of a managed part of speech or skill, with a suggested mapping for each. A suggestion is made
+// when the value differs from a name or alias only by case, accents or a small typo, or is an
+// abbreviation of exactly one name. Empty values are not reported.
+//
+// Returns:
+// - The non-conforming values, part of speech values first, each ordered by value.
+// - An error if the lookup tables or the vocab values cannot be read.
+//
+// Usage example:
+// terms, err := lookupService.FindNonConformingTerms()
+//
+//	if err != nil {
+//	    log.Printf("Failed to find non-conforming terms: %v", err)
+//	}
+func (s *LookupService) FindNonConformingTerms() (terms []NonConformingTerm, err error) {
+
+	vocabTerms, err := loadVocabTerms(s.repo)
+	if err != nil {
+		return
+	}
+
+	for _, field := range []struct {
+		name  string
+		index termIndex
+	}{
+		{name: "pos", index: vocabTerms.pos},
+		{name: "skill", index: vocabTerms.skills},
+	} {
+		counts, err := s.repo.CountVocabValues(field.name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, count := range *counts {
+			if len(strings.TrimSpace(count.Value)) == 0 || field.index.names[count.Value] {
+				continue
+			}
+			terms = append(terms, NonConformingTerm{
+				Field:      field.name,
+				Value:      count.Value,
+				Count:      count.Count,
+				Suggestion: field.index.suggest(count.Value),
+			})
+		}
+	}
+
+	return
+}
+
+// VocabTerms holds the managed parts of speech and skills used to validate vocab.
+type VocabTerms struct {
+	pos    termIndex
+	skills termIndex
+}
+
+// termIndex maps the folded names and aliases of managed terms to their canonical names.
+type termIndex struct {
+	names   map[string]bool
+	aliases map[string]string
+	ids     map[string]int
+}
+
+// term is the common form of a managed part of speech or skill.
+type term struct {
+	id      int
+	name    string
+	aliases string
+}
+
+func posTerms(list *[]mdl.PartOfSpeech) []term {
+	terms := make([]term, len(*list))
+	for i, pos := range *list {
+		terms[i] = term{id: pos.ID, name: pos.Name, aliases: pos.Aliases}
+	}
+	return terms
+}
+
+func skillTerms(list *[]mdl.Skill) []term {
+	terms := make([]term, len(*list))
+	for i, skill := range *list {
+		terms[i] = term{id: skill.ID, name: skill.Name, aliases: skill.Aliases}
+	}
+	return terms
+}
+
+// loadVocabTerms reads the lookup tables into a VocabTerms.
+func loadVocabTerms(repo db.LookupRepository) (*VocabTerms, error) {
+	posList, err := repo.FindPartsOfSpeech()
+	if err != nil {
+		return nil, err
+	}
+	skillList, err := repo.FindSkills()
+	if err != nil {
+		return nil, err
+	}
+
+	return &VocabTerms{pos: newTermIndex(posTerms(posList)), skills: newTermIndex(skillTerms(skillList))}, nil
+}
+
+func newTermIndex(terms []term) termIndex {
+	index := termIndex{names: make(map[string]bool), aliases: make(map[string]string), ids: make(map[string]int)}
+	for _, t := range terms {
+		index.names[t.name] = true
+		index.ids[t.name] = t.id
+		index.aliases[foldTerm(t.name)] = t.name
+		for _, alias := range ParseAlternatives(t.aliases) {
+			index.aliases[foldTerm(alias)] = t.name
+		}
+	}
+	return index
+}
+
+// canonical returns the managed name a value stands for, matching names and aliases ignoring case.
+func (t termIndex) canonical(value string) (string, bool) {
+	name, found := t.aliases[foldTerm(value)]
+	return name, found
+}
+
+// suggest returns the managed name a non-conforming value most likely means, or empty.
+func (t termIndex) suggest(value string) string {
+	if name, found := t.canonical(value); found {
+		return name
+	}
+
+	key := foldAccents(foldTerm(value))
+
+	best, bestDistance := "", 3
+	for alias, name := range t.aliases {
+		distance := DamerauDistance(key, foldAccents(alias))
+		if distance < bestDistance && distance < len([]rune(key))/2 {
+			best, bestDistance = name, distance
+		}
+	}
+	if len(best) > 0 {
+		return best
+	}
+
+	prefix := strings.TrimSuffix(key, ".")
+	if len([]rune(prefix)) < 2 {
+		return ""
+	}
+	for name := range t.names {
+		if strings.HasPrefix(foldAccents(foldTerm(name)), prefix) {
+			if len(best) > 0 {
+				return ""
+			}
+			best = name
+		}
+	}
+	return best
+}
+
+// conformVocabTerms replaces the vocab Pos and Skill, when checked, with the managed names
+// they stand for, and links the vocab to its managed skill by SkillID. A value that is not a
+// managed name or alias is collected in a *ValidationError. A field whose lookup table is
+// empty is not checked, so skills are only enforced once they are loaded.
+func (t *VocabTerms) conformVocabTerms(vocab *mdl.Vocab, checkPos bool, checkSkill bool) error {
+	errs := &ValidationError{}
+
+	if checkPos && len(vocab.Pos) > 0 && len(t.pos.names) > 0 {
+		if name, found := t.pos.canonical(vocab.Pos); found {
+			vocab.Pos = name
+		} else {
+			errs.add("pos", invalidField("", RuleOneOf, 0, "part of speech %s is not a managed value", vocab.Pos))
+		}
+	}
+
+	if checkSkill {
+		vocab.SkillID = nil
+		if len(vocab.Skill) > 0 && len(t.skills.names) > 0 {
+			if name, found := t.skills.canonical(vocab.Skill); found {
+				id := t.skills.ids[name]
+				vocab.Skill = name
+				vocab.SkillID = &id
+			} else {
+				errs.add("skill", invalidField("", RuleOneOf, 0, "skill %s is not a managed value", vocab.Skill))
+			}
+		}
+	}
+
+	return errs.errOrNil()
+}
+
+// conformVocabTerms loads the lookup tables and conforms the vocab Pos and Skill to them,
+// see VocabTerms.conformVocabTerms.
+func (s *VocabService) conformVocabTerms(vocab *mdl.Vocab, checkPos bool, checkSkill bool) error {
+	if !checkPos && !checkSkill {
+		return nil
+	}
+
+	terms, err := loadVocabTerms(s.lookupRepo)
+	if err != nil {
+		return err
+	}
+
+	return terms.conformVocabTerms(vocab, checkPos, checkSkill)
+}
+
+// foldTerm lower cases and trims a term for comparison.
+func foldTerm(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// normalizeTerm applies NormalizeText to the free text fields of a term.
+func normalizeTerm(name *string, description *string, aliases *string) {
+	*name = NormalizeText(*name)
+	*description = NormalizeText(*description)
+	*aliases = NormalizeText(*aliases)
+}
+
+// applyTermPatch applies the non-nil, normalized patch fields and reports whether anything changed.
+func applyTermPatch(patch *mdl.TermPatch, name *string, description *string, aliases *string) (changed bool) {
+	for _, field := range []struct {
+		value  *string
+		target *string
+	}{
+		{value: patch.Name, target: name},
+		{value: patch.Description, target: description},
+		{value: patch.Aliases, target: aliases},
+	} {
+		if field.value == nil {
+			continue
+		}
+		value := NormalizeText(*field.value)
+		if value != *field.target {
+			*field.target = value
+			changed = true
+		}
+	}
+	return
+}
+
+// validateTerm checks the fields of a part of speech or skill, and that its name and aliases
+// are not the name or an alias of another term of the same kind. The failures are collected
+// in a *ValidationError.
+func validateTerm(label string, maxNameLen int, id int, name string, description string, aliases string, existing []term) error {
+	errs := &ValidationError{}
+	if len(name) == 0 {
+		errs.add("name", invalidField("", RuleRequired, 0, "%s name is required", strings.ToLower(label)))
+	} else {
+		errs.add("name", validateFieldContent(name, label, maxNameLen))
+	}
+	errs.add("description", validateFieldContent(description, label+" description", maxTermDescriptionLen))
+	errs.add("aliases", validateFieldContent(aliases, label+" aliases", maxTermAliasesLen))
+	if err := errs.errOrNil(); err != nil {
+		return err
+	}
+
+	var others []term
+	for _, t := range existing {
+		if t.id != id {
+			others = append(others, t)
+		}
+	}
+	index := newTermIndex(others)
+
+	for i, value := range append([]string{name}, ParseAlternatives(aliases)...) {
+		if taken, found := index.canonical(value); found {
+			field := "aliases"
+			if i == 0 {
+				field = "name"
+			}
+			return invalidField(field, RuleUnique, 0, "%s %s is already used by %s", strings.ToLower(label), value, taken)
+		}
+	}
+
+	return nil
+}
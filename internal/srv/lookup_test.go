@@ -0,0 +1,136 @@
+package srv
+
+import (
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"reflect"
+	"testing"
+)
+
+func TestLookupService_PartOfSpeech(t *testing.T) {
+	lookupService, _ := createMockLookupService()
+
+	tests := []struct {
+		name    string
+		pos     *mdl.PartOfSpeech
+		wantErr bool
+		errMsg  string
+	}{
+		{name: "Created", pos: &mdl.PartOfSpeech{Name: "verb", Aliases: "v., verbo"}},
+		{name: "Second created", pos: &mdl.PartOfSpeech{Name: "noun", Aliases: "n."}},
+		{name: "Name required", pos: &mdl.PartOfSpeech{Name: " "}, wantErr: true, errMsg: "part of speech name is required"},
+		{name: "Name taken ignoring case", pos: &mdl.PartOfSpeech{Name: "Verb"}, wantErr: true, errMsg: "part of speech Verb is already used by verb"},
+		{name: "Alias taken", pos: &mdl.PartOfSpeech{Name: "adverb", Aliases: "adv., V."}, wantErr: true, errMsg: "part of speech V. is already used by verb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := lookupService.CreatePartOfSpeech(tt.pos, "sys")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreatePartOfSpeech() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil && err.Error() != tt.errMsg {
+				t.Errorf("CreatePartOfSpeech() error = %v, wantErrMsg %v", err, tt.errMsg)
+			}
+		})
+	}
+
+	list, _ := lookupService.FindPartsOfSpeech()
+	if len(*list) != 2 || (*list)[0].Name != "noun" {
+		t.Errorf("FindPartsOfSpeech() = %+v", *list)
+	}
+
+	aliases := "v., verbo, vb"
+	updated, err := lookupService.UpdatePartOfSpeech(&mdl.TermPatch{ID: 1, Aliases: &aliases}, "sys")
+	if err != nil || updated.Aliases != aliases {
+		t.Errorf("UpdatePartOfSpeech() = %+v, %v", updated, err)
+	}
+	if _, err = lookupService.UpdatePartOfSpeech(&mdl.TermPatch{ID: 1, Aliases: &aliases}, "sys"); err == nil {
+		t.Errorf("Expected an error for an update without changes")
+	}
+}
+
+func TestLookupService_TermsInUse(t *testing.T) {
+	lookupService, vocabService := createMockLookupService()
+
+	_ = lookupService.CreatePartOfSpeech(&mdl.PartOfSpeech{Name: "verb", Aliases: "v., verbo"}, "sys")
+	_ = lookupService.CreatePartOfSpeech(&mdl.PartOfSpeech{Name: "noun"}, "sys")
+
+	vocab := &mdl.Vocab{LearningLang: "hablar", FirstLang: "to speak", Pos: "Verbo", LearningLangCode: "es", KnownLangCode: "en"}
+	if err := vocabService.CreateVocab(vocab, false, "sys"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if vocab.Pos != "verb" {
+		t.Errorf("CreateVocab() pos = %s, want the canonical verb", vocab.Pos)
+	}
+
+	err := vocabService.CreateVocab(&mdl.Vocab{LearningLang: "perro", FirstLang: "dog", Pos: "nombre",
+		LearningLangCode: "es", KnownLangCode: "en"}, false, "sys")
+	if err == nil || err.Error() != "part of speech nombre is not a managed value" {
+		t.Errorf("CreateVocab() error = %v, want a non managed pos error", err)
+	}
+
+	// Skills are not checked until the skill table has entries.
+	err = vocabService.CreateVocab(&mdl.Vocab{LearningLang: "gato", FirstLang: "cat", Pos: "noun", Skill: "Pets",
+		LearningLangCode: "es", KnownLangCode: "en"}, false, "sys")
+	if err != nil {
+		t.Errorf("CreateVocab() error = %v with no managed skills", err)
+	}
+
+	newName := "verbs"
+	if _, err = lookupService.UpdatePartOfSpeech(&mdl.TermPatch{ID: 1, Name: &newName}, "sys"); err == nil {
+		t.Errorf("Expected an error renaming a part of speech in use")
+	}
+	if err = lookupService.DeletePartOfSpeech(1, "sys"); err == nil {
+		t.Errorf("Expected an error deleting a part of speech in use")
+	}
+}
+
+func TestLookupService_FindNonConformingTerms(t *testing.T) {
+	lookupService, vocabService := createMockLookupService()
+
+	_ = lookupService.CreatePartOfSpeech(&mdl.PartOfSpeech{Name: "verb", Aliases: "v., verbo"}, "sys")
+	_ = lookupService.CreatePartOfSpeech(&mdl.PartOfSpeech{Name: "adjective"}, "sys")
+	_ = lookupService.CreateSkill(&mdl.Skill{Name: "Pets"}, "sys")
+
+	// Seed production style values directly in the repository, bypassing validation.
+	for _, v := range []mdl.Vocab{
+		{LearningLang: "hablar", Pos: "verb", Skill: "Pets"},
+		{LearningLang: "comer", Pos: "Verb"},
+		{LearningLang: "beber", Pos: "v."},
+		{LearningLang: "vivir", Pos: "verbo"},
+		{LearningLang: "correr", Pos: "vreb"},
+		{LearningLang: "rojo", Pos: "adj"},
+		{LearningLang: "azul", Pos: "adj"},
+		{LearningLang: "gato", Pos: "xyz", Skill: "pets"},
+	} {
+		vocab := v
+		_ = vocabService.repo.CreateVocab(&vocab, nil)
+	}
+
+	terms, err := lookupService.FindNonConformingTerms()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []NonConformingTerm{
+		{Field: "pos", Value: "Verb", Count: 1, Suggestion: "verb"},
+		{Field: "pos", Value: "adj", Count: 2, Suggestion: "adjective"},
+		{Field: "pos", Value: "v.", Count: 1, Suggestion: "verb"},
+		{Field: "pos", Value: "verbo", Count: 1, Suggestion: "verb"},
+		{Field: "pos", Value: "vreb", Count: 1, Suggestion: "verb"},
+		{Field: "pos", Value: "xyz", Count: 1, Suggestion: ""},
+		{Field: "skill", Value: "pets", Count: 1, Suggestion: "Pets"},
+	}
+	if !reflect.DeepEqual(terms, want) {
+		t.Errorf("FindNonConformingTerms() = %+v, want %+v", terms, want)
+	}
+}
+
+// createMockLookupService returns a lookup service and a vocab service sharing the same mock repositories.
+func createMockLookupService() (LookupService, VocabService) {
+	vocabService := createMockVocabService()
+	lookupService := LookupService{
+		repo:         vocabService.lookupRepo,
+		auditService: vocabService.auditService,
+	}
+	return lookupService, vocabService
+}
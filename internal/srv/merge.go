@@ -0,0 +1,215 @@
+package srv
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"strings"
+)
+
+// hintSeparator delimits the hints folded together by a merge.
+const hintSeparator = "; "
+
+// MergeVocabs merges duplicate Vocab records into the one that is kept. The learning langs
+// and alternatives of the merged vocab become alternatives of the kept vocab and their hints
+// are appended to its hint. Fixits of the merged vocab are re-pointed to the kept vocab, and
+// the merged vocab are archived and removed. All of these changes, and an audit entry for
+// every affected row, are saved in a single transaction. As for UpdateVocab, a merge cannot
+// change a field of the kept vocab requiring review, see VocabService.reviewFields.
+//
+// Parameters:
+// - keepID: The primary ID of the Vocab record that survives the merge.
+// - mergeIDs: The primary IDs of the Vocab records merged into it.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+//   - A pointer to the kept mdl.Vocab record including its alternatives.
+//   - An error if any vocab cannot be found, the IDs are invalid, the vocab are in different
+//     learning languages, the folded vocab is invalid or changes a field requiring review, or
+//     saving fails.
+//
+// Usage example:
+// vocab, err := vocabService.MergeVocabs(123, []int{456, 789}, srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to merge vocabs: %v", err)
+//	}
+func (s *VocabService) MergeVocabs(keepID int, mergeIDs []int, actor string) (vocab *mdl.Vocab, err error) {
+
+	if err = validateMergeIDs(keepID, mergeIDs); err != nil {
+		return
+	}
+
+	before, err := s.FindVocabByID(keepID)
+	if err != nil {
+		return
+	}
+
+	merged := make([]*mdl.Vocab, len(mergeIDs))
+	for i, id := range mergeIDs {
+		if merged[i], err = s.FindVocabByID(id); err != nil {
+			return nil, err
+		}
+		if merged[i].LearningLangCode != before.LearningLangCode {
+			return nil, fmt.Errorf("vocab %d learning lang code %s does not match %s of vocab %d",
+				id, merged[i].LearningLangCode, before.LearningLangCode, keepID)
+		}
+	}
+
+	vocab = before.Clone()
+	for _, other := range merged {
+		foldVocab(vocab, other, actor)
+	}
+	vocab.Alternatives = JoinAlternatives(vocab.AlternativeList)
+
+	if err = validateAlternatives(vocab); err != nil {
+		return nil, err
+	}
+	if err = validateVocabUpdate(vocab); err != nil {
+		return nil, err
+	}
+	if err = s.checkReviewFields(before, vocab); err != nil {
+		return nil, err
+	}
+
+	merge := &mdl.VocabMerge{Keep: vocab, MergedIDs: mergeIDs}
+
+	audit, err := buildAudit("vocab", keepID, fmt.Sprintf("merged vocab %s", joinIDs(mergeIDs)), actor, before.JSON(), vocab.JSON())
+	if err != nil {
+		return nil, err
+	}
+	merge.Audits = append(merge.Audits, *audit)
+
+	for _, other := range merged {
+		comments := fmt.Sprintf("archived vocab merged into vocab %d", keepID)
+		audit, err = buildAudit("vocab", other.ID, comments, actor, other.JSON(), "")
+		if err != nil {
+			return nil, err
+		}
+		merge.Audits = append(merge.Audits, *audit)
+		merge.Archives = append(merge.Archives, mdl.VocabArchive{
+			VocabID:      other.ID,
+			MergedIntoID: keepID,
+			Record:       other.JSON(),
+			ArchivedBy:   actor,
+		})
+	}
+
+	fixits, err := s.fixitRepo.FindFixitsForVocabs(mergeIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, fixit := range *fixits {
+		repointed := fixit.Clone()
+		repointed.VocabID = keepID
+
+		comments := fmt.Sprintf("re-pointed fixit from merged vocab %d", fixit.VocabID)
+		audit, err = buildAudit("fixit", fixit.ID, comments, actor, fixit.JSON(), repointed.JSON())
+		if err != nil {
+			return nil, err
+		}
+		merge.Audits = append(merge.Audits, *audit)
+		merge.Fixits = append(merge.Fixits, *repointed)
+	}
+
+	if err = s.mergeRepo.MergeVocabs(merge); err != nil {
+		return nil, err
+	}
+
+	// The merge is saved in one transaction, its changes are published once it commits
+	publishVocabChanged(vocab)
+	for i := range merge.Fixits {
+		publishFixitChanged(&merge.Fixits[i])
+	}
+	for i := range merge.Audits {
+		publishAuditCreated(&merge.Audits[i])
+	}
+	return
+}
+
+// validateMergeIDs ensures there is something to merge, and that no vocab is merged into
+// itself or listed twice.
+func validateMergeIDs(keepID int, mergeIDs []int) error {
+	if len(mergeIDs) == 0 {
+		return fmt.Errorf("at least one vocab to merge is required")
+	}
+
+	seen := map[int]bool{keepID: true}
+	for _, id := range mergeIDs {
+		if id == keepID {
+			return fmt.Errorf("vocab %d cannot be merged into itself", id)
+		}
+		if seen[id] {
+			return fmt.Errorf("vocab %d is listed more than once", id)
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
+
+// foldVocab adds the learning lang and alternatives of a merged vocab to the kept vocab as
+// alternatives, skipping any it already has, and appends the merged hint when it is new.
+// Grammar fields the kept vocab lacks are taken from a merged vocab of the same part of speech,
+// and its audio and concept from any merged vocab when it has none.
+func foldVocab(vocab *mdl.Vocab, other *mdl.Vocab, actor string) {
+	notes := fmt.Sprintf("merged from vocab %d", other.ID)
+	foldAlternative(vocab, other.LearningLang, notes, actor)
+	for _, alternative := range other.AlternativeList {
+		altNotes := notes
+		if len(alternative.Notes) > 0 {
+			altNotes = alternative.Notes
+		}
+		foldAlternative(vocab, alternative.Alternative, altNotes, actor)
+	}
+
+	for _, field := range []struct{ target, value *string }{
+		{&vocab.Gender, &other.Gender}, {&vocab.Plural, &other.Plural},
+		{&vocab.Article, &other.Article}, {&vocab.Register, &other.Register},
+	} {
+		if len(*field.target) == 0 && vocab.Pos == other.Pos {
+			*field.target = *field.value
+		}
+	}
+
+	if vocab.AudioID == nil {
+		vocab.AudioID = other.AudioID
+	}
+	if vocab.ConceptID == nil {
+		vocab.ConceptID = other.ConceptID
+	}
+
+	hint := strings.TrimSpace(other.Hint)
+	if len(hint) == 0 || strings.Contains(strings.ToLower(vocab.Hint), strings.ToLower(hint)) {
+		return
+	}
+	if len(vocab.Hint) == 0 {
+		vocab.Hint = hint
+	} else {
+		vocab.Hint += hintSeparator + hint
+	}
+}
+
+// foldAlternative appends an alternative to the vocab list unless it repeats the learning
+// lang or an existing alternative.
+func foldAlternative(vocab *mdl.Vocab, alternative string, notes string, actor string) {
+	if strings.EqualFold(alternative, vocab.LearningLang) || indexOfAlternative(vocab.AlternativeList, alternative) >= 0 {
+		return
+	}
+
+	vocab.AlternativeList = append(vocab.AlternativeList, mdl.VocabAlternative{
+		VocabID:     vocab.ID,
+		Alternative: alternative,
+		Notes:       notes,
+		Position:    len(vocab.AlternativeList),
+		CreatedBy:   actor,
+	})
+}
+
+// joinIDs formats a list of IDs for audit comments, e.g. "12, 40".
+func joinIDs(ids []int) string {
+	texts := make([]string, len(ids))
+	for i, id := range ids {
+		texts[i] = fmt.Sprint(id)
+	}
+	return strings.Join(texts, ", ")
+}
@@ -0,0 +1,99 @@
+package srv
+
+import (
+	"github.com/heather92115/verdure-admin/internal/db/mock"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"reflect"
+	"testing"
+)
+
+func TestVocabService_MergeVocabs(t *testing.T) {
+	vocabService := createMockVocabService()
+
+	_ = vocabService.CreateVocab(&mdl.Vocab{LearningLang: "perro", FirstLang: "dog", Hint: "not gato",
+		LearningLangCode: "es", KnownLangCode: "en"}, false, "sys")
+	_ = vocabService.CreateVocab(&mdl.Vocab{LearningLang: "el perro", FirstLang: "the dog", Hint: "a pet",
+		AlternativeList:  []mdl.VocabAlternative{{Alternative: "el can", Notes: "literary"}},
+		LearningLangCode: "es", KnownLangCode: "en"}, true, "sys")
+	_ = vocabService.CreateVocab(&mdl.Vocab{LearningLang: "perros", FirstLang: "dogs", Hint: "Not gato",
+		LearningLangCode: "es", KnownLangCode: "en"}, true, "sys")
+	_ = vocabService.CreateVocab(&mdl.Vocab{LearningLang: "dog", FirstLang: "perro",
+		LearningLangCode: "en", KnownLangCode: "es"}, false, "sys")
+
+	fixit := &mdl.Fixit{VocabID: 2, Status: mdl.Pending, FieldName: "hint", Comments: "check", CreatedBy: "sys"}
+	_ = vocabService.fixitRepo.CreateFixit(fixit)
+
+	tests := []struct {
+		name     string
+		keepID   int
+		mergeIDs []int
+		// reviewFields are the fields whose changes must be approved, see VocabService.reviewFields
+		reviewFields []string
+		wantErr      bool
+	}{
+		{name: "Nothing to merge", keepID: 1, mergeIDs: nil, wantErr: true},
+		{name: "Merged into itself", keepID: 1, mergeIDs: []int{1}, wantErr: true},
+		{name: "Listed twice", keepID: 1, mergeIDs: []int{2, 2}, wantErr: true},
+		{name: "Unknown vocab", keepID: 1, mergeIDs: []int{999}, wantErr: true},
+		{name: "Different learning language", keepID: 1, mergeIDs: []int{4}, wantErr: true},
+		{name: "Folds a field requiring review", keepID: 1, mergeIDs: []int{2, 3}, reviewFields: []string{"hint"}, wantErr: true},
+		{name: "Successful merge", keepID: 1, mergeIDs: []int{2, 3}, reviewFields: []string{"first_lang"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vocabService.reviewFields = tt.reviewFields
+			vocab, err := vocabService.MergeVocabs(tt.keepID, tt.mergeIDs, "sys")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MergeVocabs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if vocab.Alternatives != "el perro, el can, perros" {
+				t.Errorf("MergeVocabs() alternatives = %q", vocab.Alternatives)
+			}
+			if vocab.Hint != "not gato; a pet" {
+				t.Errorf("MergeVocabs() hint = %q", vocab.Hint)
+			}
+		})
+	}
+
+	found, err := vocabService.FindVocabByID(1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var notes []string
+	for _, alternative := range found.AlternativeList {
+		notes = append(notes, alternative.Notes)
+	}
+	if want := []string{"merged from vocab 2", "literary", "merged from vocab 3"}; !reflect.DeepEqual(notes, want) {
+		t.Errorf("Saved alternative notes = %v, want %v", notes, want)
+	}
+
+	for _, id := range []int{2, 3} {
+		if _, err := vocabService.FindVocabByID(id); err == nil {
+			t.Errorf("Expected merged vocab %d to be removed", id)
+		}
+	}
+	if archives := vocabService.mergeRepo.(*mock.MockMergeRepository).Archives; len(archives) != 2 {
+		t.Errorf("Expected 2 archived vocab, got %d", len(archives))
+	}
+
+	repointed, _ := vocabService.fixitRepo.FindFixitByID(fixit.ID)
+	if repointed.VocabID != 1 {
+		t.Errorf("Expected fixit to be re-pointed to vocab 1, got %d", repointed.VocabID)
+	}
+
+	fixitAudits, _ := vocabService.auditService.FindAudits("fixit", fixit.ID, nil, 0)
+	if len(*fixitAudits) != 1 {
+		t.Errorf("Expected 1 fixit audit, got %d", len(*fixitAudits))
+	}
+	for _, id := range []int{1, 2, 3} {
+		vocabAudits, _ := vocabService.auditService.FindAudits("vocab", id, nil, 0)
+		if len(*vocabAudits) != 2 {
+			t.Errorf("Expected a create and a merge audit for vocab %d, got %d", id, len(*vocabAudits))
+		}
+	}
+}
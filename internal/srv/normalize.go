@@ -0,0 +1,166 @@
+package srv
+
+import (
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+)
+
+// quoteReplacer canonicalizes the typographic quotes editors paste from word processors
+// and web pages to their plain ASCII forms. Guillemets are left alone since they are the
+// correct quotes in several learning languages.
+var quoteReplacer = strings.NewReplacer(
+	"‘", "'", // left single quotation mark
+	"’", "'", // right single quotation mark
+	"‚", "'", // single low-9 quotation mark
+	"‛", "'", // single high-reversed-9 quotation mark
+	"′", "'", // prime
+	"`", "'", // grave accent used as an apostrophe
+	"“", `"`, // left double quotation mark
+	"”", `"`, // right double quotation mark
+	"„", `"`, // double low-9 quotation mark
+	"‟", `"`, // double high-reversed-9 quotation mark
+	"″", `"`, // double prime
+)
+
+// NormalizeText canonicalizes free text so visually identical input is stored identically.
+// The pipeline is:
+//  1. Unicode NFC composition, so "é" typed as "e" plus a combining accent matches "é".
+//  2. Removal of zero width characters such as the zero width space and byte order mark.
+//  3. Typographic quote canonicalization, see quoteReplacer.
+//  4. Whitespace canonicalization, every run of whitespace, including non-breaking spaces,
+//     becomes a single space and leading and trailing whitespace is trimmed.
+//
+// The unique index on the normalized learning lang mirrors this pipeline in SQL, see
+// db.NormalizedLearningLangSQL, so the two must be changed together.
+//
+// Parameters:
+// - text: The text to normalize.
+//
+// Returns:
+// - The normalized text.
+//
+// Usage example:
+// text := NormalizeText("  el perro ") // "el perro"
+func NormalizeText(text string) string {
+	text = norm.NFC.String(text)
+
+	text = strings.Map(func(r rune) rune {
+		switch r {
+		case '\u200b', '\u200c', '\u200d', '\u2060', '\ufeff':
+			return -1
+		}
+		return r
+	}, text)
+
+	text = quoteReplacer.Replace(text)
+
+	return strings.Join(strings.FieldsFunc(text, isNormalizedSpace), " ")
+}
+
+// isNormalizedSpace reports whether the rune is whitespace, including the Unicode space
+// separators such as the non-breaking space that unicode.IsSpace may not cover.
+func isNormalizedSpace(r rune) bool {
+	return unicode.IsSpace(r) || unicode.Is(unicode.Zs, r)
+}
+
+// normalizeVocab applies NormalizeText to every free text field of a vocab and its alternatives,
+// and the learning language rules to those written in it, see NormalizeLearningText.
+// It is applied before validateVocab so the validation and the uniqueness check see the stored form.
+func normalizeVocab(vocab *mdl.Vocab) {
+	vocab.KnownLangCode = CanonicalLangCode(vocab.KnownLangCode)
+	vocab.LearningLangCode = CanonicalLangCode(vocab.LearningLangCode)
+
+	vocab.LearningLang = NormalizeLearningText(vocab.LearningLang, vocab.LearningLangCode)
+	vocab.FirstLang = NormalizeText(vocab.FirstLang)
+	vocab.Alternatives = NormalizeLearningText(vocab.Alternatives, vocab.LearningLangCode)
+	vocab.Skill = NormalizeText(vocab.Skill)
+	vocab.Infinitive = NormalizeLearningText(vocab.Infinitive, vocab.LearningLangCode)
+	vocab.Pos = NormalizeText(vocab.Pos)
+	vocab.Hint = NormalizeText(vocab.Hint)
+	vocab.Gender = NormalizeText(vocab.Gender)
+	vocab.Plural = NormalizeLearningText(vocab.Plural, vocab.LearningLangCode)
+	vocab.Article = NormalizeText(vocab.Article)
+	vocab.Register = NormalizeText(vocab.Register)
+
+	for i := range vocab.AlternativeList {
+		vocab.AlternativeList[i].Alternative = NormalizeLearningText(vocab.AlternativeList[i].Alternative, vocab.LearningLangCode)
+		vocab.AlternativeList[i].Notes = NormalizeText(vocab.AlternativeList[i].Notes)
+	}
+}
+
+// normalizePatch applies NormalizeText to the free text fields provided in a vocab patch, and
+// the learning language rules to those written in it, leaving the untouched fields alone so
+// they do not show up in the audit diff.
+func normalizePatch(patch *mdl.VocabPatch, learningLangCode string) {
+	for _, field := range []*string{patch.FirstLang, patch.Skill, patch.Pos, patch.Hint, patch.Gender,
+		patch.Article, patch.Register} {
+		if field != nil {
+			*field = NormalizeText(*field)
+		}
+	}
+	for _, field := range []*string{patch.Infinitive, patch.Plural} {
+		if field != nil {
+			*field = NormalizeLearningText(*field, learningLangCode)
+		}
+	}
+}
+
+// NormalizationCollision lists the Vocab records of a learning language whose learning langs
+// differ as stored but are identical once normalized.
+type NormalizationCollision struct {
+	LearningLangCode string
+	Normalized       string
+	Vocabs           []mdl.Vocab
+}
+
+// FindNormalizationCollisions scans every Vocab record for the learning language code and
+// groups those whose learning langs collide after NormalizeText. Learning langs only collide
+// within the same learning language. These collisions must be resolved before the unique
+// index on the normalized learning lang can be created.
+//
+// Parameters:
+// - learningCode: The learning language code to scan, or empty to scan every record.
+//
+// Returns:
+// - The collisions found, ordered by the primary ID of their first vocab.
+// - An error if the scan fails.
+//
+// Usage example:
+// collisions, err := vocabService.FindNormalizationCollisions("es")
+//
+//	if err != nil {
+//	    log.Printf("Failed to find collisions: %v", err)
+//	}
+func (s *VocabService) FindNormalizationCollisions(learningCode string) (collisions []NormalizationCollision, err error) {
+
+	groups := make(map[string]int)
+
+	err = s.repo.ScanVocabs(learningCode, scanBatchSize, func(batch *[]mdl.Vocab) error {
+		for _, vocab := range *batch {
+			normalized := NormalizeText(vocab.LearningLang)
+			key := vocab.LearningLangCode + " " + normalized
+			if index, exists := groups[key]; exists {
+				collisions[index].Vocabs = append(collisions[index].Vocabs, vocab)
+				continue
+			}
+			groups[key] = len(collisions)
+			collisions = append(collisions, NormalizationCollision{LearningLangCode: vocab.LearningLangCode,
+				Normalized: normalized, Vocabs: []mdl.Vocab{vocab}})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	found := collisions[:0]
+	for _, collision := range collisions {
+		if len(collision.Vocabs) > 1 {
+			found = append(found, collision)
+		}
+	}
+
+	return found, nil
+}
@@ -0,0 +1,87 @@
+package srv
+
+import (
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"testing"
+)
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "Already normal", text: "el perro", want: "el perro"},
+		{name: "Decomposed accent", text: "cafe\u0301", want: "café"},
+		{name: "Non-breaking space", text: "buenos\u00a0días", want: "buenos días"},
+		{name: "Collapsed whitespace", text: "  buenos \t  días ", want: "buenos días"},
+		{name: "Curly apostrophe", text: "l\u2019homme", want: "l'homme"},
+		{name: "Curly double quotes", text: "\u201chola\u201d", want: `"hola"`},
+		{name: "Zero width space", text: "pe\u200brro", want: "perro"},
+		{name: "Byte order mark", text: "\ufeffperro", want: "perro"},
+		{name: "Guillemets kept", text: "«hola»", want: "«hola»"},
+		{name: "Empty", text: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeText(tt.text); got != tt.want {
+				t.Errorf("NormalizeText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVocabService_CreateVocabNormalizes(t *testing.T) {
+	vocabService := createMockVocabService()
+
+	vocab := &mdl.Vocab{
+		LearningLang:     "cafe\u0301",
+		FirstLang:        " coffee ",
+		LearningLangCode: "es",
+		KnownLangCode:    "en",
+	}
+	if err := vocabService.CreateVocab(vocab, false, "sys"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if vocab.LearningLang != "café" || vocab.FirstLang != "coffee" {
+		t.Errorf("CreateVocab() stored %q, %q", vocab.LearningLang, vocab.FirstLang)
+	}
+
+	duplicate := &mdl.Vocab{
+		LearningLang:     "café",
+		FirstLang:        "coffee",
+		LearningLangCode: "es",
+		KnownLangCode:    "en",
+	}
+	if err := vocabService.CreateVocab(duplicate, false, "sys"); err == nil {
+		t.Errorf("Expected the normalized duplicate to be rejected")
+	}
+}
+
+func TestVocabService_FindNormalizationCollisions(t *testing.T) {
+	vocabService := createMockVocabService()
+
+	// Seed records directly in the repository, bypassing the normalizing service.
+	for _, learningLang := range []string{"café", "cafe\u0301", "perro", "buenos\u00a0días", "buenos días"} {
+		_ = vocabService.repo.CreateVocab(&mdl.Vocab{
+			LearningLang:     learningLang,
+			LearningLangCode: "es",
+			KnownLangCode:    "en",
+		}, nil)
+	}
+
+	collisions, err := vocabService.FindNormalizationCollisions("es")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(collisions) != 2 {
+		t.Fatalf("FindNormalizationCollisions() found %d collisions, want 2", len(collisions))
+	}
+	if collisions[0].Normalized != "café" || len(collisions[0].Vocabs) != 2 {
+		t.Errorf("FindNormalizationCollisions() first = %+v", collisions[0])
+	}
+	if collisions[1].Normalized != "buenos días" || len(collisions[1].Vocabs) != 2 {
+		t.Errorf("FindNormalizationCollisions() second = %+v", collisions[1])
+	}
+}
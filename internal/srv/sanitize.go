@@ -0,0 +1,171 @@
+package srv
+
+import (
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxMarkupShown is the length of markup quoted in a validation error.
+const maxMarkupShown = 20
+
+// SanitizeMode controls how VocabService treats markup a field policy does not allow.
+type SanitizeMode string
+
+const (
+	// SanitizeReject fails the request naming the markup, the default.
+	SanitizeReject SanitizeMode = "reject"
+	// SanitizeEscape escapes the markup so it is stored, and shown, as plain text.
+	SanitizeEscape SanitizeMode = "escape"
+)
+
+// sanitizeModeFromEnv reads the SANITIZE_MODE environment variable, defaulting to SanitizeReject.
+func sanitizeModeFromEnv() SanitizeMode {
+	if SanitizeMode(strings.ToLower(os.Getenv("SANITIZE_MODE"))) == SanitizeEscape {
+		return SanitizeEscape
+	}
+	return SanitizeReject
+}
+
+// TextPolicy names the markup a free text field may hold. The zero value is PlainText.
+//
+// Fields:
+//   - Name: The name of the policy shown to clients, e.g. "plain".
+//   - Tags: The lower case tags allowed, without attributes, such as "b" for <b> and </b>.
+type TextPolicy struct {
+	Name string
+	Tags []string
+}
+
+var (
+	// PlainText allows no markup, the policy of every field not given another.
+	PlainText = TextPolicy{Name: "plain"}
+	// LimitedMarkup allows the emphasis and line break tags editors use in hints.
+	LimitedMarkup = TextPolicy{Name: "limited", Tags: []string{"b", "i", "em", "strong", "br"}}
+)
+
+// PolicyName returns the name of the policy, "plain" for the zero value.
+func (p TextPolicy) PolicyName() string {
+	if len(p.Name) == 0 {
+		return PlainText.Name
+	}
+	return p.Name
+}
+
+// markupPattern finds markup the way an HTML tokenizer does: a '<' followed by a letter, '/',
+// '!' or '?' opens a tag, comment or declaration, running to the next '>' or the end of the
+// text. A '<' followed by anything else, as in "a < b", is text.
+var markupPattern = regexp.MustCompile(`<[/!?a-zA-Z][^>]*>?`)
+
+// allowedTagPattern matches a tag without attributes, capturing its name.
+var allowedTagPattern = regexp.MustCompile(`^</?([a-zA-Z]+)\s*/?>$`)
+
+// allows reports whether the policy allows the markup token found by markupPattern.
+func (p TextPolicy) allows(token string) bool {
+	match := allowedTagPattern.FindStringSubmatch(token)
+	return match != nil && indexOfString(p.Tags, strings.ToLower(match[1])) >= 0
+}
+
+// findMarkup returns the first markup in the text the policy does not allow, or empty when there is none.
+func (p TextPolicy) findMarkup(text string) string {
+	for _, token := range markupPattern.FindAllString(text, -1) {
+		if !p.allows(token) {
+			return token
+		}
+	}
+	return ""
+}
+
+// EscapeMarkup escapes the angle brackets of the markup in the text the policy does not allow,
+// so "<script>" becomes "&lt;script&gt;" while allowed tags are kept.
+//
+// Parameters:
+// - text: The text to escape.
+// - policy: The policy of the field holding the text.
+//
+// Returns:
+// - The text with the markup it may not hold escaped.
+//
+// Usage example:
+// hint := EscapeMarkup("<b>ser</b> <i onclick=\"x()\">", LimitedMarkup) // "<b>ser</b> &lt;i onclick=\"x()\"&gt;"
+func EscapeMarkup(text string, policy TextPolicy) string {
+	return markupPattern.ReplaceAllStringFunc(text, func(token string) string {
+		if policy.allows(token) {
+			return token
+		}
+		return strings.NewReplacer("<", "&lt;", ">", "&gt;").Replace(token)
+	})
+}
+
+// isBidiControl reports whether the rune is a bidirectional embedding, override or isolate
+// control, which can make text display in an order other than the one it is stored in. The
+// left-to-right and right-to-left marks are allowed, right to left languages need them.
+func isBidiControl(r rune) bool {
+	return (r >= '\u202a' && r <= '\u202e') || (r >= '\u2066' && r <= '\u2069')
+}
+
+// isRestrictedControl reports whether the rune is a control character other than the tab,
+// line feed and carriage return.
+func isRestrictedControl(r rune) bool {
+	return unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r'
+}
+
+// validateText checks a free text field against its maximum length and its policy. The text
+// must be valid UTF-8 without control characters or bidirectional controls, see isBidiControl,
+// and hold no markup the policy does not allow.
+func validateText(text string, fieldName string, maxLength int, policy TextPolicy) error {
+	if !utf8.ValidString(text) {
+		return invalidField("", RuleInvalidCharacters, 0, "%s is not valid UTF-8 text", fieldName)
+	}
+	if utf8.RuneCountInString(text) > maxLength {
+		return invalidField("", RuleMaxLength, maxLength, errFmtStrLen, fieldName, maxLength)
+	}
+	if strings.IndexFunc(text, isRestrictedControl) >= 0 {
+		return invalidField("", RuleInvalidCharacters, 0, "%s contains control characters", fieldName)
+	}
+	if strings.IndexFunc(text, isBidiControl) >= 0 {
+		return invalidField("", RuleInvalidCharacters, 0, "%s contains bidirectional control characters", fieldName)
+	}
+	if markup := policy.findMarkup(text); len(markup) > 0 {
+		if utf8.RuneCountInString(markup) > maxMarkupShown {
+			markup = string([]rune(markup)[:maxMarkupShown]) + "..."
+		}
+		return invalidField("", RuleMarkup, 0, "%s contains markup that is not allowed: %s", fieldName, markup)
+	}
+	return nil
+}
+
+// escapeVocab escapes the markup of each vocab text field its policy does not allow, see
+// EscapeMarkup. It is applied in SanitizeEscape mode before the vocab is validated.
+func escapeVocab(vocab *mdl.Vocab) {
+	for name, text := range map[string]*string{
+		"learning_lang": &vocab.LearningLang, "first_lang": &vocab.FirstLang, "alternatives": &vocab.Alternatives,
+		"skill": &vocab.Skill, "infinitive": &vocab.Infinitive, "pos": &vocab.Pos, "hint": &vocab.Hint,
+		"plural": &vocab.Plural, "article": &vocab.Article,
+	} {
+		field, _ := FindVocabField(name)
+		*text = EscapeMarkup(*text, field.Policy)
+	}
+
+	for i := range vocab.AlternativeList {
+		vocab.AlternativeList[i].Alternative = EscapeMarkup(vocab.AlternativeList[i].Alternative, PlainText)
+		vocab.AlternativeList[i].Notes = EscapeMarkup(vocab.AlternativeList[i].Notes, PlainText)
+	}
+}
+
+// escapePatch escapes the markup of the text fields provided in a vocab patch their policy does
+// not allow, leaving the untouched fields alone so they do not show up in the audit diff.
+func escapePatch(patch *mdl.VocabPatch) {
+	for name, text := range map[string]*string{
+		"first_lang": patch.FirstLang, "skill": patch.Skill, "infinitive": patch.Infinitive, "pos": patch.Pos,
+		"hint": patch.Hint, "plural": patch.Plural, "article": patch.Article,
+	} {
+		if text != nil {
+			field, _ := FindVocabField(name)
+			*text = EscapeMarkup(*text, field.Policy)
+		}
+	}
+}
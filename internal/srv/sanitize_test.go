@@ -0,0 +1,86 @@
+package srv
+
+import (
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"testing"
+)
+
+func TestValidateText_LimitedMarkup(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+		errMsg  string
+	}{
+		{name: "Emphasis", text: "<b>ser</b> or <i>estar</i>"},
+		{name: "Upper case and line break", text: "<STRONG>ser</STRONG><br/><BR >estar"},
+		{name: "Text brackets", text: "1 < 2 > 0"},
+		{name: "Attribute", text: `<b onclick="x()">ser</b>`, wantErr: true,
+			errMsg: `Hint contains markup that is not allowed: <b onclick="x()">`},
+		{name: "Other tag", text: "<u>ser</u>", wantErr: true, errMsg: "Hint contains markup that is not allowed: <u>"},
+		{name: "Script", text: "<script>alert(1)</script>", wantErr: true,
+			errMsg: "Hint contains markup that is not allowed: <script>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateText(tt.text, "Hint", maxHintLen, LimitedMarkup)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateText() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil && err.Error() != tt.errMsg {
+				t.Errorf("validateText() error = %v, wantErrMsg %v", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestEscapeMarkup(t *testing.T) {
+	tests := []struct {
+		text   string
+		policy TextPolicy
+		want   string
+	}{
+		{text: "<b>ser</b>", policy: PlainText, want: "&lt;b&gt;ser&lt;/b&gt;"},
+		{text: "<b>ser</b>", policy: LimitedMarkup, want: "<b>ser</b>"},
+		{text: `<b>ser</b> <i onclick="x()">`, policy: LimitedMarkup, want: `<b>ser</b> &lt;i onclick="x()"&gt;`},
+		{text: "perro <img src=x", policy: LimitedMarkup, want: "perro &lt;img src=x"},
+		{text: "a < b > c", policy: PlainText, want: "a < b > c"},
+	}
+
+	for _, tt := range tests {
+		if got := EscapeMarkup(tt.text, tt.policy); got != tt.want {
+			t.Errorf("EscapeMarkup(%q, %s) = %q, want %q", tt.text, tt.policy.Name, got, tt.want)
+		}
+	}
+}
+
+func TestVocabService_SanitizeMode(t *testing.T) {
+	vocabService := createMockVocabService()
+
+	rejected := &mdl.Vocab{LearningLang: "el gato", FirstLang: "the <u>cat</u>", Hint: "<b>g</b>"}
+	if err := vocabService.CreateVocab(rejected, false, "sys"); err == nil ||
+		err.Error() != "First language contains markup that is not allowed: <u>" {
+		t.Errorf("CreateVocab() error = %v", err)
+	}
+
+	vocabService.sanitizeMode = SanitizeEscape
+
+	vocab := &mdl.Vocab{LearningLang: "el gato", FirstLang: "the <u>cat</u>", Hint: "<b>g</b><script>",
+		AlternativeList: []mdl.VocabAlternative{{Alternative: "gata", Notes: "<i>feminine</i>"}}}
+	if err := vocabService.CreateVocab(vocab, false, "sys"); err != nil {
+		t.Fatalf("CreateVocab() error = %v", err)
+	}
+	if vocab.FirstLang != "the &lt;u&gt;cat&lt;/u&gt;" || vocab.Hint != "<b>g</b>&lt;script&gt;" ||
+		vocab.AlternativeList[0].Notes != "&lt;i&gt;feminine&lt;/i&gt;" {
+		t.Errorf("CreateVocab() did not escape the markup, got %+v", vocab)
+	}
+
+	hint := "<em>gat</em> <a href=\"/\">"
+	updated, err := vocabService.UpdateVocab(&mdl.VocabPatch{ID: vocab.ID, Hint: &hint}, "sys")
+	if err != nil {
+		t.Fatalf("UpdateVocab() error = %v", err)
+	}
+	if updated.Hint != `<em>gat</em> &lt;a href="/"&gt;` {
+		t.Errorf("UpdateVocab() hint = %q", updated.Hint)
+	}
+}
@@ -0,0 +1,235 @@
+package srv
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"sort"
+)
+
+// SkillNode is a managed skill within the curriculum taxonomy, with the number of vocab
+// linked to it and the skills it contains.
+type SkillNode struct {
+	Skill           mdl.Skill
+	VocabCount      int
+	TotalVocabCount int
+	Children        []SkillNode
+}
+
+// FindSkillTree assembles the managed skills into the curriculum taxonomy, units at the top
+// containing their lessons and skills. Siblings are ordered by sort order, then by name. A
+// skill whose parent no longer exists is listed at the top level.
+//
+// Returns:
+//   - The top level skill nodes. VocabCount is the number of vocab linked to the skill itself,
+//     TotalVocabCount also includes the vocab of every skill below it.
+//   - An error if the skills or vocab counts cannot be read.
+//
+// Usage example:
+// tree, err := lookupService.FindSkillTree()
+//
+//	if err != nil {
+//	    log.Printf("Failed to find the skill tree: %v", err)
+//	}
+func (s *LookupService) FindSkillTree() ([]SkillNode, error) {
+
+	skills, err := s.repo.FindSkills()
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.CountVocabSkillIDs()
+	if err != nil {
+		return nil, err
+	}
+	vocabCounts := make(map[int]int)
+	for _, count := range *counts {
+		vocabCounts[count.SkillID] = count.Count
+	}
+
+	// The skills are found in name order, so a stable sort keeps ties by name.
+	ordered := append([]mdl.Skill(nil), *skills...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].SortOrder < ordered[j].SortOrder })
+
+	byID := make(map[int]bool)
+	for _, skill := range ordered {
+		byID[skill.ID] = true
+	}
+
+	var roots []mdl.Skill
+	children := make(map[int][]mdl.Skill)
+	for _, skill := range ordered {
+		if skill.ParentID == nil || !byID[*skill.ParentID] {
+			roots = append(roots, skill)
+			continue
+		}
+		children[*skill.ParentID] = append(children[*skill.ParentID], skill)
+	}
+
+	var build func(skill mdl.Skill) SkillNode
+	build = func(skill mdl.Skill) SkillNode {
+		node := SkillNode{Skill: skill, VocabCount: vocabCounts[skill.ID], Children: []SkillNode{}}
+		node.TotalVocabCount = node.VocabCount
+		for _, child := range children[skill.ID] {
+			childNode := build(child)
+			node.TotalVocabCount += childNode.TotalVocabCount
+			node.Children = append(node.Children, childNode)
+		}
+		return node
+	}
+
+	tree := make([]SkillNode, len(roots))
+	for i, root := range roots {
+		tree[i] = build(root)
+	}
+
+	return tree, nil
+}
+
+// MoveVocabsToSkill links Vocab records to a managed skill in bulk, setting both the skill
+// name and SkillID, and writes an audit entry for every vocab moved. Vocab already linked to
+// the skill are returned unchanged. Every vocab is found and validated before any is saved,
+// and the moves are saved in one transaction, so an unknown ID or an invalid vocab moves nothing.
+// As for UpdateVocab, vocab cannot be moved when the skill requires review, see VocabService.reviewFields.
+//
+// Parameters:
+// - vocabIDs: The primary IDs of the Vocab records to move.
+// - skillID: The primary ID of the managed skill to move them to.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - The vocab, in the order of the IDs given, including their alternatives.
+// - An error if the IDs are invalid, the skill or any vocab cannot be found, the skill requires
+// review, or validation or saving fails.
+//
+// Usage example:
+// vocabs, err := vocabService.MoveVocabsToSkill([]int{123, 456}, 7, srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to move vocabs: %v", err)
+//	}
+func (s *VocabService) MoveVocabsToSkill(vocabIDs []int, skillID int, actor string) (moved []mdl.Vocab, err error) {
+
+	if len(vocabIDs) == 0 {
+		return nil, fmt.Errorf("at least one vocab to move is required")
+	}
+
+	skill, err := s.lookupRepo.FindSkillByID(skillID)
+	if err != nil {
+		return
+	}
+
+	seen := make(map[int]bool)
+	vocabs := make([]*mdl.Vocab, len(vocabIDs))
+	for i, id := range vocabIDs {
+		if seen[id] {
+			return nil, fmt.Errorf("vocab %d is listed more than once", id)
+		}
+		seen[id] = true
+
+		if vocabs[i], err = s.FindVocabByID(id); err != nil {
+			return nil, err
+		}
+	}
+
+	// Every vocab is changed and validated before any is saved, and all are saved together
+	var changed []*mdl.Vocab
+	var builds []func() (*mdl.Audit, error)
+	for i, before := range vocabs {
+		if before.Skill == skill.Name && before.SkillID != nil && *before.SkillID == skill.ID {
+			continue
+		}
+
+		vocab := before.Clone()
+		vocab.Skill = skill.Name
+		vocab.SkillID = &skill.ID
+		if err = validateVocabUpdate(vocab); err != nil {
+			return nil, err
+		}
+		if err = s.checkReviewFields(before, vocab); err != nil {
+			return nil, err
+		}
+
+		comments := fmt.Sprintf("moved vocab to skill %s", skill.Name)
+		if len(before.Skill) > 0 {
+			comments = fmt.Sprintf("moved vocab from skill %s to skill %s", before.Skill, skill.Name)
+		}
+
+		builds = append(builds, func() (*mdl.Audit, error) {
+			return buildVocabAudit(comments, actor, before, vocab)
+		})
+		changed = append(changed, vocab)
+		vocabs[i] = vocab
+	}
+
+	if len(changed) > 0 {
+		var audits auditTrail
+		if err = s.repo.UpdateVocabs(changed, audits.of(builds...)); err != nil {
+			return nil, err
+		}
+
+		audits.publish()
+		for _, vocab := range changed {
+			publishVocabChanged(vocab)
+		}
+	}
+
+	for _, vocab := range vocabs {
+		moved = append(moved, *vocab)
+	}
+	return
+}
+
+// applySkillPatch applies the taxonomy fields of a skill patch and reports whether anything
+// changed. A ParentID of 0 moves the skill to the top level.
+func applySkillPatch(patch *mdl.SkillPatch, skill *mdl.Skill) (changed bool) {
+	if patch.ParentID != nil {
+		var parentID *int
+		if *patch.ParentID != 0 {
+			id := *patch.ParentID
+			parentID = &id
+		}
+		if (parentID == nil) != (skill.ParentID == nil) || (parentID != nil && *parentID != *skill.ParentID) {
+			skill.ParentID = parentID
+			changed = true
+		}
+	}
+
+	if patch.SortOrder != nil && *patch.SortOrder != skill.SortOrder {
+		skill.SortOrder = *patch.SortOrder
+		changed = true
+	}
+
+	return
+}
+
+// validateSkillParent checks that the parent of a skill exists, and that following the
+// parents up from it never reaches the skill itself, which would make the taxonomy a cycle.
+func validateSkillParent(skill *mdl.Skill, existing *[]mdl.Skill) error {
+	if skill.ParentID == nil {
+		return nil
+	}
+
+	byID := make(map[int]mdl.Skill)
+	for _, other := range *existing {
+		byID[other.ID] = other
+	}
+
+	if _, found := byID[*skill.ParentID]; !found {
+		return invalidField("parent_id", RuleNotFound, 0, "parent skill %d does not exist", *skill.ParentID)
+	}
+
+	// Walking no further than the number of skills stops on a cycle already in the table.
+	id := *skill.ParentID
+	for range len(byID) {
+		if id == skill.ID {
+			return invalidField("parent_id", RuleInvalid, 0, "skill %s cannot be moved under itself or one of its descendants", skill.Name)
+		}
+		parent := byID[id]
+		if parent.ParentID == nil {
+			return nil
+		}
+		id = *parent.ParentID
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package srv
+
+import (
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLookupService_SkillTaxonomy(t *testing.T) {
+	lookupService, _ := createMockLookupService()
+
+	unknown := 999
+	tests := []struct {
+		name    string
+		skill   *mdl.Skill
+		wantErr bool
+		errMsg  string
+	}{
+		{name: "Unit", skill: &mdl.Skill{Name: "Animals"}},
+		{name: "Lesson", skill: &mdl.Skill{Name: "Pets", ParentID: intPtr(1), SortOrder: 2}},
+		{name: "Sibling lesson", skill: &mdl.Skill{Name: "Farm", ParentID: intPtr(1), SortOrder: 1}},
+		{name: "Skill", skill: &mdl.Skill{Name: "Dogs", ParentID: intPtr(2)}},
+		{name: "Unknown parent", skill: &mdl.Skill{Name: "Birds", ParentID: &unknown}, wantErr: true,
+			errMsg: "parent skill 999 does not exist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := lookupService.CreateSkill(tt.skill, "sys")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateSkill() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil && err.Error() != tt.errMsg {
+				t.Errorf("CreateSkill() error = %v, wantErrMsg %v", err, tt.errMsg)
+			}
+		})
+	}
+
+	if _, err := lookupService.UpdateSkill(&mdl.SkillPatch{TermPatch: mdl.TermPatch{ID: 1}, ParentID: intPtr(4)}, "sys"); err == nil {
+		t.Errorf("Expected an error moving a unit under its own descendant")
+	}
+	if err := lookupService.DeleteSkill(2, "sys"); err == nil {
+		t.Errorf("Expected an error deleting a skill with children")
+	}
+
+	moved, err := lookupService.UpdateSkill(&mdl.SkillPatch{TermPatch: mdl.TermPatch{ID: 4}, ParentID: intPtr(0)}, "sys")
+	if err != nil || moved.ParentID != nil {
+		t.Errorf("UpdateSkill() = %+v, %v, want the skill moved to the top level", moved, err)
+	}
+}
+
+func TestVocabService_MoveVocabsToSkill(t *testing.T) {
+	lookupService, vocabService := createMockLookupService()
+
+	_ = lookupService.CreateSkill(&mdl.Skill{Name: "Animals"}, "sys")
+	_ = lookupService.CreateSkill(&mdl.Skill{Name: "Pets", ParentID: intPtr(1), SortOrder: 2}, "sys")
+	_ = lookupService.CreateSkill(&mdl.Skill{Name: "Farm", ParentID: intPtr(1), SortOrder: 1}, "sys")
+
+	for _, v := range []mdl.Vocab{
+		{LearningLang: "perro", FirstLang: "dog", Skill: "pets"},
+		{LearningLang: "gato", FirstLang: "cat"},
+		{LearningLang: "vaca", FirstLang: "cow", Skill: "Farm"},
+	} {
+		vocab := v
+		vocab.LearningLangCode, vocab.KnownLangCode = "es", "en"
+		if err := vocabService.CreateVocab(&vocab, false, "sys"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	created, _ := vocabService.FindVocabByID(1)
+	if created.Skill != "Pets" || created.SkillID == nil || *created.SkillID != 2 {
+		t.Errorf("CreateVocab() skill = %s %v, want Pets linked to skill 2", created.Skill, created.SkillID)
+	}
+
+	tests := []struct {
+		name     string
+		vocabIDs []int
+		skillID  int
+		// reviewFields are the fields whose changes must be approved, see VocabService.reviewFields
+		reviewFields []string
+		wantErr      bool
+	}{
+		{name: "Nothing to move", vocabIDs: nil, skillID: 2, wantErr: true},
+		{name: "Unknown skill", vocabIDs: []int{2}, skillID: 99, wantErr: true},
+		{name: "Unknown vocab", vocabIDs: []int{2, 99}, skillID: 2, wantErr: true},
+		{name: "Listed twice", vocabIDs: []int{2, 2}, skillID: 2, wantErr: true},
+		{name: "Skill requires review", vocabIDs: []int{2}, skillID: 2, reviewFields: []string{"skill"}, wantErr: true},
+		{name: "Moved", vocabIDs: []int{1, 2}, skillID: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vocabService.reviewFields = tt.reviewFields
+			moved, err := vocabService.MoveVocabsToSkill(tt.vocabIDs, tt.skillID, "sys")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MoveVocabsToSkill() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, vocab := range moved {
+				if vocab.Skill != "Pets" || vocab.SkillID == nil || *vocab.SkillID != tt.skillID {
+					t.Errorf("MoveVocabsToSkill() vocab %d skill = %s %v", vocab.ID, vocab.Skill, vocab.SkillID)
+				}
+			}
+		})
+	}
+
+	// Vocab 1 was already in the skill, so only vocab 2 has a move audit.
+	for id, want := range map[int]int{1: 1, 2: 2} {
+		audits, _ := vocabService.auditService.FindAudits("vocab", id, nil, 0)
+		if len(*audits) != want {
+			t.Errorf("Expected %d audits for vocab %d, got %d", want, id, len(*audits))
+		}
+	}
+
+	tree, err := lookupService.FindSkillTree()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	type summary struct {
+		Name  string
+		Count int
+		Total int
+	}
+	var got []summary
+	var walk func(nodes []SkillNode)
+	walk = func(nodes []SkillNode) {
+		for _, node := range nodes {
+			got = append(got, summary{Name: node.Skill.Name, Count: node.VocabCount, Total: node.TotalVocabCount})
+			walk(node.Children)
+		}
+	}
+	walk(tree)
+
+	want := []summary{{Name: "Animals", Total: 3}, {Name: "Farm", Count: 1, Total: 1}, {Name: "Pets", Count: 2, Total: 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindSkillTree() = %+v, want %+v", got, want)
+	}
+}
+
+func TestVocabService_MoveVocabsToSkillAllOrNothing(t *testing.T) {
+	lookupService, vocabService := createMockLookupService()
+	_ = lookupService.CreateSkill(&mdl.Skill{Name: "Pets"}, "sys")
+
+	for _, learningLang := range []string{"perro", "gato"} {
+		_ = vocabService.CreateVocab(&mdl.Vocab{LearningLang: learningLang, FirstLang: "pet",
+			LearningLangCode: "es", KnownLangCode: "en"}, false, "sys")
+	}
+
+	// Saved before the limit was enforced, so the later vocab cannot be saved as it is
+	invalid, _ := vocabService.repo.FindVocabByID(2)
+	invalid.FirstLang = strings.Repeat("x", maxFirstLangLen+1)
+
+	if _, err := vocabService.MoveVocabsToSkill([]int{1, 2}, 1, "sys"); err == nil {
+		t.Fatalf("MoveVocabsToSkill() expected an error for the invalid vocab")
+	}
+
+	if vocab, _ := vocabService.FindVocabByID(1); vocab.SkillID != nil {
+		t.Errorf("MoveVocabsToSkill() moved vocab 1 to skill %d, want nothing moved", *vocab.SkillID)
+	}
+	if audits, _ := vocabService.auditService.FindAudits("vocab", 1, nil, 0); len(*audits) != 1 {
+		t.Errorf("Expected only the create audit of vocab 1, got %d audits", len(*audits))
+	}
+}
+
+func intPtr(value int) *int {
+	return &value
+}
@@ -0,0 +1,520 @@
+package srv
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"github.com/heather92115/verdure-admin/internal/media"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SnapshotPath is the URL path the live snapshots are served under, followed by their
+// learning language code, e.g. "/snapshots/es".
+const SnapshotPath = "/snapshots/"
+
+// SnapshotVersionHeader is the header carrying the version of the snapshot served.
+const SnapshotVersionHeader = "X-Snapshot-Version"
+
+// snapshotTables are the audited tables whose changes make up a snapshot changelog.
+var snapshotTables = []string{"vocab", "example_sentence"}
+
+// SnapshotDocument is the JSON file of a published snapshot, the content the learner app reads.
+type SnapshotDocument struct {
+	LearningLangCode string          `json:"learning_lang_code"`
+	Version          int             `json:"version"`
+	Published        time.Time       `json:"published"`
+	Vocabs           []SnapshotVocab `json:"vocabs"`
+}
+
+// SnapshotVocab is a vocab in a SnapshotDocument, with its alternatives, the URL of its audio
+// and its example sentences.
+type SnapshotVocab struct {
+	ID               int               `json:"id"`
+	LearningLang     string            `json:"learning_lang"`
+	FirstLang        string            `json:"first_lang"`
+	KnownLangCode    string            `json:"known_lang_code"`
+	Alternatives     []string          `json:"alternatives"`
+	Skill            string            `json:"skill,omitempty"`
+	Infinitive       string            `json:"infinitive,omitempty"`
+	Pos              string            `json:"pos,omitempty"`
+	Hint             string            `json:"hint,omitempty"`
+	Gender           string            `json:"gender,omitempty"`
+	Plural           string            `json:"plural,omitempty"`
+	Article          string            `json:"article,omitempty"`
+	Register         string            `json:"register,omitempty"`
+	NumLearningWords int               `json:"num_learning_words"`
+	ConceptID        *int              `json:"concept_id,omitempty"`
+	AudioURL         string            `json:"audio_url,omitempty"`
+	Examples         []SnapshotExample `json:"examples"`
+}
+
+// SnapshotExample is an example sentence of a SnapshotVocab, with the tokens its highlights
+// refer to.
+type SnapshotExample struct {
+	Sentence    string   `json:"sentence"`
+	Translation string   `json:"translation"`
+	Source      string   `json:"source,omitempty"`
+	Tokens      []string `json:"tokens"`
+	Highlights  []int    `json:"highlights"`
+}
+
+// SnapshotChange is an audited change to the vocab of a learning language or their example
+// sentences, as listed in a snapshot changelog.
+//
+// Fields:
+//   - AuditID: The ID of the audit of the change.
+//   - TableName: The table changed, vocab or example_sentence.
+//   - ObjectID: The ID of the record changed.
+//   - VocabID: The ID of the vocab changed, or of the vocab of the example sentence changed.
+//   - Action: The change made, created, updated or deleted.
+//   - Fields: The fields an update changed, empty for other changes.
+//   - Comments: The comments of the audit.
+//   - CreatedBy: The identifier of the user or process that made the change.
+//   - Created: The timestamp of the change.
+type SnapshotChange struct {
+	AuditID   int
+	TableName string
+	ObjectID  int
+	VocabID   int
+	Action    string
+	Fields    []string
+	Comments  string
+	CreatedBy string
+	Created   time.Time
+}
+
+// SnapshotService handles business logic for the published snapshots of the vocab.
+type SnapshotService struct {
+	repo         db.SnapshotRepository
+	auditRepo    db.AuditRepository
+	vocabRepo    db.VocabRepository
+	storage      media.Storage
+	auditService AuditService
+}
+
+// NewSnapshotService creates a new instance of SnapshotService, keeping snapshot files in the
+// snapshot storage named by the SNAPSHOT_ROOT env var.
+func NewSnapshotService() (*SnapshotService, error) {
+
+	repo, err := db.NewSqlSnapshotRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	auditRepo, err := db.NewSqlAuditRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	vocabRepo, err := db.NewSqlVocabRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	storage, err := media.SnapshotStorageFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := NewAuditService()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = loadManagedLanguages(); err != nil {
+		return nil, err
+	}
+
+	return &SnapshotService{repo: repo, auditRepo: auditRepo, vocabRepo: vocabRepo, storage: storage,
+		auditService: *auditService}, nil
+}
+
+// FindSnapshots retrieves the snapshots of a learning language, newest version first.
+func (s *SnapshotService) FindSnapshots(learningCode string) (*[]mdl.Snapshot, error) {
+	return s.repo.FindSnapshots(CanonicalLangCode(learningCode))
+}
+
+// PublishSnapshot freezes the vocab of a learning language, with their alternatives, audio
+// and example sentences, into a new snapshot version and makes it the one served to learners.
+// The content is read at one point in time, written as a JSON file to the snapshot storage
+// under its checksum, and never changed afterward. The snapshot is audited.
+//
+// Parameters:
+// - learningCode: The learning language code of the vocab to publish, e.g. "es".
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the mdl.Snapshot published.
+// - An error if the code is invalid, there is no vocab to publish, or saving fails.
+//
+// Usage example:
+// snapshot, err := snapshotService.PublishSnapshot("es", srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to publish snapshot: %v", err)
+//	}
+func (s *SnapshotService) PublishSnapshot(learningCode string, actor string) (*mdl.Snapshot, error) {
+
+	code := CanonicalLangCode(learningCode)
+	if !ValidLangCode(code) {
+		return nil, invalidField("learning_code", RuleLanguage, 0, "learning code %s is not a valid language tag", learningCode)
+	}
+
+	content, err := s.repo.FindSnapshotContent(code)
+	if err != nil {
+		return nil, err
+	}
+	if len(content.Vocabs) == 0 {
+		return nil, invalidField("learning_code", RuleNotFound, 0, "there is no vocab with learning code %s to publish", code)
+	}
+
+	existing, err := s.repo.FindSnapshots(code)
+	if err != nil {
+		return nil, err
+	}
+	version := 1
+	if len(*existing) > 0 {
+		version = (*existing)[0].Version + 1
+	}
+
+	document := buildSnapshotDocument(code, version, content)
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := s.storage.Put(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &mdl.Snapshot{
+		LearningLangCode: code,
+		Version:          version,
+		Checksum:         object.Key,
+		Size:             object.Size,
+		VocabCount:       len(content.Vocabs),
+		ExampleCount:     len(content.Examples),
+		AuditID:          content.AuditID,
+		TxSnapshot:       content.TxSnapshot,
+		Live:             true,
+		CreatedBy:        actor,
+		Created:          document.Published,
+	}
+	for _, asset := range content.AudioAssets {
+		snapshot.AudioIDs = append(snapshot.AudioIDs, asset.ID)
+	}
+	if err = s.repo.CreateSnapshot(snapshot); err != nil {
+		return nil, err
+	}
+
+	comments := fmt.Sprintf("published snapshot version %d", version)
+	if err = s.auditService.CreateAudit("snapshot", snapshot.ID, comments, actor, "", snapshot.JSON()); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// buildSnapshotDocument builds the document of a snapshot from its content. Vocab keep the
+// order of the content, and their examples the order they were added in.
+func buildSnapshotDocument(learningCode string, version int, content *mdl.SnapshotContent) *SnapshotDocument {
+
+	audio := make(map[int]string)
+	for _, asset := range content.AudioAssets {
+		audio[asset.ID] = media.AudioURL(asset.Hash)
+	}
+
+	examples := make(map[int][]SnapshotExample)
+	for i := range content.Examples {
+		example := &content.Examples[i]
+		examples[example.VocabID] = append(examples[example.VocabID], SnapshotExample{
+			Sentence:    example.Sentence,
+			Translation: example.Translation,
+			Source:      example.Source,
+			Tokens:      TokenizeLearningLang(example.Sentence, learningCode),
+			Highlights:  append([]int{}, example.HighlightList()...),
+		})
+	}
+
+	document := &SnapshotDocument{
+		LearningLangCode: learningCode,
+		Version:          version,
+		Published:        time.Now().UTC().Truncate(time.Second),
+		Vocabs:           make([]SnapshotVocab, 0, len(content.Vocabs)),
+	}
+	for _, vocab := range content.Vocabs {
+		alternatives := make([]string, 0, len(vocab.AlternativeList))
+		for _, alternative := range vocab.AlternativeList {
+			alternatives = append(alternatives, alternative.Alternative)
+		}
+
+		entry := SnapshotVocab{
+			ID:               vocab.ID,
+			LearningLang:     vocab.LearningLang,
+			FirstLang:        vocab.FirstLang,
+			KnownLangCode:    vocab.KnownLangCode,
+			Alternatives:     alternatives,
+			Skill:            vocab.Skill,
+			Infinitive:       vocab.Infinitive,
+			Pos:              vocab.Pos,
+			Hint:             vocab.Hint,
+			Gender:           vocab.Gender,
+			Plural:           vocab.Plural,
+			Article:          vocab.Article,
+			Register:         vocab.Register,
+			NumLearningWords: vocab.NumLearningWords,
+			ConceptID:        vocab.ConceptID,
+			Examples:         examples[vocab.ID],
+		}
+		if vocab.AudioID != nil {
+			entry.AudioURL = audio[*vocab.AudioID]
+		}
+		if entry.Examples == nil {
+			entry.Examples = []SnapshotExample{}
+		}
+		document.Vocabs = append(document.Vocabs, entry)
+	}
+
+	return document
+}
+
+// RollbackSnapshot makes an earlier version of the snapshots of a learning language the one
+// served to learners again. The snapshot file is checked against its checksum first, so a
+// missing or damaged file is never served. The rollback is audited.
+//
+// Parameters:
+// - learningCode: The learning language code of the snapshot, e.g. "es".
+// - version: The version to serve.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the mdl.Snapshot now live.
+// - An error if the version cannot be found, is already live, its file does not match its
+// checksum, or saving fails.
+//
+// Usage example:
+// snapshot, err := snapshotService.RollbackSnapshot("es", 3, srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to roll back snapshot: %v", err)
+//	}
+func (s *SnapshotService) RollbackSnapshot(learningCode string, version int, actor string) (*mdl.Snapshot, error) {
+
+	code := CanonicalLangCode(learningCode)
+	snapshot, err := s.repo.FindSnapshot(code, version)
+	if err != nil {
+		return nil, invalidField("version", RuleNotFound, 0, "there is no snapshot version %d of %s", version, code)
+	}
+	if snapshot.Live {
+		return nil, invalidField("version", RuleInvalid, 0, "snapshot version %d of %s is already live", version, code)
+	}
+
+	if err = s.verifySnapshot(snapshot); err != nil {
+		return nil, err
+	}
+
+	beforeJson := snapshot.JSON()
+	if err = s.repo.SetLiveSnapshot(snapshot); err != nil {
+		return nil, err
+	}
+
+	comments := fmt.Sprintf("rolled back to snapshot version %d", version)
+	if err = s.auditService.CreateRevertAudit("snapshot", snapshot.ID, comments, actor, beforeJson, snapshot.JSON()); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// verifySnapshot ensures the file of a snapshot is in the snapshot storage and matches its checksum.
+func (s *SnapshotService) verifySnapshot(snapshot *mdl.Snapshot) error {
+	file, _, err := s.storage.Open(snapshot.Checksum)
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot version %d of %s, error: %v", snapshot.Version,
+			snapshot.LearningLangCode, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return fmt.Errorf("failed to read snapshot version %d of %s, error: %v", snapshot.Version,
+			snapshot.LearningLangCode, err)
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != snapshot.Checksum {
+		return fmt.Errorf("snapshot version %d of %s does not match its checksum", snapshot.Version,
+			snapshot.LearningLangCode)
+	}
+	return nil
+}
+
+// SnapshotChangelog lists the audited changes to the vocab of a learning language and their
+// example sentences made between two snapshots, oldest first. A change is in the changelog of
+// a language when the vocab changed is, or was, of that language.
+//
+// Parameters:
+// - learningCode: The learning language code of the snapshots, e.g. "es".
+// - fromVersion: The version the changes are made after.
+// - toVersion: The version the changes are made up to, or nil for the changes made since fromVersion.
+//
+// Returns:
+// - The changes, in the order they were made.
+// - An error if either version cannot be found, toVersion is not later than fromVersion, or
+// the audits cannot be read.
+//
+// Usage example:
+// changes, err := snapshotService.SnapshotChangelog("es", 2, nil)
+//
+//	if err != nil {
+//	    log.Printf("Failed to find snapshot changelog: %v", err)
+//	}
+func (s *SnapshotService) SnapshotChangelog(learningCode string, fromVersion int, toVersion *int) (changes []SnapshotChange, err error) {
+
+	code := CanonicalLangCode(learningCode)
+	from, err := s.repo.FindSnapshot(code, fromVersion)
+	if err != nil {
+		return nil, invalidField("from_version", RuleNotFound, 0, "there is no snapshot version %d of %s", fromVersion, code)
+	}
+
+	var to *mdl.Snapshot
+	if toVersion != nil {
+		if *toVersion <= fromVersion {
+			return nil, invalidField("to_version", RuleInvalid, 0, "to version %d must be later than from version %d",
+				*toVersion, fromVersion)
+		}
+		if to, err = s.repo.FindSnapshot(code, *toVersion); err != nil {
+			return nil, invalidField("to_version", RuleNotFound, 0, "there is no snapshot version %d of %s", *toVersion, code)
+		}
+	}
+
+	audits, err := s.auditRepo.FindAuditsBetween(from, to, snapshotTables)
+	if err != nil {
+		return nil, err
+	}
+
+	// The language of a vocab is taken from its audits, which also covers vocab since
+	// deleted or moved to another language, and otherwise from the vocab itself.
+	vocabLangs := make(map[int]map[string]bool)
+	for _, audit := range *audits {
+		if audit.TableName != "vocab" {
+			continue
+		}
+		if vocabLangs[audit.ObjectID] == nil {
+			vocabLangs[audit.ObjectID] = make(map[string]bool)
+		}
+		for _, state := range []string{audit.Before, audit.After} {
+			var vocab mdl.Vocab
+			if len(state) > 0 && json.Unmarshal([]byte(state), &vocab) == nil {
+				vocabLangs[audit.ObjectID][CanonicalLangCode(vocab.LearningLangCode)] = true
+			}
+		}
+	}
+
+	for i := range *audits {
+		audit := &(*audits)[i]
+
+		vocabID := audit.ObjectID
+		if audit.TableName == "example_sentence" {
+			vocabID = exampleVocabOf(audit)
+			if _, found := vocabLangs[vocabID]; !found {
+				vocabLangs[vocabID] = map[string]bool{}
+				if vocab, err := s.vocabRepo.FindVocabByID(vocabID); err == nil {
+					vocabLangs[vocabID][CanonicalLangCode(vocab.LearningLangCode)] = true
+				}
+			}
+		}
+		if !vocabLangs[vocabID][code] {
+			continue
+		}
+
+		change := SnapshotChange{
+			AuditID:   audit.ID,
+			TableName: audit.TableName,
+			ObjectID:  audit.ObjectID,
+			VocabID:   vocabID,
+			Action:    audit.DerivedAction(),
+			Comments:  audit.Comments,
+			CreatedBy: audit.CreatedBy,
+			Created:   audit.Created,
+		}
+		if change.Action == "updated" {
+			change.Fields = diffFields(audit.Diff)
+		}
+		changes = append(changes, change)
+	}
+
+	return
+}
+
+// exampleVocabOf returns the vocab ID of the audited example sentence, 0 when there is none.
+func exampleVocabOf(audit *mdl.Audit) int {
+	for _, state := range []string{audit.After, audit.Before} {
+		var example mdl.ExampleSentence
+		if len(state) > 0 && json.Unmarshal([]byte(state), &example) == nil && example.VocabID > 0 {
+			return example.VocabID
+		}
+	}
+	return 0
+}
+
+// diffFields returns the fields named by the keys of an audit diff, in order, see CompareJSON.
+func diffFields(diff string) (fields []string) {
+	var results []DiffResult
+	if len(diff) == 0 || json.Unmarshal([]byte(diff), &results) != nil {
+		return nil
+	}
+
+	for _, result := range results {
+		field, _, _ := strings.Cut(strings.TrimPrefix(result.Key, "'"), "'")
+		if len(field) > 0 && indexOfString(fields, field) < 0 {
+			fields = append(fields, field)
+		}
+	}
+	return
+}
+
+// Handler serves the live snapshot of a learning language under SnapshotPath, followed by the
+// learning language code. The checksum of the snapshot is its ETag, so the learner app can
+// check for a new version with a conditional request, and its version is sent in the
+// SnapshotVersionHeader.
+//
+// Usage example:
+//
+//	http.Handle(srv.SnapshotPath, snapshotService.Handler())
+func (s *SnapshotService) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		code := CanonicalLangCode(strings.TrimPrefix(r.URL.Path, SnapshotPath))
+		snapshot, err := s.repo.FindLiveSnapshot(code)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		file, _, err := s.storage.Open(snapshot.Checksum)
+		if err != nil {
+			http.Error(w, "failed to read snapshot", http.StatusInternalServerError)
+			return
+		}
+		defer file.Close()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("ETag", `"`+snapshot.Checksum+`"`)
+		w.Header().Set(SnapshotVersionHeader, strconv.Itoa(snapshot.Version))
+		http.ServeContent(w, r, "", snapshot.Created, file)
+	})
+}
@@ -0,0 +1,319 @@
+package srv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db/mock"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"github.com/heather92115/verdure-admin/internal/media"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnapshotService_PublishSnapshot(t *testing.T) {
+	snapshotService := createMockSnapshotService(t)
+
+	tests := []struct {
+		name         string
+		learningCode string
+		wantVersion  int
+		wantErr      bool
+		errMsg       string
+	}{
+		{name: "First version", learningCode: "es", wantVersion: 1},
+		{name: "Next version", learningCode: "ES", wantVersion: 2},
+		{name: "First version of another language", learningCode: "fr", wantVersion: 1},
+		{name: "No vocab", learningCode: "de", wantErr: true, errMsg: "there is no vocab with learning code de to publish"},
+		{name: "Invalid code", learningCode: "spanish!", wantErr: true, errMsg: "learning code spanish! is not a valid language tag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot, err := snapshotService.PublishSnapshot(tt.learningCode, "sys")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PublishSnapshot() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil && !strings.HasPrefix(err.Error(), tt.errMsg) {
+				t.Errorf("PublishSnapshot() error = %v, wantErrMsg %v", err, tt.errMsg)
+			} else if err == nil && (snapshot.Version != tt.wantVersion || !snapshot.Live) {
+				t.Errorf("PublishSnapshot() version = %d, live = %v, want version %d live", snapshot.Version,
+					snapshot.Live, tt.wantVersion)
+			}
+		})
+	}
+
+	snapshots, _ := snapshotService.FindSnapshots("es")
+	if len(*snapshots) != 2 || (*snapshots)[0].Version != 2 || !(*snapshots)[0].Live || (*snapshots)[1].Live {
+		t.Fatalf("FindSnapshots() = %+v, want version 2 live before version 1", *snapshots)
+	}
+
+	latest := (*snapshots)[0]
+	if latest.VocabCount != 2 || latest.ExampleCount != 1 {
+		t.Errorf("PublishSnapshot() counts = %d vocab, %d examples, want 2 and 1", latest.VocabCount, latest.ExampleCount)
+	}
+
+	document := readSnapshotDocument(t, snapshotService.storage, &latest)
+	if document.Version != 2 || len(document.Vocabs) != 2 {
+		t.Fatalf("PublishSnapshot() document = %+v, want version 2 with 2 vocab", document)
+	}
+	perro := document.Vocabs[0]
+	if perro.LearningLang != "perro" || !reflect.DeepEqual(perro.Alternatives, []string{"can"}) {
+		t.Errorf("PublishSnapshot() vocab = %+v, want perro with alternative can", perro)
+	}
+	if len(perro.Examples) != 1 || !reflect.DeepEqual(perro.Examples[0].Tokens, []string{"Tengo", "un", "perro"}) ||
+		!reflect.DeepEqual(perro.Examples[0].Highlights, []int{2}) {
+		t.Errorf("PublishSnapshot() examples = %+v, want the perro example highlighted", perro.Examples)
+	}
+	if gato := document.Vocabs[1]; gato.Examples == nil || len(gato.Examples) != 0 {
+		t.Errorf("PublishSnapshot() examples of gato = %v, want none", gato.Examples)
+	}
+}
+
+func TestSnapshotService_RollbackSnapshot(t *testing.T) {
+	snapshotService := createMockSnapshotService(t)
+
+	first, _ := snapshotService.PublishSnapshot("es", "sys")
+	_, _ = snapshotService.PublishSnapshot("es", "sys")
+	_, _ = snapshotService.PublishSnapshot("es", "sys")
+
+	tests := []struct {
+		name    string
+		version int
+		wantErr bool
+		errMsg  string
+	}{
+		{name: "Earlier version", version: 2},
+		{name: "Already live", version: 2, wantErr: true, errMsg: "snapshot version 2 of es is already live"},
+		{name: "Missing version", version: 9, wantErr: true, errMsg: "there is no snapshot version 9 of es"},
+		{name: "Missing file", version: 1, wantErr: true, errMsg: "failed to open snapshot version 1 of es"},
+		{name: "Latest version", version: 3},
+	}
+
+	// Each version is its own file, since the version is part of the content
+	_ = snapshotService.storage.Delete(first.Checksum)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot, err := snapshotService.RollbackSnapshot("es", tt.version, "sys")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RollbackSnapshot() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil && !strings.HasPrefix(err.Error(), tt.errMsg) {
+				t.Errorf("RollbackSnapshot() error = %v, wantErrMsg %v", err, tt.errMsg)
+			} else if err == nil {
+				live, _ := snapshotService.repo.FindLiveSnapshot("es")
+				if !snapshot.Live || live.Version != tt.version {
+					t.Errorf("RollbackSnapshot() live version = %d, want %d", live.Version, tt.version)
+				}
+			}
+		})
+	}
+}
+
+func TestSnapshotService_SnapshotChangelog(t *testing.T) {
+	snapshotService := createMockSnapshotService(t)
+	_, _ = snapshotService.PublishSnapshot("es", "sys")
+
+	perro, _ := snapshotService.vocabRepo.FindVocabByID(1)
+	updated := *perro
+	updated.Hint = "a pet"
+	chien, _ := snapshotService.vocabRepo.FindVocabByID(3)
+	moved := *chien
+	moved.Hint = "a pet"
+	example := mdl.ExampleSentence{VocabID: 2, Sentence: "Un gato.", Translation: "A cat."}
+
+	saveAudit := func(tableName string, objectID int, beforeJson string, afterJson string) {
+		audit, _ := buildAudit(tableName, objectID, "test change", "sys", beforeJson, afterJson)
+		_ = snapshotService.auditRepo.CreateAudit(audit)
+	}
+	saveAudit("vocab", 1, perro.JSON(), updated.JSON())
+	saveAudit("vocab", 3, chien.JSON(), moved.JSON())
+	saveAudit("example_sentence", 2, "", example.JSON())
+	_, _ = snapshotService.PublishSnapshot("es", "sys")
+	saveAudit("example_sentence", 2, example.JSON(), "")
+
+	two := 2
+	tests := []struct {
+		name        string
+		fromVersion int
+		toVersion   *int
+		wantActions []string
+		wantErr     bool
+		errMsg      string
+	}{
+		{name: "Between versions", fromVersion: 1, toVersion: &two, wantActions: []string{"vocab 1 updated [hint]", "example_sentence 2 created []"}},
+		{name: "Since a version", fromVersion: 1, wantActions: []string{"vocab 1 updated [hint]", "example_sentence 2 created []",
+			"example_sentence 2 deleted []"}},
+		{name: "Since the latest version", fromVersion: 2, wantActions: []string{"example_sentence 2 deleted []"}},
+		{name: "Versions out of order", fromVersion: 2, toVersion: &two, wantErr: true, errMsg: "to version 2 must be later than from version 2"},
+		{name: "Missing version", fromVersion: 9, wantErr: true, errMsg: "there is no snapshot version 9 of es"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := snapshotService.SnapshotChangelog("es", tt.fromVersion, tt.toVersion)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SnapshotChangelog() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil && !strings.HasPrefix(err.Error(), tt.errMsg) {
+				t.Errorf("SnapshotChangelog() error = %v, wantErrMsg %v", err, tt.errMsg)
+			} else if err == nil {
+				var actions []string
+				for _, change := range changes {
+					actions = append(actions, fmt.Sprintf("%s %d %s %v", change.TableName, change.VocabID, change.Action,
+						change.Fields))
+				}
+				if !reflect.DeepEqual(actions, tt.wantActions) {
+					t.Errorf("SnapshotChangelog() = %v, want %v", actions, tt.wantActions)
+				}
+			}
+		})
+	}
+}
+
+func TestSnapshotService_Handler(t *testing.T) {
+	snapshotService := createMockSnapshotService(t)
+	snapshot, _ := snapshotService.PublishSnapshot("es", "sys")
+	handler := snapshotService.Handler()
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, SnapshotPath+"es", nil))
+	if response.Code != http.StatusOK {
+		t.Fatalf("Handler() status = %d, want %d", response.Code, http.StatusOK)
+	}
+	if etag := response.Header().Get("ETag"); etag != `"`+snapshot.Checksum+`"` {
+		t.Errorf("Handler() ETag = %s, want the checksum", etag)
+	}
+	if version := response.Header().Get(SnapshotVersionHeader); version != "1" {
+		t.Errorf("Handler() version = %s, want 1", version)
+	}
+	var document SnapshotDocument
+	if err := json.Unmarshal(response.Body.Bytes(), &document); err != nil || len(document.Vocabs) != 2 {
+		t.Errorf("Handler() document = %+v, error = %v, want 2 vocab", document, err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		etag       string
+		wantStatus int
+	}{
+		{name: "Not modified", method: http.MethodGet, path: SnapshotPath + "es", etag: `"` + snapshot.Checksum + `"`,
+			wantStatus: http.StatusNotModified},
+		{name: "Stale etag", method: http.MethodGet, path: SnapshotPath + "es", etag: `"stale"`, wantStatus: http.StatusOK},
+		{name: "Not published", method: http.MethodGet, path: SnapshotPath + "de", wantStatus: http.StatusNotFound},
+		{name: "Method not allowed", method: http.MethodPost, path: SnapshotPath + "es", wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(tt.method, tt.path, nil)
+			if len(tt.etag) > 0 {
+				request.Header.Set("If-None-Match", tt.etag)
+			}
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, request)
+			if response.Code != tt.wantStatus {
+				t.Errorf("Handler() status = %d, want %d", response.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// readSnapshotDocument reads the file of a snapshot, checking it matches its checksum.
+func readSnapshotDocument(t *testing.T, storage media.Storage, snapshot *mdl.Snapshot) *SnapshotDocument {
+	file, _, err := storage.Open(snapshot.Checksum)
+	if err != nil {
+		t.Fatalf("failed to open snapshot: %v", err)
+	}
+	defer file.Close()
+
+	data, _ := io.ReadAll(file)
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != snapshot.Checksum {
+		t.Fatalf("snapshot does not match its checksum %s", snapshot.Checksum)
+	}
+
+	document := &SnapshotDocument{}
+	if err = json.Unmarshal(data, document); err != nil {
+		t.Fatalf("failed to decode snapshot: %v", err)
+	}
+	return document
+}
+
+func TestSnapshotService_PublishedAudioKept(t *testing.T) {
+	storage, err := media.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	auditRepo := mock.NewMockAuditRepository()
+	alternativeRepo := mock.NewMockAlternativeRepository()
+	vocabRepo := mock.NewMockVocabRepository(auditRepo, alternativeRepo)
+	audioRepo := mock.NewMockAudioRepository(vocabRepo)
+	snapshotService := SnapshotService{
+		repo: mock.NewMockSnapshotRepository(vocabRepo, alternativeRepo, mock.NewMockExampleRepository(auditRepo),
+			audioRepo, auditRepo),
+		auditRepo:    auditRepo,
+		vocabRepo:    vocabRepo,
+		storage:      storage,
+		auditService: AuditService{repo: auditRepo},
+	}
+	audioStorage, err := media.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	audioService := AudioService{repo: audioRepo, storage: audioStorage, auditService: AuditService{repo: auditRepo}}
+
+	asset := &mdl.AudioAsset{Hash: "abc", Filename: "perro.wav", Created: time.Now().Add(-time.Hour)}
+	_ = audioRepo.CreateAudioAsset(asset)
+	vocab := &mdl.Vocab{LearningLang: "perro", FirstLang: "dog", Pos: "noun", LearningLangCode: "es",
+		KnownLangCode: "en", AudioID: &asset.ID}
+	_ = vocabRepo.CreateVocab(vocab, nil)
+
+	if _, err = snapshotService.PublishSnapshot("es", "sys"); err != nil {
+		t.Fatalf("PublishSnapshot() error = %v", err)
+	}
+
+	// Detached once published, the recording is still served by the snapshot
+	vocab.AudioID = nil
+	cleanup, err := audioService.CleanupOrphanAudio(0, true)
+	if err != nil || len(cleanup.Assets) != 0 {
+		t.Errorf("CleanupOrphanAudio() = %+v, %v, want the published audio kept", cleanup, err)
+	}
+	if _, err = audioRepo.FindAudioAssetByID(asset.ID); err != nil {
+		t.Errorf("CleanupOrphanAudio() deleted the published audio: %v", err)
+	}
+}
+
+func createMockSnapshotService(t *testing.T) SnapshotService {
+	storage, err := media.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	auditRepo := mock.NewMockAuditRepository()
+	alternativeRepo := mock.NewMockAlternativeRepository()
+	vocabRepo := mock.NewMockVocabRepository(auditRepo, alternativeRepo)
+	exampleRepo := mock.NewMockExampleRepository(auditRepo)
+	audioRepo := mock.NewMockAudioRepository(vocabRepo)
+
+	_ = vocabRepo.CreateVocab(&mdl.Vocab{LearningLang: "perro", FirstLang: "dog", Pos: "noun", LearningLangCode: "es", KnownLangCode: "en"}, nil)
+	_ = vocabRepo.CreateVocab(&mdl.Vocab{LearningLang: "gato", FirstLang: "cat", Pos: "noun", LearningLangCode: "es", KnownLangCode: "en"}, nil)
+	_ = vocabRepo.CreateVocab(&mdl.Vocab{LearningLang: "chien", FirstLang: "dog", Pos: "noun", LearningLangCode: "fr", KnownLangCode: "en"}, nil)
+	_ = alternativeRepo.CreateAlternative(&mdl.VocabAlternative{VocabID: 1, Alternative: "can"})
+	_ = exampleRepo.CreateExampleSentence(&mdl.ExampleSentence{VocabID: 1, Sentence: "Tengo un perro.",
+		Translation: "I have a dog.", Highlights: "2"}, nil)
+
+	return SnapshotService{
+		repo:         mock.NewMockSnapshotRepository(vocabRepo, alternativeRepo, exampleRepo, audioRepo, auditRepo),
+		auditRepo:    auditRepo,
+		vocabRepo:    vocabRepo,
+		storage:      storage,
+		auditService: AuditService{repo: auditRepo},
+	}
+}
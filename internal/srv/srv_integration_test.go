@@ -4,14 +4,17 @@
 package srv
 
 import (
+	"context"
 	"fmt"
 	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/event"
 	"github.com/heather92115/verdure-admin/internal/mdl"
 	"github.com/joho/godotenv"
 	"log"
 	"math"
 	"math/rand"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -48,12 +51,12 @@ func TestIntegrationFixitService_CreateFindFixitByID(t *testing.T) {
 
 	testFixit := &mdl.Fixit{
 		Status:    "pending",
-		FieldName: "Definition",
+		FieldName: "first_lang",
 		Comments:  "Initial comment",
 		CreatedBy: "tester",
 		Created:   time.Now(),
 	}
-	_ = fixitService.CreateFixit(testFixit)
+	_ = fixitService.CreateFixit(testFixit, "sys")
 
 	fixit, err := fixitService.FindFixitByID(testFixit.ID)
 	if err != nil {
@@ -79,12 +82,12 @@ func TestIntegrationFixitService_CreateFindUpdate(t *testing.T) {
 
 	testFixit := &mdl.Fixit{
 		Status:    "pending",
-		FieldName: "Definition",
+		FieldName: "first_lang",
 		Comments:  "Initial comment",
 		CreatedBy: "tester",
 		Created:   time.Now(),
 	}
-	err = fixitService.CreateFixit(testFixit)
+	err = fixitService.CreateFixit(testFixit, "sys")
 	if err != nil {
 		t.Errorf("Unexpected error on create: %v", err)
 	}
@@ -104,9 +107,9 @@ func TestIntegrationFixitService_CreateFindUpdate(t *testing.T) {
 			t.Errorf("Expected fixit with id %d to have status 'pending'", fixit.ID)
 		}
 
-		fixit.Status = "completed"
-		fixit.Comments = randomLetters(20)
-		updated, err := fixitService.UpdateFixit(&fixit)
+		completed := mdl.Completed
+		comments := randomLetters(20)
+		updated, err := fixitService.UpdateFixit(&mdl.FixitPatch{ID: fixit.ID, Status: &completed, Comments: &comments}, "sys")
 		if err != nil {
 			t.Errorf("Unexpected error on update: %v", err)
 		}
@@ -142,7 +145,7 @@ func TestIntegrationVocabService_CreateFindUpdate(t *testing.T) {
 		KnownLangCode:    "en",
 	}
 
-	err = vocabService.CreateVocab(testVocab)
+	err = vocabService.CreateVocab(testVocab, false, "sys")
 	if err != nil {
 		log.Printf("Validation error on create vocab %+v, err: %v", testVocab, err)
 		t.Errorf("Unexpected error on create: %v", err)
@@ -207,7 +210,7 @@ func TestIntegrationVocabService_CreateFindUpdate(t *testing.T) {
 		}
 
 		vocab.Hint = randomLetters(20)
-		updated, err := vocabService.UpdateVocab(&vocab)
+		updated, err := vocabService.UpdateVocab(&mdl.VocabPatch{ID: vocab.ID, Hint: &vocab.Hint}, "sys")
 		if err != nil {
 			t.Errorf("Unexpected error on update %+v, err: %v", vocab, err)
 			return
@@ -233,3 +236,44 @@ func randomLetters(length int) string {
 
 	return string(result)
 }
+
+func TestIntegrationNotifyRelay(t *testing.T) {
+	testUrl := os.Getenv("PAL_TEST_DATABASE_URL")
+
+	// A second instance, with a bus of its own, listens for the changes published here
+	other := event.NewBus()
+	listener, err := db.NewNotifyRelay(testUrl, other)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	received := other.Subscribe(ctx, TopicFixitChanged)
+	go listener.Listen(ctx)
+	time.Sleep(500 * time.Millisecond)
+
+	relay, err := db.NewNotifyRelay(testUrl, event.Default())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	event.Default().SetRelay(relay)
+	defer event.Default().SetRelay(nil)
+
+	fixitService, err := NewFixitService()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v, failed to create Fixit Service", err)
+	}
+	fixit := &mdl.Fixit{Status: "pending", FieldName: "hint", Comments: "relayed", CreatedBy: "tester"}
+	if err = fixitService.CreateFixit(fixit, "sys"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	select {
+	case relayed := <-received:
+		if !strings.Contains(string(relayed.Payload), fmt.Sprintf(`"id":%d`, fixit.ID)) {
+			t.Errorf("Relayed event %s is not fixit %d", relayed.Payload, fixit.ID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("No event relayed to the other instance")
+	}
+}
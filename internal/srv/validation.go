@@ -1,19 +1,125 @@
 package srv
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"strings"
-	"unicode/utf8"
 )
 
 const (
 	errFmtStrLen = "%s must be shorter than %d characters"
 )
 
-// validateFieldContent checks a string field's content for compliance with specified length and character restrictions.
-// It ensures the field does not exceed a maximum length and does not contain characters that could be used for XSS or injection attacks.
-// This function is intended for basic validation and sanitization of input fields to prevent common security vulnerabilities.
+// The rule codes of a FieldError, telling clients which kind of check failed.
+const (
+	RuleMaxLength         = "MAX_LENGTH"
+	RuleMinLength         = "MIN_LENGTH"
+	RuleInvalidCharacters = "INVALID_CHARACTERS"
+	RuleRequired          = "REQUIRED"
+	RuleOneOf             = "ONE_OF"
+	RuleFormat            = "FORMAT"
+	RuleUnique            = "UNIQUE"
+	RuleNotFound          = "NOT_FOUND"
+	RuleLanguage          = "LANGUAGE"
+	RuleNotAllowed        = "NOT_ALLOWED"
+	RuleMarkup            = "MARKUP"
+	RuleReviewRequired    = "REVIEW_REQUIRED"
+	RuleInvalid           = "INVALID"
+)
+
+// FieldError is a single validation failure of an input field.
+//
+// Fields:
+//   - Field: The path of the field in the GraphQL input, e.g. "hint" or "alternatives[1].notes".
+//     Empty when the failure is not about one field.
+//   - Rule: The rule code of the check that failed, e.g. RuleMaxLength.
+//   - Limit: The limit the value broke, such as the maximum length, 0 when the rule has none.
+//   - Message: The human readable description of the failure.
+type FieldError struct {
+	Field   string
+	Rule    string
+	Limit   int
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// invalidField returns a FieldError for a failed rule with its message.
+func invalidField(field string, rule string, limit int, format string, args ...interface{}) *FieldError {
+	return &FieldError{Field: field, Rule: rule, Limit: limit, Message: fmt.Sprintf(format, args...)}
+}
+
+// ValidationError collects every validation failure of an input rather than only the first,
+// so a client can point at each field. Its message joins the failure messages, so a single
+// failure reads as it always has.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		messages[i] = fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// add records a failure of the field at the path, ignoring a nil error. A FieldError without
+// a path is placed at the path, the failures of a nested ValidationError are placed below it,
+// e.g. "alternatives[1].notes", and other errors are recorded with RuleInvalid.
+func (e *ValidationError) add(path string, err error) {
+	var fieldErr *FieldError
+	var nested *ValidationError
+
+	switch {
+	case err == nil:
+		return
+	case errors.As(err, &nested):
+		for _, found := range nested.Errors {
+			if len(path) > 0 && len(found.Field) > 0 {
+				found.Field = path + "." + found.Field
+			} else if len(path) > 0 {
+				found.Field = path
+			}
+			e.Errors = append(e.Errors, found)
+		}
+	case errors.As(err, &fieldErr):
+		found := *fieldErr
+		if len(found.Field) == 0 {
+			found.Field = path
+		}
+		e.Errors = append(e.Errors, found)
+	default:
+		e.Errors = append(e.Errors, FieldError{Field: path, Rule: RuleInvalid, Message: err.Error()})
+	}
+}
+
+// failed reports whether the field at the path already has a failure.
+func (e *ValidationError) failed(path string) bool {
+	for _, fieldErr := range e.Errors {
+		if fieldErr.Field == path {
+			return true
+		}
+	}
+	return false
+}
+
+// errOrNil returns the ValidationError when it holds any failure and nil otherwise, so the
+// result can be returned as an error.
+func (e *ValidationError) errOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// validateFieldContent checks a plain text field's content for compliance with specified length and character restrictions.
+// It ensures the field does not exceed a maximum length and holds no control characters, bidirectional controls or markup,
+// see validateText, so the text cannot be used for XSS or injection attacks or display other than it is stored.
+// Fields allowing some markup are checked with validateText and their TextPolicy.
 //
 // Parameters:
 // - fieldValue: The content of the field to validate.
@@ -21,8 +127,8 @@ const (
 // - maxLength: The maximum allowed length of the field content in Unicode code points.
 //
 // Returns:
-//   - An error if the field content exceeds the maxLength or contains restricted characters, specifying the nature of the validation failure.
-//     Returns nil if the field content passes all validation checks.
+//   - A *FieldError if the field content exceeds the maxLength or contains restricted characters or markup, specifying the nature of the validation failure.
+//     Its Field is left empty for the caller to place, see ValidationError. Returns nil if the field content passes all validation checks.
 //
 // Usage example:
 // err := validateFieldContent(userInput, "username", 50)
@@ -31,14 +137,9 @@ const (
 //	    log.Printf("Validation error: %v", err)
 //	}
 func validateFieldContent(fieldValue, fieldName string, maxLength int) error {
-	if utf8.RuneCountInString(fieldValue) > maxLength {
-		return fmt.Errorf(errFmtStrLen, fieldName, maxLength)
-	}
-	// Example basic check against common XSS/injection patterns. Expand as necessary.
-	if strings.ContainsAny(fieldValue, "<>") && strings.ContainsAny(fieldValue, "\"/") {
-		log.Printf("Validation error on fieldName %s, fieldValue %s ", fieldName, fieldValue)
-
-		return fmt.Errorf("%s contains invalid characters", fieldName)
+	err := validateText(fieldValue, fieldName, maxLength, PlainText)
+	if err != nil {
+		log.Printf("Validation error on fieldName %s, fieldValue %q: %v", fieldName, fieldValue, err)
 	}
-	return nil
+	return err
 }
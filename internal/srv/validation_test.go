@@ -1,8 +1,13 @@
 package srv
 
 import (
+	"errors"
 	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"reflect"
+	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func TestValidateFieldContent(t *testing.T) {
@@ -36,7 +41,69 @@ func TestValidateFieldContent(t *testing.T) {
 			fieldName:  "username",
 			maxLength:  50,
 			wantErr:    true,
-			errMsg:     "username contains invalid characters",
+			errMsg:     "username contains markup that is not allowed: <script/>",
+		},
+		{
+			name:       "Input contains a tag without a slash or quote",
+			fieldValue: "<b>ser</b>",
+			fieldName:  "username",
+			maxLength:  50,
+			wantErr:    true,
+			errMsg:     "username contains markup that is not allowed: <b>",
+		},
+		{
+			name:       "Input contains an unclosed tag",
+			fieldValue: "perro <img src=x onerror=alert(1)",
+			fieldName:  "username",
+			maxLength:  50,
+			wantErr:    true,
+			errMsg:     "username contains markup that is not allowed: <img src=x onerror=a...",
+		},
+		{
+			name:       "Input contains a comment",
+			fieldValue: "perro <!-- x -->",
+			fieldName:  "username",
+			maxLength:  50,
+			wantErr:    true,
+			errMsg:     "username contains markup that is not allowed: <!-- x -->",
+		},
+		{
+			name:       "Input with angle brackets and quotes as text",
+			fieldValue: `1/2 > 1/3, "a < b"`,
+			fieldName:  "username",
+			maxLength:  50,
+			wantErr:    false,
+		},
+		{
+			name:       "Input contains a control character",
+			fieldValue: "perro\x00",
+			fieldName:  "username",
+			maxLength:  50,
+			wantErr:    true,
+			errMsg:     "username contains control characters",
+		},
+		{
+			name:       "Input contains a bidi override",
+			fieldValue: "perro\u202egod",
+			fieldName:  "username",
+			maxLength:  50,
+			wantErr:    true,
+			errMsg:     "username contains bidirectional control characters",
+		},
+		{
+			name:       "Input with right to left marks",
+			fieldValue: "\u200fكلب\u200f",
+			fieldName:  "username",
+			maxLength:  50,
+			wantErr:    false,
+		},
+		{
+			name:       "Input is not UTF-8",
+			fieldValue: "perro\xff",
+			fieldName:  "username",
+			maxLength:  50,
+			wantErr:    true,
+			errMsg:     "username is not valid UTF-8 text",
 		},
 		{
 			name:       "Empty input",
@@ -58,3 +125,120 @@ func TestValidateFieldContent(t *testing.T) {
 		})
 	}
 }
+
+func TestValidationError_Add(t *testing.T) {
+	nested := &ValidationError{}
+	nested.add("notes", validateFieldContent("<a href=\"/\">", "Notes", 50))
+
+	errs := &ValidationError{}
+	errs.add("hint", nil)
+	errs.add("hint", validateFieldContent("a long hint", "Hint", 5))
+	errs.add("alternatives[1]", nested)
+	errs.add("pos", invalidField("pos", RuleOneOf, 0, "part of speech %s is not a managed value", "nou"))
+	errs.add("skill", fmt.Errorf("skill lookup failed"))
+
+	want := []FieldError{
+		{Field: "hint", Rule: RuleMaxLength, Limit: 5, Message: "Hint must be shorter than 5 characters"},
+		{Field: "alternatives[1].notes", Rule: RuleMarkup, Message: `Notes contains markup that is not allowed: <a href="/">`},
+		{Field: "pos", Rule: RuleOneOf, Message: "part of speech nou is not a managed value"},
+		{Field: "skill", Rule: RuleInvalid, Message: "skill lookup failed"},
+	}
+	if !reflect.DeepEqual(errs.Errors, want) {
+		t.Errorf("add() errors = %+v, want %+v", errs.Errors, want)
+	}
+	if !errs.failed("alternatives[1].notes") || errs.failed("first_lang") {
+		t.Errorf("failed() did not report the failed paths")
+	}
+	if (&ValidationError{}).errOrNil() != nil {
+		t.Errorf("errOrNil() of no failures is not nil")
+	}
+}
+
+func TestVocabService_CreateVocabCollectsFailures(t *testing.T) {
+	vocabService := createMockVocabService()
+
+	vocab := &mdl.Vocab{LearningLang: "", FirstLang: "dog", Hint: "<b>hint</b> <u>here</u>", Register: "slangy",
+		KnownLangCode: "en", LearningLangCode: "es",
+		AlternativeList: []mdl.VocabAlternative{{Alternative: "perra"}, {Alternative: "perra"}}}
+
+	err := vocabService.CreateVocab(vocab, false, "sys")
+
+	var validation *ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("CreateVocab() error = %v, want a *ValidationError", err)
+	}
+
+	var got []string
+	for _, fieldErr := range validation.Errors {
+		got = append(got, fieldErr.Field+" "+fieldErr.Rule)
+	}
+	want := []string{"alternatives[1] UNIQUE", "learning_lang REQUIRED", "hint MARKUP", "register ONE_OF"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CreateVocab() failures = %v, want %v", got, want)
+	}
+}
+
+// FuzzValidateFieldContent checks that text passing validateFieldContent is within its length and
+// holds no control characters, bidirectional controls or markup, and that failures carry their rule.
+func FuzzValidateFieldContent(f *testing.F) {
+	for _, seed := range []string{"perro", "el perro", "<b>ser</b>", "a < b", "1/2 > 1/3", `"quoted"`,
+		"<script>alert(1)</script>", "<img src=x onerror=alert(1)", "</p", "<!-- x -->", "<?xml?>",
+		"perro\u202egod", "perro\x00", "\u200fكلب", "perro\xff", "col·legi", "<<b>>", "<3"} {
+		f.Add(seed)
+	}
+
+	const maxLength = 40
+	f.Fuzz(func(t *testing.T, text string) {
+		err := validateFieldContent(text, "Text", maxLength)
+		if err == nil {
+			if !utf8.ValidString(text) || utf8.RuneCountInString(text) > maxLength {
+				t.Fatalf("validateFieldContent(%q) passed invalid or long text", text)
+			}
+			if strings.IndexFunc(text, isRestrictedControl) >= 0 || strings.IndexFunc(text, isBidiControl) >= 0 {
+				t.Fatalf("validateFieldContent(%q) passed control characters", text)
+			}
+			for i := 0; i+1 < len(text); i++ {
+				if c := text[i+1]; text[i] == '<' && (c == '/' || c == '!' || c == '?' ||
+					('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')) {
+					t.Fatalf("validateFieldContent(%q) passed markup", text)
+				}
+			}
+			return
+		}
+
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) {
+			t.Fatalf("validateFieldContent(%q) error = %v, want a *FieldError", text, err)
+		}
+		switch fieldErr.Rule {
+		case RuleMaxLength:
+			if fieldErr.Limit != maxLength {
+				t.Fatalf("validateFieldContent(%q) limit = %d", text, fieldErr.Limit)
+			}
+		case RuleInvalidCharacters, RuleMarkup:
+		default:
+			t.Fatalf("validateFieldContent(%q) rule = %s", text, fieldErr.Rule)
+		}
+	})
+}
+
+// FuzzEscapeMarkup checks that escaped text holds no markup the policy does not allow, and that
+// escaping it again changes nothing.
+func FuzzEscapeMarkup(f *testing.F) {
+	for _, seed := range []string{"perro", "<b>ser</b>", "<i onclick=\"x()\">", "a < b", "</", "<<b>>",
+		"<br/>", "<BR >", "<!-- <b> -->", "<a <b>>"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, text string) {
+		for _, policy := range []TextPolicy{PlainText, LimitedMarkup} {
+			escaped := EscapeMarkup(text, policy)
+			if markup := policy.findMarkup(escaped); len(markup) > 0 {
+				t.Fatalf("EscapeMarkup(%q, %s) = %q still holds %q", text, policy.Name, escaped, markup)
+			}
+			if again := EscapeMarkup(escaped, policy); again != escaped {
+				t.Fatalf("EscapeMarkup(%q, %s) is not idempotent, %q then %q", text, policy.Name, escaped, again)
+			}
+		}
+	})
+}
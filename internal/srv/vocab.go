@@ -4,13 +4,23 @@ import (
 	"fmt"
 	"github.com/heather92115/verdure-admin/internal/db"
 	"github.com/heather92115/verdure-admin/internal/mdl"
-	"regexp"
+	"strings"
 )
 
 // VocabService handles business logic for Vocab entities.
 type VocabService struct {
-	repo         db.VocabRepository
-	auditService AuditService
+	repo          db.VocabRepository
+	altRepo       db.AlternativeRepository
+	fixitRepo     db.FixitRepository
+	mergeRepo     db.MergeRepository
+	lookupRepo    db.LookupRepository
+	audioRepo     db.AudioRepository
+	conceptRepo   db.ConceptRepository
+	changeRepo    db.ChangeRequestRepository
+	auditService  AuditService
+	wordCountMode WordCountMode
+	sanitizeMode  SanitizeMode
+	reviewFields  []string
 }
 
 // NewVocabService creates a new instance of VocabService.
@@ -21,12 +31,64 @@ func NewVocabService() (*VocabService, error) {
 		return nil, err
 	}
 
+	altRepo, err := db.NewSqlAlternativeRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	fixitRepo, err := db.NewSqlFixitRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	mergeRepo, err := db.NewSqlMergeRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	lookupRepo, err := db.NewSqlLookupRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	audioRepo, err := db.NewSqlAudioRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	conceptRepo, err := db.NewSqlConceptRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	changeRepo, err := db.NewSqlChangeRequestRepository()
+	if err != nil {
+		return nil, err
+	}
+
 	auditService, err := NewAuditService()
 	if err != nil {
 		return nil, err
 	}
 
-	return &VocabService{repo: repo, auditService: *auditService}, nil
+	if err = loadManagedLanguages(); err != nil {
+		return nil, err
+	}
+
+	return &VocabService{
+		repo:          repo,
+		altRepo:       altRepo,
+		fixitRepo:     fixitRepo,
+		mergeRepo:     mergeRepo,
+		lookupRepo:    lookupRepo,
+		audioRepo:     audioRepo,
+		conceptRepo:   conceptRepo,
+		changeRepo:    changeRepo,
+		auditService:  *auditService,
+		wordCountMode: wordCountModeFromEnv(),
+		sanitizeMode:  sanitizeModeFromEnv(),
+		reviewFields:  reviewFieldsFromEnv(),
+	}, nil
 }
 
 // FindVocabByID retrieves a single Vocab record by its primary ID.
@@ -52,8 +114,16 @@ func NewVocabService() (*VocabService, error) {
 //
 //	    fmt.Printf("Found vocab: %+v\n", vocab)
 //	}
-func (s *VocabService) FindVocabByID(id int) (*mdl.Vocab, error) {
-	return s.repo.FindVocabByID(id)
+func (s *VocabService) FindVocabByID(id int) (vocab *mdl.Vocab, err error) {
+	vocab, err = s.repo.FindVocabByID(id)
+	if err != nil {
+		return
+	} else if vocab == nil {
+		return nil, fmt.Errorf("expected to find existing vocab with id %d", id)
+	}
+
+	err = s.attachAlternatives(vocab)
+	return
 }
 
 // FindVocabs retrieves a list of Vocab records from the database based on the specified criteria.
@@ -81,87 +151,314 @@ func (s *VocabService) FindVocabByID(id int) (*mdl.Vocab, error) {
 //	    }
 //	}
 func (s *VocabService) FindVocabs(learningCode string, hasFirst bool, limit int) (vocabs *[]mdl.Vocab, err error) {
-	return s.repo.FindVocabs(learningCode, hasFirst, limit)
+	vocabs, err = s.repo.FindVocabs(learningCode, hasFirst, limit)
+	if err != nil {
+		return
+	}
+
+	list := make([]*mdl.Vocab, len(*vocabs))
+	for i := range *vocabs {
+		list[i] = &(*vocabs)[i]
+	}
+
+	err = s.attachAlternatives(list...)
+	return
 }
 
 // CreateVocab attempts to create a new Vocab record in the database.
+// Vocab without language codes are given the default known and learning languages, see
+// DefaultLangCodes, and the free text fields are then normalized, see normalizeVocab.
+// The number of learning words is computed from the learning lang, see applyWordCount.
+// The part of speech and skill must be managed values, see conformVocabTerms.
 // Before creation, it validates the Vocab struct's fields to ensure they meet defined criteria
-// and checks if a Vocab record with the same learning language already exists in the database.
+// and checks if a Vocab record with the same learning language already exists in the database
+// for the learning language code. A vocab given a ConceptID joins that concept as one of its
+// translations, otherwise a new concept glossed by the first lang is created for it.
 // If the record exists, or if validation fails, it returns an error.
+// Unless forced, it also refuses to create a near duplicate of existing vocab, see FindDuplicateCandidates.
 //
 // Parameters:
 // - vocab: A pointer to the mdl.Vocab struct to be created.
+// - force: Whether to create the vocab even when near duplicates exist.
+// - actor: The user making the change, see ActorFrom.
 //
 // Returns:
+//   - A *DuplicateVocabError listing the candidate IDs when near duplicates exist and force is false.
 //   - An error if validation fails, if a record with the same learning language already exists,
 //     or if there's an error during the creation process. Returns nil if the record is successfully created.
 //
 // Usage example:
-// err := vocabService.CreateVocab(&vocab)
+// err := vocabService.CreateVocab(&vocab, false, srv.ActorFrom(ctx))
 //
 //	if err != nil {
 //	    log.Printf("Failed to create vocab: %v", err)
 //	}
-func (s *VocabService) CreateVocab(vocab *mdl.Vocab) (err error) {
+func (s *VocabService) CreateVocab(vocab *mdl.Vocab, force bool, actor string) (err error) {
 
-	if err = validateVocab(vocab); err != nil {
+	// Vocab created without language codes are in the default languages
+	known, learning := DefaultLangCodes()
+	if len(strings.TrimSpace(vocab.KnownLangCode)) == 0 {
+		vocab.KnownLangCode = known
+	}
+	if len(strings.TrimSpace(vocab.LearningLangCode)) == 0 {
+		vocab.LearningLangCode = learning
+	}
+
+	normalizeVocab(vocab)
+	if s.sanitizeMode == SanitizeEscape {
+		escapeVocab(vocab)
+	}
+
+	// The input checks are collected so every failing field is reported at once
+	errs := &ValidationError{}
+	errs.add("num_learning_words", applyWordCount(vocab, vocab.NumLearningWords, s.wordCountMode))
+
+	// Alternatives given only as delimited text are moved into the list
+	if len(vocab.AlternativeList) == 0 {
+		vocab.AlternativeList = legacyAlternatives(vocab)
+	}
+	errs.add("", validateAlternatives(vocab))
+	vocab.Alternatives = JoinAlternatives(vocab.AlternativeList)
+
+	errs.add("", validateVocab(vocab))
+	if err = errs.errOrNil(); err != nil {
+		return
+	}
+
+	if err = s.conformVocabTerms(vocab, true, true); err != nil {
+		return
+	}
+
+	// Grammar fields depend on the part of speech, so they are checked once it is conformed.
+	if err = validateGrammar(vocab); err != nil {
 		return
 	}
 
-	existing, err := s.repo.FindVocabByLearningLang(vocab.LearningLang)
+	existing, err := s.repo.FindVocabByLearningLang(vocab.LearningLang, vocab.LearningLangCode)
 	if err == nil && existing != nil {
-		return fmt.Errorf("vocab with learning lang %s and id %d already exists", vocab.LearningLang, existing.ID)
+		return invalidField("learning_lang", RuleUnique, 0, "vocab with learning lang %s and id %d already exists", vocab.LearningLang, existing.ID)
 	}
 
-	err = s.repo.CreateVocab(vocab)
+	if !force {
+		candidates, err := s.FindDuplicateCandidates(vocab)
+		if err != nil {
+			return err
+		}
+		if len(candidates) > 0 {
+			duplicate := &DuplicateVocabError{LearningLang: vocab.LearningLang}
+			for _, candidate := range candidates {
+				duplicate.CandidateIDs = append(duplicate.CandidateIDs, candidate.ID)
+			}
+			return duplicate
+		}
+	}
 
-	err = s.auditService.CreateVocabAudit("created vocab", "sys", nil, vocab)
+	// A vocab without a concept is wrapped in its own, created in the same transaction so a
+	// vocab that cannot be saved leaves no concept behind.
+	var audits auditTrail
+	if vocab.ConceptID != nil {
+		if _, err = s.conceptRepo.FindConceptByID(*vocab.ConceptID); err != nil {
+			return
+		}
+		if err = s.repo.CreateVocab(vocab, audits.vocab("created vocab", actor, nil, vocab)); err != nil {
+			return
+		}
+	} else {
+		concept := &mdl.Concept{Gloss: vocab.FirstLang, GlossLangCode: vocab.KnownLangCode, CreatedBy: actor}
+		if err = prepareConcept(concept); err != nil {
+			return
+		}
+		conceptAudit := func() (*mdl.Audit, error) {
+			return buildAudit("concept", concept.ID, "created concept", actor, "", concept.JSON())
+		}
+		vocabAudit := func() (*mdl.Audit, error) {
+			return buildVocabAudit("created vocab", actor, nil, vocab)
+		}
+		if err = s.conceptRepo.CreateConceptWithVocab(concept, vocab, audits.of(conceptAudit, vocabAudit)); err != nil {
+			return
+		}
+	}
+	audits.publish()
 
+	publishVocabChanged(vocab)
 	return
 }
 
-func (s *VocabService) UpdateVocab(updating *mdl.Vocab) (vocab *mdl.Vocab, err error) {
+// UpdateVocab applies a partial update to an existing Vocab record. Only the fields
+// present in the patch are changed, so clients no longer need to echo back values
+// they did not intend to edit. The patched record is validated before it is saved
+// and an audit entry is written containing just the fields that changed. Changes to the
+// fields that require review are refused, they are proposed with SubmitChangeRequest.
+//
+// Parameters:
+// - patch: A pointer to the mdl.VocabPatch describing the record ID and the fields to change.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the updated mdl.Vocab record.
+// - An error if the record cannot be found, the patch holds no changes, changes a field that
+// requires review, or validation or saving fails.
+//
+// Usage example:
+// hint := "past tense"
+// vocab, err := vocabService.UpdateVocab(&mdl.VocabPatch{ID: 123, Hint: &hint}, srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to update vocab: %v", err)
+//	}
+func (s *VocabService) UpdateVocab(patch *mdl.VocabPatch, actor string) (vocab *mdl.Vocab, err error) {
 
-	if err = validateVocabUpdate(updating); err != nil {
+	before, err := s.FindVocabByID(patch.ID)
+	if err != nil {
 		return
 	}
 
-	before, err := s.repo.FindVocabByID(updating.ID)
+	vocab, err = s.patchVocab(before, patch)
 	if err != nil {
-		return
-	} else if before == nil {
-		err = fmt.Errorf("expected to find existing vocab with id %d", updating.ID)
-		return
+		return nil, err
+	}
+
+	if err = s.checkReviewFields(before, vocab); err != nil {
+		return nil, err
 	}
 
+	if err = s.saveVocabUpdate(before, vocab, "updated vocab", actor); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// patchVocab applies a patch to a copy of the vocab, normalizing and validating the result
+// the same way for direct updates and for the changes proposed in change requests.
+func (s *VocabService) patchVocab(before *mdl.Vocab, patch *mdl.VocabPatch) (vocab *mdl.Vocab, err error) {
+
 	vocab = before.Clone()
 
-	if vocab.Hint != updating.Hint ||
-		vocab.Pos != updating.Pos ||
-		vocab.Skill != updating.Skill ||
-		vocab.FirstLang != updating.FirstLang ||
-		vocab.Infinitive != updating.Infinitive ||
-		vocab.Alternatives != updating.Alternatives ||
-		vocab.NumLearningWords != updating.NumLearningWords {
-		// Update allowed to change fields
-		vocab.Hint = updating.Hint
-		vocab.Pos = updating.Pos
-		vocab.Skill = updating.Skill
-		vocab.FirstLang = updating.FirstLang
-		vocab.Infinitive = updating.Infinitive
-		vocab.Alternatives = updating.Alternatives
-		vocab.NumLearningWords = updating.NumLearningWords
-	} else {
+	// Update allowed to change fields, the number of learning words always follows the learning lang
+	normalizePatch(patch, before.LearningLangCode)
+	if s.sanitizeMode == SanitizeEscape {
+		escapePatch(patch)
+	}
+	patch.ApplyTo(vocab)
+
+	supplied := 0
+	if patch.NumLearningWords != nil {
+		supplied = *patch.NumLearningWords
+	}
+	if err = applyWordCount(vocab, supplied, s.wordCountMode); err != nil {
+		return nil, err
+	}
+
+	if err = s.conformVocabTerms(vocab, patch.Pos != nil, patch.Skill != nil); err != nil {
+		return nil, err
+	}
+
+	if vocab.Compare(before) {
 		return nil, fmt.Errorf("update for vocab %d has no changes", vocab.ID)
 	}
 
-	err = s.repo.UpdateVocab(vocab)
+	if err = validateVocabUpdate(vocab); err != nil {
+		return nil, err
+	}
+	if err = validateGrammar(vocab); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// saveVocabUpdate saves a patched vocab and audits the change with the comments.
+func (s *VocabService) saveVocabUpdate(before *mdl.Vocab, vocab *mdl.Vocab, comments string, actor string) (err error) {
+
+	var audits auditTrail
+	if err = s.repo.UpdateVocab(vocab, audits.vocab(comments, actor, before, vocab)); err != nil {
+		return
+	}
+
+	audits.publish()
+	publishVocabChanged(vocab)
+	return
+}
+
+// RenameVocab corrects the learning lang of an existing Vocab record and optionally its
+// language codes. These fields key the record so they cannot be changed through UpdateVocab.
+// The new learning lang must not already belong to another record, the number of learning
+// words is re-computed from the new text, and a dedicated audit entry is written.
+//
+// Parameters:
+//   - rename: A pointer to the mdl.VocabRename holding the record ID, the corrected learning lang,
+//     the optional language codes and whether to keep the old spelling as an alternative.
+//   - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+//   - A pointer to the renamed mdl.Vocab record.
+//   - An error if the record cannot be found, the new learning lang is taken, nothing changes,
+//     or validation or saving fails.
+//
+// Usage example:
+// vocab, err := vocabService.RenameVocab(&mdl.VocabRename{ID: 123, LearningLang: "empezar", KeepOldAsAlternative: true}, srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to rename vocab: %v", err)
+//	}
+func (s *VocabService) RenameVocab(rename *mdl.VocabRename, actor string) (vocab *mdl.Vocab, err error) {
+
+	before, err := s.FindVocabByID(rename.ID)
 	if err != nil {
 		return
 	}
 
-	err = s.auditService.CreateVocabAudit("updated vocab", "sys", before, vocab)
+	vocab = before.Clone()
+
+	if len(rename.KnownLangCode) > 0 {
+		vocab.KnownLangCode = CanonicalLangCode(rename.KnownLangCode)
+	}
+	if len(rename.LearningLangCode) > 0 {
+		vocab.LearningLangCode = CanonicalLangCode(rename.LearningLangCode)
+	}
+
+	rename.LearningLang = NormalizeLearningText(rename.LearningLang, vocab.LearningLangCode)
+	vocab.LearningLang = rename.LearningLang
+
+	existing, findErr := s.repo.FindVocabByLearningLang(vocab.LearningLang, vocab.LearningLangCode)
+	if findErr == nil && existing != nil && existing.ID != rename.ID {
+		return nil, invalidField("learning_lang", RuleUnique, 0, "vocab with learning lang %s and id %d already exists", rename.LearningLang, existing.ID)
+	}
+
+	// The new spelling can no longer be one of its own alternatives
+	removeAlternative(vocab, rename.LearningLang)
+
+	if rename.KeepOldAsAlternative && before.LearningLang != rename.LearningLang {
+		if err = addAlternative(vocab, before.LearningLang, "former spelling", actor); err != nil {
+			return nil, err
+		}
+	}
+	if err = applyWordCount(vocab, 0, s.wordCountMode); err != nil {
+		return nil, err
+	}
+
+	if vocab.Compare(before) {
+		return nil, fmt.Errorf("rename for vocab %d has no changes", rename.ID)
+	}
+
+	if err = validateAlternatives(vocab); err != nil {
+		return nil, err
+	}
+	if err = validateVocab(vocab); err != nil {
+		return nil, err
+	}
+
+	comments := fmt.Sprintf("renamed vocab from %s to %s", before.LearningLang, vocab.LearningLang)
 
+	var audits auditTrail
+	err = s.repo.UpdateVocabAlternatives(vocab, removedAlternatives(before, vocab), audits.vocab(comments, actor, before, vocab))
+	if err != nil {
+		return
+	}
+
+	audits.publish()
+	publishVocabChanged(vocab)
 	return
 }
 
@@ -173,20 +470,18 @@ const (
 	maxInfinitiveLen   = 40
 	maxPosLen          = 40
 	maxHintLen         = 255
-	errFmtStrLangCode  = "%s must consist of two lowercase letters"
+	errFmtStrLangCode  = "%s must be a BCP-47 language tag such as es or pt-BR"
 )
 
 // validateVocab checks the validity of a Vocab struct's fields against defined constraints.
-// It ensures that string fields do not exceed their maximum lengths and do not contain characters
-// potentially harmful in the context of HTML or SQL. Specifically, it validates the 'LearningLang',
-// 'FirstLang', 'Alternatives', 'Skill', 'Infinitive', 'Pos', and 'Hint' fields for length and restricted
-// characters, and ensures 'KnownLangCode' and 'LearningLangCode' match the expected pattern for language codes.
+// Every registered field is checked, see validateVocabUpdate, and 'KnownLangCode' and
+// 'LearningLangCode' must name enabled managed languages, see validateManagedLangCode.
 //
 // Parameters:
 // - vocab: A pointer to the Vocab struct to validate.
 //
 // Returns:
-//   - An error if any validation fails, detailing the issue with the corresponding field.
+//   - A *ValidationError holding every failure, detailing the issue with each corresponding field.
 //     If all fields pass validation, nil is returned.
 //
 // Usage example:
@@ -197,37 +492,32 @@ const (
 //	}
 func validateVocab(vocab *mdl.Vocab) error {
 
-	if err := validateFieldContent(vocab.LearningLang, "Learning language", maxLearningLangLen); err != nil {
+	errs := &ValidationError{}
+	errs.add("", validateVocabUpdate(vocab))
 
-		return err
-	} else if len(vocab.LearningLang) == 0 {
-		return fmt.Errorf("learning lang field is required")
+	// Validate language codes, that are valid tags, as tags of enabled languages
+	if !errs.failed("known_lang_code") {
+		errs.add("known_lang_code", validateManagedLangCode(vocab.KnownLangCode, "Known language"))
 	}
-
-	if err := validateVocabUpdate(vocab); err != nil {
-		return err
+	if !errs.failed("learning_lang_code") {
+		errs.add("learning_lang_code", validateManagedLangCode(vocab.LearningLangCode, "Learning language"))
 	}
 
-	// Validate language codes with a more specific pattern
-	langCodePattern := regexp.MustCompile(`^[a-z]{2}$`)
-	if !langCodePattern.MatchString(vocab.KnownLangCode) || !langCodePattern.MatchString(vocab.LearningLangCode) {
-		return fmt.Errorf(errFmtStrLangCode, "Language codes")
-	}
-
-	return nil
+	return errs.errOrNil()
 }
 
-// validateVocabUpdate checks the validity of a Vocab struct's fields in the context of an update against defined
-// constraints. It ensures that string fields do not exceed their maximum lengths and do not contain characters
-// potentially harmful in the context of HTML or SQL. Specifically, it validates the 'LearningLang',
-// 'FirstLang', 'Alternatives', 'Skill', 'Infinitive', 'Pos', and 'Hint' fields for length and restricted
-// characters, and ensures 'KnownLangCode' and 'LearningLangCode' match the expected pattern for language codes.
+// validateVocabUpdate checks the validity of a Vocab struct's fields in the context of an update against the
+// registered vocab fields, see VocabFields. It ensures that text fields do not exceed their maximum lengths and
+// do not contain characters potentially harmful in the context of HTML or SQL, that required fields such as
+// 'LearningLang' are present, that limited fields such as 'Gender' hold one of their values, and that
+// 'KnownLangCode' and 'LearningLangCode' are canonical BCP-47 language tags, see ValidLangCode. The fields are
+// checked in registry order and the first failure of each field is collected in a *ValidationError.
 //
 // Parameters:
 // - vocab: A pointer to the Vocab struct to validate.
 //
 // Returns:
-//   - An error if any validation fails, detailing the issue with the corresponding field.
+//   - A *ValidationError holding every failure, detailing the issue with each corresponding field.
 //     If all fields pass validation, nil is returned.
 //
 // Usage example:
@@ -238,24 +528,10 @@ func validateVocab(vocab *mdl.Vocab) error {
 //	}
 func validateVocabUpdate(vocab *mdl.Vocab) error {
 
-	if err := validateFieldContent(vocab.FirstLang, "First language", maxFirstLangLen); err != nil {
-		return err
-	}
-	if err := validateFieldContent(vocab.Alternatives, "Alternatives", maxAlternativesLen); err != nil {
-		return err
-	}
-	if err := validateFieldContent(vocab.Skill, "Skill", maxSkillLen); err != nil {
-		return err
-	}
-	if err := validateFieldContent(vocab.Infinitive, "Infinitive", maxInfinitiveLen); err != nil {
-		return err
-	}
-	if err := validateFieldContent(vocab.Pos, "Part of speech", maxPosLen); err != nil {
-		return err
-	}
-	if err := validateFieldContent(vocab.Hint, "Hint", maxHintLen); err != nil {
-		return err
+	errs := &ValidationError{}
+	for _, field := range VocabFields {
+		errs.add(field.Name, validateVocabField(field, vocab))
 	}
 
-	return nil
+	return errs.errOrNil()
 }
@@ -65,7 +65,7 @@ func TestValidateVocab(t *testing.T) {
 				LearningLangCode: validLangCode,
 			},
 			wantErr: true,
-			errMsg:  fmt.Sprintf(errFmtStrLangCode, "Language codes"),
+			errMsg:  fmt.Sprintf(errFmtStrLangCode, "Known language code"),
 		},
 		{
 			name: "Missing LearningLang format",
@@ -81,7 +81,7 @@ func TestValidateVocab(t *testing.T) {
 				LearningLangCode: validLangCode,
 			},
 			wantErr: true,
-			errMsg:  "learning lang field is required",
+			errMsg:  "learning lang field is required; Known language code must be a BCP-47 language tag such as es or pt-BR",
 		},
 	}
 
@@ -110,7 +110,7 @@ func TestVocabService_FindVocabByID(t *testing.T) {
 		LearningLangCode: "es",
 		KnownLangCode:    "en",
 	}
-	_ = vocabService.CreateVocab(testVocab)
+	_ = vocabService.CreateVocab(testVocab, false, "sys")
 
 	// Execute the test
 	vocab, err := vocabService.FindVocabByID(testVocab.ID)
@@ -148,8 +148,8 @@ func TestVocabService_FindVocabs(t *testing.T) {
 		LearningLangCode: "es",
 		KnownLangCode:    "en",
 	}
-	_ = vocabService.CreateVocab(testVocab1)
-	_ = vocabService.CreateVocab(testVocab2)
+	_ = vocabService.CreateVocab(testVocab1, false, "sys")
+	_ = vocabService.CreateVocab(testVocab2, false, "sys")
 
 	// Define test cases
 	tests := []struct {
@@ -244,12 +244,12 @@ func TestVocabService_CreateVocab(t *testing.T) {
 		Created:          time.Now(),
 		LearningLangCode: "es",
 		KnownLangCode:    "en",
-	})
+	}, false, "sys")
 
 	// Execute test cases
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := vocabService.CreateVocab(tt.vocab)
+			err := vocabService.CreateVocab(tt.vocab, false, "sys")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreateVocab() error = %v, wantErr %v", err, tt.wantErr)
 			} else if err != nil && len(tt.errMsg) > 0 && err.Error() != tt.errMsg {
@@ -269,38 +269,57 @@ func TestVocabService_UpdateVocab(t *testing.T) {
 		ID:               1,
 		LearningLang:     "hola",
 		FirstLang:        "hello",
+		Hint:             "a greeting",
 		Created:          time.Now(),
 		LearningLangCode: "es",
 		KnownLangCode:    "en",
 	}
-	_ = vocabService.CreateVocab(existingVocab)
+	_ = vocabService.CreateVocab(existingVocab, false, "sys")
+
+	updatedFirst := "hello updated"
+	sameFirst := "hello updated"
+	longHint := strings.Repeat("a", maxHintLen+1)
 
 	// Define test cases
 	tests := []struct {
-		name    string
-		vocab   *mdl.Vocab // Vocab to update
-		wantErr bool
-		errMsg  string
+		name      string
+		patch     *mdl.VocabPatch
+		wantFirst string
+		wantErr   bool
+		errMsg    string
 	}{
 		{
 			name: "Successful vocab update",
-			vocab: &mdl.Vocab{
-				ID:               1, // Assumes ID 1 exists
-				LearningLang:     "hola",
-				FirstLang:        "hello updated",
-				LearningLangCode: "es",
-				KnownLangCode:    "en",
+			patch: &mdl.VocabPatch{
+				ID:        1, // Assumes ID 1 exists
+				FirstLang: &updatedFirst,
 			},
-			wantErr: false,
+			wantFirst: updatedFirst,
+			wantErr:   false,
+		},
+		{
+			name: "Patch with no changes",
+			patch: &mdl.VocabPatch{
+				ID:        1,
+				FirstLang: &sameFirst,
+			},
+			wantErr: true,
+			errMsg:  "update for vocab 1 has no changes",
+		},
+		{
+			name: "Patch with invalid hint",
+			patch: &mdl.VocabPatch{
+				ID:   1,
+				Hint: &longHint,
+			},
+			wantErr: true,
+			errMsg:  fmt.Sprintf(errFmtStrLen, "Hint", maxHintLen),
 		},
 		{
 			name: "Update non-existing vocab",
-			vocab: &mdl.Vocab{
-				ID:               999, // Assumes ID 999 does not exist
-				LearningLang:     "adios",
-				FirstLang:        "goodbye",
-				LearningLangCode: "es",
-				KnownLangCode:    "en",
+			patch: &mdl.VocabPatch{
+				ID:        999, // Assumes ID 999 does not exist
+				FirstLang: &updatedFirst,
 			},
 			wantErr: true,
 			errMsg:  "error finding vocab with id 999",
@@ -310,11 +329,13 @@ func TestVocabService_UpdateVocab(t *testing.T) {
 	// Execute test cases
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			updatedVocab, err := vocabService.UpdateVocab(tt.vocab)
+			updatedVocab, err := vocabService.UpdateVocab(tt.patch, "sys")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("UpdateVocab() error = %v, wantErr %v", err, tt.wantErr)
-			} else if err != nil && !tt.wantErr && updatedVocab.FirstLang != tt.vocab.FirstLang {
-				t.Errorf("UpdateVocab() failed to update fields properly. Expected firstLang %v, got %v", tt.vocab.FirstLang, updatedVocab.FirstLang)
+			} else if err == nil && updatedVocab.FirstLang != tt.wantFirst {
+				t.Errorf("UpdateVocab() failed to update fields properly. Expected firstLang %v, got %v", tt.wantFirst, updatedVocab.FirstLang)
+			} else if err == nil && updatedVocab.Hint != existingVocab.Hint {
+				t.Errorf("UpdateVocab() changed an untouched field. Expected hint %v, got %v", existingVocab.Hint, updatedVocab.Hint)
 			} else if err != nil && err.Error() != tt.errMsg {
 				t.Errorf("UpdateVocab() error = %v, wantErrMsg %v", err, tt.errMsg)
 			}
@@ -322,14 +343,133 @@ func TestVocabService_UpdateVocab(t *testing.T) {
 	}
 }
 
+// TestVocabService_RenameVocab tests the functionality of RenameVocab method.
+func TestVocabService_RenameVocab(t *testing.T) {
+	// Setup
+	vocabService := createMockVocabService()
+
+	_ = vocabService.CreateVocab(&mdl.Vocab{
+		LearningLang:     "empesar",
+		FirstLang:        "to begin",
+		NumLearningWords: 1,
+		LearningLangCode: "es",
+		KnownLangCode:    "en",
+	}, false, "sys")
+	_ = vocabService.CreateVocab(&mdl.Vocab{
+		LearningLang:     "perro",
+		FirstLang:        "dog",
+		NumLearningWords: 1,
+		LearningLangCode: "es",
+		KnownLangCode:    "en",
+	}, false, "sys")
+
+	tests := []struct {
+		name             string
+		rename           *mdl.VocabRename
+		wantAlternatives string
+		wantNumWords     int
+		wantErr          bool
+		errMsg           string
+	}{
+		{
+			name:             "Successful rename keeping old spelling",
+			rename:           &mdl.VocabRename{ID: 1, LearningLang: "empezar", KeepOldAsAlternative: true},
+			wantAlternatives: "empesar",
+			wantNumWords:     1,
+			wantErr:          false,
+		},
+		{
+			name:             "Rename recomputes number of words",
+			rename:           &mdl.VocabRename{ID: 1, LearningLang: "empezar a"},
+			wantAlternatives: "empesar",
+			wantNumWords:     2,
+			wantErr:          false,
+		},
+		{
+			name:    "Rename to an existing learning lang",
+			rename:  &mdl.VocabRename{ID: 1, LearningLang: "perro"},
+			wantErr: true,
+			errMsg:  "vocab with learning lang perro and id 2 already exists",
+		},
+		{
+			name:    "Rename with no changes",
+			rename:  &mdl.VocabRename{ID: 2, LearningLang: "perro"},
+			wantErr: true,
+			errMsg:  "rename for vocab 2 has no changes",
+		},
+		{
+			name:    "Rename with invalid language code",
+			rename:  &mdl.VocabRename{ID: 2, LearningLang: "perro", LearningLangCode: "Spanish"},
+			wantErr: true,
+			errMsg:  fmt.Sprintf(errFmtStrLangCode, "Learning language code"),
+		},
+		{
+			name:    "Rename non-existing vocab",
+			rename:  &mdl.VocabRename{ID: 999, LearningLang: "gato"},
+			wantErr: true,
+			errMsg:  "error finding vocab with id 999",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renamed, err := vocabService.RenameVocab(tt.rename, "sys")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RenameVocab() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil && err.Error() != tt.errMsg {
+				t.Errorf("RenameVocab() error = %v, wantErrMsg %v", err, tt.errMsg)
+			} else if err == nil {
+				if renamed.LearningLang != tt.rename.LearningLang {
+					t.Errorf("RenameVocab() learning lang = %v, want %v", renamed.LearningLang, tt.rename.LearningLang)
+				}
+				if renamed.Alternatives != tt.wantAlternatives {
+					t.Errorf("RenameVocab() alternatives = %v, want %v", renamed.Alternatives, tt.wantAlternatives)
+				}
+				if renamed.NumLearningWords != tt.wantNumWords {
+					t.Errorf("RenameVocab() num learning words = %v, want %v", renamed.NumLearningWords, tt.wantNumWords)
+				}
+			}
+		})
+	}
+}
+
+func TestVocabService_RecordsActor(t *testing.T) {
+	vocabService := createMockVocabService()
+
+	vocab := &mdl.Vocab{LearningLang: "perro", FirstLang: "dog", Pos: "noun", LearningLangCode: "es", KnownLangCode: "en"}
+	if err := vocabService.CreateVocab(vocab, false, "ana"); err != nil {
+		t.Fatalf("CreateVocab() error = %v", err)
+	}
+	hint := "a pet"
+	if _, err := vocabService.UpdateVocab(&mdl.VocabPatch{ID: vocab.ID, Hint: &hint}, "ben"); err != nil {
+		t.Fatalf("UpdateVocab() error = %v", err)
+	}
+
+	for actor, want := range map[string]string{"ana": mdl.AuditCreated, "ben": mdl.AuditUpdated} {
+		audits, err := vocabService.auditService.FindAuditsMatching(&mdl.AuditFilter{TableName: "vocab", CreatedBy: actor}, 10)
+		if err != nil || len(*audits) != 1 || (*audits)[0].Action != want {
+			t.Errorf("FindAuditsMatching() by %s = %+v, %v, want the %s vocab", actor, audits, err, want)
+		}
+	}
+}
+
 func createMockVocabService() VocabService {
 	// Initialize the mock repositories
-	mockVocabRepo := mock.NewMockVocabRepository()
 	mockAuditRepo := mock.NewMockAuditRepository()
+	mockAltRepo := mock.NewMockAlternativeRepository()
+	mockVocabRepo := mock.NewMockVocabRepository(mockAuditRepo, mockAltRepo)
+	mockFixitRepo := mock.NewMockFixitRepository()
 	mockAuditService := &AuditService{repo: mockAuditRepo}
 
 	vocabService := VocabService{
 		repo:         mockVocabRepo,
+		altRepo:      mockAltRepo,
+		fixitRepo:    mockFixitRepo,
+		mergeRepo:    mock.NewMockMergeRepository(mockVocabRepo, mockAltRepo, mockFixitRepo, mockAuditRepo),
+		lookupRepo:   mock.NewMockLookupRepository(mockVocabRepo),
+		audioRepo:    mock.NewMockAudioRepository(mockVocabRepo),
+		conceptRepo:  mock.NewMockConceptRepository(mockVocabRepo),
+		changeRepo:   mock.NewMockChangeRequestRepository(mockVocabRepo),
 		auditService: *mockAuditService,
 	}
 
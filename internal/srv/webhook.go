@@ -0,0 +1,418 @@
+package srv
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/db"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The headers of a webhook delivery. The signature is "sha256=" followed by the hex encoded
+// HMAC-SHA256 of the request body keyed with the secret of the webhook, see SignWebhookPayload.
+const (
+	WebhookSignatureHeader = "X-Verdure-Signature"
+	WebhookEventHeader     = "X-Verdure-Event"
+	WebhookDeliveryHeader  = "X-Verdure-Delivery"
+)
+
+const (
+	minWebhookSecretLen      = 16
+	maxWebhookURLLen         = 2000
+	maxWebhookDescriptionLen = 500
+	maxWebhookErrorLen       = 500
+
+	// maxWebhookAttempts is the number of attempts made before a delivery is failed.
+	maxWebhookAttempts = 8
+
+	// webhookBackoff doubles from webhookBaseBackoff after each failed attempt, up to webhookMaxBackoff.
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+
+	webhookTimeout = 10 * time.Second
+
+	// webhookBatch is the number of due deliveries claimed at once, and webhookLease how long
+	// they are held, long enough for each of them to time out in turn.
+	webhookBatch = 20
+	webhookLease = webhookBatch*webhookTimeout + time.Minute
+)
+
+// SignWebhookPayload returns the WebhookSignatureHeader value of a payload. Receivers check a
+// delivery by computing the same signature over the body with their secret and comparing
+// the two with hmac.Equal.
+//
+// Parameters:
+// - secret: The secret of the webhook.
+// - payload: The request body.
+//
+// Returns:
+// - The signature, "sha256=" followed by the hex encoded HMAC-SHA256 of the payload.
+//
+// Usage example:
+// expected := srv.SignWebhookPayload(secret, body)
+//
+//	if !hmac.Equal([]byte(expected), []byte(r.Header.Get(srv.WebhookSignatureHeader))) {
+//	    http.Error(w, "bad signature", http.StatusUnauthorized)
+//	}
+func SignWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff returns how long a delivery waits after its failed attempts before it is
+// attempted again, doubling from webhookBaseBackoff up to webhookMaxBackoff.
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := 1; i < attempts && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, webhookMaxBackoff)
+}
+
+// WebhookService handles business logic for registered webhooks and delivers the changes
+// queued for them.
+type WebhookService struct {
+	repo         db.WebhookRepository
+	auditService AuditService
+	client       *http.Client
+}
+
+// NewWebhookService creates a new instance of WebhookService.
+func NewWebhookService() (*WebhookService, error) {
+
+	repo, err := db.NewSqlWebhookRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := NewAuditService()
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebhookService{repo: repo, auditService: *auditService, client: &http.Client{Timeout: webhookTimeout}}, nil
+}
+
+// FindWebhooks retrieves the registered webhooks, ordered by primary ID.
+func (s *WebhookService) FindWebhooks() (*[]mdl.Webhook, error) {
+	return s.repo.FindWebhooks()
+}
+
+// CreateWebhook registers a webhook and writes an audit entry, without its secret.
+//
+// Parameters:
+// - webhook: A pointer to the mdl.Webhook to create, its ID is set on success.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - An error if validation fails or saving fails.
+//
+// Usage example:
+// err := webhookService.CreateWebhook(&mdl.Webhook{URL: "https://cache.example.com/hooks", Secret: secret, EventTypes: "vocab.*", Enabled: true}, srv.ActorFrom(ctx))
+//
+//	if err != nil {
+//	    log.Printf("Failed to create webhook: %v", err)
+//	}
+func (s *WebhookService) CreateWebhook(webhook *mdl.Webhook, actor string) (err error) {
+
+	normalizeWebhook(webhook)
+	if len(webhook.CreatedBy) == 0 {
+		webhook.CreatedBy = actor
+	}
+	if err = validateWebhook(webhook); err != nil {
+		return
+	}
+
+	if err = s.repo.CreateWebhook(webhook); err != nil {
+		return
+	}
+
+	return s.auditService.CreateAudit("webhook", webhook.ID, "created webhook", actor, "", webhook.JSON())
+}
+
+// UpdateWebhook applies a partial update to a webhook and writes an audit entry. A changed
+// secret signs the deliveries attempted from then on, including those already queued.
+//
+// Parameters:
+// - patch: The fields to change, only the non-nil fields are applied.
+// - actor: The user making the change, see ActorFrom.
+//
+// Returns:
+// - A pointer to the updated mdl.Webhook.
+// - An error if the webhook cannot be found, the patch changes nothing, validation fails, or saving fails.
+func (s *WebhookService) UpdateWebhook(patch *mdl.WebhookPatch, actor string) (webhook *mdl.Webhook, err error) {
+
+	before, err := s.repo.FindWebhookByID(patch.ID)
+	if err != nil {
+		return
+	}
+
+	webhook = &mdl.Webhook{}
+	*webhook = *before
+	if !patch.ApplyTo(webhook) {
+		return nil, fmt.Errorf("update for webhook %d has no changes", patch.ID)
+	}
+
+	normalizeWebhook(webhook)
+	if err = validateWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.UpdateWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	comments := "updated webhook"
+	if webhook.Secret != before.Secret {
+		comments = "updated webhook and its secret"
+	}
+	err = s.auditService.CreateAudit("webhook", webhook.ID, comments, actor, before.JSON(), webhook.JSON())
+	if err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// FindDeliveries retrieves the deliveries of a webhook, newest first.
+//
+// Parameters:
+// - webhookID: The primary ID of the webhook, or 0 for every webhook.
+// - status: Optional. Only the deliveries with this status, e.g. mdl.DeliveryFailed.
+// - limit: The maximum number of deliveries returned, from 1 to 500.
+//
+// Returns:
+// - A pointer to the slice of deliveries found.
+// - An error if the status or limit is invalid or the query fails.
+func (s *WebhookService) FindDeliveries(webhookID int, status string, limit int) (*[]mdl.WebhookDelivery, error) {
+	if len(status) > 0 && status != mdl.DeliveryPending && status != mdl.DeliveryDelivered && status != mdl.DeliveryFailed {
+		return nil, invalidField("status", RuleOneOf, 0, "status %s must be one of %s, %s, %s", status,
+			mdl.DeliveryPending, mdl.DeliveryDelivered, mdl.DeliveryFailed)
+	}
+	if limit < 1 || limit > 500 {
+		return nil, invalidField("limit", RuleInvalid, 500, "limit %d must be from 1 to 500", limit)
+	}
+
+	return s.repo.FindDeliveries(webhookID, status, limit)
+}
+
+// RedeliverWebhook queues a delivery again, usually a failed one, to be attempted right away
+// with a full set of attempts. The payload is sent as it was first queued.
+//
+// Parameters:
+// - deliveryID: The primary ID of the delivery.
+//
+// Returns:
+// - A pointer to the queued mdl.WebhookDelivery.
+// - An error if the delivery cannot be found, is already pending, or saving fails.
+//
+// Usage example:
+// delivery, err := webhookService.RedeliverWebhook(42)
+//
+//	if err != nil {
+//	    log.Printf("Failed to redeliver: %v", err)
+//	}
+func (s *WebhookService) RedeliverWebhook(deliveryID int) (delivery *mdl.WebhookDelivery, err error) {
+
+	delivery, err = s.repo.FindDeliveryByID(deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status == mdl.DeliveryPending {
+		return nil, invalidField("delivery_id", RuleInvalid, 0, "webhook delivery %d is already pending", deliveryID)
+	}
+
+	delivery.Status = mdl.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttempt = time.Now()
+	delivery.Delivered = nil
+	if err = s.repo.UpdateDelivery(delivery); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Redelivering webhook delivery %d of webhook %d", delivery.ID, delivery.WebhookID)
+	return
+}
+
+// DeliverDue claims the deliveries due by now and attempts each of them, recording the
+// outcome. A delivery answered with a 2xx status is delivered. Otherwise it is attempted
+// again after webhookBackoff, and failed once it has made maxWebhookAttempts, or at once
+// when its webhook is disabled.
+//
+// Parameters:
+// - ctx: The context of the attempts, a done context cancels the attempt in flight.
+// - now: The current time.
+//
+// Returns:
+// - The number of deliveries attempted.
+// - An error if the deliveries cannot be claimed or an outcome cannot be saved.
+func (s *WebhookService) DeliverDue(ctx context.Context, now time.Time) (attempted int, err error) {
+
+	due, err := s.repo.ClaimDueDeliveries(now, webhookLease, webhookBatch)
+	if err != nil {
+		return
+	}
+
+	webhooks := make(map[int]*mdl.Webhook)
+	for i := range *due {
+		delivery := &(*due)[i]
+
+		webhook, found := webhooks[delivery.WebhookID]
+		if !found {
+			if webhook, err = s.repo.FindWebhookByID(delivery.WebhookID); err != nil {
+				return
+			}
+			webhooks[delivery.WebhookID] = webhook
+		}
+
+		if webhook.Enabled {
+			s.attempt(ctx, webhook, delivery, now)
+			attempted++
+		} else {
+			delivery.Status = mdl.DeliveryFailed
+			delivery.LastError = fmt.Sprintf("webhook %d is disabled", webhook.ID)
+		}
+
+		if err = s.repo.UpdateDelivery(delivery); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// attempt posts the payload of a delivery to its webhook and records the outcome on the delivery.
+func (s *WebhookService) attempt(ctx context.Context, webhook *mdl.Webhook, delivery *mdl.WebhookDelivery, now time.Time) {
+	delivery.Attempts++
+	delivery.ResponseStatus = 0
+
+	failure := s.post(ctx, webhook, delivery)
+	if failure == nil {
+		delivered := time.Now()
+		delivery.Status = mdl.DeliveryDelivered
+		delivery.Delivered = &delivered
+		delivery.LastError = ""
+		return
+	}
+
+	delivery.LastError = failure.Error()
+	if runes := []rune(delivery.LastError); len(runes) > maxWebhookErrorLen {
+		delivery.LastError = string(runes[:maxWebhookErrorLen])
+	}
+	if delivery.Attempts >= maxWebhookAttempts {
+		delivery.Status = mdl.DeliveryFailed
+		log.Printf("Webhook delivery %d to webhook %d failed after %d attempts: %v",
+			delivery.ID, webhook.ID, delivery.Attempts, failure)
+		return
+	}
+	delivery.NextAttempt = now.Add(webhookBackoff(delivery.Attempts))
+}
+
+// post sends the signed payload of the delivery, setting its response status. It returns why
+// the attempt failed, or nil when the webhook answered with a 2xx status.
+func (s *WebhookService) post(ctx context.Context, webhook *mdl.Webhook, delivery *mdl.WebhookDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "verdure-admin-webhooks")
+	request.Header.Set(WebhookEventHeader, delivery.EventType)
+	request.Header.Set(WebhookDeliveryHeader, strconv.Itoa(delivery.ID))
+	request.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, body))
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+
+	delivery.ResponseStatus = response.StatusCode
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook answered with status %s", response.Status)
+	}
+	return nil
+}
+
+// RunDeliveries delivers the due deliveries every interval until the context is done. Failed
+// rounds are logged and tried again at the next interval.
+//
+// Usage example:
+// go webhookService.RunDeliveries(ctx, 5*time.Second)
+func (s *WebhookService) RunDeliveries(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				attempted, err := s.DeliverDue(ctx, time.Now())
+				if err != nil {
+					log.Printf("Failed to deliver webhooks: %v", err)
+				}
+				// A full batch may have more due deliveries behind it
+				if err != nil || attempted < webhookBatch || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+// normalizeWebhook tidies the URL and description of a webhook and stores its event types
+// lower cased and separated by ", ".
+func normalizeWebhook(webhook *mdl.Webhook) {
+	webhook.URL = strings.TrimSpace(webhook.URL)
+	webhook.Description = NormalizeText(webhook.Description)
+	webhook.EventTypes = strings.Join(mdl.SplitWebhookEventTypes(webhook.EventTypes), ", ")
+}
+
+// validateWebhook checks a webhook has an absolute http or https URL, a secret long enough to
+// sign with, and known event types.
+func validateWebhook(webhook *mdl.Webhook) error {
+
+	errs := &ValidationError{}
+	if len(webhook.URL) == 0 {
+		errs.add("url", invalidField("", RuleRequired, 0, "url field is required"))
+	} else if len(webhook.URL) > maxWebhookURLLen {
+		errs.add("url", invalidField("", RuleMaxLength, maxWebhookURLLen, errFmtStrLen, "url", maxWebhookURLLen))
+	} else if parsed, err := url.Parse(webhook.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") ||
+		len(parsed.Host) == 0 {
+		errs.add("url", invalidField("", RuleFormat, 0, "url %s must be an absolute http or https URL", webhook.URL))
+	}
+
+	if len(webhook.Secret) < minWebhookSecretLen {
+		errs.add("secret", invalidField("", RuleMinLength, minWebhookSecretLen,
+			"secret must be at least %d characters", minWebhookSecretLen))
+	}
+
+	for _, eventType := range mdl.SplitWebhookEventTypes(webhook.EventTypes) {
+		if !mdl.ValidWebhookEventType(eventType) {
+			errs.add("event_types", invalidField("", RuleOneOf, 0,
+				"event type %s must be a table event such as vocab.created, vocab.*, or fixit.completed", eventType))
+		}
+	}
+
+	errs.add("description", validateFieldContent(webhook.Description, "Description", maxWebhookDescriptionLen))
+
+	return errs.errOrNil()
+}
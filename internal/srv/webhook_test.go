@@ -0,0 +1,253 @@
+package srv
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"github.com/heather92115/verdure-admin/internal/db/mock"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const testWebhookSecret = "0123456789abcdef"
+
+func createMockWebhookService() WebhookService {
+	webhookRepo := mock.NewMockWebhookRepository()
+	return WebhookService{
+		repo:         webhookRepo,
+		auditService: AuditService{repo: mock.NewMockAuditRepositoryWithWebhooks(webhookRepo)},
+		client:       &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// receivedHook is a request received by a test webhook endpoint.
+type receivedHook struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookEndpoint starts an endpoint answering with the status and recording the requests it receives.
+func webhookEndpoint(t *testing.T, status int) (*httptest.Server, chan receivedHook) {
+	received := make(chan receivedHook, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- receivedHook{header: r.Header, body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{4, 4 * time.Minute},
+		{7, 32 * time.Minute},
+		{20, webhookMaxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := webhookBackoff(tt.attempts); got != tt.want {
+			t.Errorf("webhookBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookService_CreateWebhook(t *testing.T) {
+	tests := []struct {
+		name      string
+		webhook   mdl.Webhook
+		wantRule  string
+		wantField string
+		wantTypes string
+	}{
+		{"Valid webhook", mdl.Webhook{URL: " https://cache.example.com/hooks ", Secret: testWebhookSecret,
+			EventTypes: "Vocab.*, fixit.completed,vocab.*"}, "", "", "vocab.*, fixit.completed"},
+		{"Every event type", mdl.Webhook{URL: "http://localhost:8080", Secret: testWebhookSecret}, "", "", ""},
+		{"Missing URL", mdl.Webhook{Secret: testWebhookSecret}, RuleRequired, "url", ""},
+		{"Not an http URL", mdl.Webhook{URL: "ftp://cache.example.com", Secret: testWebhookSecret}, RuleFormat, "url", ""},
+		{"Relative URL", mdl.Webhook{URL: "/hooks", Secret: testWebhookSecret}, RuleFormat, "url", ""},
+		{"Short secret", mdl.Webhook{URL: "https://cache.example.com", Secret: "secret"}, RuleMinLength, "secret", ""},
+		{"Unknown event type", mdl.Webhook{URL: "https://cache.example.com", Secret: testWebhookSecret,
+			EventTypes: "vocab.renamed"}, RuleOneOf, "event_types", ""},
+		{"Unknown table", mdl.Webhook{URL: "https://cache.example.com", Secret: testWebhookSecret,
+			EventTypes: "webhook.*"}, RuleOneOf, "event_types", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhookService := createMockWebhookService()
+			webhook := tt.webhook
+
+			err := webhookService.CreateWebhook(&webhook, "sys")
+			if len(tt.wantRule) == 0 {
+				if err != nil {
+					t.Fatalf("CreateWebhook() error = %v", err)
+				}
+				if webhook.ID == 0 || webhook.EventTypes != tt.wantTypes || webhook.CreatedBy != "sys" {
+					t.Errorf("CreateWebhook() = %+v", webhook)
+				}
+				audits, _ := webhookService.auditService.FindAudits("webhook", webhook.ID, nil, 0)
+				if len(*audits) != 1 || !json.Valid([]byte((*audits)[0].After)) {
+					t.Fatalf("CreateWebhook() audits = %+v", audits)
+				}
+				var audited map[string]interface{}
+				_ = json.Unmarshal([]byte((*audits)[0].After), &audited)
+				if _, found := audited["secret"]; found {
+					t.Errorf("CreateWebhook() audited the secret, %s", (*audits)[0].After)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 ||
+				validationErr.Errors[0].Rule != tt.wantRule || validationErr.Errors[0].Field != tt.wantField {
+				t.Errorf("CreateWebhook() error = %#v, want %s at %s", err, tt.wantRule, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestWebhookService_DeliverDue(t *testing.T) {
+	server, received := webhookEndpoint(t, http.StatusNoContent)
+	webhookService := createMockWebhookService()
+	fixitService := FixitService{repo: mock.NewMockFixitRepository(), auditService: webhookService.auditService}
+
+	completedOnly := &mdl.Webhook{URL: server.URL, Secret: testWebhookSecret, EventTypes: "fixit.completed", Enabled: true}
+	everything := &mdl.Webhook{URL: server.URL, Secret: "fedcba9876543210", Enabled: true}
+	disabled := &mdl.Webhook{URL: server.URL, Secret: testWebhookSecret, Enabled: false}
+	for _, webhook := range []*mdl.Webhook{completedOnly, everything, disabled} {
+		if err := webhookService.CreateWebhook(webhook, "sys"); err != nil {
+			t.Fatalf("CreateWebhook() error = %v", err)
+		}
+	}
+
+	fixit := &mdl.Fixit{VocabID: 101, Status: mdl.Pending, FieldName: "hint", Comments: "add a hint", CreatedBy: "tester"}
+	if err := fixitService.CreateFixit(fixit, "sys"); err != nil {
+		t.Fatalf("CreateFixit() error = %v", err)
+	}
+	status := mdl.Completed
+	if _, err := fixitService.UpdateFixit(&mdl.FixitPatch{ID: fixit.ID, Status: &status}, "sys"); err != nil {
+		t.Fatalf("UpdateFixit() error = %v", err)
+	}
+
+	// fixit.created and fixit.updated for everything, fixit.completed for both enabled webhooks
+	queued, _ := webhookService.repo.FindDeliveries(0, mdl.DeliveryPending, 10)
+	if len(*queued) != 4 {
+		t.Fatalf("queued deliveries = %+v, want 4", queued)
+	}
+
+	attempted, err := webhookService.DeliverDue(context.Background(), time.Now())
+	if err != nil || attempted != 4 {
+		t.Fatalf("DeliverDue() = %d, %v, want 4", attempted, err)
+	}
+
+	secrets := map[int]string{completedOnly.ID: completedOnly.Secret, everything.ID: everything.Secret}
+	for i := 0; i < 4; i++ {
+		hook := <-received
+
+		var payload mdl.WebhookPayload
+		if err = json.Unmarshal(hook.body, &payload); err != nil {
+			t.Fatalf("payload %s, error = %v", hook.body, err)
+		}
+		if payload.Event != hook.header.Get(WebhookEventHeader) || payload.Table != "fixit" || payload.ObjectID != fixit.ID {
+			t.Errorf("payload = %+v, event header %s", payload, hook.header.Get(WebhookEventHeader))
+		}
+
+		deliveryID, _ := strconv.Atoi(hook.header.Get(WebhookDeliveryHeader))
+		delivery, _ := webhookService.repo.FindDeliveryByID(deliveryID)
+		expected := SignWebhookPayload(secrets[delivery.WebhookID], hook.body)
+		if !hmac.Equal([]byte(expected), []byte(hook.header.Get(WebhookSignatureHeader))) {
+			t.Errorf("signature = %s, want %s", hook.header.Get(WebhookSignatureHeader), expected)
+		}
+		if delivery.Status != mdl.DeliveryDelivered || delivery.Attempts != 1 || delivery.ResponseStatus != 204 || delivery.Delivered == nil {
+			t.Errorf("delivery = %+v", delivery)
+		}
+	}
+
+	completions, _ := webhookService.repo.FindDeliveries(completedOnly.ID, "", 10)
+	if len(*completions) != 1 || (*completions)[0].EventType != "fixit.completed" {
+		t.Errorf("deliveries of the completed only webhook = %+v", completions)
+	}
+
+	// Nothing is left due
+	if attempted, err = webhookService.DeliverDue(context.Background(), time.Now()); err != nil || attempted != 0 {
+		t.Errorf("DeliverDue() again = %d, %v, want 0", attempted, err)
+	}
+}
+
+func TestWebhookService_Retries(t *testing.T) {
+	server, received := webhookEndpoint(t, http.StatusServiceUnavailable)
+	webhookService := createMockWebhookService()
+
+	webhook := &mdl.Webhook{URL: server.URL, Secret: testWebhookSecret, EventTypes: "vocab.*", Enabled: true}
+	if err := webhookService.CreateWebhook(webhook, "sys"); err != nil {
+		t.Fatalf("CreateWebhook() error = %v", err)
+	}
+	if err := webhookService.auditService.CreateAudit("vocab", 7, "created vocab", "sys", "", `{"id":7}`); err != nil {
+		t.Fatalf("CreateAudit() error = %v", err)
+	}
+
+	now := time.Now()
+	for attempt := 1; attempt <= maxWebhookAttempts; attempt++ {
+		if attempted, err := webhookService.DeliverDue(context.Background(), now); err != nil || attempted != 1 {
+			t.Fatalf("DeliverDue() attempt %d = %d, %v", attempt, attempted, err)
+		}
+		<-received
+
+		delivery, _ := webhookService.repo.FindDeliveryByID(1)
+		if delivery.Attempts != attempt || delivery.ResponseStatus != http.StatusServiceUnavailable || len(delivery.LastError) == 0 {
+			t.Fatalf("delivery after attempt %d = %+v", attempt, delivery)
+		}
+		if attempt == maxWebhookAttempts {
+			if delivery.Status != mdl.DeliveryFailed {
+				t.Fatalf("delivery after the last attempt = %+v", delivery)
+			}
+			break
+		}
+		if delivery.Status != mdl.DeliveryPending || !delivery.NextAttempt.Equal(now.Add(webhookBackoff(attempt))) {
+			t.Fatalf("delivery after attempt %d = %+v", attempt, delivery)
+		}
+
+		// It is not due again until the backoff has passed
+		if attempted, _ := webhookService.DeliverDue(context.Background(), now); attempted != 0 {
+			t.Fatalf("DeliverDue() before the backoff = %d", attempted)
+		}
+		now = delivery.NextAttempt
+	}
+
+	failed, _ := webhookService.FindDeliveries(webhook.ID, mdl.DeliveryFailed, 10)
+	if len(*failed) != 1 {
+		t.Fatalf("FindDeliveries(failed) = %+v", failed)
+	}
+
+	delivery, err := webhookService.RedeliverWebhook(1)
+	if err != nil || delivery.Status != mdl.DeliveryPending || delivery.Attempts != 0 {
+		t.Fatalf("RedeliverWebhook() = %+v, %v", delivery, err)
+	}
+	if _, err = webhookService.RedeliverWebhook(1); err == nil {
+		t.Errorf("RedeliverWebhook() of a pending delivery expected an error")
+	}
+
+	// Disabling the webhook fails its due deliveries without posting them
+	enabled := false
+	if _, err = webhookService.UpdateWebhook(&mdl.WebhookPatch{ID: webhook.ID, Enabled: &enabled}, "sys"); err != nil {
+		t.Fatalf("UpdateWebhook() error = %v", err)
+	}
+	if attempted, err := webhookService.DeliverDue(context.Background(), time.Now()); err != nil || attempted != 0 {
+		t.Fatalf("DeliverDue() of a disabled webhook = %d, %v", attempted, err)
+	}
+	if delivery, _ = webhookService.repo.FindDeliveryByID(1); delivery.Status != mdl.DeliveryFailed {
+		t.Errorf("delivery of a disabled webhook = %+v", delivery)
+	}
+}
@@ -0,0 +1,201 @@
+package srv
+
+import (
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WordCountMode controls how VocabService treats a client supplied NumLearningWords
+// that does not match the value computed from the learning lang text.
+type WordCountMode string
+
+const (
+	// WordCountCorrect silently replaces the client value with the computed one.
+	WordCountCorrect WordCountMode = "correct"
+	// WordCountReject fails the request when the client value does not match.
+	WordCountReject WordCountMode = "reject"
+
+	errFmtStrWordCount = "num learning words %d does not match the %d words found in '%s'"
+)
+
+// wordCountModeFromEnv reads the WORD_COUNT_MODE environment variable, defaulting to WordCountCorrect.
+func wordCountModeFromEnv() WordCountMode {
+	if WordCountMode(strings.ToLower(os.Getenv("WORD_COUNT_MODE"))) == WordCountReject {
+		return WordCountReject
+	}
+	return WordCountCorrect
+}
+
+// TokenizeLearningLang splits learning lang text into its words using the rules for the
+// given language code. The rules are intentionally orthographic:
+//   - Words are separated by whitespace and by em or en dashes.
+//   - Hyphenated compounds such as "franco-alemán" are a single word.
+//   - Apostrophes inside a word, as in "don't" or "pa'lante", keep it a single word, except
+//     for managed languages with elision, where "l'homme" is the two words "l'" and "homme".
+//   - Clitic pronouns attached to a verb, as in "dámelo", are part of that verb.
+//   - Leading and trailing punctuation such as "¿", "?", "¡", "!", "," and quotes is
+//     dropped, and tokens made only of punctuation are not words.
+//
+// A language with a tokenize hook is split by the hook instead, see LanguageHooks.
+//
+// Parameters:
+// - text: The learning lang text to tokenize.
+// - langCode: The learning language code, e.g. "es".
+//
+// Returns:
+// - The words found in the text, in order.
+//
+// Usage example:
+// words := TokenizeLearningLang("¿Me lo das?", "es") // ["Me", "lo", "das"]
+func TokenizeLearningLang(text string, langCode string) (words []string) {
+	if tokenize := lookupLanguageHooks(langCode).Tokenize; tokenize != nil {
+		return tokenize(text)
+	}
+
+	language := lookupLanguage(langCode)
+	elision := language != nil && language.Elision
+
+	for _, field := range strings.FieldsFunc(text, isWordSeparator) {
+		token := strings.TrimFunc(field, isEdgePunctuation)
+		if strings.IndexFunc(token, isWordRune) == -1 {
+			continue
+		}
+
+		if elision {
+			words = append(words, splitElision(token)...)
+		} else {
+			words = append(words, token)
+		}
+	}
+
+	return
+}
+
+// CountLearningWords returns the number of words in the learning lang text, never less
+// than one since every vocab holds at least one word.
+func CountLearningWords(text string, langCode string) int {
+	count := len(TokenizeLearningLang(text, langCode))
+	if count == 0 {
+		return 1
+	}
+	return count
+}
+
+// applyWordCount sets the vocab NumLearningWords to the value computed from its learning
+// lang. When the mode is WordCountReject and a client supplied value disagrees with the
+// computed one, an error is returned instead. A supplied value of zero means none was given.
+func applyWordCount(vocab *mdl.Vocab, supplied int, mode WordCountMode) error {
+	computed := CountLearningWords(vocab.LearningLang, vocab.LearningLangCode)
+
+	if mode == WordCountReject && supplied != 0 && supplied != computed {
+		return invalidField("num_learning_words", RuleInvalid, 0, errFmtStrWordCount, supplied, computed, vocab.LearningLang)
+	}
+
+	vocab.NumLearningWords = computed
+	return nil
+}
+
+// splitElision separates an elided prefix such as "l'" or "qu'" from the word that follows it.
+func splitElision(token string) []string {
+	index := strings.IndexAny(token, "'’")
+	if index <= 0 || index > 3 {
+		return []string{token}
+	}
+
+	_, size := utf8.DecodeRuneInString(token[index:])
+	rest := token[index+size:]
+	if strings.IndexFunc(rest, isWordRune) == -1 {
+		return []string{token}
+	}
+
+	return append([]string{token[:index+size]}, splitElision(rest)...)
+}
+
+// isWordSeparator reports whether the rune separates two words.
+func isWordSeparator(r rune) bool {
+	return unicode.IsSpace(r) || r == '—' || r == '–'
+}
+
+// isEdgePunctuation reports whether the rune is punctuation or a symbol that may surround a word.
+func isEdgePunctuation(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// isWordRune reports whether the rune can make up part of a word.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
+
+// WordCountMismatch describes a Vocab record whose stored NumLearningWords disagrees
+// with the value computed from its learning lang.
+type WordCountMismatch struct {
+	VocabID      int
+	LearningLang string
+	Stored       int
+	Computed     int
+}
+
+// scanBatchSize is the number of vocab records inspected per batch by the maintenance scans.
+const scanBatchSize = 500
+
+// CheckWordCounts scans every Vocab record for the learning language code and reports the
+// ones whose stored NumLearningWords does not match the computed word count. When fix is
+// true, each mismatched record is corrected and an audit entry is written for it, unless the
+// number of learning words requires review, see VocabService.reviewFields.
+//
+// Parameters:
+// - learningCode: The learning language code to scan, or empty to scan every record.
+// - fix: Whether to correct the mismatched records.
+//
+// Returns:
+// - The mismatches found, in primary key order.
+// - An error if the scan fails, a correction requires review, or a correction cannot be saved.
+//
+// Usage example:
+// mismatches, err := vocabService.CheckWordCounts("es", false)
+//
+//	if err != nil {
+//	    log.Printf("Failed to check word counts: %v", err)
+//	}
+func (s *VocabService) CheckWordCounts(learningCode string, fix bool) (mismatches []WordCountMismatch, err error) {
+
+	err = s.repo.ScanVocabs(learningCode, scanBatchSize, func(batch *[]mdl.Vocab) error {
+		for _, stored := range *batch {
+			computed := CountLearningWords(stored.LearningLang, stored.LearningLangCode)
+			if computed == stored.NumLearningWords {
+				continue
+			}
+
+			mismatches = append(mismatches, WordCountMismatch{
+				VocabID:      stored.ID,
+				LearningLang: stored.LearningLang,
+				Stored:       stored.NumLearningWords,
+				Computed:     computed,
+			})
+
+			if !fix {
+				continue
+			}
+
+			before := stored.Clone()
+			vocab := stored.Clone()
+			vocab.NumLearningWords = computed
+			if err := s.checkReviewFields(before, vocab); err != nil {
+				return err
+			}
+
+			var audits auditTrail
+			if err := s.repo.UpdateVocab(vocab, audits.vocab("corrected num learning words", "wordcount", before, vocab)); err != nil {
+				return err
+			}
+			audits.publish()
+			publishVocabChanged(vocab)
+		}
+		return nil
+	})
+
+	return
+}
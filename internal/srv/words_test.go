@@ -0,0 +1,116 @@
+package srv
+
+import (
+	"fmt"
+	"github.com/heather92115/verdure-admin/internal/mdl"
+	"reflect"
+	"testing"
+)
+
+func TestTokenizeLearningLang(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		langCode string
+		want     []string
+	}{
+		{name: "Single word", text: "perro", langCode: "es", want: []string{"perro"}},
+		{name: "Spanish question marks", text: "¿Me lo das?", langCode: "es", want: []string{"Me", "lo", "das"}},
+		{name: "Attached clitics", text: "¡Dámelo!", langCode: "es", want: []string{"Dámelo"}},
+		{name: "Hyphenated compound", text: "franco-alemán", langCode: "es", want: []string{"franco-alemán"}},
+		{name: "Extra whitespace", text: "  buenos    días ", langCode: "es", want: []string{"buenos", "días"}},
+		{name: "Punctuation only token", text: "sí , no", langCode: "es", want: []string{"sí", "no"}},
+		{name: "Dash separated", text: "hola—adiós", langCode: "es", want: []string{"hola", "adiós"}},
+		{name: "English contraction", text: "don't", langCode: "en", want: []string{"don't"}},
+		{name: "French elision", text: "l'homme", langCode: "fr", want: []string{"l'", "homme"}},
+		{name: "French curly elision", text: "qu’il", langCode: "fr", want: []string{"qu’", "il"}},
+		{name: "Empty", text: "", langCode: "es", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TokenizeLearningLang(tt.text, tt.langCode); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TokenizeLearningLang() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyWordCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		supplied int
+		mode     WordCountMode
+		want     int
+		wantErr  bool
+	}{
+		{name: "Computed when not supplied", text: "buenos días", supplied: 0, mode: WordCountReject, want: 2},
+		{name: "Matching value accepted", text: "buenos días", supplied: 2, mode: WordCountReject, want: 2},
+		{name: "Mismatch corrected", text: "buenos días", supplied: 3, mode: WordCountCorrect, want: 2},
+		{name: "Mismatch rejected", text: "buenos días", supplied: 3, mode: WordCountReject, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vocab := &mdl.Vocab{LearningLang: tt.text, LearningLangCode: "es"}
+			err := applyWordCount(vocab, tt.supplied, tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("applyWordCount() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil && err.Error() != fmt.Sprintf(errFmtStrWordCount, tt.supplied, 2, tt.text) {
+				t.Errorf("applyWordCount() unexpected error = %v", err)
+			} else if err == nil && vocab.NumLearningWords != tt.want {
+				t.Errorf("applyWordCount() NumLearningWords = %d, want %d", vocab.NumLearningWords, tt.want)
+			}
+		})
+	}
+}
+
+func TestVocabService_CheckWordCounts(t *testing.T) {
+	vocabService := createMockVocabService()
+
+	_ = vocabService.CreateVocab(&mdl.Vocab{
+		LearningLang:     "buenos días",
+		LearningLangCode: "es",
+		KnownLangCode:    "en",
+	}, false, "sys")
+
+	// Seed an inconsistent record directly in the repository, bypassing the service.
+	_ = vocabService.repo.CreateVocab(&mdl.Vocab{
+		LearningLang:     "¿qué tal?",
+		NumLearningWords: 1,
+		LearningLangCode: "es",
+		KnownLangCode:    "en",
+	}, nil)
+
+	mismatches, err := vocabService.CheckWordCounts("es", false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []WordCountMismatch{{VocabID: 2, LearningLang: "¿qué tal?", Stored: 1, Computed: 2}}
+	if !reflect.DeepEqual(mismatches, want) {
+		t.Errorf("CheckWordCounts() = %+v, want %+v", mismatches, want)
+	}
+
+	vocabService.reviewFields = []string{"num_learning_words"}
+	if _, err = vocabService.CheckWordCounts("es", true); err == nil {
+		t.Errorf("CheckWordCounts() expected the fix to require review")
+	}
+	if vocab, _ := vocabService.FindVocabByID(2); vocab.NumLearningWords != 1 {
+		t.Errorf("CheckWordCounts() fixed a field requiring review to %d", vocab.NumLearningWords)
+	}
+
+	vocabService.reviewFields = nil
+	_, err = vocabService.CheckWordCounts("es", true)
+	if err != nil {
+		t.Fatalf("Unexpected error on fix: %v", err)
+	}
+
+	mismatches, err = vocabService.CheckWordCounts("es", false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mismatches) != 0 {
+		t.Errorf("Expected no mismatches after fix, got %+v", mismatches)
+	}
+}